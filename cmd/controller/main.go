@@ -4,10 +4,14 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+
 	internalclient "github.com/skupperproject/skupper/internal/kube/client"
 	"github.com/skupperproject/skupper/internal/kube/controller"
 	"github.com/skupperproject/skupper/internal/version"
@@ -58,6 +62,9 @@ func main() {
 	} else {
 		log.Println("Skupper controller watching namespace", config.WatchNamespace)
 	}
+	if config.LeaderElection.Enabled {
+		log.Println("Skupper controller leader election enabled")
+	}
 
 	// set up signals so we handle the first shutdown signal gracefully
 	stopCh := SetupSignalHandler()
@@ -73,6 +80,19 @@ func main() {
 		log.Fatal("Error getting new site controller ", err.Error())
 	}
 
+	if config.MetricsAddress != "" {
+		reg := prometheus.NewRegistry()
+		reg.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+		if err := controller.RegisterMetrics(reg); err != nil {
+			log.Fatal("Error registering metrics: ", err.Error())
+		}
+		go func() {
+			if err := http.ListenAndServe(config.MetricsAddress, controller.Handler(reg)); err != nil {
+				log.Fatal("Error serving metrics: ", err.Error())
+			}
+		}()
+	}
+
 	if err = controller.Run(stopCh); err != nil {
 		log.Fatal("Error running site controller: ", err.Error())
 	}
@@ -24,7 +24,21 @@ type Config struct {
 	RouterTLS     TLSSpec
 	FlowRecordTTL time.Duration
 
+	FlowStorePath    string
+	FlowStoreMaxAge  time.Duration
+	FlowStoreMaxSize int64
+
+	HistoryRetention time.Duration
+
 	VanflowLoggingProfile string
+	VanflowCapturePath    string
+	VanflowReplayPath     string
+	VanflowReplaySpeed    float64
+
+	OTLPEndpoint string
+	OTLPProtocol string
+	OTLPInsecure bool
+	OTLPHeaders  string
 
 	EnableProfile bool
 	CORSAllowAll  bool
@@ -94,6 +94,21 @@ func (r *FlowAggregateResponse) SetResults(v FlowAggregateRecord) {
 	r.Results = v
 }
 
+// SetCount
+func (r *HistoryListResponse) SetCount(v int64) {
+	r.Count = v
+}
+
+// SetResults
+func (r *HistoryListResponse) SetResults(v []HistoryRecord) {
+	r.Results = v
+}
+
+// SetTimeRangeCount
+func (r *HistoryListResponse) SetTimeRangeCount(v int64) {
+	r.TimeRangeCount = v
+}
+
 // SetCount
 func (r *ListenerListResponse) SetCount(v int64) {
 	r.Count = v
@@ -296,6 +311,16 @@ func (r FlowAggregateRecord) GetStartTime() uint64 {
 	return r.StartTime
 }
 
+// GetEndTime
+func (r HistoryRecord) GetEndTime() uint64 {
+	return r.EndTime
+}
+
+// GetStartTime
+func (r HistoryRecord) GetStartTime() uint64 {
+	return r.StartTime
+}
+
 // GetEndTime
 func (r ListenerRecord) GetEndTime() uint64 {
 	return r.EndTime
@@ -23,6 +23,15 @@ const (
 	Remote   ProcessRecordRole = "remote"
 )
 
+// Defines values for WatchEventType.
+const (
+	ADDED    WatchEventType = "ADDED"
+	BOOKMARK WatchEventType = "BOOKMARK"
+	DELETED  WatchEventType = "DELETED"
+	ERROR    WatchEventType = "ERROR"
+	MODIFIED WatchEventType = "MODIFIED"
+)
+
 // Defines values for FlowAggregatePairType.
 const (
 	PROCESS      FlowAggregatePairType = "PROCESS"
@@ -274,6 +283,45 @@ type FlowAggregateResponse struct {
 	Results FlowAggregateRecord `json:"results"`
 }
 
+// HistoryListResponse defines model for HistoryListResponse.
+type HistoryListResponse struct {
+	// Count number of results in response
+	Count   int64           `json:"count"`
+	Results []HistoryRecord `json:"results"`
+
+	// TimeRangeCount number of results matching filtering and time range constraints before any limit or offset is applied.
+	TimeRangeCount int64 `json:"timeRangeCount"`
+}
+
+// HistoryRecord defines model for HistoryRecord.
+type HistoryRecord struct {
+	BytesReceived uint64 `json:"bytesReceived"`
+	BytesSent     uint64 `json:"bytesSent"`
+
+	// ConnectionErrors connections closed with an error
+	ConnectionErrors  uint64 `json:"connectionErrors"`
+	ConnectionsClosed uint64 `json:"connectionsClosed"`
+	ConnectionsOpened uint64 `json:"connectionsOpened"`
+
+	// EndTime The end time in microseconds of the record in Unix timestamp format.
+	EndTime uint64 `json:"endTime"`
+
+	// Identity The unique identifier for the record.
+	Identity string `json:"identity"`
+
+	// LinkDowns number of times a router link went down
+	LinkDowns uint64 `json:"linkDowns"`
+
+	// RequestErrors application requests completed with a server error
+	RequestErrors uint64 `json:"requestErrors"`
+
+	// Requests application requests completed
+	Requests uint64 `json:"requests"`
+
+	// StartTime The creation time in microseconds of the record in Unix timestamp format. The value 0 means that the record is not terminated
+	StartTime uint64 `json:"startTime"`
+}
+
 // ListenerListResponse defines model for ListenerListResponse.
 type ListenerListResponse struct {
 	// Count number of results in response
@@ -567,6 +615,22 @@ type SiteResponse struct {
 	Results SiteRecord `json:"results"`
 }
 
+// WatchEvent defines model for WatchEvent.
+type WatchEvent struct {
+	Error *ErrorResponse `json:"error,omitempty"`
+
+	// Kind the kind of record changed, as used in the types parameter. One of SITE, ROUTER, LINK, ROUTER_ACCESS, CONNECTOR, LISTENER, PROCESS, SERVICE, COMPONENT, SITEPAIR, PROCESSPAIR or COMPONENTPAIR.
+	Kind *string `json:"kind,omitempty"`
+
+	// Record the record as returned by the endpoint for its kind. Deleted records only have their identity set when they cannot be mapped.
+	Record          *interface{}   `json:"record,omitempty"`
+	ResourceVersion uint64         `json:"resourceVersion"`
+	Type            WatchEventType `json:"type"`
+}
+
+// WatchEventType defines model for WatchEvent.Type.
+type WatchEventType string
+
 // BaseRecord defines model for baseRecord.
 type BaseRecord struct {
 	// EndTime The end time in microseconds of the record in Unix timestamp format.
@@ -639,6 +703,9 @@ type GetFlowAggregateByID = FlowAggregateResponse
 // GetFlowAggregates defines model for getFlowAggregates.
 type GetFlowAggregates = FlowAggregateListResponse
 
+// GetHistory defines model for getHistory.
+type GetHistory = HistoryListResponse
+
 // GetListenerByID defines model for getListenerByID.
 type GetListenerByID = ListenerResponse
 
@@ -684,6 +751,15 @@ type GetSites = SiteListResponse
 // NotSupported defines model for notSupported.
 type NotSupported = ErrorResponse
 
+// WatchParams defines parameters for Watch.
+type WatchParams struct {
+	// Types comma separated list of the kinds of records to watch. All kinds when unset.
+	Types *string `form:"types,omitempty" json:"types,omitempty"`
+
+	// ResourceVersion resource version to resume the watch from
+	ResourceVersion *uint64 `form:"resourceVersion,omitempty" json:"resourceVersion,omitempty"`
+}
+
 // RequestEditorFn  is the function signature for the RequestEditor callback function
 type RequestEditorFn func(ctx context.Context, req *http.Request) error
 
@@ -805,6 +881,9 @@ type ClientInterface interface {
 	// ProcesspairByID request
 	ProcesspairByID(ctx context.Context, id PathID, reqEditors ...RequestEditorFn) (*http.Response, error)
 
+	// ProcesspairHistoryByID request
+	ProcesspairHistoryByID(ctx context.Context, id PathID, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// Routeraccess request
 	Routeraccess(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
 
@@ -817,6 +896,9 @@ type ClientInterface interface {
 	// RouterlinkByID request
 	RouterlinkByID(ctx context.Context, id PathID, reqEditors ...RequestEditorFn) (*http.Response, error)
 
+	// RouterlinkHistoryByID request
+	RouterlinkHistoryByID(ctx context.Context, id PathID, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// Routers request
 	Routers(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
 
@@ -832,6 +914,9 @@ type ClientInterface interface {
 	// ConnectionsByService request
 	ConnectionsByService(ctx context.Context, id PathID, reqEditors ...RequestEditorFn) (*http.Response, error)
 
+	// ServiceHistoryByID request
+	ServiceHistoryByID(ctx context.Context, id PathID, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// ProcessesByService request
 	ProcessesByService(ctx context.Context, id PathID, reqEditors ...RequestEditorFn) (*http.Response, error)
 
@@ -844,6 +929,9 @@ type ClientInterface interface {
 	// SitepairByID request
 	SitepairByID(ctx context.Context, id PathID, reqEditors ...RequestEditorFn) (*http.Response, error)
 
+	// SitepairHistoryByID request
+	SitepairHistoryByID(ctx context.Context, id PathID, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// Sites request
 	Sites(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
 
@@ -858,6 +946,9 @@ type ClientInterface interface {
 
 	// RoutersBySite request
 	RoutersBySite(ctx context.Context, id PathID, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// Watch request
+	Watch(ctx context.Context, params *WatchParams, reqEditors ...RequestEditorFn) (*http.Response, error)
 }
 
 func (c *Client) Applicationflows(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
@@ -1052,6 +1143,18 @@ func (c *Client) ProcesspairByID(ctx context.Context, id PathID, reqEditors ...R
 	return c.Client.Do(req)
 }
 
+func (c *Client) ProcesspairHistoryByID(ctx context.Context, id PathID, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewProcesspairHistoryByIDRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
 func (c *Client) Routeraccess(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
 	req, err := NewRouteraccessRequest(c.Server)
 	if err != nil {
@@ -1100,6 +1203,18 @@ func (c *Client) RouterlinkByID(ctx context.Context, id PathID, reqEditors ...Re
 	return c.Client.Do(req)
 }
 
+func (c *Client) RouterlinkHistoryByID(ctx context.Context, id PathID, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewRouterlinkHistoryByIDRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
 func (c *Client) Routers(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
 	req, err := NewRoutersRequest(c.Server)
 	if err != nil {
@@ -1160,6 +1275,18 @@ func (c *Client) ConnectionsByService(ctx context.Context, id PathID, reqEditors
 	return c.Client.Do(req)
 }
 
+func (c *Client) ServiceHistoryByID(ctx context.Context, id PathID, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewServiceHistoryByIDRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
 func (c *Client) ProcessesByService(ctx context.Context, id PathID, reqEditors ...RequestEditorFn) (*http.Response, error) {
 	req, err := NewProcessesByServiceRequest(c.Server, id)
 	if err != nil {
@@ -1208,6 +1335,18 @@ func (c *Client) SitepairByID(ctx context.Context, id PathID, reqEditors ...Requ
 	return c.Client.Do(req)
 }
 
+func (c *Client) SitepairHistoryByID(ctx context.Context, id PathID, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewSitepairHistoryByIDRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
 func (c *Client) Sites(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
 	req, err := NewSitesRequest(c.Server)
 	if err != nil {
@@ -1268,6 +1407,18 @@ func (c *Client) RoutersBySite(ctx context.Context, id PathID, reqEditors ...Req
 	return c.Client.Do(req)
 }
 
+func (c *Client) Watch(ctx context.Context, params *WatchParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewWatchRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
 // NewApplicationflowsRequest generates requests for Applicationflows
 func NewApplicationflowsRequest(server string) (*http.Request, error) {
 	var err error
@@ -1749,6 +1900,40 @@ func NewProcesspairByIDRequest(server string, id PathID) (*http.Request, error)
 	return req, nil
 }
 
+// NewProcesspairHistoryByIDRequest generates requests for ProcesspairHistoryByID
+func NewProcesspairHistoryByIDRequest(server string, id PathID) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v2alpha1/processpairs/%s/history", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
 // NewRouteraccessRequest generates requests for Routeraccess
 func NewRouteraccessRequest(server string) (*http.Request, error) {
 	var err error
@@ -1871,6 +2056,40 @@ func NewRouterlinkByIDRequest(server string, id PathID) (*http.Request, error) {
 	return req, nil
 }
 
+// NewRouterlinkHistoryByIDRequest generates requests for RouterlinkHistoryByID
+func NewRouterlinkHistoryByIDRequest(server string, id PathID) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v2alpha1/routerlinks/%s/history", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
 // NewRoutersRequest generates requests for Routers
 func NewRoutersRequest(server string) (*http.Request, error) {
 	var err error
@@ -2027,6 +2246,40 @@ func NewConnectionsByServiceRequest(server string, id PathID) (*http.Request, er
 	return req, nil
 }
 
+// NewServiceHistoryByIDRequest generates requests for ServiceHistoryByID
+func NewServiceHistoryByIDRequest(server string, id PathID) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v2alpha1/services/%s/history", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
 // NewProcessesByServiceRequest generates requests for ProcessesByService
 func NewProcessesByServiceRequest(server string, id PathID) (*http.Request, error) {
 	var err error
@@ -2156,6 +2409,40 @@ func NewSitepairByIDRequest(server string, id PathID) (*http.Request, error) {
 	return req, nil
 }
 
+// NewSitepairHistoryByIDRequest generates requests for SitepairHistoryByID
+func NewSitepairHistoryByIDRequest(server string, id PathID) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v2alpha1/sitepairs/%s/history", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
 // NewSitesRequest generates requests for Sites
 func NewSitesRequest(server string) (*http.Request, error) {
 	var err error
@@ -2319,6 +2606,71 @@ func NewRoutersBySiteRequest(server string, id PathID) (*http.Request, error) {
 	return req, nil
 }
 
+// NewWatchRequest generates requests for Watch
+func NewWatchRequest(server string, params *WatchParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v2alpha1/watch")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Types != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "types", runtime.ParamLocationQuery, *params.Types); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.ResourceVersion != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "resourceVersion", runtime.ParamLocationQuery, *params.ResourceVersion); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
 func (c *Client) applyEditors(ctx context.Context, req *http.Request, additionalEditors []RequestEditorFn) error {
 	for _, r := range c.RequestEditors {
 		if err := r(ctx, req); err != nil {
@@ -2410,6 +2762,9 @@ type ClientWithResponsesInterface interface {
 	// ProcesspairByIDWithResponse request
 	ProcesspairByIDWithResponse(ctx context.Context, id PathID, reqEditors ...RequestEditorFn) (*ProcesspairByIDResponse, error)
 
+	// ProcesspairHistoryByIDWithResponse request
+	ProcesspairHistoryByIDWithResponse(ctx context.Context, id PathID, reqEditors ...RequestEditorFn) (*ProcesspairHistoryByIDResponse, error)
+
 	// RouteraccessWithResponse request
 	RouteraccessWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*RouteraccessResponse, error)
 
@@ -2422,6 +2777,9 @@ type ClientWithResponsesInterface interface {
 	// RouterlinkByIDWithResponse request
 	RouterlinkByIDWithResponse(ctx context.Context, id PathID, reqEditors ...RequestEditorFn) (*RouterlinkByIDResponse, error)
 
+	// RouterlinkHistoryByIDWithResponse request
+	RouterlinkHistoryByIDWithResponse(ctx context.Context, id PathID, reqEditors ...RequestEditorFn) (*RouterlinkHistoryByIDResponse, error)
+
 	// RoutersWithResponse request
 	RoutersWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*RoutersResponse, error)
 
@@ -2437,6 +2795,9 @@ type ClientWithResponsesInterface interface {
 	// ConnectionsByServiceWithResponse request
 	ConnectionsByServiceWithResponse(ctx context.Context, id PathID, reqEditors ...RequestEditorFn) (*ConnectionsByServiceResponse, error)
 
+	// ServiceHistoryByIDWithResponse request
+	ServiceHistoryByIDWithResponse(ctx context.Context, id PathID, reqEditors ...RequestEditorFn) (*ServiceHistoryByIDResponse, error)
+
 	// ProcessesByServiceWithResponse request
 	ProcessesByServiceWithResponse(ctx context.Context, id PathID, reqEditors ...RequestEditorFn) (*ProcessesByServiceResponse, error)
 
@@ -2449,6 +2810,9 @@ type ClientWithResponsesInterface interface {
 	// SitepairByIDWithResponse request
 	SitepairByIDWithResponse(ctx context.Context, id PathID, reqEditors ...RequestEditorFn) (*SitepairByIDResponse, error)
 
+	// SitepairHistoryByIDWithResponse request
+	SitepairHistoryByIDWithResponse(ctx context.Context, id PathID, reqEditors ...RequestEditorFn) (*SitepairHistoryByIDResponse, error)
+
 	// SitesWithResponse request
 	SitesWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*SitesResponse, error)
 
@@ -2463,6 +2827,9 @@ type ClientWithResponsesInterface interface {
 
 	// RoutersBySiteWithResponse request
 	RoutersBySiteWithResponse(ctx context.Context, id PathID, reqEditors ...RequestEditorFn) (*RoutersBySiteResponse, error)
+
+	// WatchWithResponse request
+	WatchWithResponse(ctx context.Context, params *WatchParams, reqEditors ...RequestEditorFn) (*WatchResponse, error)
 }
 
 type ApplicationflowsResponse struct {
@@ -2830,10 +3197,34 @@ func (r ProcesspairByIDResponse) StatusCode() int {
 	return 0
 }
 
-type RouteraccessResponse struct {
+type ProcesspairHistoryByIDResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
-	JSON200      *GetRouterAccess
+	JSON200      *GetHistory
+	JSON400      *ErrorBadRequest
+	JSON404      *ErrorNotFound
+}
+
+// Status returns HTTPResponse.Status
+func (r ProcesspairHistoryByIDResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ProcesspairHistoryByIDResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type RouteraccessResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *GetRouterAccess
 	JSON400      *ErrorBadRequest
 }
 
@@ -2922,6 +3313,30 @@ func (r RouterlinkByIDResponse) StatusCode() int {
 	return 0
 }
 
+type RouterlinkHistoryByIDResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *GetHistory
+	JSON400      *ErrorBadRequest
+	JSON404      *ErrorNotFound
+}
+
+// Status returns HTTPResponse.Status
+func (r RouterlinkHistoryByIDResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r RouterlinkHistoryByIDResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
 type RoutersResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
@@ -3037,6 +3452,30 @@ func (r ConnectionsByServiceResponse) StatusCode() int {
 	return 0
 }
 
+type ServiceHistoryByIDResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *GetHistory
+	JSON400      *ErrorBadRequest
+	JSON404      *ErrorNotFound
+}
+
+// Status returns HTTPResponse.Status
+func (r ServiceHistoryByIDResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ServiceHistoryByIDResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
 type ProcessesByServiceResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
@@ -3129,6 +3568,30 @@ func (r SitepairByIDResponse) StatusCode() int {
 	return 0
 }
 
+type SitepairHistoryByIDResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *GetHistory
+	JSON400      *ErrorBadRequest
+	JSON404      *ErrorNotFound
+}
+
+// Status returns HTTPResponse.Status
+func (r SitepairHistoryByIDResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r SitepairHistoryByIDResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
 type SitesResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
@@ -3243,6 +3706,29 @@ func (r RoutersBySiteResponse) StatusCode() int {
 	return 0
 }
 
+type WatchResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON400      *ErrorBadRequest
+	JSON410      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r WatchResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r WatchResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
 // ApplicationflowsWithResponse request returning *ApplicationflowsResponse
 func (c *ClientWithResponses) ApplicationflowsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ApplicationflowsResponse, error) {
 	rsp, err := c.Applicationflows(ctx, reqEditors...)
@@ -3387,6 +3873,15 @@ func (c *ClientWithResponses) ProcesspairByIDWithResponse(ctx context.Context, i
 	return ParseProcesspairByIDResponse(rsp)
 }
 
+// ProcesspairHistoryByIDWithResponse request returning *ProcesspairHistoryByIDResponse
+func (c *ClientWithResponses) ProcesspairHistoryByIDWithResponse(ctx context.Context, id PathID, reqEditors ...RequestEditorFn) (*ProcesspairHistoryByIDResponse, error) {
+	rsp, err := c.ProcesspairHistoryByID(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseProcesspairHistoryByIDResponse(rsp)
+}
+
 // RouteraccessWithResponse request returning *RouteraccessResponse
 func (c *ClientWithResponses) RouteraccessWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*RouteraccessResponse, error) {
 	rsp, err := c.Routeraccess(ctx, reqEditors...)
@@ -3423,6 +3918,15 @@ func (c *ClientWithResponses) RouterlinkByIDWithResponse(ctx context.Context, id
 	return ParseRouterlinkByIDResponse(rsp)
 }
 
+// RouterlinkHistoryByIDWithResponse request returning *RouterlinkHistoryByIDResponse
+func (c *ClientWithResponses) RouterlinkHistoryByIDWithResponse(ctx context.Context, id PathID, reqEditors ...RequestEditorFn) (*RouterlinkHistoryByIDResponse, error) {
+	rsp, err := c.RouterlinkHistoryByID(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseRouterlinkHistoryByIDResponse(rsp)
+}
+
 // RoutersWithResponse request returning *RoutersResponse
 func (c *ClientWithResponses) RoutersWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*RoutersResponse, error) {
 	rsp, err := c.Routers(ctx, reqEditors...)
@@ -3468,6 +3972,15 @@ func (c *ClientWithResponses) ConnectionsByServiceWithResponse(ctx context.Conte
 	return ParseConnectionsByServiceResponse(rsp)
 }
 
+// ServiceHistoryByIDWithResponse request returning *ServiceHistoryByIDResponse
+func (c *ClientWithResponses) ServiceHistoryByIDWithResponse(ctx context.Context, id PathID, reqEditors ...RequestEditorFn) (*ServiceHistoryByIDResponse, error) {
+	rsp, err := c.ServiceHistoryByID(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseServiceHistoryByIDResponse(rsp)
+}
+
 // ProcessesByServiceWithResponse request returning *ProcessesByServiceResponse
 func (c *ClientWithResponses) ProcessesByServiceWithResponse(ctx context.Context, id PathID, reqEditors ...RequestEditorFn) (*ProcessesByServiceResponse, error) {
 	rsp, err := c.ProcessesByService(ctx, id, reqEditors...)
@@ -3504,6 +4017,15 @@ func (c *ClientWithResponses) SitepairByIDWithResponse(ctx context.Context, id P
 	return ParseSitepairByIDResponse(rsp)
 }
 
+// SitepairHistoryByIDWithResponse request returning *SitepairHistoryByIDResponse
+func (c *ClientWithResponses) SitepairHistoryByIDWithResponse(ctx context.Context, id PathID, reqEditors ...RequestEditorFn) (*SitepairHistoryByIDResponse, error) {
+	rsp, err := c.SitepairHistoryByID(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSitepairHistoryByIDResponse(rsp)
+}
+
 // SitesWithResponse request returning *SitesResponse
 func (c *ClientWithResponses) SitesWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*SitesResponse, error) {
 	rsp, err := c.Sites(ctx, reqEditors...)
@@ -3549,6 +4071,15 @@ func (c *ClientWithResponses) RoutersBySiteWithResponse(ctx context.Context, id
 	return ParseRoutersBySiteResponse(rsp)
 }
 
+// WatchWithResponse request returning *WatchResponse
+func (c *ClientWithResponses) WatchWithResponse(ctx context.Context, params *WatchParams, reqEditors ...RequestEditorFn) (*WatchResponse, error) {
+	rsp, err := c.Watch(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseWatchResponse(rsp)
+}
+
 // ParseApplicationflowsResponse parses an HTTP response from a ApplicationflowsWithResponse call
 func ParseApplicationflowsResponse(rsp *http.Response) (*ApplicationflowsResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
@@ -4056,6 +4587,46 @@ func ParseProcesspairByIDResponse(rsp *http.Response) (*ProcesspairByIDResponse,
 	return response, nil
 }
 
+// ParseProcesspairHistoryByIDResponse parses an HTTP response from a ProcesspairHistoryByIDWithResponse call
+func ParseProcesspairHistoryByIDResponse(rsp *http.Response) (*ProcesspairHistoryByIDResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ProcesspairHistoryByIDResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest GetHistory
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest ErrorBadRequest
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest ErrorNotFound
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	}
+
+	return response, nil
+}
+
 // ParseRouteraccessResponse parses an HTTP response from a RouteraccessWithResponse call
 func ParseRouteraccessResponse(rsp *http.Response) (*RouteraccessResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
@@ -4188,6 +4759,46 @@ func ParseRouterlinkByIDResponse(rsp *http.Response) (*RouterlinkByIDResponse, e
 	return response, nil
 }
 
+// ParseRouterlinkHistoryByIDResponse parses an HTTP response from a RouterlinkHistoryByIDWithResponse call
+func ParseRouterlinkHistoryByIDResponse(rsp *http.Response) (*RouterlinkHistoryByIDResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &RouterlinkHistoryByIDResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest GetHistory
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest ErrorBadRequest
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest ErrorNotFound
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	}
+
+	return response, nil
+}
+
 // ParseRoutersResponse parses an HTTP response from a RoutersWithResponse call
 func ParseRoutersResponse(rsp *http.Response) (*RoutersResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
@@ -4353,6 +4964,46 @@ func ParseConnectionsByServiceResponse(rsp *http.Response) (*ConnectionsByServic
 	return response, nil
 }
 
+// ParseServiceHistoryByIDResponse parses an HTTP response from a ServiceHistoryByIDWithResponse call
+func ParseServiceHistoryByIDResponse(rsp *http.Response) (*ServiceHistoryByIDResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ServiceHistoryByIDResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest GetHistory
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest ErrorBadRequest
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest ErrorNotFound
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	}
+
+	return response, nil
+}
+
 // ParseProcessesByServiceResponse parses an HTTP response from a ProcessesByServiceWithResponse call
 func ParseProcessesByServiceResponse(rsp *http.Response) (*ProcessesByServiceResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
@@ -4485,6 +5136,46 @@ func ParseSitepairByIDResponse(rsp *http.Response) (*SitepairByIDResponse, error
 	return response, nil
 }
 
+// ParseSitepairHistoryByIDResponse parses an HTTP response from a SitepairHistoryByIDWithResponse call
+func ParseSitepairHistoryByIDResponse(rsp *http.Response) (*SitepairHistoryByIDResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SitepairHistoryByIDResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest GetHistory
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest ErrorBadRequest
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest ErrorNotFound
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	}
+
+	return response, nil
+}
+
 // ParseSitesResponse parses an HTTP response from a SitesWithResponse call
 func ParseSitesResponse(rsp *http.Response) (*SitesResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
@@ -4643,6 +5334,39 @@ func ParseRoutersBySiteResponse(rsp *http.Response) (*RoutersBySiteResponse, err
 	return response, nil
 }
 
+// ParseWatchResponse parses an HTTP response from a WatchWithResponse call
+func ParseWatchResponse(rsp *http.Response) (*WatchResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &WatchResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest ErrorBadRequest
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 410:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON410 = &dest
+
+	}
+
+	return response, nil
+}
+
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
 
@@ -4694,6 +5418,9 @@ type ServerInterface interface {
 	// (GET /api/v2alpha1/processpairs/{id})
 	ProcesspairByID(w http.ResponseWriter, r *http.Request, id PathID)
 
+	// (GET /api/v2alpha1/processpairs/{id}/history)
+	ProcesspairHistoryByID(w http.ResponseWriter, r *http.Request, id PathID)
+
 	// (GET /api/v2alpha1/routeraccess)
 	Routeraccess(w http.ResponseWriter, r *http.Request)
 
@@ -4706,6 +5433,9 @@ type ServerInterface interface {
 	// (GET /api/v2alpha1/routerlinks/{id})
 	RouterlinkByID(w http.ResponseWriter, r *http.Request, id PathID)
 
+	// (GET /api/v2alpha1/routerlinks/{id}/history)
+	RouterlinkHistoryByID(w http.ResponseWriter, r *http.Request, id PathID)
+
 	// (GET /api/v2alpha1/routers)
 	Routers(w http.ResponseWriter, r *http.Request)
 
@@ -4721,6 +5451,9 @@ type ServerInterface interface {
 	// (GET /api/v2alpha1/services/{id}/connections)
 	ConnectionsByService(w http.ResponseWriter, r *http.Request, id PathID)
 
+	// (GET /api/v2alpha1/services/{id}/history)
+	ServiceHistoryByID(w http.ResponseWriter, r *http.Request, id PathID)
+
 	// (GET /api/v2alpha1/services/{id}/processes)
 	ProcessesByService(w http.ResponseWriter, r *http.Request, id PathID)
 
@@ -4733,6 +5466,9 @@ type ServerInterface interface {
 	// (GET /api/v2alpha1/sitepairs/{id})
 	SitepairByID(w http.ResponseWriter, r *http.Request, id PathID)
 
+	// (GET /api/v2alpha1/sitepairs/{id}/history)
+	SitepairHistoryByID(w http.ResponseWriter, r *http.Request, id PathID)
+
 	// (GET /api/v2alpha1/sites)
 	Sites(w http.ResponseWriter, r *http.Request)
 
@@ -4747,6 +5483,9 @@ type ServerInterface interface {
 
 	// (GET /api/v2alpha1/sites/{id}/routers)
 	RoutersBySite(w http.ResponseWriter, r *http.Request, id PathID)
+
+	// (GET /api/v2alpha1/watch)
+	Watch(w http.ResponseWriter, r *http.Request, params WatchParams)
 }
 
 // ServerInterfaceWrapper converts contexts to parameters.
@@ -5075,6 +5814,32 @@ func (siw *ServerInterfaceWrapper) ProcesspairByID(w http.ResponseWriter, r *htt
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
+// ProcesspairHistoryByID operation middleware
+func (siw *ServerInterfaceWrapper) ProcesspairHistoryByID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id PathID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", mux.Vars(r)["id"], &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ProcesspairHistoryByID(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
 // Routeraccess operation middleware
 func (siw *ServerInterfaceWrapper) Routeraccess(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -5157,6 +5922,32 @@ func (siw *ServerInterfaceWrapper) RouterlinkByID(w http.ResponseWriter, r *http
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
+// RouterlinkHistoryByID operation middleware
+func (siw *ServerInterfaceWrapper) RouterlinkHistoryByID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id PathID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", mux.Vars(r)["id"], &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RouterlinkHistoryByID(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
 // Routers operation middleware
 func (siw *ServerInterfaceWrapper) Routers(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -5265,6 +6056,32 @@ func (siw *ServerInterfaceWrapper) ConnectionsByService(w http.ResponseWriter, r
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
+// ServiceHistoryByID operation middleware
+func (siw *ServerInterfaceWrapper) ServiceHistoryByID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id PathID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", mux.Vars(r)["id"], &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ServiceHistoryByID(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
 // ProcessesByService operation middleware
 func (siw *ServerInterfaceWrapper) ProcessesByService(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -5358,6 +6175,32 @@ func (siw *ServerInterfaceWrapper) SitepairByID(w http.ResponseWriter, r *http.R
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
+// SitepairHistoryByID operation middleware
+func (siw *ServerInterfaceWrapper) SitepairHistoryByID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id PathID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", mux.Vars(r)["id"], &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SitepairHistoryByID(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
 // Sites operation middleware
 func (siw *ServerInterfaceWrapper) Sites(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -5477,6 +6320,42 @@ func (siw *ServerInterfaceWrapper) RoutersBySite(w http.ResponseWriter, r *http.
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
+// Watch operation middleware
+func (siw *ServerInterfaceWrapper) Watch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params WatchParams
+
+	// ------------- Optional query parameter "types" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "types", r.URL.Query(), &params.Types)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "types", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "resourceVersion" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "resourceVersion", r.URL.Query(), &params.ResourceVersion)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "resourceVersion", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Watch(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
 type UnescapedCookieParamError struct {
 	ParamName string
 	Err       error
@@ -5622,6 +6501,8 @@ func HandlerWithOptions(si ServerInterface, options GorillaServerOptions) http.H
 
 	r.HandleFunc(options.BaseURL+"/api/v2alpha1/processpairs/{id}", wrapper.ProcesspairByID).Methods("GET")
 
+	r.HandleFunc(options.BaseURL+"/api/v2alpha1/processpairs/{id}/history", wrapper.ProcesspairHistoryByID).Methods("GET")
+
 	r.HandleFunc(options.BaseURL+"/api/v2alpha1/routeraccess", wrapper.Routeraccess).Methods("GET")
 
 	r.HandleFunc(options.BaseURL+"/api/v2alpha1/routeraccess/{id}", wrapper.RouteraccessByID).Methods("GET")
@@ -5630,6 +6511,8 @@ func HandlerWithOptions(si ServerInterface, options GorillaServerOptions) http.H
 
 	r.HandleFunc(options.BaseURL+"/api/v2alpha1/routerlinks/{id}", wrapper.RouterlinkByID).Methods("GET")
 
+	r.HandleFunc(options.BaseURL+"/api/v2alpha1/routerlinks/{id}/history", wrapper.RouterlinkHistoryByID).Methods("GET")
+
 	r.HandleFunc(options.BaseURL+"/api/v2alpha1/routers", wrapper.Routers).Methods("GET")
 
 	r.HandleFunc(options.BaseURL+"/api/v2alpha1/routers/{id}", wrapper.RouterByID).Methods("GET")
@@ -5640,6 +6523,8 @@ func HandlerWithOptions(si ServerInterface, options GorillaServerOptions) http.H
 
 	r.HandleFunc(options.BaseURL+"/api/v2alpha1/services/{id}/connections", wrapper.ConnectionsByService).Methods("GET")
 
+	r.HandleFunc(options.BaseURL+"/api/v2alpha1/services/{id}/history", wrapper.ServiceHistoryByID).Methods("GET")
+
 	r.HandleFunc(options.BaseURL+"/api/v2alpha1/services/{id}/processes", wrapper.ProcessesByService).Methods("GET")
 
 	r.HandleFunc(options.BaseURL+"/api/v2alpha1/services/{id}/processpairs", wrapper.ProcessPairsByService).Methods("GET")
@@ -5648,6 +6533,8 @@ func HandlerWithOptions(si ServerInterface, options GorillaServerOptions) http.H
 
 	r.HandleFunc(options.BaseURL+"/api/v2alpha1/sitepairs/{id}", wrapper.SitepairByID).Methods("GET")
 
+	r.HandleFunc(options.BaseURL+"/api/v2alpha1/sitepairs/{id}/history", wrapper.SitepairHistoryByID).Methods("GET")
+
 	r.HandleFunc(options.BaseURL+"/api/v2alpha1/sites", wrapper.Sites).Methods("GET")
 
 	r.HandleFunc(options.BaseURL+"/api/v2alpha1/sites/{id}", wrapper.SiteById).Methods("GET")
@@ -5658,5 +6545,7 @@ func HandlerWithOptions(si ServerInterface, options GorillaServerOptions) http.H
 
 	r.HandleFunc(options.BaseURL+"/api/v2alpha1/sites/{id}/routers", wrapper.RoutersBySite).Methods("GET")
 
+	r.HandleFunc(options.BaseURL+"/api/v2alpha1/watch", wrapper.Watch).Methods("GET")
+
 	return r
 }
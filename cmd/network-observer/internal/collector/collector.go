@@ -20,13 +20,88 @@ import (
 	"github.com/skupperproject/skupper/pkg/vanflow/eventsource"
 	"github.com/skupperproject/skupper/pkg/vanflow/session"
 	"github.com/skupperproject/skupper/pkg/vanflow/store"
+	bolt "go.etcd.io/bbolt"
 	"golang.org/x/sync/errgroup"
 )
 
+const storageFlushInterval = 5 * time.Second
+
 func New(logger *slog.Logger, factory session.ContainerFactory, reg *prometheus.Registry, flowRecordTTL time.Duration, flowLogger func(vanflow.RecordMessage)) *Collector {
-	sessionCtr := factory.Create()
+	collector := newCollector(logger, factory, reg, flowRecordTTL, flowLogger)
+	collector.Records = store.NewSyncMapStore(collector.recordsStoreConfig())
+	collector.init()
+	return collector
+}
 
-	collector := &Collector{
+// StorageConfig configures the persistence of flows and the connection and
+// request records reconciled from them
+type StorageConfig struct {
+	DB *bolt.DB
+	// MaxAge of a flow since its last update before it is purged
+	MaxAge time.Duration
+	// MaxSize in bytes of the persisted flows
+	MaxSize int64
+}
+
+// NewPersistent creates a Collector that keeps flows, connections and
+// requests in a bbolt database, so that they outlive the collector and are
+// retained according to the storage configuration instead of the flow record
+// ttl.
+func NewPersistent(logger *slog.Logger, factory session.ContainerFactory, reg *prometheus.Registry, flowRecordTTL time.Duration, flowLogger func(vanflow.RecordMessage), storage StorageConfig) (*Collector, error) {
+	collector := newCollector(logger, factory, reg, flowRecordTTL, flowLogger)
+	flows, err := store.NewBoltStore(storage.DB, store.BoltStoreConfig{
+		SyncMapStoreConfig: store.SyncMapStoreConfig{
+			Handlers: store.EventHandlerFuncs{
+				OnAdd:    collector.handleFlowAdd,
+				OnChange: collector.handleFlowChange,
+				OnDelete: collector.handleFlowDelete,
+			},
+			Indexers: map[string]store.Indexer{
+				store.SourceIndex: store.SourceIndexer,
+				store.TypeIndex:   store.TypeIndexer,
+			},
+		},
+		Bucket:      "flows",
+		RecordTypes: []vanflow.Record{vanflow.TransportBiflowRecord{}, vanflow.AppBiflowRecord{}},
+		MaxAge:      storage.MaxAge,
+		MaxSize:     storage.MaxSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	records, err := store.NewBoltStore(storage.DB, store.BoltStoreConfig{
+		SyncMapStoreConfig: collector.recordsStoreConfig(),
+		Bucket:             "records",
+		RecordTypes:        []vanflow.Record{ConnectionRecord{}, RequestRecord{}},
+		OnLoad: func(e store.Entry) store.Entry {
+			switch record := e.Record.(type) {
+			case ConnectionRecord:
+				record.FlowStore = flows
+				e.Record = record
+			case RequestRecord:
+				record.stor = flows
+				e.Record = record
+			}
+			return e
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("loaded persisted flows",
+		slog.Int("flows", flows.Loaded()),
+		slog.Int("records", records.Loaded()),
+	)
+	collector.flows = flows
+	collector.Records = records
+	collector.storage = []*store.BoltStore{flows, records}
+	collector.init()
+	return collector, nil
+}
+
+func newCollector(logger *slog.Logger, factory session.ContainerFactory, reg *prometheus.Registry, flowRecordTTL time.Duration, flowLogger func(vanflow.RecordMessage)) *Collector {
+	sessionCtr := factory.Create()
+	return &Collector{
 		logger:         logger,
 		flowRecordTTL:  flowRecordTTL,
 		session:        sessionCtr,
@@ -38,25 +113,31 @@ func New(logger *slog.Logger, factory session.ContainerFactory, reg *prometheus.
 		metrics:        register(reg),
 		metricsAdaptor: opmetrics.New(reg),
 		flowLogging:    flowLogger,
+		history:        newHistoryStore(DefaultHistoryRetention),
+		watch:          newWatchBroadcaster(),
 	}
+}
 
-	collector.Records = store.NewSyncMapStore(store.SyncMapStoreConfig{
+func (c *Collector) recordsStoreConfig() store.SyncMapStoreConfig {
+	return store.SyncMapStoreConfig{
 		Handlers: store.EventHandlerFuncs{
-			OnAdd:    collector.handleStoreAdd,
-			OnChange: collector.handleStoreChange,
-			OnDelete: collector.handleStoreDelete,
+			OnAdd:    c.handleStoreAdd,
+			OnChange: c.handleStoreChange,
+			OnDelete: c.handleStoreDelete,
 		},
 		Indexers: RecordIndexers(),
-	})
-	collector.graph = NewGraph(collector.Records).(*graph)
-	collector.processManager = newProcessManager(logger, collector.Records, collector.graph, newStableIdentityProvider(), collector.metrics)
-	collector.addressManager = newAddressManager(collector.logger, collector.Records)
-	collector.pairManager = newPairManager(logger, collector.Records, collector.graph, collector.metrics)
-	routerCfg := collector.recordRouting
+	}
+}
+
+func (c *Collector) init() {
+	c.graph = NewGraph(c.Records).(*graph)
+	c.processManager = newProcessManager(c.logger, c.Records, c.graph, newStableIdentityProvider(), c.metrics)
+	c.addressManager = newAddressManager(c.logger, c.Records)
+	c.pairManager = newPairManager(c.logger, c.Records, c.graph, c.metrics)
+	routerCfg := c.recordRouting
 	for _, typ := range standardRecordTypes {
-		routerCfg[typ.String()] = collector.Records
+		routerCfg[typ.String()] = c.Records
 	}
-	return collector
 }
 
 type Collector struct {
@@ -82,6 +163,16 @@ type Collector struct {
 	events     chan changeEvent
 	purgeQueue chan store.SourceRef
 
+	// flows is the store shared by the connection managers when
+	// persistent, with its events dispatched to the managers by source
+	flows        store.Interface
+	flowManagers sync.Map
+	storage      []*store.BoltStore
+
+	exporter FlowExporter
+	history  *historyStore
+	watch    *watchBroadcaster
+
 	metrics metrics
 }
 
@@ -95,7 +186,6 @@ func (c *Collector) GetGraph() Graph {
 }
 
 func (c *Collector) Run(ctx context.Context) error {
-	c.session.Start(ctx)
 	g, ctx := errgroup.WithContext(ctx)
 	g.Go(c.runSession(ctx))
 	g.Go(c.runWorkQueue(ctx))
@@ -105,9 +195,55 @@ func (c *Collector) Run(ctx context.Context) error {
 	g.Go(c.processManager.run(ctx))
 	g.Go(c.addressManager.run(ctx))
 	g.Go(c.pairManager.run(ctx))
+	g.Go(c.history.run(ctx))
+	for _, stor := range c.storage {
+		g.Go(c.runStorage(ctx, stor))
+	}
 	return g.Wait()
 }
 
+func (c *Collector) runStorage(ctx context.Context, stor *store.BoltStore) func() error {
+	return func() error {
+		defer func() {
+			c.logger.Info("storage worker shutdown complete")
+		}()
+		stor.Run(ctx, storageFlushInterval, func(err error) {
+			c.logger.Error("error persisting records", slog.Any("error", err))
+		})
+		return nil
+	}
+}
+
+func (c *Collector) flowManager(e store.Entry) (*connectionManager, bool) {
+	manager, ok := c.flowManagers.Load(e.Source.ID)
+	if !ok {
+		return nil, false
+	}
+	return manager.(*connectionManager), true
+}
+
+func (c *Collector) handleFlowAdd(e store.Entry) {
+	if manager, ok := c.flowManager(e); ok {
+		manager.handleAdd(e)
+	}
+}
+
+func (c *Collector) handleFlowChange(p, e store.Entry) {
+	if manager, ok := c.flowManager(e); ok {
+		manager.handleChange(p, e)
+	}
+}
+
+func (c *Collector) handleFlowDelete(e store.Entry) {
+	if manager, ok := c.flowManager(e); ok {
+		manager.handleDelete(e)
+		return
+	}
+	// flows from sources no longer present, including the ones loaded on
+	// startup, are only referenced by their reconciled record
+	c.Records.Delete(e.Record.Identity())
+}
+
 func (c *Collector) updateGraph(event changeEvent, stor readonly) {
 	if dEvent, ok := event.(deleteEvent); ok {
 		c.graph.Unindex(dEvent.Record)
@@ -178,6 +314,7 @@ func (c *Collector) runWorkQueue(ctx context.Context) func() error {
 		c.processManager.handleChangeEvent,
 	)
 	reactors[ProcPairRecord{}.GetTypeMeta()] = append(reactors[ProcPairRecord{}.GetTypeMeta()], c.pairManager.handleChangeEvent)
+	reactors[vanflow.LinkRecord{}.GetTypeMeta()] = append(reactors[vanflow.LinkRecord{}.GetTypeMeta()], c.history.handleChangeEvent)
 
 	return func() error {
 		defer func() {
@@ -194,6 +331,7 @@ func (c *Collector) runWorkQueue(ctx context.Context) func() error {
 				for _, reactor := range reactors[typ] {
 					reactor(event, c.Records)
 				}
+				c.watch.handleChangeEvent(event, c.Records)
 				c.metrics.internal.flowProcessingTime.WithLabelValues(typ.String()).Observe(time.Since(start).Seconds())
 			}
 		}
@@ -319,10 +457,19 @@ func (c *Collector) handleStoreDelete(e store.Entry) {
 
 func (c *Collector) purge(source store.SourceRef) int {
 	matching := c.Records.Index(store.SourceIndex, store.Entry{Metadata: store.Metadata{Source: source}})
+	var ct int
 	for _, record := range matching {
+		if c.flows != nil {
+			// retained until the flows they were reconciled from are purged
+			switch record.Record.(type) {
+			case ConnectionRecord, RequestRecord:
+				continue
+			}
+		}
 		c.Records.Delete(record.Record.Identity())
+		ct++
 	}
-	return len(matching)
+	return ct
 }
 
 func (c *Collector) discoveryHandler(ctx context.Context) func(eventsource.Info) {
@@ -369,10 +516,16 @@ func (c *Collector) discoveryHandler(ctx context.Context) func(eventsource.Info)
 				c.logger.With(slog.String("eventsource", fmt.Sprintf("%d/%s", source.Version, source.ID))),
 				sourceRef(source),
 				c.Records,
+				c.flows,
 				c.graph,
 				c.metrics,
 				c.flowRecordTTL,
+				c.exporter,
+				c.history,
 			)
+			if c.flows != nil {
+				c.flowManagers.Store(source.ID, sourceCtr.manager)
+			}
 
 			// route flow records to source-specific stores
 			router.Stores = maps.Clone(router.Stores)
@@ -419,6 +572,7 @@ func (c *Collector) handleForgotten(source eventsource.Info) {
 		s.client.Close()
 		if s.manager != nil {
 			s.manager.Stop()
+			c.flowManagers.Delete(source.ID)
 		}
 		delete(c.sources, source.ID)
 	}
@@ -0,0 +1,132 @@
+package collector
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/skupperproject/skupper/internal/qdr"
+	"github.com/skupperproject/skupper/internal/qdr/fake"
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	"github.com/skupperproject/skupper/pkg/vanflow/session"
+	"github.com/skupperproject/skupper/pkg/vanflow/store"
+	bolt "go.etcd.io/bbolt"
+	"gotest.tools/v3/assert"
+)
+
+func TestCollectorPersistent(t *testing.T) {
+	tlog := slog.Default()
+	path := filepath.Join(t.TempDir(), "flows.db")
+	source := store.SourceRef{ID: "router-1"}
+
+	newTestCollector := func(db *bolt.DB) *Collector {
+		c, err := NewPersistent(tlog, session.NewMockContainerFactory(), prometheus.NewRegistry(), time.Minute, nil, StorageConfig{DB: db})
+		assert.Assert(t, err)
+		return c
+	}
+
+	db, err := bolt.Open(path, 0600, nil)
+	assert.Assert(t, err)
+	c := newTestCollector(db)
+	c.flows.Add(vanflow.TransportBiflowRecord{
+		BaseRecord: vanflow.NewBase("tflow-01", time.Now()),
+		Parent:     ptrTo("listener-backend"),
+		Octets:     ptrTo(uint64(1024)),
+	}, source)
+	c.Records.Add(ConnectionRecord{ID: "tflow-01", RoutingKey: "backend", FlowStore: c.flows}, source)
+	c.Records.Add(vanflow.SiteRecord{BaseRecord: vanflow.NewBase("site-1")}, source)
+	for _, stor := range c.storage {
+		assert.Assert(t, stor.Flush())
+	}
+	assert.Assert(t, db.Close())
+
+	db, err = bolt.Open(path, 0600, nil)
+	assert.Assert(t, err)
+	defer db.Close()
+	c = newTestCollector(db)
+
+	_, ok := c.Records.Get("site-1")
+	assert.Assert(t, !ok, "expected only connections and requests to be persisted")
+	entry, ok := c.Records.Get("tflow-01")
+	assert.Assert(t, ok)
+	connection, ok := entry.Record.(ConnectionRecord)
+	assert.Assert(t, ok)
+	assert.Equal(t, connection.RoutingKey, "backend")
+	flow, ok := connection.GetFlow()
+	assert.Assert(t, ok)
+	assert.Equal(t, dref(flow.Octets), uint64(1024))
+
+	// connections from a forgotten source are kept until their flow is purged
+	assert.Equal(t, c.purge(source), 0)
+	_, ok = c.Records.Get("tflow-01")
+	assert.Assert(t, ok)
+	c.flows.Delete("tflow-01")
+	_, ok = c.Records.Get("tflow-01")
+	assert.Assert(t, !ok)
+}
+
+func TestCollectorWithRouter(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	router, err := fake.NewRouter(fake.Config{ID: "router-1", SiteID: "site-1"})
+	assert.Assert(t, err)
+	defer router.Close()
+
+	c := New(slog.Default(), session.NewContainerFactory(router.URL(), session.ContainerConfig{ContainerID: "test-collector"}), prometheus.NewRegistry(), time.Minute, nil)
+	go c.Run(ctx)
+	router.Emit(vanflow.SiteRecord{BaseRecord: vanflow.NewBase("site-1", time.Now()), Name: ptrTo("west")})
+
+	waitFor := func(description string, condition func() bool) {
+		t.Helper()
+		for !condition() {
+			select {
+			case <-ctx.Done():
+				t.Fatalf("timed out waiting for %s", description)
+			case <-time.After(20 * time.Millisecond):
+			}
+		}
+	}
+	waitFor("router and site records", func() bool {
+		_, router := c.Records.Get("router-1")
+		_, site := c.Records.Get("site-1")
+		return router && site
+	})
+
+	agent, err := qdr.Connect(router.URL(), nil)
+	assert.Assert(t, err)
+	defer agent.Close()
+	assert.Assert(t, agent.Create("io.skupper.router.tcpListener", "backend",
+		qdr.TcpEndpoint{Name: "backend", Host: "0.0.0.0", Port: "8080", Address: "backend"}))
+	var listener vanflow.ListenerRecord
+	waitFor("listener record", func() bool {
+		entry, ok := c.Records.Get("router-1:tcpListener:backend")
+		if ok {
+			listener, ok = entry.Record.(vanflow.ListenerRecord)
+		}
+		return ok
+	})
+	assert.Equal(t, dref(listener.Name), "backend")
+	assert.Equal(t, dref(listener.Address), "backend")
+	assert.Equal(t, dref(listener.Parent), "router-1")
+	waitFor("address record", func() bool {
+		for _, entry := range c.Records.Index(store.TypeIndex, store.Entry{Record: AddressRecord{}}) {
+			if address, ok := entry.Record.(AddressRecord); ok && address.Name == "backend" {
+				return true
+			}
+		}
+		return false
+	})
+
+	assert.Assert(t, agent.Delete("io.skupper.router.tcpListener", "backend"))
+	waitFor("listener removal", func() bool {
+		entry, ok := c.Records.Get("router-1:tcpListener:backend")
+		if !ok {
+			return true
+		}
+		listener := entry.Record.(vanflow.ListenerRecord)
+		return listener.EndTime != nil
+	})
+}
@@ -24,6 +24,8 @@ type connectionManager struct {
 	records               store.Interface
 	source                store.SourceRef
 	graph                 *graph
+	exporter              FlowExporter
+	history               *historyStore
 	idp                   idProvider
 	metrics               metrics
 	mcMu                  sync.Mutex
@@ -35,6 +37,10 @@ type connectionManager struct {
 	transportProcessingTime prometheus.Observer
 	appProcessingTime       prometheus.Observer
 
+	// retain flows when they are kept by a persistent store, evicting only
+	// their state when the ttl expires
+	retain bool
+
 	transportFlows *keyedLRUCache[transportState, *transportState]
 	appFlows       *keyedLRUCache[appState, *appState]
 
@@ -47,11 +53,19 @@ type connectionManager struct {
 	routerCache     map[string]routerAttrs
 }
 
-func newConnectionmanager(ctx context.Context, log *slog.Logger, source store.SourceRef, records store.Interface, graph *graph, metrics metrics, ttl time.Duration) *connectionManager {
+// newConnectionmanager creates a connectionManager for a source. When flows
+// is nil, the manager keeps flow records in its own in-memory store.
+// Otherwise the flows store is shared with other sources and its events must
+// be dispatched to the manager handlers.
+func newConnectionmanager(ctx context.Context, log *slog.Logger, source store.SourceRef, records store.Interface, flows store.Interface, graph *graph, metrics metrics, ttl time.Duration, exporter FlowExporter, history *historyStore) *connectionManager {
 	m := &connectionManager{
 		logger:                  log,
 		records:                 records,
+		flows:                   flows,
+		retain:                  flows != nil,
 		graph:                   graph,
+		exporter:                exporter,
+		history:                 history,
 		source:                  source,
 		idp:                     newStableIdentityProvider(),
 		metrics:                 metrics,
@@ -72,16 +86,18 @@ func newConnectionmanager(ctx context.Context, log *slog.Logger, source store.So
 		routerCache:     make(map[string]routerAttrs),
 	}
 
-	m.flows = store.NewSyncMapStore(store.SyncMapStoreConfig{
-		Handlers: store.EventHandlerFuncs{
-			OnAdd:    m.handleAdd,
-			OnChange: m.handleChange,
-			OnDelete: m.handleDelete,
-		},
-		Indexers: map[string]store.Indexer{
-			store.TypeIndex: store.TypeIndexer,
-		},
-	})
+	if m.flows == nil {
+		m.flows = store.NewSyncMapStore(store.SyncMapStoreConfig{
+			Handlers: store.EventHandlerFuncs{
+				OnAdd:    m.handleAdd,
+				OnChange: m.handleChange,
+				OnDelete: m.handleDelete,
+			},
+			Indexers: map[string]store.Indexer{
+				store.TypeIndex: store.TypeIndexer,
+			},
+		})
+	}
 
 	go m.run(ctx)
 	return m
@@ -104,12 +120,23 @@ func (c *connectionManager) handleTransportFlow(record vanflow.TransportBiflowRe
 		metrics.opened.Inc()
 		metrics.closed.Add(0)
 		state.Opened = true
+		c.history.record(state.history, func(sample *HistorySample) {
+			sample.ConnectionsOpened++
+		})
 	}
 	if !state.Terminated && record.EndTime != nil {
 		terminated := record.EndTime.Compare(dref(record.StartTime).Time) >= 0
 		if terminated {
 			state.Terminated = true
 			metrics.closed.Inc()
+			failed := dref(record.ErrorListener) != "" || dref(record.ErrorConnector) != ""
+			c.history.record(state.history, func(sample *HistorySample) {
+				sample.ConnectionsClosed++
+				if failed {
+					sample.ConnectionErrors++
+				}
+			})
+			c.exportConnection(record)
 		}
 	}
 	if !state.LatencySet && record.Latency != nil && record.LatencyReverse != nil {
@@ -125,6 +152,10 @@ func (c *connectionManager) handleTransportFlow(record vanflow.TransportBiflowRe
 	if receivedInc != 0 {
 		metrics.sent.Add(sentInc)
 		metrics.received.Add(receivedInc)
+		c.history.record(state.history, func(sample *HistorySample) {
+			sample.BytesSent += bs - state.BytesSent
+			sample.BytesReceived += br - state.BytesReceived
+		})
 		state.BytesSent = bs
 		state.BytesReceived = br
 	}
@@ -153,6 +184,14 @@ func (c *connectionManager) handleAppFlow(record vanflow.AppBiflowRecord) {
 				"method": normalizeHTTPMethod(record.Method),
 				"code":   normalizeHTTPResponseClass(record.Result),
 			}).Inc()
+			failed := isErrorResult(record.Result)
+			c.history.record(state.history, func(sample *HistorySample) {
+				sample.Requests++
+				if failed {
+					sample.RequestErrors++
+				}
+			})
+			c.exportRequest(record)
 		}
 	}
 	c.appFlows.Push(record.ID, state)
@@ -525,15 +564,13 @@ func (c *connectionManager) run(ctx context.Context) {
 					if ct := len(terminated); ct > 0 {
 						c.logger.Debug("purging terminated transport flows", slog.Int("count", ct))
 						for id := range terminated {
-							c.flows.Delete(id)
-							c.records.Delete(id)
+							c.evict(id)
 						}
 					}
 					if ct := len(stale); ct > 0 {
 						c.logger.Info("purging stale transport flows", slog.Int("count", ct))
 						for id := range stale {
-							c.flows.Delete(id)
-							c.records.Delete(id)
+							c.evict(id)
 						}
 					}
 				}
@@ -555,13 +592,13 @@ func (c *connectionManager) run(ctx context.Context) {
 					if ct := len(terminated); ct > 0 {
 						c.logger.Debug("purging terminated app flows", slog.Int("count", ct))
 						for id := range terminated {
-							c.flows.Delete(id)
+							c.evict(id)
 						}
 					}
 					if ct := len(stale); ct > 0 {
 						c.logger.Info("purging stale app flows", slog.Int("count", ct))
 						for id := range stale {
-							c.flows.Delete(id)
+							c.evict(id)
 						}
 					}
 				}
@@ -635,6 +672,9 @@ func (c *connectionManager) runAppReconcile() appReconcileResult {
 			push = true
 			metrics := request.metrics
 			state.metrics = &metrics
+			if transState, ok := c.transportFlows.Get(state.TransportID); ok {
+				state.history = transState.history
+			}
 			result.Reconciled = append(result.Reconciled, request)
 
 			c.pairMu.Lock()
@@ -752,6 +792,7 @@ func (c *connectionManager) runReconcile() reconcileResult {
 			c.records.Add(connection, c.source)
 			metrics := connection.metrics
 			state.metrics = &metrics
+			state.history = c.history.connectionSeries(connection)
 			result.Reconciled = append(result.Reconciled, connection)
 
 			c.pairMu.Lock()
@@ -826,7 +867,22 @@ func (c *connectionManager) scheduleReconcile(ctx context.Context) {
 	}
 }
 
+// evict stops tracking a flow once its ttl has expired. Unless flows are
+// retained, the flow and the record reconciled from it are deleted.
+func (c *connectionManager) evict(id string) {
+	if c.retain {
+		c.transportFlows.Pop(id)
+		c.appFlows.Pop(id)
+		return
+	}
+	c.flows.Delete(id)
+	c.records.Delete(id)
+}
+
 func (c *connectionManager) Stop() {
+	if c.retain {
+		return
+	}
 	for _, e := range c.flows.List() {
 		c.flows.Delete(e.Record.Identity())
 	}
@@ -852,6 +908,7 @@ type appState struct {
 	Terminated  bool
 
 	metrics *appMetrics
+	history historySeries
 
 	FirstSeen time.Time
 	LastSeen  time.Time
@@ -872,6 +929,7 @@ type transportState struct {
 	LatencySet    bool
 
 	metrics *transportMetrics
+	history historySeries
 
 	FirstSeen time.Time
 	LastSeen  time.Time
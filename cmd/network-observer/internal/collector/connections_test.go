@@ -22,7 +22,7 @@ func TestConnectionManager(t *testing.T) {
 	// TODO(ck)  newConnectionmanager starts goroutines that can "steal" work
 	// from manually invoked manager methods (i.e. runReconcile). Write
 	// idempotent assertions.
-	manager := newConnectionmanager(tCtx, tlog, store.SourceRef{}, vanStor, graf, register(prometheus.NewRegistry()), time.Minute)
+	manager := newConnectionmanager(tCtx, tlog, store.SourceRef{}, vanStor, nil, graf, register(prometheus.NewRegistry()), time.Minute, nil, nil)
 	defer manager.Stop()
 	flowStor := manager.flows
 
@@ -93,7 +93,7 @@ func benchmarkRunReconcile(b *testing.B, connections int) {
 	tlog := slog.Default()
 	vanStor := store.NewSyncMapStore(store.SyncMapStoreConfig{Indexers: RecordIndexers()})
 	graf := NewGraph(vanStor).(*graph)
-	manager := newConnectionmanager(tCtx, tlog, store.SourceRef{}, vanStor, graf, register(prometheus.NewRegistry()), time.Minute)
+	manager := newConnectionmanager(tCtx, tlog, store.SourceRef{}, vanStor, nil, graf, register(prometheus.NewRegistry()), time.Minute, nil, nil)
 	defer manager.Stop()
 	flowStor := manager.flows
 
@@ -132,7 +132,7 @@ func benchmarkRunAppReconcile(b *testing.B, connections int, requests int) {
 	tlog := slog.Default()
 	vanStor := store.NewSyncMapStore(store.SyncMapStoreConfig{Indexers: RecordIndexers()})
 	graf := NewGraph(vanStor).(*graph)
-	manager := newConnectionmanager(tCtx, tlog, store.SourceRef{}, vanStor, graf, register(prometheus.NewRegistry()), time.Minute)
+	manager := newConnectionmanager(tCtx, tlog, store.SourceRef{}, vanStor, nil, graf, register(prometheus.NewRegistry()), time.Minute, nil, nil)
 	defer manager.Stop()
 	flowStor := manager.flows
 
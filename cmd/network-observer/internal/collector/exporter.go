@@ -0,0 +1,42 @@
+package collector
+
+import "github.com/skupperproject/skupper/pkg/vanflow"
+
+// FlowExporter receives connections and requests once the flows they were
+// reconciled from have terminated. Implementations must not block.
+type FlowExporter interface {
+	ExportConnection(connection ConnectionRecord, flow vanflow.TransportBiflowRecord)
+	ExportRequest(request RequestRecord, flow vanflow.AppBiflowRecord)
+}
+
+// ExportFlows configures the collector to export terminated flows. It must
+// be called before Run.
+func (c *Collector) ExportFlows(exporter FlowExporter) {
+	c.exporter = exporter
+}
+
+func (c *connectionManager) exportConnection(flow vanflow.TransportBiflowRecord) {
+	if c.exporter == nil {
+		return
+	}
+	entry, ok := c.records.Get(flow.ID)
+	if !ok {
+		return
+	}
+	if connection, ok := entry.Record.(ConnectionRecord); ok {
+		c.exporter.ExportConnection(connection, flow)
+	}
+}
+
+func (c *connectionManager) exportRequest(flow vanflow.AppBiflowRecord) {
+	if c.exporter == nil {
+		return
+	}
+	entry, ok := c.records.Get(flow.ID)
+	if !ok {
+		return
+	}
+	if request, ok := entry.Record.(RequestRecord); ok {
+		c.exporter.ExportRequest(request, flow)
+	}
+}
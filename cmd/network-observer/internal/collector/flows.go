@@ -33,7 +33,7 @@ type ConnectionRecord struct {
 	// FlowStore is the backing store containing the Biflow records. This was
 	// split from the main record store to keep high volume flow producers from
 	// affecting the rest of the event sources.
-	FlowStore store.Interface
+	FlowStore store.Interface `json:"-"`
 	metrics   transportMetrics
 }
 
@@ -0,0 +1,229 @@
+package collector
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/skupperproject/skupper/pkg/vanflow"
+)
+
+const (
+	// HistoryResolution is the interval samples are rolled up into
+	HistoryResolution = time.Minute
+	// DefaultHistoryRetention is how long samples are kept by default
+	DefaultHistoryRetention = 6 * time.Hour
+)
+
+// HistoryKind is the type of resource a time series belongs to
+type HistoryKind string
+
+const (
+	HistorySitePair    HistoryKind = "sitepair"
+	HistoryProcessPair HistoryKind = "processpair"
+	HistoryService     HistoryKind = "service"
+	HistoryRouterLink  HistoryKind = "routerlink"
+)
+
+// HistorySample is the activity observed for a resource over one
+// HistoryResolution interval starting at Start
+type HistorySample struct {
+	Start time.Time
+
+	ConnectionsOpened uint64
+	ConnectionsClosed uint64
+	ConnectionErrors  uint64
+	Requests          uint64
+	RequestErrors     uint64
+	BytesSent         uint64
+	BytesReceived     uint64
+	LinkDowns         uint64
+}
+
+// History gives access to the per minute time series kept by the collector
+type History interface {
+	// Samples returns the samples of a resource within the time range,
+	// oldest first
+	Samples(kind HistoryKind, id string, start, end time.Time) []HistorySample
+}
+
+type historyKey struct {
+	Kind HistoryKind
+	ID   string
+}
+
+// historySeries are the time series a flow contributes to
+type historySeries []historyKey
+
+// historyStore is an in-memory time series store bounded by its retention.
+// Samples older than the retention are discarded, along with the series that
+// have not seen any activity since.
+type historyStore struct {
+	mu        sync.Mutex
+	retention time.Duration
+	series    map[historyKey][]HistorySample
+	idp       idProvider
+	now       func() time.Time
+}
+
+func newHistoryStore(retention time.Duration) *historyStore {
+	return &historyStore{
+		retention: retention,
+		series:    make(map[historyKey][]HistorySample),
+		idp:       newStableIdentityProvider(),
+		now:       time.Now,
+	}
+}
+
+// History returns the time series kept by the collector
+func (c *Collector) History() History {
+	return c.history
+}
+
+// SetHistoryRetention configures how long samples are kept for. It must be
+// called before Run.
+func (c *Collector) SetHistoryRetention(retention time.Duration) {
+	c.history.retention = retention
+}
+
+// connectionSeries returns the series a connection contributes to. The
+// identifiers match the ones of the site pair, process pair and address
+// records.
+func (h *historyStore) connectionSeries(connection ConnectionRecord) historySeries {
+	if h == nil {
+		return nil
+	}
+	series := historySeries{
+		{Kind: HistoryService, ID: h.idp.ID("adr", connection.RoutingKey, connection.Protocol)},
+	}
+	if connection.Source.ID != "" && connection.Dest.ID != "" {
+		series = append(series, historyKey{
+			Kind: HistoryProcessPair,
+			ID:   h.idp.ID("processpair", connection.Source.ID, connection.Dest.ID, connection.Protocol),
+		})
+	}
+	if connection.SourceSite.ID != "" && connection.DestSite.ID != "" {
+		series = append(series, historyKey{
+			Kind: HistorySitePair,
+			ID:   h.idp.ID("sitepair", connection.SourceSite.ID, connection.DestSite.ID, connection.Protocol),
+		})
+	}
+	return series
+}
+
+// record applies update to the current sample of each series
+func (h *historyStore) record(series historySeries, update func(*HistorySample)) {
+	if h == nil || len(series) == 0 || h.retention <= 0 {
+		return
+	}
+	start := h.now().Truncate(HistoryResolution)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, key := range series {
+		samples := h.series[key]
+		if n := len(samples); n == 0 || samples[n-1].Start.Before(start) {
+			samples = append(samples, HistorySample{Start: start})
+		}
+		update(&samples[len(samples)-1])
+		h.series[key] = samples
+	}
+}
+
+func (h *historyStore) Samples(kind HistoryKind, id string, start, end time.Time) []HistorySample {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	samples := h.series[historyKey{Kind: kind, ID: id}]
+	first := sort.Search(len(samples), func(i int) bool {
+		return !samples[i].Start.Add(HistoryResolution).Before(start)
+	})
+	var out []HistorySample
+	for _, sample := range samples[first:] {
+		if sample.Start.After(end) {
+			break
+		}
+		out = append(out, sample)
+	}
+	return out
+}
+
+// prune discards samples older than the retention and the series left empty
+func (h *historyStore) prune() {
+	cutoff := h.now().Add(-h.retention)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for key, samples := range h.series {
+		expired := sort.Search(len(samples), func(i int) bool {
+			return samples[i].Start.Add(HistoryResolution).After(cutoff)
+		})
+		if expired == len(samples) {
+			delete(h.series, key)
+			continue
+		}
+		if expired > 0 {
+			h.series[key] = append([]HistorySample(nil), samples[expired:]...)
+		}
+	}
+}
+
+func (h *historyStore) run(ctx context.Context) func() error {
+	return func() error {
+		ticker := time.NewTicker(HistoryResolution)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+				h.prune()
+			}
+		}
+	}
+}
+
+// handleChangeEvent records the traffic and outages of router links from the
+// changes to their counters
+func (h *historyStore) handleChangeEvent(event changeEvent, stor readonly) {
+	update, ok := event.(updateEvent)
+	if !ok {
+		return
+	}
+	prev, ok := update.Prev.(vanflow.LinkRecord)
+	if !ok {
+		return
+	}
+	curr, ok := update.Curr.(vanflow.LinkRecord)
+	if !ok {
+		return
+	}
+	sent := counterDelta(prev.Octets, curr.Octets)
+	received := counterDelta(prev.OctetsReverse, curr.OctetsReverse)
+	downs := counterDelta(prev.DownCount, curr.DownCount)
+	if sent == 0 && received == 0 && downs == 0 {
+		return
+	}
+	h.record(historySeries{{Kind: HistoryRouterLink, ID: curr.ID}}, func(sample *HistorySample) {
+		sample.BytesSent += sent
+		sample.BytesReceived += received
+		sample.LinkDowns += downs
+	})
+}
+
+// counterDelta is the increase of a counter, treating a decrease as a reset
+func counterDelta(prev, curr *uint64) uint64 {
+	if prev == nil || curr == nil {
+		return 0
+	}
+	p, c := *prev, *curr
+	if c < p {
+		return c
+	}
+	return c - p
+}
+
+// isErrorResult returns true for http responses with a server error status
+func isErrorResult(result *string) bool {
+	code, err := strconv.Atoi(dref(result))
+	return err == nil && code >= 500
+}
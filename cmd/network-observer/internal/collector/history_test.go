@@ -0,0 +1,98 @@
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	"gotest.tools/v3/assert"
+)
+
+func TestHistoryStore(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 30, 0, time.UTC)
+	h := newHistoryStore(time.Hour)
+	h.now = func() time.Time { return now }
+
+	connection := ConnectionRecord{
+		RoutingKey: "backend",
+		Protocol:   "tcp",
+		Source:     NamedReference{ID: "process-1"},
+		Dest:       NamedReference{ID: "process-2"},
+		SourceSite: NamedReference{ID: "site-1"},
+		DestSite:   NamedReference{ID: "site-2"},
+	}
+	series := h.connectionSeries(connection)
+	idp := newStableIdentityProvider()
+	assert.DeepEqual(t, series, historySeries{
+		{Kind: HistoryService, ID: idp.ID("adr", "backend", "tcp")},
+		{Kind: HistoryProcessPair, ID: idp.ID("processpair", "process-1", "process-2", "tcp")},
+		{Kind: HistorySitePair, ID: idp.ID("sitepair", "site-1", "site-2", "tcp")},
+	})
+
+	opened := func(sample *HistorySample) { sample.ConnectionsOpened++ }
+	h.record(series, opened)
+	h.record(series, opened)
+	now = now.Add(time.Minute)
+	h.record(series, func(sample *HistorySample) {
+		sample.ConnectionsClosed++
+		sample.BytesSent += 1024
+	})
+
+	serviceID := series[0].ID
+	samples := h.Samples(HistoryService, serviceID, time.Time{}, now)
+	assert.DeepEqual(t, samples, []HistorySample{
+		{Start: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), ConnectionsOpened: 2},
+		{Start: time.Date(2024, 1, 1, 12, 1, 0, 0, time.UTC), ConnectionsClosed: 1, BytesSent: 1024},
+	})
+	// samples overlapping the range are included
+	samples = h.Samples(HistorySitePair, series[2].ID, now.Add(-30*time.Second), now)
+	assert.Equal(t, len(samples), 2)
+	samples = h.Samples(HistorySitePair, series[2].ID, now.Add(-30*time.Second), now.Add(-time.Minute))
+	assert.Equal(t, len(samples), 1)
+	assert.Equal(t, len(h.Samples(HistorySitePair, "unknown", time.Time{}, now)), 0)
+
+	now = now.Add(time.Hour)
+	h.prune()
+	assert.DeepEqual(t, h.Samples(HistoryService, serviceID, time.Time{}, now), []HistorySample{
+		{Start: time.Date(2024, 1, 1, 12, 1, 0, 0, time.UTC), ConnectionsClosed: 1, BytesSent: 1024},
+	})
+	now = now.Add(time.Minute)
+	h.prune()
+	assert.Equal(t, len(h.series), 0)
+}
+
+func TestHistoryStoreRouterLinks(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	h := newHistoryStore(time.Hour)
+	h.now = func() time.Time { return now }
+
+	link := func(octets, octetsReverse, downs uint64) vanflow.LinkRecord {
+		return vanflow.LinkRecord{
+			BaseRecord:    vanflow.NewBase("link-1"),
+			Octets:        &octets,
+			OctetsReverse: &octetsReverse,
+			DownCount:     &downs,
+		}
+	}
+	h.handleChangeEvent(addEvent{Record: link(100, 100, 0)}, nil)
+	h.handleChangeEvent(updateEvent{Prev: link(100, 100, 0), Curr: link(300, 150, 0)}, nil)
+	h.handleChangeEvent(updateEvent{Prev: link(300, 150, 0), Curr: link(400, 150, 1)}, nil)
+	// counters reset when the router restarts
+	h.handleChangeEvent(updateEvent{Prev: link(400, 150, 1), Curr: link(50, 10, 0)}, nil)
+
+	assert.DeepEqual(t, h.Samples(HistoryRouterLink, "link-1", time.Time{}, now), []HistorySample{
+		{Start: now, BytesSent: 350, BytesReceived: 60, LinkDowns: 1},
+	})
+}
+
+func TestHistoryStoreDisabled(t *testing.T) {
+	h := newHistoryStore(0)
+	h.record(historySeries{{Kind: HistoryService, ID: "adr-1"}}, func(sample *HistorySample) {
+		sample.Requests++
+	})
+	assert.Equal(t, len(h.series), 0)
+
+	var unset *historyStore
+	assert.Assert(t, unset.connectionSeries(ConnectionRecord{}) == nil)
+	unset.record(historySeries{{Kind: HistoryService, ID: "adr-1"}}, func(sample *HistorySample) {})
+}
@@ -0,0 +1,250 @@
+package collector
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/skupperproject/skupper/pkg/vanflow"
+)
+
+const (
+	// watchBacklogSize is the number of events retained to resume watches
+	watchBacklogSize = 4096
+	// watchBufferSize is the number of events buffered for each watch
+	// before it is considered a slow consumer and stopped
+	watchBufferSize = 256
+)
+
+var (
+	// ErrVersionExpired is returned when resuming a watch from a resource
+	// version older than the retained events
+	ErrVersionExpired = errors.New("resource version expired")
+	// ErrSlowConsumer is the reason a watch is stopped when it does not
+	// keep up with events
+	ErrSlowConsumer = errors.New("watch stopped: consumer too slow")
+)
+
+type WatchEventType string
+
+const (
+	WatchAdded    WatchEventType = "ADDED"
+	WatchModified WatchEventType = "MODIFIED"
+	WatchDeleted  WatchEventType = "DELETED"
+)
+
+// watchKinds are the names clients use to select the records to watch
+var watchKinds = map[vanflow.TypeMeta]string{
+	vanflow.SiteRecord{}.GetTypeMeta():         "SITE",
+	vanflow.RouterRecord{}.GetTypeMeta():       "ROUTER",
+	vanflow.LinkRecord{}.GetTypeMeta():         "LINK",
+	vanflow.RouterAccessRecord{}.GetTypeMeta(): "ROUTER_ACCESS",
+	vanflow.ConnectorRecord{}.GetTypeMeta():    "CONNECTOR",
+	vanflow.ListenerRecord{}.GetTypeMeta():     "LISTENER",
+	vanflow.ProcessRecord{}.GetTypeMeta():      "PROCESS",
+	AddressRecord{}.GetTypeMeta():              "SERVICE",
+	ProcessGroupRecord{}.GetTypeMeta():         "COMPONENT",
+	SitePairRecord{}.GetTypeMeta():             "SITEPAIR",
+	ProcPairRecord{}.GetTypeMeta():             "PROCESSPAIR",
+	ProcGroupPairRecord{}.GetTypeMeta():        "COMPONENTPAIR",
+}
+
+// WatchKinds returns the kinds of records that can be watched
+func WatchKinds() []string {
+	kinds := make([]string, 0, len(watchKinds))
+	for _, kind := range watchKinds {
+		kinds = append(kinds, kind)
+	}
+	slices.Sort(kinds)
+	return kinds
+}
+
+// WatchEvent is a change to a record. Events are ordered by their
+// ResourceVersion.
+type WatchEvent struct {
+	ResourceVersion uint64
+	Type            WatchEventType
+	Kind            string
+	Record          vanflow.Record
+}
+
+// WatchOptions select the events delivered to a Watch
+type WatchOptions struct {
+	// Kinds of the records to watch. All kinds when empty.
+	Kinds []string
+	// ResourceVersion to resume from. Events after it are delivered.
+	// Only new events are delivered when unset.
+	ResourceVersion uint64
+}
+
+// Watcher streams changes to the records of the collector
+type Watcher interface {
+	Watch(opts WatchOptions) (*Watch, error)
+}
+
+// Watch is a subscription to record changes
+type Watch struct {
+	// ResourceVersion of the last event before the watch started
+	ResourceVersion uint64
+
+	kinds  map[string]bool
+	events chan WatchEvent
+	err    error
+	b      *watchBroadcaster
+}
+
+// Events delivers the changes. It is closed when the watch stops.
+func (w *Watch) Events() <-chan WatchEvent {
+	return w.events
+}
+
+// Err returns the reason the watch was stopped by the collector, if any
+func (w *Watch) Err() error {
+	w.b.mu.Lock()
+	defer w.b.mu.Unlock()
+	return w.err
+}
+
+// Stop ends the watch
+func (w *Watch) Stop() {
+	w.b.mu.Lock()
+	defer w.b.mu.Unlock()
+	w.b.remove(w, nil)
+}
+
+func (w *Watch) matches(event WatchEvent) bool {
+	return len(w.kinds) == 0 || w.kinds[event.Kind]
+}
+
+// watchBroadcaster fans out record changes to watches, retaining a backlog
+// of recent events to resume from
+type watchBroadcaster struct {
+	mu      sync.Mutex
+	version uint64
+	backlog []WatchEvent
+	next    int
+	watches map[*Watch]struct{}
+}
+
+func newWatchBroadcaster() *watchBroadcaster {
+	return &watchBroadcaster{
+		backlog: make([]WatchEvent, 0, watchBacklogSize),
+		watches: make(map[*Watch]struct{}),
+	}
+}
+
+// Watcher returns the Watcher of the collector records
+func (c *Collector) Watcher() Watcher {
+	return c.watch
+}
+
+func (b *watchBroadcaster) Watch(opts WatchOptions) (*Watch, error) {
+	kinds := make(map[string]bool, len(opts.Kinds))
+	for _, kind := range opts.Kinds {
+		if !slices.Contains(WatchKinds(), kind) {
+			return nil, fmt.Errorf("unknown kind %q", kind)
+		}
+		kinds[kind] = true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	w := &Watch{
+		ResourceVersion: b.version,
+		kinds:           kinds,
+		b:               b,
+	}
+	var pending []WatchEvent
+	if opts.ResourceVersion > b.version {
+		// from before the collector was restarted
+		return nil, ErrVersionExpired
+	}
+	if opts.ResourceVersion != 0 && opts.ResourceVersion < b.version {
+		if opts.ResourceVersion < b.oldest()-1 {
+			return nil, ErrVersionExpired
+		}
+		for _, event := range b.ordered() {
+			if event.ResourceVersion > opts.ResourceVersion && w.matches(event) {
+				pending = append(pending, event)
+			}
+		}
+		w.ResourceVersion = opts.ResourceVersion
+	}
+	w.events = make(chan WatchEvent, len(pending)+watchBufferSize)
+	for _, event := range pending {
+		w.events <- event
+	}
+	b.watches[w] = struct{}{}
+	return w, nil
+}
+
+// oldest returns the version of the oldest retained event
+func (b *watchBroadcaster) oldest() uint64 {
+	if len(b.backlog) == 0 {
+		return 0
+	}
+	if len(b.backlog) < watchBacklogSize {
+		return b.backlog[0].ResourceVersion
+	}
+	return b.backlog[b.next].ResourceVersion
+}
+
+func (b *watchBroadcaster) ordered() []WatchEvent {
+	if len(b.backlog) < watchBacklogSize {
+		return b.backlog
+	}
+	return append(slices.Clone(b.backlog[b.next:]), b.backlog[:b.next]...)
+}
+
+func (b *watchBroadcaster) remove(w *Watch, err error) {
+	if _, ok := b.watches[w]; !ok {
+		return
+	}
+	delete(b.watches, w)
+	w.err = err
+	close(w.events)
+}
+
+// handleChangeEvent publishes changes to watchable records
+func (b *watchBroadcaster) handleChangeEvent(event changeEvent, _ readonly) {
+	kind, ok := watchKinds[event.GetTypeMeta()]
+	if !ok {
+		return
+	}
+	out := WatchEvent{Kind: kind}
+	switch event := event.(type) {
+	case addEvent:
+		out.Type, out.Record = WatchAdded, event.Record
+	case updateEvent:
+		out.Type, out.Record = WatchModified, event.Curr
+	case deleteEvent:
+		out.Type, out.Record = WatchDeleted, event.Record
+	default:
+		return
+	}
+	b.publish(out)
+}
+
+func (b *watchBroadcaster) publish(event WatchEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.version++
+	event.ResourceVersion = b.version
+	if len(b.backlog) < watchBacklogSize {
+		b.backlog = append(b.backlog, event)
+	} else {
+		b.backlog[b.next] = event
+		b.next = (b.next + 1) % watchBacklogSize
+	}
+	for w := range b.watches {
+		if !w.matches(event) {
+			continue
+		}
+		select {
+		case w.events <- event:
+		default:
+			// the consumer can resume from the last event it received
+			b.remove(w, ErrSlowConsumer)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	"gotest.tools/v3/assert"
+)
+
+func TestWatchBroadcaster(t *testing.T) {
+	b := newWatchBroadcaster()
+	site := vanflow.SiteRecord{BaseRecord: vanflow.NewBase("site-1")}
+	link := vanflow.LinkRecord{BaseRecord: vanflow.NewBase("link-1")}
+	b.handleChangeEvent(addEvent{Record: site}, nil)
+
+	all, err := b.Watch(WatchOptions{})
+	assert.Assert(t, err)
+	defer all.Stop()
+	assert.Equal(t, all.ResourceVersion, uint64(1))
+	links, err := b.Watch(WatchOptions{Kinds: []string{"LINK"}})
+	assert.Assert(t, err)
+	defer links.Stop()
+
+	b.handleChangeEvent(addEvent{Record: link}, nil)
+	b.handleChangeEvent(updateEvent{Prev: site, Curr: site}, nil)
+	b.handleChangeEvent(deleteEvent{Record: link}, nil)
+	// records that cannot be watched are ignored
+	b.handleChangeEvent(addEvent{Record: vanflow.TransportBiflowRecord{BaseRecord: vanflow.NewBase("flow-1")}}, nil)
+
+	assert.DeepEqual(t, drain(all), []WatchEvent{
+		{ResourceVersion: 2, Type: WatchAdded, Kind: "LINK", Record: link},
+		{ResourceVersion: 3, Type: WatchModified, Kind: "SITE", Record: site},
+		{ResourceVersion: 4, Type: WatchDeleted, Kind: "LINK", Record: link},
+	})
+	assert.DeepEqual(t, drain(links), []WatchEvent{
+		{ResourceVersion: 2, Type: WatchAdded, Kind: "LINK", Record: link},
+		{ResourceVersion: 4, Type: WatchDeleted, Kind: "LINK", Record: link},
+	})
+
+	resumed, err := b.Watch(WatchOptions{Kinds: []string{"SITE"}, ResourceVersion: 1})
+	assert.Assert(t, err)
+	defer resumed.Stop()
+	assert.Equal(t, resumed.ResourceVersion, uint64(1))
+	assert.DeepEqual(t, drain(resumed), []WatchEvent{
+		{ResourceVersion: 3, Type: WatchModified, Kind: "SITE", Record: site},
+	})
+
+	_, err = b.Watch(WatchOptions{Kinds: []string{"FLOW"}})
+	assert.ErrorContains(t, err, "unknown kind")
+	_, err = b.Watch(WatchOptions{ResourceVersion: 5})
+	assert.ErrorIs(t, err, ErrVersionExpired)
+
+	all.Stop()
+	_, ok := <-all.Events()
+	assert.Assert(t, !ok)
+	assert.Assert(t, all.Err() == nil)
+}
+
+func TestWatchBroadcasterBacklog(t *testing.T) {
+	b := newWatchBroadcaster()
+	site := vanflow.SiteRecord{BaseRecord: vanflow.NewBase("site-1")}
+	for i := 0; i < watchBacklogSize+10; i++ {
+		b.handleChangeEvent(updateEvent{Prev: site, Curr: site}, nil)
+	}
+	_, err := b.Watch(WatchOptions{ResourceVersion: 1})
+	assert.ErrorIs(t, err, ErrVersionExpired)
+
+	w, err := b.Watch(WatchOptions{ResourceVersion: 10})
+	assert.Assert(t, err)
+	events := drain(w)
+	assert.Equal(t, len(events), watchBacklogSize)
+	assert.Equal(t, events[0].ResourceVersion, uint64(11))
+	assert.Equal(t, events[len(events)-1].ResourceVersion, uint64(watchBacklogSize+10))
+}
+
+func TestWatchBroadcasterSlowConsumer(t *testing.T) {
+	b := newWatchBroadcaster()
+	site := vanflow.SiteRecord{BaseRecord: vanflow.NewBase("site-1")}
+	slow, err := b.Watch(WatchOptions{})
+	assert.Assert(t, err)
+	fast, err := b.Watch(WatchOptions{})
+	assert.Assert(t, err)
+	defer fast.Stop()
+	for i := 0; i < watchBufferSize+1; i++ {
+		b.handleChangeEvent(updateEvent{Prev: site, Curr: site}, nil)
+		if i < watchBufferSize {
+			<-fast.Events()
+		}
+	}
+	assert.Equal(t, len(drain(slow)), watchBufferSize)
+	assert.ErrorIs(t, slow.Err(), ErrSlowConsumer)
+	assert.Equal(t, len(drain(fast)), 1)
+	assert.Assert(t, fast.Err() == nil)
+}
+
+// drain returns the events buffered by a watch
+func drain(w *Watch) []WatchEvent {
+	var events []WatchEvent
+	for {
+		select {
+		case event, ok := <-w.Events():
+			if !ok {
+				return events
+			}
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/skupperproject/skupper/internal/utils/tlscfg"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+const tracesPath = "/v1/traces"
+
+type grpcClient struct {
+	conn    *grpc.ClientConn
+	service coltracepb.TraceServiceClient
+	headers metadata.MD
+}
+
+func newGRPCClient(cfg Config) (*grpcClient, error) {
+	creds := insecure.NewCredentials()
+	if !cfg.Insecure {
+		creds = credentials.NewTLS(tlscfg.Modern())
+	}
+	conn, err := grpc.NewClient(cfg.Endpoint, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("invalid otlp grpc endpoint %q: %w", cfg.Endpoint, err)
+	}
+	return &grpcClient{
+		conn:    conn,
+		service: coltracepb.NewTraceServiceClient(conn),
+		headers: metadata.New(cfg.Headers),
+	}, nil
+}
+
+func (c *grpcClient) upload(ctx context.Context, request *coltracepb.ExportTraceServiceRequest) error {
+	if len(c.headers) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, c.headers)
+	}
+	response, err := c.service.Export(ctx, request)
+	if err != nil {
+		return err
+	}
+	return partialSuccessError(response)
+}
+
+func (c *grpcClient) close() error {
+	return c.conn.Close()
+}
+
+type httpClient struct {
+	url     string
+	client  *http.Client
+	headers map[string]string
+}
+
+func newHTTPClient(cfg Config) (*httpClient, error) {
+	endpoint := cfg.Endpoint
+	if !strings.Contains(endpoint, "://") {
+		if cfg.Insecure {
+			endpoint = "http://" + endpoint
+		} else {
+			endpoint = "https://" + endpoint
+		}
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid otlp http endpoint %q: %w", cfg.Endpoint, err)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = tracesPath
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlscfg.Modern()
+	return &httpClient{
+		url:     u.String(),
+		client:  &http.Client{Transport: transport},
+		headers: cfg.Headers,
+	}, nil
+}
+
+func (c *httpClient) upload(ctx context.Context, request *coltracepb.ExportTraceServiceRequest) error {
+	body, err := proto.Marshal(request)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	for key, value := range c.headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("otlp receiver responded with status %d", resp.StatusCode)
+	}
+	response := &coltracepb.ExportTraceServiceResponse{}
+	if err := proto.Unmarshal(data, response); err != nil {
+		// the response body is optional for successful requests
+		return nil
+	}
+	return partialSuccessError(response)
+}
+
+func (c *httpClient) close() error {
+	c.client.CloseIdleConnections()
+	return nil
+}
+
+func partialSuccessError(response *coltracepb.ExportTraceServiceResponse) error {
+	partial := response.GetPartialSuccess()
+	if partial == nil || partial.GetRejectedSpans() == 0 {
+		return nil
+	}
+	return fmt.Errorf("otlp receiver rejected %d spans: %s", partial.GetRejectedSpans(), partial.GetErrorMessage())
+}
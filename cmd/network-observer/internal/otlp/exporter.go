@@ -0,0 +1,186 @@
+// Package otlp implements an OpenTelemetry Protocol exporter for the network
+// observer. It exports the connections and requests reconciled by the
+// collector as spans to an OTLP receiver over gRPC or HTTP.
+package otlp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/collector"
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+type Protocol string
+
+const (
+	ProtocolGRPC Protocol = "grpc"
+	ProtocolHTTP Protocol = "http"
+
+	defaultBatchSize    = 512
+	defaultBatchTimeout = 5 * time.Second
+	defaultQueueSize    = 4096
+	uploadTimeout       = 10 * time.Second
+)
+
+// Config for an OTLP Exporter
+type Config struct {
+	Protocol Protocol
+	// Endpoint of the OTLP receiver. A host and port for gRPC, or a URL
+	// for HTTP, where the /v1/traces path is used when no path is set.
+	Endpoint string
+	// Insecure disables TLS when connecting to the endpoint
+	Insecure bool
+	// Headers sent with each export request
+	Headers map[string]string
+
+	// BatchSize is the maximum number of spans per export request
+	BatchSize int
+	// BatchTimeout is the maximum delay before pending spans are exported
+	BatchTimeout time.Duration
+	// QueueSize is the number of spans buffered before new spans are
+	// dropped
+	QueueSize int
+}
+
+type client interface {
+	upload(ctx context.Context, request *coltracepb.ExportTraceServiceRequest) error
+	close() error
+}
+
+var _ collector.FlowExporter = (*Exporter)(nil)
+
+// Exporter batches the spans of terminated flows and exports them to an OTLP
+// receiver
+type Exporter struct {
+	logger  *slog.Logger
+	cfg     Config
+	client  client
+	queue   chan *tracepb.Span
+	dropped atomic.Int64
+}
+
+func New(logger *slog.Logger, cfg Config) (*Exporter, error) {
+	if cfg.Endpoint == "" {
+		return nil, fmt.Errorf("otlp endpoint is required")
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultBatchSize
+	}
+	if cfg.BatchTimeout <= 0 {
+		cfg.BatchTimeout = defaultBatchTimeout
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaultQueueSize
+	}
+	var (
+		c   client
+		err error
+	)
+	switch cfg.Protocol {
+	case ProtocolGRPC, "":
+		c, err = newGRPCClient(cfg)
+	case ProtocolHTTP:
+		c, err = newHTTPClient(cfg)
+	default:
+		return nil, fmt.Errorf("unknown otlp protocol %q: expected %s or %s", cfg.Protocol, ProtocolGRPC, ProtocolHTTP)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Exporter{
+		logger: logger,
+		cfg:    cfg,
+		client: c,
+		queue:  make(chan *tracepb.Span, cfg.QueueSize),
+	}, nil
+}
+
+// ParseHeaders parses a comma separated list of key=value pairs
+func ParseHeaders(s string) (map[string]string, error) {
+	headers := map[string]string{}
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid header %q: expected key=value", pair)
+		}
+		headers[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return headers, nil
+}
+
+func (e *Exporter) ExportConnection(connection collector.ConnectionRecord, flow vanflow.TransportBiflowRecord) {
+	e.enqueue(connectionSpan(connection, flow))
+}
+
+func (e *Exporter) ExportRequest(request collector.RequestRecord, flow vanflow.AppBiflowRecord) {
+	e.enqueue(requestSpan(request, flow))
+}
+
+func (e *Exporter) enqueue(span *tracepb.Span) {
+	select {
+	case e.queue <- span:
+	default:
+		e.dropped.Add(1)
+	}
+}
+
+// Run exports batches of spans until the context is cancelled, when the
+// pending spans are exported before closing the connection to the receiver.
+func (e *Exporter) Run(ctx context.Context) error {
+	defer func() {
+		e.logger.Info("otlp exporter shutdown complete")
+	}()
+	ticker := time.NewTicker(e.cfg.BatchTimeout)
+	defer ticker.Stop()
+	var batch []*tracepb.Span
+	flush := func(ctx context.Context) {
+		if dropped := e.dropped.Swap(0); dropped > 0 {
+			e.logger.Warn("otlp export queue full, spans dropped", slog.Int64("count", dropped))
+		}
+		if len(batch) == 0 {
+			return
+		}
+		ctx, cancel := context.WithTimeout(ctx, uploadTimeout)
+		defer cancel()
+		if err := e.client.upload(ctx, exportRequest(batch)); err != nil {
+			e.logger.Error("error exporting spans",
+				slog.Int("count", len(batch)),
+				slog.Any("error", err),
+			)
+		}
+		batch = nil
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			for drained := false; !drained; {
+				select {
+				case span := <-e.queue:
+					batch = append(batch, span)
+				default:
+					drained = true
+				}
+			}
+			flush(context.Background())
+			return e.client.close()
+		case span := <-e.queue:
+			batch = append(batch, span)
+			if len(batch) >= e.cfg.BatchSize {
+				flush(ctx)
+			}
+		case <-ticker.C:
+			flush(ctx)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package otlp
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/collector"
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/poll"
+)
+
+// receiver is a stand-in for an OTLP receiver collecting exported spans
+type receiver struct {
+	coltracepb.UnimplementedTraceServiceServer
+	mu      sync.Mutex
+	spans   []*tracepb.Span
+	headers []string
+}
+
+func (r *receiver) Export(ctx context.Context, request *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	r.collect(request, md.Get("x-token"))
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path != tracesPath || req.Header.Get("Content-Type") != "application/x-protobuf" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	request := &coltracepb.ExportTraceServiceRequest{}
+	if err := proto.Unmarshal(body, request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	r.collect(request, req.Header.Values("X-Token"))
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (r *receiver) collect(request *coltracepb.ExportTraceServiceRequest, headers []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.headers = append(r.headers, headers...)
+	for _, rs := range request.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			r.spans = append(r.spans, ss.Spans...)
+		}
+	}
+}
+
+func (r *receiver) received() ([]*tracepb.Span, []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*tracepb.Span(nil), r.spans...), append([]string(nil), r.headers...)
+}
+
+func TestExporter(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Protocol Protocol
+		Serve    func(t *testing.T, r *receiver) string
+	}{
+		{
+			Name:     "grpc",
+			Protocol: ProtocolGRPC,
+			Serve: func(t *testing.T, r *receiver) string {
+				listener, err := net.Listen("tcp", "127.0.0.1:0")
+				assert.Assert(t, err)
+				server := grpc.NewServer()
+				coltracepb.RegisterTraceServiceServer(server, r)
+				go server.Serve(listener)
+				t.Cleanup(server.Stop)
+				return listener.Addr().String()
+			},
+		}, {
+			Name:     "http",
+			Protocol: ProtocolHTTP,
+			Serve: func(t *testing.T, r *receiver) string {
+				server := httptest.NewServer(r)
+				t.Cleanup(server.Close)
+				return server.URL
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			r := &receiver{}
+			exporter, err := New(slog.Default(), Config{
+				Protocol:     tc.Protocol,
+				Endpoint:     tc.Serve(t, r),
+				Insecure:     true,
+				Headers:      map[string]string{"x-token": "secret"},
+				BatchTimeout: 50 * time.Millisecond,
+			})
+			assert.Assert(t, err)
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error, 1)
+			go func() { done <- exporter.Run(ctx) }()
+
+			start, end := time.Now().Add(-time.Second), time.Now()
+			exporter.ExportConnection(collector.ConnectionRecord{
+				ID:            "tflow-01",
+				RoutingKey:    "backend",
+				Protocol:      "tcp",
+				ConnectorHost: "10.0.0.2",
+				ConnectorPort: "8080",
+				Listener:      collector.NamedReference{ID: "listener-01"},
+				Connector:     collector.NamedReference{ID: "connector-01"},
+				Source:        collector.NamedReference{ID: "process-01", Name: "frontend"},
+				SourceSite:    collector.NamedReference{ID: "site-01", Name: "east"},
+				Dest:          collector.NamedReference{ID: "process-02", Name: "backend"},
+				DestSite:      collector.NamedReference{ID: "site-02", Name: "west"},
+			}, vanflow.TransportBiflowRecord{
+				BaseRecord:    vanflow.NewBase("tflow-01", start, end),
+				SourceHost:    ptrTo("10.0.0.1"),
+				SourcePort:    ptrTo("51234"),
+				Octets:        ptrTo(uint64(512)),
+				OctetsReverse: ptrTo(uint64(2048)),
+			})
+			exporter.ExportRequest(collector.RequestRecord{
+				ID:          "aflow-01",
+				TransportID: "tflow-01",
+				RoutingKey:  "backend",
+				Protocol:    "HTTP/1.1",
+			}, vanflow.AppBiflowRecord{
+				BaseRecord: vanflow.NewBase("aflow-01", start, end),
+				Parent:     ptrTo("tflow-01"),
+				Method:     ptrTo("GET"),
+				Result:     ptrTo("503"),
+			})
+
+			poll.WaitOn(t, func(poll.LogT) poll.Result {
+				if spans, _ := r.received(); len(spans) < 2 {
+					return poll.Continue("received %d spans", len(spans))
+				}
+				return poll.Success()
+			}, poll.WithTimeout(5*time.Second), poll.WithDelay(20*time.Millisecond))
+			cancel()
+			assert.Assert(t, <-done)
+
+			spans, headers := r.received()
+			assert.Equal(t, len(spans), 2)
+			assert.DeepEqual(t, headers, []string{"secret"})
+			connection, request := spans[0], spans[1]
+			assert.Equal(t, connection.Name, "tcp backend")
+			assert.Equal(t, connection.StartTimeUnixNano, uint64(start.UnixNano()))
+			assert.Equal(t, connection.EndTimeUnixNano, uint64(end.UnixNano()))
+			assert.DeepEqual(t, attributeMap(connection), map[string]any{
+				"skupper.flow.type":           "connection",
+				"skupper.flow.id":             "tflow-01",
+				"skupper.routing_key":         "backend",
+				"skupper.protocol":            "tcp",
+				"skupper.listener.id":         "listener-01",
+				"skupper.connector.id":        "connector-01",
+				"skupper.source.site.id":      "site-01",
+				"skupper.source.site.name":    "east",
+				"skupper.source.process.id":   "process-01",
+				"skupper.source.process.name": "frontend",
+				"skupper.dest.site.id":        "site-02",
+				"skupper.dest.site.name":      "west",
+				"skupper.dest.process.id":     "process-02",
+				"skupper.dest.process.name":   "backend",
+				"client.address":              "10.0.0.1",
+				"client.port":                 int64(51234),
+				"server.address":              "10.0.0.2",
+				"server.port":                 int64(8080),
+				"skupper.octets.sent":         int64(512),
+				"skupper.octets.received":     int64(2048),
+			})
+			assert.Equal(t, request.Name, "GET backend")
+			assert.DeepEqual(t, request.TraceId, connection.TraceId)
+			assert.DeepEqual(t, request.ParentSpanId, connection.SpanId)
+			assert.Equal(t, request.Status.GetCode(), tracepb.Status_STATUS_CODE_ERROR)
+			assert.Equal(t, attributeMap(request)["http.response.status_code"], int64(503))
+		})
+	}
+}
+
+func TestParseHeaders(t *testing.T) {
+	headers, err := ParseHeaders("authorization=Bearer abc, x-scope = tenant-1,")
+	assert.Assert(t, err)
+	assert.DeepEqual(t, headers, map[string]string{
+		"authorization": "Bearer abc",
+		"x-scope":       "tenant-1",
+	})
+	_, err = ParseHeaders("invalid")
+	assert.ErrorContains(t, err, "expected key=value")
+}
+
+func attributeMap(span *tracepb.Span) map[string]any {
+	attrs := map[string]any{}
+	for _, kv := range span.Attributes {
+		switch value := kv.Value.Value.(type) {
+		case *commonpb.AnyValue_StringValue:
+			attrs[kv.Key] = value.StringValue
+		case *commonpb.AnyValue_IntValue:
+			attrs[kv.Key] = value.IntValue
+		}
+	}
+	return attrs
+}
+
+func ptrTo[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,202 @@
+package otlp
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/collector"
+	"github.com/skupperproject/skupper/internal/version"
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+const (
+	serviceName = "skupper-network-observer"
+	scopeName   = "github.com/skupperproject/skupper/cmd/network-observer"
+)
+
+func exportRequest(spans []*tracepb.Span) *coltracepb.ExportTraceServiceRequest {
+	return &coltracepb.ExportTraceServiceRequest{
+		ResourceSpans: []*tracepb.ResourceSpans{
+			{
+				Resource: &resourcepb.Resource{
+					Attributes: attributes(
+						stringAttr("service.name", serviceName),
+						stringAttr("service.version", version.Version),
+					),
+				},
+				ScopeSpans: []*tracepb.ScopeSpans{
+					{
+						Scope: &commonpb.InstrumentationScope{
+							Name:    scopeName,
+							Version: version.Version,
+						},
+						Spans: spans,
+					},
+				},
+			},
+		},
+	}
+}
+
+// connectionSpan is the root span of the trace of a transport flow
+func connectionSpan(connection collector.ConnectionRecord, flow vanflow.TransportBiflowRecord) *tracepb.Span {
+	span := &tracepb.Span{
+		TraceId:           traceID(connection.ID),
+		SpanId:            spanID(connection.ID),
+		Name:              fmt.Sprintf("%s %s", connection.Protocol, connection.RoutingKey),
+		Kind:              tracepb.Span_SPAN_KIND_CLIENT,
+		StartTimeUnixNano: unixNano(flow.StartTime),
+		EndTimeUnixNano:   unixNano(flow.EndTime),
+		Attributes: attributes(
+			stringAttr("skupper.flow.type", "connection"),
+			stringAttr("skupper.flow.id", connection.ID),
+			stringAttr("skupper.routing_key", connection.RoutingKey),
+			stringAttr("skupper.protocol", connection.Protocol),
+			stringAttr("skupper.listener.id", connection.Listener.ID),
+			stringAttr("skupper.connector.id", connection.Connector.ID),
+			stringAttr("skupper.source.site.id", connection.SourceSite.ID),
+			stringAttr("skupper.source.site.name", connection.SourceSite.Name),
+			stringAttr("skupper.source.process.id", connection.Source.ID),
+			stringAttr("skupper.source.process.name", connection.Source.Name),
+			stringAttr("skupper.source.component.name", connection.SourceGroup.Name),
+			stringAttr("skupper.dest.site.id", connection.DestSite.ID),
+			stringAttr("skupper.dest.site.name", connection.DestSite.Name),
+			stringAttr("skupper.dest.process.id", connection.Dest.ID),
+			stringAttr("skupper.dest.process.name", connection.Dest.Name),
+			stringAttr("skupper.dest.component.name", connection.DestGroup.Name),
+			stringAttr("client.address", deref(flow.SourceHost)),
+			portAttr("client.port", deref(flow.SourcePort)),
+			stringAttr("server.address", connection.ConnectorHost),
+			portAttr("server.port", connection.ConnectorPort),
+			intAttr("skupper.octets.sent", flow.Octets),
+			intAttr("skupper.octets.received", flow.OctetsReverse),
+			intAttr("skupper.latency_us", flow.Latency),
+			intAttr("skupper.latency_reverse_us", flow.LatencyReverse),
+		),
+	}
+	if errListener, errConnector := deref(flow.ErrorListener), deref(flow.ErrorConnector); errListener != "" || errConnector != "" {
+		message := errListener
+		if message == "" {
+			message = errConnector
+		}
+		span.Status = &tracepb.Status{Code: tracepb.Status_STATUS_CODE_ERROR, Message: message}
+	}
+	return span
+}
+
+// requestSpan is a child of the span of the transport flow the request was
+// made on
+func requestSpan(request collector.RequestRecord, flow vanflow.AppBiflowRecord) *tracepb.Span {
+	method := deref(flow.Method)
+	name := fmt.Sprintf("%s %s", request.Protocol, request.RoutingKey)
+	if method != "" {
+		name = fmt.Sprintf("%s %s", method, request.RoutingKey)
+	}
+	span := &tracepb.Span{
+		TraceId:           traceID(request.TransportID),
+		SpanId:            spanID(request.ID),
+		ParentSpanId:      spanID(request.TransportID),
+		Name:              name,
+		Kind:              tracepb.Span_SPAN_KIND_CLIENT,
+		StartTimeUnixNano: unixNano(flow.StartTime),
+		EndTimeUnixNano:   unixNano(flow.EndTime),
+		Attributes: attributes(
+			stringAttr("skupper.flow.type", "request"),
+			stringAttr("skupper.flow.id", request.ID),
+			stringAttr("skupper.routing_key", request.RoutingKey),
+			stringAttr("skupper.protocol", request.Protocol),
+			stringAttr("skupper.listener.id", request.Listener.ID),
+			stringAttr("skupper.connector.id", request.Connector.ID),
+			stringAttr("skupper.source.site.id", request.SourceSite.ID),
+			stringAttr("skupper.source.site.name", request.SourceSite.Name),
+			stringAttr("skupper.source.process.id", request.Source.ID),
+			stringAttr("skupper.source.process.name", request.Source.Name),
+			stringAttr("skupper.source.component.name", request.SourceGroup.Name),
+			stringAttr("skupper.dest.site.id", request.DestSite.ID),
+			stringAttr("skupper.dest.site.name", request.DestSite.Name),
+			stringAttr("skupper.dest.process.id", request.Dest.ID),
+			stringAttr("skupper.dest.process.name", request.Dest.Name),
+			stringAttr("skupper.dest.component.name", request.DestGroup.Name),
+			stringAttr("http.request.method", method),
+			portAttr("http.response.status_code", deref(flow.Result)),
+			intAttr("skupper.octets.sent", flow.Octets),
+			intAttr("skupper.octets.received", flow.OctetsReverse),
+			intAttr("skupper.latency_us", flow.Latency),
+		),
+	}
+	if code, err := strconv.Atoi(deref(flow.Result)); err == nil && code >= 500 {
+		span.Status = &tracepb.Status{Code: tracepb.Status_STATUS_CODE_ERROR}
+	}
+	return span
+}
+
+// traceID and spanID are derived from flow identifiers, so that requests can
+// reference the span of their transport flow without any shared state
+func traceID(id string) []byte {
+	sum := sha256.Sum256([]byte(id))
+	return sum[:16]
+}
+
+func spanID(id string) []byte {
+	sum := sha256.Sum256([]byte(id))
+	return sum[16:24]
+}
+
+func unixNano(t *vanflow.Time) uint64 {
+	if t == nil || t.IsZero() {
+		return uint64(time.Now().UnixNano())
+	}
+	return uint64(t.UnixNano())
+}
+
+func attributes(attrs ...*commonpb.KeyValue) []*commonpb.KeyValue {
+	var result []*commonpb.KeyValue
+	for _, attr := range attrs {
+		if attr != nil {
+			result = append(result, attr)
+		}
+	}
+	return result
+}
+
+func stringAttr(key string, value string) *commonpb.KeyValue {
+	if value == "" {
+		return nil
+	}
+	return &commonpb.KeyValue{
+		Key:   key,
+		Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}},
+	}
+}
+
+func intAttr(key string, value *uint64) *commonpb.KeyValue {
+	if value == nil {
+		return nil
+	}
+	return &commonpb.KeyValue{
+		Key:   key,
+		Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: int64(*value)}},
+	}
+}
+
+func portAttr(key string, value string) *commonpb.KeyValue {
+	port, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return nil
+	}
+	return intAttr(key, &port)
+}
+
+func deref[T any](p *T) T {
+	var t T
+	if p != nil {
+		t = *p
+	}
+	return t
+}
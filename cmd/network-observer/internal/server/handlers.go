@@ -2,6 +2,7 @@ package server
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/skupperproject/skupper/cmd/network-observer/internal/api"
 	"github.com/skupperproject/skupper/cmd/network-observer/internal/collector"
@@ -55,6 +56,11 @@ func (s *server) ServiceByID(w http.ResponseWriter, r *http.Request, id string)
 	}
 }
 
+// (GET /api/v2alpha1/services/{id}/history)
+func (s *server) ServiceHistoryByID(w http.ResponseWriter, r *http.Request, id string) {
+	s.handleHistory(w, r, collector.HistoryService, fetchByType[collector.AddressRecord](s.records, id))
+}
+
 // (GET /api/v2alpha1/services/{id}/processes)
 func (s *server) ProcessesByService(w http.ResponseWriter, r *http.Request, id string) {
 	//todo(ck) find a way to more directly index this
@@ -236,6 +242,11 @@ func (s *server) ProcesspairByID(w http.ResponseWriter, r *http.Request, id stri
 	}
 }
 
+// (GET /api/v2alpha1/processpairs/{id}/history)
+func (s *server) ProcesspairHistoryByID(w http.ResponseWriter, r *http.Request, id string) {
+	s.handleHistory(w, r, collector.HistoryProcessPair, fetchByType[collector.ProcPairRecord](s.records, id))
+}
+
 // (GET /api/v2alpha1/routeraccess)
 func (s *server) Routeraccess(w http.ResponseWriter, r *http.Request) {
 	results := views.RouterAccessList(listByType[vanflow.RouterAccessRecord](s.records))
@@ -268,6 +279,11 @@ func (s *server) RouterlinkByID(w http.ResponseWriter, r *http.Request, id strin
 	}
 }
 
+// (GET /api/v2alpha1/routerlinks/{id}/history)
+func (s *server) RouterlinkHistoryByID(w http.ResponseWriter, r *http.Request, id string) {
+	s.handleHistory(w, r, collector.HistoryRouterLink, fetchByType[vanflow.LinkRecord](s.records, id))
+}
+
 // (GET /api/v2alpha1/routers)
 func (s *server) Routers(w http.ResponseWriter, r *http.Request) {
 	results := views.Routers(listByType[vanflow.RouterRecord](s.records))
@@ -296,6 +312,11 @@ func (s *server) Sitepairs(w http.ResponseWriter, r *http.Request) {
 func (s *server) SitepairByID(w http.ResponseWriter, r *http.Request, id string) {
 }
 
+// (GET /api/v2alpha1/sitepairs/{id}/history)
+func (s *server) SitepairHistoryByID(w http.ResponseWriter, r *http.Request, id string) {
+	s.handleHistory(w, r, collector.HistorySitePair, fetchByType[collector.SitePairRecord](s.records, id))
+}
+
 // (GET /api/v2alpha1/sites)
 func (s *server) Sites(w http.ResponseWriter, r *http.Request) {
 	results := views.NewSiteSliceProvider(s.graph)(listByType[vanflow.SiteRecord](s.records))
@@ -337,3 +358,16 @@ func (s *server) RoutersBySite(w http.ResponseWriter, r *http.Request, id string
 		s.logWriteError(r, err)
 	}
 }
+
+func (s *server) handleHistory(w http.ResponseWriter, r *http.Request, kind collector.HistoryKind, getRecord func() (store.Entry, bool)) {
+	history := func(store.Entry) []api.HistoryRecord { return []api.HistoryRecord{} }
+	if s.history != nil {
+		qp := getQueryParams(r)
+		start := time.UnixMicro(int64(qp.TimeRangeStart))
+		end := time.UnixMicro(int64(qp.TimeRangeEnd))
+		history = views.NewHistoryProvider(s.history, kind, start, end)
+	}
+	if err := handleSubCollection(w, r, &api.HistoryListResponse{}, getRecord, history); err != nil {
+		s.logWriteError(r, err)
+	}
+}
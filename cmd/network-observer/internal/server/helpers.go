@@ -38,6 +38,18 @@ func fetchAndConditionalMap[V vanflow.Record, R any](stor store.Interface, mappi
 	}
 }
 
+// fetchByType returns the entry with the id if it holds a record of type T
+func fetchByType[T vanflow.Record](stor store.Interface, id string) func() (store.Entry, bool) {
+	return func() (store.Entry, bool) {
+		entry, ok := stor.Get(id)
+		if !ok {
+			return entry, false
+		}
+		_, ok = entry.Record.(T)
+		return entry, ok
+	}
+}
+
 func listByType[T vanflow.Record](stor store.Interface) []store.Entry {
 	var r T
 	return ordered(stor.Index(store.TypeIndex, store.Entry{Record: r}))
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/api"
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/collector"
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	"github.com/skupperproject/skupper/pkg/vanflow/store"
+	"gotest.tools/v3/assert"
+)
+
+type testHistory map[collector.HistoryKind]map[string][]collector.HistorySample
+
+func (h testHistory) Samples(kind collector.HistoryKind, id string, start, end time.Time) []collector.HistorySample {
+	var out []collector.HistorySample
+	for _, sample := range h[kind][id] {
+		if sample.Start.Add(collector.HistoryResolution).Before(start) || sample.Start.After(end) {
+			continue
+		}
+		out = append(out, sample)
+	}
+	return out
+}
+
+func TestHistory(t *testing.T) {
+	tlog := slog.Default()
+	stor := store.NewSyncMapStore(store.SyncMapStoreConfig{Indexers: collector.RecordIndexers()})
+	graph := collector.NewGraph(stor)
+
+	now := time.Now().Truncate(collector.HistoryResolution)
+	samples := []collector.HistorySample{
+		{Start: now.Add(-time.Hour), ConnectionsOpened: 1},
+		{Start: now.Add(-2 * time.Minute), ConnectionsOpened: 4, BytesSent: 2048},
+		{Start: now.Add(-time.Minute), ConnectionsClosed: 4, Requests: 10, RequestErrors: 2},
+	}
+	history := testHistory{
+		collector.HistoryService:     {"adr-1": samples},
+		collector.HistorySitePair:    {"sitepair-1": samples},
+		collector.HistoryProcessPair: {"processpair-1": samples},
+		collector.HistoryRouterLink:  {"link-1": samples[:1]},
+	}
+	srv, c := requireTestClient(t, New(tlog, stor, graph, history, nil))
+	defer srv.Close()
+
+	stor.Replace(wrapRecords(
+		collector.AddressRecord{ID: "adr-1", Name: "backend", Protocol: "tcp"},
+		collector.SitePairRecord{ID: "sitepair-1"},
+		collector.ProcPairRecord{ID: "processpair-1"},
+		vanflow.LinkRecord{BaseRecord: vanflow.NewBase("link-1")},
+	))
+
+	type historyResponse struct {
+		StatusCode int
+		Results    *api.HistoryListResponse
+	}
+	endpoints := map[string]func(id string, params map[string][]string) (historyResponse, error){
+		"services": func(id string, params map[string][]string) (historyResponse, error) {
+			resp, err := c.ServiceHistoryByIDWithResponse(context.TODO(), id, withParameters(params))
+			if err != nil {
+				return historyResponse{}, err
+			}
+			return historyResponse{resp.StatusCode(), resp.JSON200}, nil
+		},
+		"sitepairs": func(id string, params map[string][]string) (historyResponse, error) {
+			resp, err := c.SitepairHistoryByIDWithResponse(context.TODO(), id, withParameters(params))
+			if err != nil {
+				return historyResponse{}, err
+			}
+			return historyResponse{resp.StatusCode(), resp.JSON200}, nil
+		},
+		"processpairs": func(id string, params map[string][]string) (historyResponse, error) {
+			resp, err := c.ProcesspairHistoryByIDWithResponse(context.TODO(), id, withParameters(params))
+			if err != nil {
+				return historyResponse{}, err
+			}
+			return historyResponse{resp.StatusCode(), resp.JSON200}, nil
+		},
+	}
+	ids := map[string]string{
+		"services":     "adr-1",
+		"sitepairs":    "sitepair-1",
+		"processpairs": "processpair-1",
+	}
+	for name, get := range endpoints {
+		t.Run(name, func(t *testing.T) {
+			// default time range is the last 15 minutes
+			resp, err := get(ids[name], nil)
+			assert.Assert(t, err)
+			assert.Equal(t, resp.StatusCode, 200)
+			assert.Equal(t, resp.Results.Count, int64(2))
+			first := resp.Results.Results[0]
+			assert.Equal(t, first.Identity, fmt.Sprintf("%s@%d", ids[name], now.Add(-2*time.Minute).UnixMicro()))
+			assert.Equal(t, first.StartTime, uint64(now.Add(-2*time.Minute).UnixMicro()))
+			assert.Equal(t, first.EndTime, uint64(now.Add(-time.Minute).UnixMicro()))
+			assert.Equal(t, first.ConnectionsOpened, uint64(4))
+			assert.Equal(t, first.BytesSent, uint64(2048))
+			assert.Equal(t, resp.Results.Results[1].RequestErrors, uint64(2))
+
+			resp, err = get(ids[name], map[string][]string{
+				"timeRangeStart": {strconv.FormatInt(now.Add(-2*time.Hour).UnixMicro(), 10)},
+				"sortBy":         {"startTime.desc"},
+			})
+			assert.Assert(t, err)
+			assert.Equal(t, resp.Results.Count, int64(3))
+			assert.Equal(t, resp.Results.Results[0].Requests, uint64(10))
+
+			resp, err = get("dne", nil)
+			assert.Assert(t, err)
+			assert.Equal(t, resp.StatusCode, 404)
+		})
+	}
+
+	t.Run("routerlinks", func(t *testing.T) {
+		resp, err := c.RouterlinkHistoryByIDWithResponse(context.TODO(), "link-1", withParameters(map[string][]string{
+			"timeRangeStart": {strconv.FormatInt(now.Add(-2*time.Hour).UnixMicro(), 10)},
+		}))
+		assert.Assert(t, err)
+		assert.Equal(t, resp.StatusCode(), 200)
+		assert.Equal(t, resp.JSON200.Count, int64(1))
+
+		// the history of a resource is only available for its type
+		sitepair, err := c.SitepairHistoryByIDWithResponse(context.TODO(), "link-1")
+		assert.Assert(t, err)
+		assert.Equal(t, sitepair.StatusCode(), 404)
+	})
+}
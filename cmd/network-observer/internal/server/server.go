@@ -12,11 +12,13 @@ import (
 	"github.com/skupperproject/skupper/pkg/vanflow/store"
 )
 
-func New(logger *slog.Logger, records store.Interface, graph collector.Graph) api.ServerInterface {
+func New(logger *slog.Logger, records store.Interface, graph collector.Graph, history collector.History, watcher collector.Watcher) api.ServerInterface {
 	return &server{
 		logger:  logger,
 		records: records,
 		graph:   graph,
+		history: history,
+		watcher: watcher,
 	}
 }
 
@@ -24,6 +26,8 @@ type server struct {
 	logger  *slog.Logger
 	records store.Interface
 	graph   collector.Graph
+	history collector.History
+	watcher collector.Watcher
 }
 
 func (c *server) logWriteError(r *http.Request, err error) {
@@ -3,8 +3,10 @@
 package views
 
 import (
+	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/skupperproject/skupper/cmd/network-observer/internal/api"
 	"github.com/skupperproject/skupper/cmd/network-observer/internal/collector"
@@ -654,6 +656,33 @@ func defaultSite(id string) api.SiteRecord {
 	}
 }
 
+// NewHistoryProvider returns the samples of the given kind for a
+// record that overlap the time range from start to end.
+func NewHistoryProvider(history collector.History, kind collector.HistoryKind, start, end time.Time) func(store.Entry) []api.HistoryRecord {
+	return func(e store.Entry) []api.HistoryRecord {
+		id := e.Record.Identity()
+		samples := history.Samples(kind, id, start, end)
+		results := make([]api.HistoryRecord, 0, len(samples))
+		for _, sample := range samples {
+			start := uint64(sample.Start.UnixMicro())
+			results = append(results, api.HistoryRecord{
+				Identity:          fmt.Sprintf("%s@%d", id, start),
+				StartTime:         start,
+				EndTime:           uint64(sample.Start.Add(collector.HistoryResolution).UnixMicro()),
+				ConnectionsOpened: sample.ConnectionsOpened,
+				ConnectionsClosed: sample.ConnectionsClosed,
+				ConnectionErrors:  sample.ConnectionErrors,
+				Requests:          sample.Requests,
+				RequestErrors:     sample.RequestErrors,
+				BytesSent:         sample.BytesSent,
+				BytesReceived:     sample.BytesReceived,
+				LinkDowns:         sample.LinkDowns,
+			})
+		}
+		return results
+	}
+}
+
 func vanflowTimes(b vanflow.BaseRecord) (start, end uint64) {
 	if b.StartTime != nil {
 		start = uint64(b.StartTime.UnixMicro())
@@ -0,0 +1,179 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/api"
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/collector"
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/server/views"
+	"github.com/skupperproject/skupper/pkg/vanflow"
+)
+
+const (
+	watchKeepaliveInterval = 15 * time.Second
+	watchWriteTimeout      = 10 * time.Second
+)
+
+// (GET /api/v2alpha1/watch)
+func (s *server) Watch(w http.ResponseWriter, r *http.Request, params api.WatchParams) {
+	if s.watcher == nil {
+		if err := encodeResponse(w, http.StatusNotFound, api.ErrorNotFound{Code: "ErrNotFound"}); err != nil {
+			s.logWriteError(r, err)
+		}
+		return
+	}
+	var opts collector.WatchOptions
+	if params.Types != nil {
+		for _, kind := range strings.Split(*params.Types, ",") {
+			if kind = strings.ToUpper(strings.TrimSpace(kind)); kind != "" {
+				opts.Kinds = append(opts.Kinds, kind)
+			}
+		}
+	}
+	if params.ResourceVersion != nil {
+		opts.ResourceVersion = *params.ResourceVersion
+	} else if lastEventID := r.Header.Get("Last-Event-ID"); lastEventID != "" {
+		version, err := strconv.ParseUint(lastEventID, 10, 64)
+		if err != nil {
+			if err := encodeResponse(w, http.StatusBadRequest, api.ErrorBadRequest{
+				Message: fmt.Sprintf("invalid Last-Event-ID %q", lastEventID),
+			}); err != nil {
+				s.logWriteError(r, err)
+			}
+			return
+		}
+		opts.ResourceVersion = version
+	}
+
+	watch, err := s.watcher.Watch(opts)
+	if err != nil {
+		status, out := http.StatusBadRequest, any(api.ErrorBadRequest{Message: err.Error()})
+		if errors.Is(err, collector.ErrVersionExpired) {
+			status, out = http.StatusGone, api.ErrorResponse{Code: "ErrResourceVersionExpired", Message: err.Error()}
+		}
+		if err := encodeResponse(w, status, out); err != nil {
+			s.logWriteError(r, err)
+		}
+		return
+	}
+	defer watch.Stop()
+
+	stream := &eventStream{w: w, rc: http.NewResponseController(w)}
+	// the stream outlives the write timeout of the server
+	stream.rc.SetWriteDeadline(time.Time{})
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	version := watch.ResourceVersion
+	if err := stream.send(api.WatchEvent{Type: api.BOOKMARK, ResourceVersion: version}); err != nil {
+		s.logWriteError(r, err)
+		return
+	}
+	keepalive := time.NewTicker(watchKeepaliveInterval)
+	defer keepalive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepalive.C:
+			if err := stream.keepalive(); err != nil {
+				s.logWriteError(r, err)
+				return
+			}
+		case event, ok := <-watch.Events():
+			if !ok {
+				if err := watch.Err(); err != nil {
+					requestLogger(s.logger, r).Info("watch stopped", slog.Any("reason", err))
+					stream.send(api.WatchEvent{
+						Type:            api.ERROR,
+						ResourceVersion: version,
+						Error:           &api.ErrorResponse{Code: "ErrSlowConsumer", Message: err.Error()},
+					})
+				}
+				return
+			}
+			version = event.ResourceVersion
+			kind := event.Kind
+			record := s.watchRecord(event.Record)
+			if err := stream.send(api.WatchEvent{
+				Type:            api.WatchEventType(event.Type),
+				Kind:            &kind,
+				ResourceVersion: event.ResourceVersion,
+				Record:          &record,
+			}); err != nil {
+				s.logWriteError(r, err)
+				return
+			}
+		}
+	}
+}
+
+// watchRecord maps a record to its representation in the api
+func (s *server) watchRecord(record vanflow.Record) any {
+	switch record := record.(type) {
+	case vanflow.SiteRecord:
+		return views.NewSiteProvider(s.graph)(record)
+	case vanflow.RouterRecord:
+		return views.Router(record)
+	case vanflow.LinkRecord:
+		if out, ok := views.NewRouterLinkProvider(s.graph)(record); ok {
+			return out
+		}
+	case vanflow.RouterAccessRecord:
+		return views.RouterAccess(record)
+	case vanflow.ConnectorRecord:
+		return views.NewConnectorProvider(s.graph)(record)
+	case vanflow.ListenerRecord:
+		return views.NewListenerProvider(s.graph)(record)
+	case vanflow.ProcessRecord:
+		if out, ok := views.NewProcessProvider(s.records, s.graph)(record); ok {
+			return out
+		}
+	case collector.AddressRecord:
+		return views.NewServiceProvider(s.records, s.graph)(record)
+	case collector.ProcessGroupRecord:
+		return views.NewComponentProvider(s.records)(record)
+	case collector.SitePairRecord:
+		return views.NewSitePairProvider(s.graph)(record)
+	case collector.ProcPairRecord:
+		return views.NewProcessPairProvider(s.graph)(record)
+	case collector.ProcGroupPairRecord:
+		return views.NewComponentPairProvider()(record)
+	}
+	return api.BaseRecord{Identity: record.Identity()}
+}
+
+// eventStream writes server-sent events
+type eventStream struct {
+	w  http.ResponseWriter
+	rc *http.ResponseController
+}
+
+func (s *eventStream) send(event api.WatchEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return s.write("id: %d\nevent: %s\ndata: %s\n\n", event.ResourceVersion, event.Type, data)
+}
+
+func (s *eventStream) keepalive() error {
+	return s.write(": keepalive\n\n")
+}
+
+func (s *eventStream) write(format string, args ...any) error {
+	s.rc.SetWriteDeadline(time.Now().Add(watchWriteTimeout))
+	if _, err := fmt.Fprintf(s.w, format, args...); err != nil {
+		return err
+	}
+	return s.rc.Flush()
+}
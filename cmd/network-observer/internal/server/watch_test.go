@@ -0,0 +1,120 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/api"
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/collector"
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	"github.com/skupperproject/skupper/pkg/vanflow/session"
+	"github.com/skupperproject/skupper/pkg/vanflow/store"
+	"gotest.tools/v3/assert"
+)
+
+type sseEvent struct {
+	ID    string
+	Event string
+	Data  api.WatchEvent
+}
+
+// readEvent reads the next server-sent event, skipping comments
+func readEvent(t *testing.T, r *bufio.Reader) sseEvent {
+	t.Helper()
+	var event sseEvent
+	for {
+		line, err := r.ReadString('\n')
+		assert.Assert(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			if event.Event != "" {
+				return event
+			}
+		case strings.HasPrefix(line, ":"):
+		case strings.HasPrefix(line, "id: "):
+			event.ID = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			event.Event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			assert.Assert(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event.Data))
+		}
+	}
+}
+
+func TestWatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tlog := slog.Default()
+	c := collector.New(tlog, session.NewMockContainerFactory(), prometheus.NewRegistry(), time.Minute, nil)
+	go c.Run(ctx)
+
+	srv, _ := requireTestClient(t, New(tlog, c.Records, c.GetGraph(), nil, c.Watcher()))
+	defer srv.Close()
+
+	watch := func(query string, header http.Header) *http.Response {
+		t.Helper()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/api/v2alpha1/watch"+query, nil)
+		assert.Assert(t, err)
+		for key, values := range header {
+			req.Header[key] = values
+		}
+		resp, err := srv.Client().Do(req)
+		assert.Assert(t, err)
+		return resp
+	}
+
+	resp := watch("?types=site,%20LINK", nil)
+	defer resp.Body.Close()
+	assert.Equal(t, resp.StatusCode, http.StatusOK)
+	assert.Equal(t, resp.Header.Get("Content-Type"), "text/event-stream")
+	events := bufio.NewReader(resp.Body)
+	bookmark := readEvent(t, events)
+	assert.Equal(t, bookmark.Event, "BOOKMARK")
+	assert.Equal(t, bookmark.Data.ResourceVersion, uint64(0))
+
+	source := store.SourceRef{ID: "router-1"}
+	c.Records.Add(vanflow.RouterRecord{BaseRecord: vanflow.NewBase("router-1"), Name: ptrTo("router-1")}, source)
+	c.Records.Add(vanflow.SiteRecord{BaseRecord: vanflow.NewBase("site-1"), Name: ptrTo("west")}, source)
+
+	added := readEvent(t, events)
+	assert.Equal(t, added.Event, "ADDED")
+	assert.Equal(t, added.ID, "2")
+	assert.Equal(t, *added.Data.Kind, "SITE")
+	record, ok := (*added.Data.Record).(map[string]any)
+	assert.Assert(t, ok)
+	assert.Equal(t, record["identity"], "site-1")
+	assert.Equal(t, record["name"], "west")
+
+	c.Records.Delete("site-1")
+	deleted := readEvent(t, events)
+	assert.Equal(t, deleted.Event, "DELETED")
+	assert.Equal(t, deleted.ID, "3")
+
+	// resume from the last event id
+	resumed := watch("", http.Header{"Last-Event-ID": {"1"}})
+	defer resumed.Body.Close()
+	assert.Equal(t, resumed.StatusCode, http.StatusOK)
+	events = bufio.NewReader(resumed.Body)
+	assert.Equal(t, readEvent(t, events).Data.ResourceVersion, uint64(1))
+	assert.Equal(t, readEvent(t, events).ID, "2")
+	assert.Equal(t, readEvent(t, events).ID, "3")
+
+	expired := watch("?resourceVersion=100", nil)
+	defer expired.Body.Close()
+	assert.Equal(t, expired.StatusCode, http.StatusGone)
+	var errResp api.ErrorResponse
+	assert.Assert(t, json.NewDecoder(expired.Body).Decode(&errResp))
+	assert.Equal(t, errResp.Code, "ErrResourceVersionExpired")
+
+	unknown := watch("?types=FLOW", nil)
+	defer unknown.Body.Close()
+	assert.Equal(t, unknown.StatusCode, http.StatusBadRequest)
+}
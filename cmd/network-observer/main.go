@@ -15,15 +15,18 @@ import (
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
 	"github.com/prometheus/client_golang/prometheus"
+	bolt "go.etcd.io/bbolt"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/skupperproject/skupper/cmd/network-observer/internal/api"
 	"github.com/skupperproject/skupper/cmd/network-observer/internal/cmd"
 	"github.com/skupperproject/skupper/cmd/network-observer/internal/collector"
 	"github.com/skupperproject/skupper/cmd/network-observer/internal/flowlog"
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/otlp"
 	"github.com/skupperproject/skupper/cmd/network-observer/internal/server"
 	"github.com/skupperproject/skupper/internal/version"
 	"github.com/skupperproject/skupper/pkg/vanflow"
+	"github.com/skupperproject/skupper/pkg/vanflow/capture"
 	"github.com/skupperproject/skupper/pkg/vanflow/session"
 )
 
@@ -61,18 +64,83 @@ func run(cfg Config) error {
 		return fmt.Errorf("unknown logging profile: %s", cfg.VanflowLoggingProfile)
 	}
 
-	collector := collector.New(
+	var db *bolt.DB
+	if cfg.FlowStorePath != "" {
+		db, err = bolt.Open(cfg.FlowStorePath, 0600, &bolt.Options{Timeout: 5 * time.Second})
+		if err != nil {
+			return fmt.Errorf("failed to open flow store %q: %s", cfg.FlowStorePath, err)
+		}
+		defer db.Close()
+	}
+
+	var factory session.ContainerFactory = session.NewContainerFactory(cfg.RouterURL, sessionConfig)
+	var replayer *capture.Replayer
+	if cfg.VanflowReplayPath != "" {
+		replayFile, err := os.Open(cfg.VanflowReplayPath)
+		if err != nil {
+			return fmt.Errorf("failed to open vanflow replay %q: %s", cfg.VanflowReplayPath, err)
+		}
+		defer replayFile.Close()
+		reader, err := capture.NewReader(replayFile)
+		if err != nil {
+			return fmt.Errorf("failed to read vanflow replay %q: %s", cfg.VanflowReplayPath, err)
+		}
+		factory = replayFactory{Router: session.NewMockRouter()}
+		replayer = capture.NewReplayer(factory.Create(), reader, capture.ReplayOptions{Speed: cfg.VanflowReplaySpeed})
+	}
+
+	var recorder *capture.Recorder
+	if cfg.VanflowCapturePath != "" {
+		captureFile, err := os.Create(cfg.VanflowCapturePath)
+		if err != nil {
+			return fmt.Errorf("failed to create vanflow capture %q: %s", cfg.VanflowCapturePath, err)
+		}
+		defer captureFile.Close()
+		writer, err := capture.NewWriter(captureFile)
+		if err != nil {
+			return fmt.Errorf("failed to write vanflow capture %q: %s", cfg.VanflowCapturePath, err)
+		}
+		defer writer.Close()
+		recorder = capture.NewRecorder(factory.Create(), writer)
+	}
+
+	collector, err := newCollector(
 		logger.With(slog.String("component", "collector")),
-		session.NewContainerFactory(cfg.RouterURL, sessionConfig),
+		factory,
 		reg,
-		cfg.FlowRecordTTL,
+		cfg,
 		flowLogger,
+		db,
 	)
+	if err != nil {
+		return fmt.Errorf("failed to load flow store %q: %s", cfg.FlowStorePath, err)
+	}
+	collector.SetHistoryRetention(cfg.HistoryRetention)
+
+	var exporter *otlp.Exporter
+	if cfg.OTLPEndpoint != "" {
+		headers, err := otlp.ParseHeaders(cfg.OTLPHeaders)
+		if err != nil {
+			return fmt.Errorf("error parsing otlp-headers: %s", err)
+		}
+		exporter, err = otlp.New(logger.With(slog.String("component", "otlp")), otlp.Config{
+			Protocol: otlp.Protocol(cfg.OTLPProtocol),
+			Endpoint: cfg.OTLPEndpoint,
+			Insecure: cfg.OTLPInsecure,
+			Headers:  headers,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to configure otlp exporter: %s", err)
+		}
+		collector.ExportFlows(exporter)
+	}
 
 	collectorAPI := server.New(
 		logger.With(slog.String("component", "api")),
 		collector.Records,
 		collector.GetGraph(),
+		collector.History(),
+		collector.Watcher(),
 	)
 
 	var mux = mux.NewRouter().StrictSlash(true)
@@ -178,6 +246,39 @@ func run(cfg Config) error {
 		})
 	}
 
+	if exporter != nil {
+		g.Go(func() error {
+			logger.Info("Starting OTLP Exporter",
+				slog.String("endpoint", cfg.OTLPEndpoint),
+				slog.String("protocol", cfg.OTLPProtocol))
+			if err := exporter.Run(runCtx); err != nil {
+				return fmt.Errorf("otlp exporter error: %w", err)
+			}
+			return nil
+		})
+	}
+
+	if replayer != nil {
+		g.Go(func() error {
+			logger.Info("Starting vanflow replay", slog.String("path", cfg.VanflowReplayPath))
+			if err := replayer.Run(runCtx); err != nil && !errors.Is(err, runCtx.Err()) {
+				return fmt.Errorf("vanflow replay error: %w", err)
+			}
+			logger.Info("vanflow replay complete")
+			return nil
+		})
+	}
+
+	if recorder != nil {
+		g.Go(func() error {
+			logger.Info("Starting vanflow capture", slog.String("path", cfg.VanflowCapturePath))
+			if err := recorder.Run(runCtx); err != nil {
+				return fmt.Errorf("vanflow capture error: %w", err)
+			}
+			return nil
+		})
+	}
+
 	g.Go(func() error {
 		logger.Debug("Starting Network Observer Collector")
 		if err := collector.Run(runCtx); err != nil {
@@ -192,6 +293,29 @@ func run(cfg Config) error {
 	return nil
 }
 
+// replayFactory creates containers connected to the mock router a vanflow
+// capture is replayed on
+type replayFactory struct {
+	Router *session.MockRouter
+}
+
+func (f replayFactory) Create() session.Container {
+	return session.NewMockContainer(f.Router)
+}
+
+// newCollector creates a collector that persists flow records in db, or keeps
+// them in memory when db is nil
+func newCollector(logger *slog.Logger, factory session.ContainerFactory, reg *prometheus.Registry, cfg Config, flowLogger func(vanflow.RecordMessage), db *bolt.DB) (*collector.Collector, error) {
+	if db == nil {
+		return collector.New(logger, factory, reg, cfg.FlowRecordTTL, flowLogger), nil
+	}
+	return collector.NewPersistent(logger, factory, reg, cfg.FlowRecordTTL, flowLogger, collector.StorageConfig{
+		DB:      db,
+		MaxAge:  cfg.FlowStoreMaxAge,
+		MaxSize: cfg.FlowStoreMaxSize,
+	})
+}
+
 func main() {
 	var cfg Config
 	flags := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
@@ -214,10 +338,22 @@ func main() {
 	flags.StringVar(&cfg.PrometheusAPI, "prometheus-api", "http://127.0.0.1:9090", "Prometheus API HTTP endpoint for console")
 
 	flags.DurationVar(&cfg.FlowRecordTTL, "flow-record-ttl", 15*time.Minute, "How long to retain flow records in memory")
+	flags.StringVar(&cfg.FlowStorePath, "flow-store-path", "", "Path to a database file where flow records are persisted. When unset flow records are only kept in memory")
+	flags.DurationVar(&cfg.FlowStoreMaxAge, "flow-store-max-age", 24*time.Hour, "How long to retain persisted flow records since their last update. Zero disables retention by age")
+	flags.Int64Var(&cfg.FlowStoreMaxSize, "flow-store-max-size", 256*1024*1024, "Maximum size in bytes of the persisted flow records. Zero disables retention by size")
+	flags.DurationVar(&cfg.HistoryRetention, "history-retention", collector.DefaultHistoryRetention, "How long to retain the per minute history of services, pairs and router links. Zero disables history")
 	flags.BoolVar(&cfg.CORSAllowAll, "cors-allow-all", false, "Development option to allow all origins")
 	flags.BoolVar(&cfg.EnableProfile, "profile", false, "Exposes the runtime profiling facilities from net/http/pprof on http://localhost:9970")
 
+	flags.StringVar(&cfg.OTLPEndpoint, "otlp-endpoint", "", "OTLP receiver endpoint where terminated flows are exported as spans. A host:port for grpc or a URL for http. When unset flows are not exported")
+	flags.StringVar(&cfg.OTLPProtocol, "otlp-protocol", "grpc", "Protocol used to export flows to the OTLP receiver. Options are grpc and http")
+	flags.BoolVar(&cfg.OTLPInsecure, "otlp-insecure", false, "Set to connect to the OTLP receiver without TLS")
+	flags.StringVar(&cfg.OTLPHeaders, "otlp-headers", "", "Comma separated list of key=value headers sent to the OTLP receiver")
+
 	flags.StringVar(&cfg.VanflowLoggingProfile, "vanflow-logging-profile", "silent", "Controls low level vanflow record logging. Options are silent, minimal, moderate and all")
+	flags.StringVar(&cfg.VanflowCapturePath, "vanflow-capture-path", "", "Path to a file where the vanflow messages received from the router network are captured for debugging. When unset no capture is made")
+	flags.StringVar(&cfg.VanflowReplayPath, "vanflow-replay-path", "", "Path to a vanflow capture replayed in place of connecting to the router")
+	flags.Float64Var(&cfg.VanflowReplaySpeed, "vanflow-replay-speed", 1, "Speed of the vanflow replay relative to the pace it was captured at. Zero replays as fast as possible")
 
 	flags.Parse(os.Args[1:])
 	if *isVersion {
@@ -21,7 +21,6 @@ import (
 	"crypto/x509/pkix"
 	"encoding/pem"
 	"fmt"
-	"log"
 	"math/big"
 	"net"
 	"strings"
@@ -31,24 +30,6 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-func publicKey(priv interface{}) interface{} {
-	switch k := priv.(type) {
-	case *rsa.PrivateKey:
-		return &k.PublicKey
-	default:
-		return nil
-	}
-}
-
-func pemBlockForKey(priv interface{}) *pem.Block {
-	switch k := priv.(type) {
-	case *rsa.PrivateKey:
-		return &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(k)}
-	default:
-		return nil
-	}
-}
-
 type CertificateAuthority struct {
 	Certificate *x509.Certificate
 	Key         interface{}
@@ -57,45 +38,67 @@ type CertificateAuthority struct {
 
 type CertificateData map[string][]byte
 
-func decodeDataElement(in []byte, name string) []byte {
+func decodeDataElement(in []byte, name string) (*pem.Block, error) {
 	block, _ := pem.Decode(in)
 	if block == nil {
-		log.Fatal("failed to decode PEM block of type " + name)
+		return nil, fmt.Errorf("failed to decode PEM block of type %s", name)
 	}
-	return block.Bytes
+	return block, nil
 }
 
-func getCAFromSecret(secret *corev1.Secret) *CertificateAuthority {
+func getCAFromSecret(secret *corev1.Secret) (*CertificateAuthority, error) {
 	if secret == nil || secret.Data == nil {
-		return nil
+		return nil, nil
+	}
+	certBlock, err := decodeDataElement(secret.Data["tls.crt"], "certificate")
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get CA certificate from secret %s: %w", secret.Name, err)
 	}
-	cert, err := x509.ParseCertificate(decodeDataElement(secret.Data["tls.crt"], "certificate"))
+	keyBlock, err := decodeDataElement(secret.Data["tls.key"], "private key")
 	if err != nil {
-		log.Fatal("failed to get CA certificate from secret")
+		return nil, err
 	}
-	key, err := x509.ParsePKCS1PrivateKey(decodeDataElement(secret.Data["tls.key"], "private key"))
+	key, err := parsePrivateKey(keyBlock)
 	if err != nil {
-		log.Fatal("failed to get CA private key from secret", err)
+		return nil, fmt.Errorf("failed to get CA private key from secret %s: %w", secret.Name, err)
 	}
 	return &CertificateAuthority{
 		Certificate: cert,
 		Key:         key,
 		CrtData:     secret.Data["tls.crt"],
-	}
+	}, nil
+}
+
+// GenerateSecret generates a kubernetes secret using the default key
+// options (see GenerateSecretWithKey).
+func GenerateSecret(name string, subject string, hosts string, expiration time.Duration, ca *corev1.Secret) (corev1.Secret, error) {
+	return GenerateSecretWithKey(name, subject, hosts, expiration, ca, KeyOptions{})
 }
 
-// GenerateSecret generates a kubernetes secret.
+// GenerateSecretWithKey generates a kubernetes secret.
 // name is the corev1.Secret's name.
 // subject is the x509 certificate's common name.
 // hosts are the host names in the x509 certificate. Comma separated if more than one hostname
 // expiration is when the secret expires, if zero is passed in, the expiration is set to 5 years from now
 // ca is the certificate authority, if nil a ca cert will be created.
-func GenerateSecret(name string, subject string, hosts string, expiration time.Duration, ca *corev1.Secret) corev1.Secret {
-	caCert := getCAFromSecret(ca)
+// key determines the type of private key generated. If it is empty,
+// the key type of the ca is used, or RSA 2048 if there is no ca.
+func GenerateSecretWithKey(name string, subject string, hosts string, expiration time.Duration, ca *corev1.Secret, key KeyOptions) (corev1.Secret, error) {
+	caCert, err := getCAFromSecret(ca)
+	if err != nil {
+		return corev1.Secret{}, err
+	}
+	if key.IsZero() && caCert != nil {
+		key = keyOptionsFor(caCert.Key)
+	}
 
-	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	priv, err := key.generateKey()
 	if err != nil {
-		log.Fatalf("failed to generate private key: %s", err)
+		return corev1.Secret{}, fmt.Errorf("failed to generate private key: %w", err)
 	}
 
 	notBefore := time.Now()
@@ -107,7 +110,7 @@ func GenerateSecret(name string, subject string, hosts string, expiration time.D
 	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
 	serialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
 	if err != nil {
-		log.Fatalf("failed to generate serial number: %s", err)
+		return corev1.Secret{}, fmt.Errorf("failed to generate serial number: %w", err)
 	}
 
 	template := x509.Certificate{
@@ -117,10 +120,13 @@ func GenerateSecret(name string, subject string, hosts string, expiration time.D
 		},
 		NotBefore:             notBefore,
 		NotAfter:              notAfter,
-		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		KeyUsage:              x509.KeyUsageDigitalSignature,
 		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
 		BasicConstraintsValid: true,
 	}
+	if _, ok := priv.(*rsa.PrivateKey); ok {
+		template.KeyUsage |= x509.KeyUsageKeyEncipherment
+	}
 
 	hosts_list := strings.Split(hosts, ",")
 	for _, h := range hosts_list {
@@ -145,9 +151,14 @@ func GenerateSecret(name string, subject string, hosts string, expiration time.D
 		cakey = caCert.Key
 	}
 
-	derBytes, err := x509.CreateCertificate(rand.Reader, &template, parent, publicKey(priv), cakey)
+	derBytes, err := x509.CreateCertificate(rand.Reader, &template, parent, priv.Public(), cakey)
+	if err != nil {
+		return corev1.Secret{}, fmt.Errorf("failed to create certificate: %w", err)
+	}
+
+	keyBlock, err := pemBlockForKey(priv)
 	if err != nil {
-		log.Fatalf("Failed to create certificate: %s", err)
+		return corev1.Secret{}, err
 	}
 
 	secret := corev1.Secret{
@@ -163,17 +174,51 @@ func GenerateSecret(name string, subject string, hosts string, expiration time.D
 	}
 
 	certString := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: derBytes})
-	keyString := pem.EncodeToMemory(pemBlockForKey(priv))
+	keyString := pem.EncodeToMemory(keyBlock)
 
 	secret.Data["tls.crt"] = []byte(certString)
 	secret.Data["tls.key"] = []byte(keyString)
-	if ca != nil {
+	if caCert != nil {
 		secret.Data["ca.crt"] = caCert.CrtData
 	} else {
 		secret.Data["ca.crt"] = secret.Data["tls.crt"] //self.signed
 	}
 
-	return secret
+	return secret, nil
+}
+
+// GenerateCertificateRequest generates a private key and a PEM
+// encoded certificate signing request for that key, for use with an
+// external issuer. The PEM encoded private key is also returned.
+func GenerateCertificateRequest(subject string, hosts string, key KeyOptions) ([]byte, []byte, error) {
+	priv, err := key.generateKey()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate private key: %w", err)
+	}
+	template := x509.CertificateRequest{
+		Subject: pkix.Name{
+			CommonName: subject,
+		},
+	}
+	for _, h := range strings.Split(hosts, ",") {
+		h = strings.TrimSpace(h)
+		if h == "" {
+			continue
+		}
+		if ip := net.ParseIP(h); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		}
+		template.DNSNames = append(template.DNSNames, h)
+	}
+	derBytes, err := x509.CreateCertificateRequest(rand.Reader, &template, priv)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create certificate request: %w", err)
+	}
+	keyBlock, err := pemBlockForKey(priv)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: derBytes}), pem.EncodeToMemory(keyBlock), nil
 }
 
 func DecodeCertificate(data []byte) (*x509.Certificate, error) {
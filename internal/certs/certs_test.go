@@ -15,6 +15,12 @@ limitations under the License.
 package certs
 
 import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
 	"testing"
 
 	"gotest.tools/v3/assert"
@@ -26,7 +32,8 @@ func TestGenerateCASecret(t *testing.T) {
 	name := "ca-secret"
 	host := host1 + ", " + host2
 	cn := "www.example.com"
-	ca_secret := GenerateSecret(name, cn, host, 0, nil)
+	ca_secret, err := GenerateSecret(name, cn, host, 0, nil)
+	assert.Assert(t, err)
 	data, ok := ca_secret.Data["tls.crt"]
 	if !ok {
 		t.Error("Invalid secret, tls.crt is missing")
@@ -46,10 +53,12 @@ func TestGenerateCASecret(t *testing.T) {
 
 func TestGenerateSecret(t *testing.T) {
 	ca_cn := "www.example.com"
-	ca_secret := GenerateSecret("test-secret", ca_cn, "134.565.56.77", 0, nil)
+	ca_secret, err := GenerateSecret("test-secret", ca_cn, "134.565.56.77", 0, nil)
+	assert.Assert(t, err)
 	my_secret_cn := "www.my.example.com"
 	my_secret_host := "172.565.56.77"
-	my_secret := GenerateSecret("my_secret", my_secret_cn, my_secret_host, 86400000000000 /*duration of 1 day*/, &ca_secret)
+	my_secret, err := GenerateSecret("my_secret", my_secret_cn, my_secret_host, 86400000000000 /*duration of 1 day*/, &ca_secret)
+	assert.Assert(t, err)
 	data, ok := my_secret.Data["tls.crt"]
 	if !ok {
 		t.Error("Invalid secret, tls.crt is missing")
@@ -62,3 +71,182 @@ func TestGenerateSecret(t *testing.T) {
 	assert.Equal(t, my_secret_cn, my_cert.Subject.CommonName)
 	assert.Equal(t, ca_cn, my_cert.Issuer.CommonName)
 }
+
+func TestGenerateSecretWithKey(t *testing.T) {
+	tests := []struct {
+		name          string
+		caKey         KeyOptions
+		key           KeyOptions
+		expectedKey   KeyOptions
+		expectedError string
+	}{
+		{
+			name:        "default",
+			expectedKey: KeyOptions{Algorithm: KeyAlgorithmRSA, Size: 2048},
+		},
+		{
+			name:        "ecdsa p256",
+			caKey:       KeyOptions{Algorithm: KeyAlgorithmECDSA},
+			key:         KeyOptions{Algorithm: KeyAlgorithmECDSA},
+			expectedKey: KeyOptions{Algorithm: KeyAlgorithmECDSA, Size: 256},
+		},
+		{
+			name:        "ecdsa p384 signed by rsa",
+			key:         KeyOptions{Algorithm: KeyAlgorithmECDSA, Size: 384},
+			expectedKey: KeyOptions{Algorithm: KeyAlgorithmECDSA, Size: 384},
+		},
+		{
+			name:        "ed25519",
+			caKey:       KeyOptions{Algorithm: KeyAlgorithmEd25519},
+			key:         KeyOptions{Algorithm: KeyAlgorithmEd25519},
+			expectedKey: KeyOptions{Algorithm: KeyAlgorithmEd25519},
+		},
+		{
+			name:        "inherit from ca",
+			caKey:       KeyOptions{Algorithm: KeyAlgorithmECDSA, Size: 384},
+			expectedKey: KeyOptions{Algorithm: KeyAlgorithmECDSA, Size: 384},
+		},
+		{
+			name:        "rsa 3072",
+			key:         KeyOptions{Algorithm: KeyAlgorithmRSA, Size: 3072},
+			expectedKey: KeyOptions{Algorithm: KeyAlgorithmRSA, Size: 3072},
+		},
+		{
+			name:          "bad curve",
+			key:           KeyOptions{Algorithm: KeyAlgorithmECDSA, Size: 128},
+			expectedError: "unsupported ECDSA key size 128",
+		},
+		{
+			name:          "bad algorithm",
+			key:           KeyOptions{Algorithm: "dsa"},
+			expectedError: "unsupported key algorithm \"dsa\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ca, err := GenerateSecretWithKey("my-ca", "my-ca", "", 0, nil, tt.caKey)
+			assert.Assert(t, err)
+			secret, err := GenerateSecretWithKey("my-cert", "my-cert", "my.host", 0, &ca, tt.key)
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			assert.Assert(t, err)
+			cert, err := DecodeCertificate(secret.Data["tls.crt"])
+			assert.Assert(t, err)
+			caCert, err := DecodeCertificate(ca.Data["tls.crt"])
+			assert.Assert(t, err)
+			assert.Assert(t, cert.CheckSignatureFrom(caCert))
+			assert.Assert(t, MatchesKeyOptions(cert, tt.expectedKey))
+			pair, err := tls.X509KeyPair(secret.Data["tls.crt"], secret.Data["tls.key"])
+			assert.Assert(t, err)
+			switch tt.expectedKey.Algorithm {
+			case KeyAlgorithmRSA:
+				_, ok := pair.PrivateKey.(*rsa.PrivateKey)
+				assert.Assert(t, ok)
+			case KeyAlgorithmECDSA:
+				_, ok := pair.PrivateKey.(*ecdsa.PrivateKey)
+				assert.Assert(t, ok)
+			case KeyAlgorithmEd25519:
+				_, ok := pair.PrivateKey.(ed25519.PrivateKey)
+				assert.Assert(t, ok)
+			}
+		})
+	}
+}
+
+func TestGenerateSecretBadCA(t *testing.T) {
+	ca, err := GenerateSecret("my-ca", "my-ca", "", 0, nil)
+	assert.Assert(t, err)
+	ca.Data["tls.key"] = []byte("not a key")
+	_, err = GenerateSecret("my-cert", "my-cert", "", 0, &ca)
+	assert.ErrorContains(t, err, "failed to decode PEM block of type private key")
+}
+
+func TestKeyOptionsFromSettings(t *testing.T) {
+	tests := []struct {
+		name          string
+		settings      map[string]string
+		defaults      KeyOptions
+		expected      KeyOptions
+		expectedError string
+	}{
+		{
+			name: "no settings",
+		},
+		{
+			name:     "defaults",
+			defaults: KeyOptions{Algorithm: KeyAlgorithmECDSA, Size: 384},
+			expected: KeyOptions{Algorithm: KeyAlgorithmECDSA, Size: 384},
+		},
+		{
+			name:     "algorithm",
+			settings: map[string]string{"key-algorithm": "ECDSA"},
+			expected: KeyOptions{Algorithm: KeyAlgorithmECDSA},
+		},
+		{
+			name:     "algorithm and size",
+			settings: map[string]string{"key-algorithm": "ecdsa", "key-size": "384"},
+			expected: KeyOptions{Algorithm: KeyAlgorithmECDSA, Size: 384},
+		},
+		{
+			name:     "algorithm overrides default",
+			settings: map[string]string{"key-algorithm": "ed25519"},
+			defaults: KeyOptions{Algorithm: KeyAlgorithmRSA, Size: 4096},
+			expected: KeyOptions{Algorithm: KeyAlgorithmEd25519},
+		},
+		{
+			name:     "size overrides default",
+			settings: map[string]string{"key-size": "521"},
+			defaults: KeyOptions{Algorithm: KeyAlgorithmECDSA, Size: 384},
+			expected: KeyOptions{Algorithm: KeyAlgorithmECDSA, Size: 521},
+		},
+		{
+			name:          "bad size",
+			settings:      map[string]string{"key-algorithm": "ecdsa", "key-size": "big"},
+			expectedError: "invalid key-size \"big\"",
+		},
+		{
+			name:          "size for ed25519",
+			settings:      map[string]string{"key-algorithm": "ed25519", "key-size": "256"},
+			expectedError: "key size cannot be specified for ed25519 keys",
+		},
+		{
+			name:          "rsa too small",
+			settings:      map[string]string{"key-size": "1024"},
+			expectedError: "unsupported RSA key size 1024",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := KeyOptionsFromSettings(tt.settings, tt.defaults)
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+			} else {
+				assert.Assert(t, err)
+				assert.Equal(t, actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGenerateCertificateRequest(t *testing.T) {
+	csrData, keyData, err := GenerateCertificateRequest("my-subject", "my.host, 10.0.0.1", KeyOptions{Algorithm: KeyAlgorithmECDSA})
+	assert.Assert(t, err)
+	block, _ := pem.Decode(csrData)
+	assert.Assert(t, block != nil)
+	assert.Equal(t, block.Type, "CERTIFICATE REQUEST")
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	assert.Assert(t, err)
+	assert.Assert(t, csr.CheckSignature())
+	assert.Equal(t, csr.Subject.CommonName, "my-subject")
+	assert.DeepEqual(t, csr.DNSNames, []string{"my.host", "10.0.0.1"})
+	assert.Equal(t, csr.IPAddresses[0].String(), "10.0.0.1")
+
+	keyBlock, _ := pem.Decode(keyData)
+	assert.Assert(t, keyBlock != nil)
+	key, err := parsePrivateKey(keyBlock)
+	assert.Assert(t, err)
+	_, ok := key.(*ecdsa.PrivateKey)
+	assert.Assert(t, ok)
+}
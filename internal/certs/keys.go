@@ -0,0 +1,230 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certs
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type KeyAlgorithm string
+
+const (
+	KeyAlgorithmRSA     KeyAlgorithm = "rsa"
+	KeyAlgorithmECDSA   KeyAlgorithm = "ecdsa"
+	KeyAlgorithmEd25519 KeyAlgorithm = "ed25519"
+
+	// Keys in CertificateSpec.Settings (or Site.Spec.Settings for
+	// non-kube sites) through which key options can be specified.
+	SettingKeyAlgorithm = "key-algorithm"
+	SettingKeySize      = "key-size"
+
+	defaultRSAKeySize   = 2048
+	defaultECDSAKeySize = 256
+)
+
+var KeyAlgorithms = []string{string(KeyAlgorithmRSA), string(KeyAlgorithmECDSA), string(KeyAlgorithmEd25519)}
+
+// KeyOptions determines the type of private key generated for a
+// certificate. Size is the modulus length in bits for RSA and the
+// curve size for ECDSA (256, 384 or 521). It is ignored for Ed25519. A
+// zero Size selects the default for the algorithm.
+type KeyOptions struct {
+	Algorithm KeyAlgorithm
+	Size      int
+}
+
+func (o KeyOptions) IsZero() bool {
+	return o.Algorithm == "" && o.Size == 0
+}
+
+func (o KeyOptions) String() string {
+	if o.Algorithm == KeyAlgorithmEd25519 {
+		return string(o.Algorithm)
+	}
+	return fmt.Sprintf("%s-%d", o.algorithm(), o.size())
+}
+
+func (o KeyOptions) algorithm() KeyAlgorithm {
+	if o.Algorithm == "" {
+		return KeyAlgorithmRSA
+	}
+	return o.Algorithm
+}
+
+func (o KeyOptions) size() int {
+	if o.Size != 0 {
+		return o.Size
+	}
+	switch o.algorithm() {
+	case KeyAlgorithmECDSA:
+		return defaultECDSAKeySize
+	case KeyAlgorithmRSA:
+		return defaultRSAKeySize
+	default:
+		return 0
+	}
+}
+
+// Verify returns an error if the options do not describe a supported
+// key type.
+func (o KeyOptions) Verify() error {
+	switch o.algorithm() {
+	case KeyAlgorithmRSA:
+		if size := o.size(); size < 2048 || size > 8192 || size%8 != 0 {
+			return fmt.Errorf("unsupported RSA key size %d, must be a multiple of 8 between 2048 and 8192", size)
+		}
+	case KeyAlgorithmECDSA:
+		if _, err := curve(o.size()); err != nil {
+			return err
+		}
+	case KeyAlgorithmEd25519:
+		if o.Size != 0 {
+			return fmt.Errorf("key size cannot be specified for %s keys", KeyAlgorithmEd25519)
+		}
+	default:
+		return fmt.Errorf("unsupported key algorithm %q, must be one of %v", o.Algorithm, KeyAlgorithms)
+	}
+	return nil
+}
+
+func (o KeyOptions) generateKey() (crypto.Signer, error) {
+	if err := o.Verify(); err != nil {
+		return nil, err
+	}
+	switch o.algorithm() {
+	case KeyAlgorithmECDSA:
+		c, _ := curve(o.size())
+		return ecdsa.GenerateKey(c, rand.Reader)
+	case KeyAlgorithmEd25519:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		return key, err
+	default:
+		return rsa.GenerateKey(rand.Reader, o.size())
+	}
+}
+
+// KeyOptionsFromSettings reads key options from the supplied
+// settings. Any option not specified there is taken from defaults.
+func KeyOptionsFromSettings(settings map[string]string, defaults KeyOptions) (KeyOptions, error) {
+	options := defaults
+	if value, ok := settings[SettingKeyAlgorithm]; ok && value != "" {
+		algorithm := KeyAlgorithm(strings.ToLower(value))
+		if algorithm != options.Algorithm {
+			// default size applies only to the default algorithm
+			options.Size = 0
+		}
+		options.Algorithm = algorithm
+	}
+	if value, ok := settings[SettingKeySize]; ok && value != "" {
+		size, err := strconv.Atoi(value)
+		if err != nil {
+			return options, fmt.Errorf("invalid %s %q: %w", SettingKeySize, value, err)
+		}
+		options.Size = size
+	}
+	if err := options.Verify(); err != nil {
+		return options, err
+	}
+	return options, nil
+}
+
+func curve(size int) (elliptic.Curve, error) {
+	switch size {
+	case 256:
+		return elliptic.P256(), nil
+	case 384:
+		return elliptic.P384(), nil
+	case 521:
+		return elliptic.P521(), nil
+	default:
+		return nil, fmt.Errorf("unsupported ECDSA key size %d, must be one of 256, 384 or 521", size)
+	}
+}
+
+func keyOptionsFor(key interface{}) KeyOptions {
+	switch k := key.(type) {
+	case *ecdsa.PrivateKey:
+		return KeyOptions{Algorithm: KeyAlgorithmECDSA, Size: k.Curve.Params().BitSize}
+	case ed25519.PrivateKey:
+		return KeyOptions{Algorithm: KeyAlgorithmEd25519}
+	case *rsa.PrivateKey:
+		return KeyOptions{Algorithm: KeyAlgorithmRSA, Size: k.N.BitLen()}
+	default:
+		return KeyOptions{}
+	}
+}
+
+func pemBlockForKey(priv interface{}) (*pem.Block, error) {
+	switch k := priv.(type) {
+	case *rsa.PrivateKey:
+		return &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(k)}, nil
+	case *ecdsa.PrivateKey:
+		data, err := x509.MarshalECPrivateKey(k)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode private key: %w", err)
+		}
+		return &pem.Block{Type: "EC PRIVATE KEY", Bytes: data}, nil
+	case ed25519.PrivateKey:
+		data, err := x509.MarshalPKCS8PrivateKey(k)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode private key: %w", err)
+		}
+		return &pem.Block{Type: "PRIVATE KEY", Bytes: data}, nil
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", priv)
+	}
+}
+
+func parsePrivateKey(block *pem.Block) (interface{}, error) {
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	default:
+		return x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+}
+
+// MatchesKeyOptions returns true if the public key in the supplied
+// certificate is of the type described by the options. Empty options
+// match any key.
+func MatchesKeyOptions(cert *x509.Certificate, options KeyOptions) bool {
+	if options.IsZero() {
+		return true
+	}
+	var actual KeyOptions
+	switch k := cert.PublicKey.(type) {
+	case *ecdsa.PublicKey:
+		actual = KeyOptions{Algorithm: KeyAlgorithmECDSA, Size: k.Curve.Params().BitSize}
+	case ed25519.PublicKey:
+		actual = KeyOptions{Algorithm: KeyAlgorithmEd25519}
+	case *rsa.PublicKey:
+		actual = KeyOptions{Algorithm: KeyAlgorithmRSA, Size: k.N.BitLen()}
+	default:
+		return false
+	}
+	return actual.algorithm() == options.algorithm() && actual.size() == options.size()
+}
@@ -3,8 +3,8 @@ package common
 var (
 	LinkAccessTypes = []string{"route", "loadbalancer", "default"}
 	OutputTypes     = []string{"json", "yaml"}
-	ListenerTypes   = []string{"tcp"}
-	ConnectorTypes  = []string{"tcp"}
+	ListenerTypes   = []string{"tcp", "udp", "http", "http2"}
+	ConnectorTypes  = []string{"tcp", "udp", "http", "http2"}
 	WorkloadTypes   = []string{"deployment", "service", "daemonset", "statefulset"}
 	WaitStatusTypes = []string{"ready", "configured", "none"}
 	BundleTypes     = []string{"tarball", "shell-script"}
@@ -21,6 +21,7 @@ const (
 	ConfigMaps      string = "ConfigMap"
 	Certificates    string = "Certificate"
 	SecuredAccesses string = "SecuredAccess"
+	AccessGrants    string = "AccessGrant"
 )
 
 const (
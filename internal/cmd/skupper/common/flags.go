@@ -47,7 +47,7 @@ for other Kubernetes flavors, loadbalancer is the default.`
 	FlagNameHost                = "host"
 	FlagDescHost                = "The hostname or IP address of the local connector"
 	FlagNameConnectorType       = "type"
-	FlagDescConnectorType       = "The connector type. Choices: [tcp]."
+	FlagDescConnectorType       = "The connector type. Choices: [tcp|udp|http|http2]."
 	FlagNameIncludeNotReadyPods = "include-not-ready"
 	FlagDescIncludeNotRead      = "If true, include server pods that are not in the ready state."
 	FlagNameSelector            = "selector"
@@ -62,7 +62,7 @@ for other Kubernetes flavors, loadbalancer is the default.`
 	FlagDescConnectorStatusOutput = "print status of connectors Choices: json, yaml"
 
 	FlagNameListenerType = "type"
-	FlagDescListenerType = "The listener type. Choices: [tcp]."
+	FlagDescListenerType = "The listener type. Choices: [tcp|udp|http|http2]."
 	FlagNameListenerPort = "port"
 	FlagDescListenerPort = "The port of the local listener"
 	FlagNameListenerHost = "host"
@@ -89,6 +89,9 @@ for other Kubernetes flavors, loadbalancer is the default.`
 
 	FlagNameFileName = "filename"
 	FlagDescFileName = "The name of the file with custom resources"
+
+	FlagDescNetworkStatusOutput = "print status of the application network. Choices: json, yaml"
+	FlagDescSystemStatusOutput  = "print the health report of the site. Choices: json, yaml"
 )
 
 type CommandSiteCreateFlags struct {
@@ -265,3 +268,11 @@ type CommandSystemApplyFlags struct {
 type CommandSystemDeleteFlags struct {
 	Filename string
 }
+
+type CommandSystemStatusFlags struct {
+	Output string
+}
+
+type CommandNetworkStatusFlags struct {
+	Output string
+}
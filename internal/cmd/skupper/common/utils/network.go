@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/skupperproject/skupper/internal/network"
+)
+
+// PrintApplicationNetwork writes the sites, links and services of an
+// application network to the writer, either encoded in the requested
+// output type or as tables when no output type is given.
+func PrintApplicationNetwork(out io.Writer, appNetwork network.ApplicationNetwork, outputType string) error {
+	if outputType != "" {
+		encodedOutput, err := Encode(outputType, appNetwork)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, encodedOutput)
+		return nil
+	}
+
+	if len(appNetwork.Sites) == 0 {
+		fmt.Fprintln(out, "The network status is not yet available")
+		return nil
+	}
+
+	writer := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(writer, "SITE\tPLATFORM\tNAMESPACE\tVERSION\tLINKS\tSERVICES")
+	for _, site := range appNetwork.Sites {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%d\t%d\n", site.Name, site.Platform, site.Namespace, site.Version, len(site.Links), len(site.Services))
+	}
+	writer.Flush()
+
+	fmt.Fprintln(out)
+	writer = tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(writer, "LINK\tFROM\tTO\tSTATUS\tCOST")
+	for _, site := range appNetwork.Sites {
+		for _, link := range site.Links {
+			status := "down"
+			if link.Operational {
+				status = "up"
+			}
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%d\n", link.Name, site.Name, link.RemoteSiteName, status, link.Cost)
+		}
+	}
+	writer.Flush()
+
+	fmt.Fprintln(out)
+	writer = tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(writer, "ROUTING KEY\tLISTENER SITES\tCONNECTOR SITES")
+	for _, service := range appNetwork.Services {
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", service.RoutingKey, sitesOrNone(service.ListenerSites), sitesOrNone(service.ConnectorSites))
+	}
+	writer.Flush()
+
+	return nil
+}
+
+func sitesOrNone(sites []string) string {
+	if len(sites) == 0 {
+		return "-"
+	}
+	return strings.Join(sites, ", ")
+}
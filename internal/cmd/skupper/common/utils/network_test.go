@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/skupperproject/skupper/internal/network"
+	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+	"gotest.tools/v3/assert"
+)
+
+func TestPrintApplicationNetwork(t *testing.T) {
+	appNetwork := network.NewApplicationNetwork([]v2alpha1.SiteRecord{
+		{
+			Id:        "west-id",
+			Name:      "west",
+			Namespace: "west",
+			Platform:  "kubernetes",
+			Version:   "2.0.0",
+			Links: []v2alpha1.LinkRecord{
+				{Name: "west-to-east", RemoteSiteId: "east-id", RemoteSiteName: "east", Operational: true, Cost: 2},
+			},
+			Services: []v2alpha1.ServiceRecord{
+				{RoutingKey: "backend", Listeners: []string{"backend"}},
+			},
+		},
+		{
+			Id:        "east-id",
+			Name:      "east",
+			Namespace: "default",
+			Platform:  "podman",
+			Version:   "2.0.0",
+			Services: []v2alpha1.ServiceRecord{
+				{RoutingKey: "backend", Connectors: []string{"10.0.0.2"}},
+			},
+		},
+	})
+	tests := []struct {
+		name           string
+		network        network.ApplicationNetwork
+		outputType     string
+		expectedOutput string
+		expectedError  string
+	}{
+		{
+			name:    "tables",
+			network: appNetwork,
+			expectedOutput: `SITE  PLATFORM    NAMESPACE  VERSION  LINKS  SERVICES
+east  podman      default    2.0.0    0      1
+west  kubernetes  west       2.0.0    1      1
+
+LINK          FROM  TO    STATUS  COST
+west-to-east  west  east  up      2
+
+ROUTING KEY  LISTENER SITES  CONNECTOR SITES
+backend      west            east
+`,
+		},
+		{
+			name:           "no sites",
+			network:        network.ApplicationNetwork{},
+			expectedOutput: "The network status is not yet available\n",
+		},
+		{
+			name:       "json",
+			network:    network.NewApplicationNetwork([]v2alpha1.SiteRecord{{Id: "west-id", Name: "west"}}),
+			outputType: "json",
+			expectedOutput: `{
+  "sites": [
+    {
+      "id": "west-id",
+      "name": "west"
+    }
+  ]
+}
+`,
+		},
+		{
+			name:          "unsupported output",
+			network:       appNetwork,
+			outputType:    "xml",
+			expectedError: "format xml not supported",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			err := PrintApplicationNetwork(out, test.network, test.outputType)
+			if test.expectedError != "" {
+				assert.Error(t, err, test.expectedError)
+				return
+			}
+			assert.Assert(t, err)
+			assert.Equal(t, out.String(), test.expectedOutput)
+		})
+	}
+}
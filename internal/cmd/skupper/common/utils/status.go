@@ -33,3 +33,12 @@ func SiteLinkAccessEnabled(siteList *v2alpha1.SiteList, linkAccessTypes []string
 	}
 	return false, ""
 }
+
+// StatusType returns the status type of a resource, or "Not Ready"
+// when no status has been computed for it yet
+func StatusType(status v2alpha1.Status) string {
+	if status.StatusType == "" {
+		return "Not Ready"
+	}
+	return string(status.StatusType)
+}
@@ -157,7 +157,7 @@ func TestCmdConnectorCreate_ValidateInput(t *testing.T) {
 				Timeout:       1 * time.Minute,
 				Selector:      "backend",
 			},
-			expectedError: "connector type is not valid: value not-valid not allowed. It should be one of this options: [tcp]",
+			expectedError: "connector type is not valid: value not-valid not allowed. It should be one of this options: [tcp udp http http2]",
 		},
 		{
 			name: "routing key is not valid",
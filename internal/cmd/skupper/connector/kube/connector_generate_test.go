@@ -100,7 +100,7 @@ func TestCmdConnectorGenerate_ValidateInput(t *testing.T) {
 				ConnectorType: "not-valid",
 				Selector:      "backend",
 			},
-			expectedError: "connector type is not valid: value not-valid not allowed. It should be one of this options: [tcp]",
+			expectedError: "connector type is not valid: value not-valid not allowed. It should be one of this options: [tcp udp http http2]",
 		},
 		{
 			name: "routing key is not valid",
@@ -93,15 +93,12 @@ func (cmd *CmdConnectorStatus) Run() error {
 			}
 		} else {
 			tw := tabwriter.NewWriter(os.Stdout, 8, 8, 1, '\t', tabwriter.TabIndent)
-			_, _ = fmt.Fprintln(tw, fmt.Sprintf("%s\t%s\t%s\t%s\t%s",
-				"NAME", "STATUS", "ROUTING-KEY", "HOST", "PORT"))
+			_, _ = fmt.Fprintln(tw, fmt.Sprintf("%s\t%s\t%s\t%s\t%s\t%s\t%s",
+				"NAME", "STATUS", "ROUTING-KEY", "HOST", "PORT", "MATCHING-LISTENER", "MESSAGE"))
 			for _, connector := range connectors {
-				status := "Not Ready"
-				if connector.IsConfigured() {
-					status = "Ok"
-				}
-				fmt.Fprintln(tw, fmt.Sprintf("%s\t%s\t%s\t%s\t%d",
-					connector.Name, status, connector.Spec.RoutingKey, connector.Spec.Host, connector.Spec.Port))
+				fmt.Fprintln(tw, fmt.Sprintf("%s\t%s\t%s\t%s\t%d\t%t\t%s",
+					connector.Name, utils.StatusType(connector.Status.Status), connector.Spec.RoutingKey, connector.Spec.Host,
+					connector.Spec.Port, connector.Status.HasMatchingListener, connector.Status.Message))
 			}
 			_ = tw.Flush()
 		}
@@ -118,13 +115,10 @@ func (cmd *CmdConnectorStatus) Run() error {
 			}
 			fmt.Println(encodedOutput)
 		} else {
-			status := "Not Ready"
-			if connector.IsConfigured() {
-				status = "Ok"
-			}
 			tw := tabwriter.NewWriter(os.Stdout, 8, 8, 1, '\t', tabwriter.TabIndent)
-			fmt.Fprintln(tw, fmt.Sprintf("Name:\t%s\nStatus:\t%s\nRouting key:\t%s\nHost:\t%s\nPort:\t%d\nTlsCredentials:\t%s",
-				connector.Name, status, connector.Spec.RoutingKey, connector.Spec.Host, connector.Spec.Port, connector.Spec.TlsCredentials))
+			fmt.Fprintln(tw, fmt.Sprintf("Name:\t%s\nStatus:\t%s\nRouting key:\t%s\nHost:\t%s\nPort:\t%d\nTlsCredentials:\t%s\nHas Matching Listener:\t%t\nMessage:\t%s",
+				connector.Name, utils.StatusType(connector.Status.Status), connector.Spec.RoutingKey, connector.Spec.Host, connector.Spec.Port,
+				connector.Spec.TlsCredentials, connector.Status.HasMatchingListener, connector.Status.Message))
 			_ = tw.Flush()
 		}
 	}
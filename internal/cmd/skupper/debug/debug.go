@@ -18,6 +18,7 @@ func NewCmdDebug() *cobra.Command {
 	}
 	platform := common.Platform(config.GetPlatform())
 	cmd.AddCommand(CmdDebugDumpFactory(platform))
+	cmd.AddCommand(CmdDebugDegradedFactory(platform))
 
 	return cmd
 }
@@ -46,3 +47,29 @@ func CmdDebugDumpFactory(configuredPlatform common.Platform) *cobra.Command {
 	return cmd
 
 }
+
+func CmdDebugDegradedFactory(configuredPlatform common.Platform) *cobra.Command {
+	kubeCommand := kube.NewCmdDebugDegraded()
+	nonKubeCommand := nonkube.NewCmdDebugDegraded()
+
+	cmdDebugDegradedDesc := common.SkupperCmdDescription{
+		Use:   "degraded",
+		Short: "List resources the controller has repeatedly failed to process",
+		Long: `List resources in the namespace with a Degraded condition. The controller sets
+this condition when processing a change related to a resource still fails after
+repeated retries, and removes it once processing succeeds. Such changes are
+retried periodically; the message shows the last error encountered.`,
+		Example: "skupper debug degraded",
+	}
+
+	cmd := common.ConfigureCobraCommand(configuredPlatform, cmdDebugDegradedDesc, kubeCommand, nonKubeCommand)
+
+	cmdFlags := common.CommandDebugFlags{}
+
+	kubeCommand.CobraCmd = cmd
+	kubeCommand.Flags = &cmdFlags
+	nonKubeCommand.CobraCmd = cmd
+	nonKubeCommand.Flags = &cmdFlags
+
+	return cmd
+}
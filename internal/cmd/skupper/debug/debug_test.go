@@ -24,6 +24,11 @@ func TestCmdLinkFactory(t *testing.T) {
 			expectedFlagsWithDefaultValue: map[string]interface{}{},
 			command:                       CmdDebugDumpFactory(common.PlatformKubernetes),
 		},
+		{
+			name:                          "CmdDebugDegradedFactory",
+			expectedFlagsWithDefaultValue: map[string]interface{}{},
+			command:                       CmdDebugDegradedFactory(common.PlatformKubernetes),
+		},
 	}
 
 	for _, test := range testTable {
@@ -0,0 +1,195 @@
+package kube
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/skupperproject/skupper/internal/cmd/skupper/common"
+	"github.com/skupperproject/skupper/internal/cmd/skupper/common/utils"
+	"github.com/skupperproject/skupper/internal/kube/client"
+	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/generated/client/clientset/versioned/typed/skupper/v2alpha1"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type CmdDebugDegraded struct {
+	Client    skupperv2alpha1.SkupperV2alpha1Interface
+	CobraCmd  *cobra.Command
+	Flags     *common.CommandDebugFlags
+	Namespace string
+}
+
+func NewCmdDebugDegraded() *CmdDebugDegraded {
+
+	return &CmdDebugDegraded{}
+}
+
+func (cmd *CmdDebugDegraded) NewClient(cobraCommand *cobra.Command, args []string) {
+	cli, err := client.NewClient(cobraCommand.Flag("namespace").Value.String(), cobraCommand.Flag("context").Value.String(), cobraCommand.Flag("kubeconfig").Value.String())
+	utils.HandleError(utils.GenericError, err)
+
+	cmd.Client = cli.GetSkupperClient().SkupperV2alpha1()
+	cmd.Namespace = cli.Namespace
+}
+
+func (cmd *CmdDebugDegraded) ValidateInput(args []string) error {
+	var validationErrors []error
+
+	// Check if CRDs are installed
+	_, err := cmd.Client.Sites(cmd.Namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		validationErrors = append(validationErrors, utils.HandleMissingCrds(err))
+		return errors.Join(validationErrors...)
+	}
+
+	if len(args) > 0 {
+		validationErrors = append(validationErrors, fmt.Errorf("this command does not accept arguments"))
+	}
+
+	return errors.Join(validationErrors...)
+}
+
+func (cmd *CmdDebugDegraded) InputToOptions() {}
+
+func (cmd *CmdDebugDegraded) Run() error {
+	resources, err := cmd.listDegraded()
+	if err != nil {
+		return err
+	}
+	if len(resources) == 0 {
+		fmt.Println("There are no degraded resources in the namespace")
+		return nil
+	}
+	displayDegradedList(resources, time.Now())
+	return nil
+}
+
+func (cmd *CmdDebugDegraded) WaitUntil() error { return nil }
+
+type degradedResource struct {
+	Kind    string
+	Name    string
+	Since   metav1.Time
+	Message string
+}
+
+// listDegraded returns the resources in the namespace on which the
+// controller has recorded a Degraded condition, i.e. for which it has
+// repeatedly failed to process changes.
+func (cmd *CmdDebugDegraded) listDegraded() ([]degradedResource, error) {
+	var resources []degradedResource
+	check := func(kind string, name string, status v2alpha1.Status) {
+		condition := meta.FindStatusCondition(status.Conditions, v2alpha1.CONDITION_TYPE_DEGRADED)
+		if condition != nil && condition.Status == metav1.ConditionTrue {
+			resources = append(resources, degradedResource{
+				Kind:    kind,
+				Name:    name,
+				Since:   condition.LastTransitionTime,
+				Message: condition.Message,
+			})
+		}
+	}
+	ctx := context.TODO()
+	options := metav1.ListOptions{}
+
+	sites, err := cmd.Client.Sites(cmd.Namespace).List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range sites.Items {
+		check("Site", item.Name, item.Status.Status)
+	}
+	listeners, err := cmd.Client.Listeners(cmd.Namespace).List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range listeners.Items {
+		check("Listener", item.Name, item.Status.Status)
+	}
+	connectors, err := cmd.Client.Connectors(cmd.Namespace).List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range connectors.Items {
+		check("Connector", item.Name, item.Status.Status)
+	}
+	links, err := cmd.Client.Links(cmd.Namespace).List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range links.Items {
+		check("Link", item.Name, item.Status.Status)
+	}
+	tokens, err := cmd.Client.AccessTokens(cmd.Namespace).List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range tokens.Items {
+		check("AccessToken", item.Name, item.Status.Status)
+	}
+	grants, err := cmd.Client.AccessGrants(cmd.Namespace).List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range grants.Items {
+		check("AccessGrant", item.Name, item.Status.Status)
+	}
+	securedAccesses, err := cmd.Client.SecuredAccesses(cmd.Namespace).List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range securedAccesses.Items {
+		check("SecuredAccess", item.Name, item.Status.Status)
+	}
+	certificates, err := cmd.Client.Certificates(cmd.Namespace).List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range certificates.Items {
+		check("Certificate", item.Name, item.Status.Status)
+	}
+	routerAccesses, err := cmd.Client.RouterAccesses(cmd.Namespace).List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range routerAccesses.Items {
+		check("RouterAccess", item.Name, item.Status.Status)
+	}
+	attachedConnectors, err := cmd.Client.AttachedConnectors(cmd.Namespace).List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range attachedConnectors.Items {
+		check("AttachedConnector", item.Name, item.Status.Status)
+	}
+	bindings, err := cmd.Client.AttachedConnectorBindings(cmd.Namespace).List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range bindings.Items {
+		check("AttachedConnectorBinding", item.Name, item.Status.Status)
+	}
+	return resources, nil
+}
+
+func displayDegradedList(resources []degradedResource, now time.Time) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', tabwriter.AlignRight)
+	fmt.Fprintln(writer, "KIND\tNAME\tSINCE\tMESSAGE")
+
+	for _, resource := range resources {
+		since := ""
+		if !resource.Since.IsZero() {
+			since = now.Sub(resource.Since.Time).Truncate(time.Second).String()
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s", resource.Kind, resource.Name, since, resource.Message)
+		fmt.Fprintln(writer)
+	}
+
+	writer.Flush()
+}
@@ -0,0 +1,151 @@
+package kube
+
+import (
+	"testing"
+
+	"github.com/skupperproject/skupper/internal/cmd/skupper/common"
+	"github.com/skupperproject/skupper/internal/cmd/skupper/common/testutils"
+	"github.com/skupperproject/skupper/internal/cmd/skupper/common/utils"
+	fakeclient "github.com/skupperproject/skupper/internal/kube/client/fake"
+	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+	"gotest.tools/v3/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestCmdDebugDegraded_ValidateInput(t *testing.T) {
+	type test struct {
+		name                string
+		args                []string
+		skupperErrorMessage string
+		expectedError       string
+	}
+
+	testTable := []test{
+		{
+			name:                "missing CRD",
+			skupperErrorMessage: utils.CrdErr,
+			expectedError:       utils.CrdHelpErr,
+		},
+		{
+			name:          "arguments are not accepted",
+			args:          []string{"my-site"},
+			expectedError: "this command does not accept arguments",
+		},
+		{
+			name: "ok",
+		},
+	}
+
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			command, err := newCmdDebugDegradedWithMocks("test", nil, nil, test.skupperErrorMessage)
+			assert.Assert(t, err)
+			command.Flags = &common.CommandDebugFlags{}
+
+			testutils.CheckValidateInput(t, command, test.expectedError, test.args)
+		})
+	}
+}
+
+func TestCmdDebugDegraded_Run(t *testing.T) {
+	degraded := func(message string) v2alpha1.Status {
+		return v2alpha1.Status{
+			Conditions: []v1.Condition{
+				{
+					Type:    v2alpha1.CONDITION_TYPE_DEGRADED,
+					Status:  v1.ConditionTrue,
+					Reason:  "RetriesExhausted",
+					Message: message,
+				},
+			},
+		}
+	}
+	type test struct {
+		name                string
+		skupperObjects      []runtime.Object
+		skupperErrorMessage string
+		expected            []degradedResource
+		errorMessage        string
+	}
+
+	testTable := []test{
+		{
+			name: "lists degraded resources",
+			skupperObjects: []runtime.Object{
+				&v2alpha1.Site{
+					ObjectMeta: v1.ObjectMeta{Name: "my-site", Namespace: "test"},
+					Status:     v2alpha1.SiteStatus{Status: degraded("Failed to process ConfigMap test/skupper-router: broken")},
+				},
+				&v2alpha1.Listener{
+					ObjectMeta: v1.ObjectMeta{Name: "healthy", Namespace: "test"},
+				},
+				&v2alpha1.Connector{
+					ObjectMeta: v1.ObjectMeta{Name: "backend", Namespace: "test"},
+					Status:     v2alpha1.ConnectorStatus{Status: degraded("Failed to process Connector test/backend: broken")},
+				},
+				&v2alpha1.Connector{
+					ObjectMeta: v1.ObjectMeta{Name: "elsewhere", Namespace: "other"},
+					Status:     v2alpha1.ConnectorStatus{Status: degraded("Failed to process Connector other/elsewhere: broken")},
+				},
+			},
+			expected: []degradedResource{
+				{
+					Kind:    "Site",
+					Name:    "my-site",
+					Message: "Failed to process ConfigMap test/skupper-router: broken",
+				},
+				{
+					Kind:    "Connector",
+					Name:    "backend",
+					Message: "Failed to process Connector test/backend: broken",
+				},
+			},
+		},
+		{
+			name: "no degraded resources",
+			skupperObjects: []runtime.Object{
+				&v2alpha1.Site{
+					ObjectMeta: v1.ObjectMeta{Name: "my-site", Namespace: "test"},
+				},
+			},
+		},
+		{
+			name:                "list fails",
+			skupperErrorMessage: "error",
+			errorMessage:        "error",
+		},
+	}
+
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			cmd, err := newCmdDebugDegradedWithMocks("test", nil, test.skupperObjects, test.skupperErrorMessage)
+			assert.Assert(t, err)
+
+			resources, err := cmd.listDegraded()
+			if test.errorMessage != "" {
+				assert.Error(t, err, test.errorMessage)
+				return
+			}
+			assert.Assert(t, err)
+			assert.DeepEqual(t, resources, test.expected)
+			assert.Assert(t, cmd.Run())
+		})
+	}
+}
+
+// --- helper methods
+
+func newCmdDebugDegradedWithMocks(namespace string, k8sObjects []runtime.Object, skupperObjects []runtime.Object, fakeSkupperError string) (*CmdDebugDegraded, error) {
+
+	client, err := fakeclient.NewFakeClient(namespace, k8sObjects, skupperObjects, fakeSkupperError)
+	if err != nil {
+		return nil, err
+	}
+	cmdDebugDegraded := &CmdDebugDegraded{
+		Client:    client.GetSkupperClient().SkupperV2alpha1(),
+		Namespace: namespace,
+	}
+
+	return cmdDebugDegraded, nil
+}
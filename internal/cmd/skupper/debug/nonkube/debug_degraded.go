@@ -0,0 +1,32 @@
+package nonkube
+
+import (
+	"fmt"
+
+	"github.com/skupperproject/skupper/internal/cmd/skupper/common"
+	"github.com/spf13/cobra"
+)
+
+type CmdDebugDegraded struct {
+	CobraCmd  *cobra.Command
+	Flags     *common.CommandDebugFlags
+	Namespace string
+}
+
+func NewCmdDebugDegraded() *CmdDebugDegraded {
+
+	return &CmdDebugDegraded{}
+}
+
+func (cmd *CmdDebugDegraded) NewClient(cobraCommand *cobra.Command, args []string) {}
+
+func (cmd *CmdDebugDegraded) ValidateInput(args []string) error { return nil }
+
+func (cmd *CmdDebugDegraded) InputToOptions() {}
+
+func (cmd *CmdDebugDegraded) Run() error {
+	fmt.Println("This command does not support non-kubernetes platforms.")
+	return nil
+}
+
+func (cmd *CmdDebugDegraded) WaitUntil() error { return nil }
@@ -6,11 +6,11 @@ package nonkube
 import (
 	"errors"
 	"fmt"
-	"os"
 
 	"github.com/skupperproject/skupper/internal/cmd/skupper/common"
 	"github.com/skupperproject/skupper/internal/nonkube/client/fs"
 	nonkubecommon "github.com/skupperproject/skupper/internal/nonkube/common"
+	"github.com/skupperproject/skupper/internal/nonkube/secrets"
 	"github.com/skupperproject/skupper/pkg/nonkube/api"
 	"github.com/spf13/cobra"
 )
@@ -94,8 +94,12 @@ func (cmd *CmdLinkGenerate) Run() error {
 		return nil
 	}
 
+	store, err := secrets.StoreForNamespace(cmd.siteState.Site.Namespace)
+	if err != nil {
+		return err
+	}
 	for _, tokenFile := range tokenFiles {
-		file, errFile := os.ReadFile(hostTokenPath + "/" + tokenFile)
+		file, errFile := secrets.ReadFile(store, hostTokenPath+"/"+tokenFile)
 		if errFile != nil {
 			return fmt.Errorf("error reading file %s: %s", hostTokenPath+"/"+tokenFile, errFile)
 		}
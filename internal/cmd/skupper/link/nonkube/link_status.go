@@ -7,6 +7,7 @@ import (
 	"text/tabwriter"
 
 	"github.com/skupperproject/skupper/internal/cmd/skupper/common"
+	"github.com/skupperproject/skupper/internal/cmd/skupper/common/utils"
 	"github.com/skupperproject/skupper/internal/nonkube/client/fs"
 	"github.com/skupperproject/skupper/internal/utils/validator"
 	"github.com/spf13/cobra"
@@ -65,25 +66,16 @@ func (cmd *CmdLinkStatus) Run() error {
 	}
 	if cmd.linkName == "" {
 		tw := tabwriter.NewWriter(os.Stdout, 8, 8, 1, '\t', tabwriter.TabIndent)
-		_, _ = fmt.Fprintln(tw, fmt.Sprintf("%s\t%s",
-			"NAME", "STATUS"))
+		_, _ = fmt.Fprintln(tw, fmt.Sprintf("%s\t%s\t%s\t%s\t%s",
+			"NAME", "STATUS", "REMOTE-SITE", "COST", "MESSAGE"))
 		for _, link := range links {
-			status := "Not Ready"
-			if link.IsConfigured() {
-				status = "Ok"
-			}
-			fmt.Fprintln(tw, fmt.Sprintf("%s\t%s",
-				link.Name, status))
+			fmt.Fprintln(tw, fmt.Sprintf("%s\t%s\t%s\t%d\t%s",
+				link.Name, utils.StatusType(link.Status.Status), link.Status.RemoteSiteName, link.Spec.Cost, link.Status.Message))
 		}
 		_ = tw.Flush()
 	} else {
 		for _, link := range links {
 			if link.Name == cmd.linkName {
-				status := "Not Ready"
-				if link.IsConfigured() {
-					status = "Ok"
-				}
-
 				// get the site and determine role of router, default to interRouter
 				endpointName := ""
 				endPointType := common.InterRouterRole
@@ -100,8 +92,8 @@ func (cmd *CmdLinkStatus) Run() error {
 				}
 
 				tw := tabwriter.NewWriter(os.Stdout, 8, 8, 1, '\t', tabwriter.TabIndent)
-				fmt.Fprintln(tw, fmt.Sprintf("Name:\t%s\nStatus:\t%s\nCost:\t%d\nTlsCredentials:\t%s\nEndpoint:\t%s\n",
-					link.Name, status, link.Spec.Cost, link.Spec.TlsCredentials, endpointName))
+				fmt.Fprintln(tw, fmt.Sprintf("Name:\t%s\nStatus:\t%s\nRemote site:\t%s\nCost:\t%d\nTlsCredentials:\t%s\nEndpoint:\t%s\nMessage:\t%s\n",
+					link.Name, utils.StatusType(link.Status.Status), link.Status.RemoteSiteName, link.Spec.Cost, link.Spec.TlsCredentials, endpointName, link.Status.Message))
 				_ = tw.Flush()
 			}
 		}
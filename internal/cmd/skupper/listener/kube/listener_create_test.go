@@ -119,7 +119,7 @@ func TestCmdListenerCreate_ValidateInput(t *testing.T) {
 				Timeout:      1 * time.Minute,
 				ListenerType: "not-valid",
 			},
-			expectedError: "listener type is not valid: value not-valid not allowed. It should be one of this options: [tcp]",
+			expectedError: "listener type is not valid: value not-valid not allowed. It should be one of this options: [tcp udp http http2]",
 		},
 		{
 			name: "routing key is not valid",
@@ -93,15 +93,12 @@ func (cmd *CmdListenerStatus) Run() error {
 			}
 		} else {
 			tw := tabwriter.NewWriter(os.Stdout, 8, 8, 1, '\t', tabwriter.TabIndent)
-			_, _ = fmt.Fprintln(tw, fmt.Sprintf("%s\t%s\t%s\t%s\t%s",
-				"NAME", "STATUS", "ROUTING-KEY", "HOST", "PORT"))
+			_, _ = fmt.Fprintln(tw, fmt.Sprintf("%s\t%s\t%s\t%s\t%s\t%s\t%s",
+				"NAME", "STATUS", "ROUTING-KEY", "HOST", "PORT", "MATCHING-CONNECTOR", "MESSAGE"))
 			for _, listener := range listeners {
-				status := "Not Ready"
-				if listener.IsConfigured() {
-					status = "Ok"
-				}
-				fmt.Fprintln(tw, fmt.Sprintf("%s\t%s\t%s\t%s\t%d",
-					listener.Name, status, listener.Spec.RoutingKey, listener.Spec.Host, listener.Spec.Port))
+				fmt.Fprintln(tw, fmt.Sprintf("%s\t%s\t%s\t%s\t%d\t%t\t%s",
+					listener.Name, utils.StatusType(listener.Status.Status), listener.Spec.RoutingKey, listener.Spec.Host,
+					listener.Spec.Port, listener.Status.HasMatchingConnector, listener.Status.Message))
 			}
 			_ = tw.Flush()
 		}
@@ -118,13 +115,10 @@ func (cmd *CmdListenerStatus) Run() error {
 			}
 			fmt.Println(encodedOutput)
 		} else {
-			status := "Not Ready"
-			if listener.IsConfigured() {
-				status = "Ok"
-			}
 			tw := tabwriter.NewWriter(os.Stdout, 8, 8, 1, '\t', tabwriter.TabIndent)
-			fmt.Fprintln(tw, fmt.Sprintf("Name:\t%s\nStatus:\t%s\nRouting key:\t%s\nHost:\t%s\nPort:\t%d\nTlsCredentials:\t%s\n",
-				listener.Name, status, listener.Spec.RoutingKey, listener.Spec.Host, listener.Spec.Port, listener.Spec.TlsCredentials))
+			fmt.Fprintln(tw, fmt.Sprintf("Name:\t%s\nStatus:\t%s\nRouting key:\t%s\nHost:\t%s\nPort:\t%d\nTlsCredentials:\t%s\nHas Matching Connector:\t%t\nMessage:\t%s\n",
+				listener.Name, utils.StatusType(listener.Status.Status), listener.Spec.RoutingKey, listener.Spec.Host, listener.Spec.Port,
+				listener.Spec.TlsCredentials, listener.Status.HasMatchingConnector, listener.Status.Message))
 			_ = tw.Flush()
 		}
 	}
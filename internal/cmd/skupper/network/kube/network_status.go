@@ -0,0 +1,88 @@
+package kube
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/skupperproject/skupper/internal/cmd/skupper/common"
+	"github.com/skupperproject/skupper/internal/cmd/skupper/common/utils"
+	"github.com/skupperproject/skupper/internal/kube/client"
+	"github.com/skupperproject/skupper/internal/network"
+	"github.com/skupperproject/skupper/internal/utils/validator"
+	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/generated/client/clientset/versioned/typed/skupper/v2alpha1"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type CmdNetworkStatus struct {
+	Client    skupperv2alpha1.SkupperV2alpha1Interface
+	CobraCmd  *cobra.Command
+	Flags     *common.CommandNetworkStatusFlags
+	Namespace string
+	output    string
+}
+
+func NewCmdNetworkStatus() *CmdNetworkStatus {
+
+	return &CmdNetworkStatus{}
+}
+
+func (cmd *CmdNetworkStatus) NewClient(cobraCommand *cobra.Command, args []string) {
+	cli, err := client.NewClient(cobraCommand.Flag("namespace").Value.String(), cobraCommand.Flag("context").Value.String(), cobraCommand.Flag("kubeconfig").Value.String())
+	utils.HandleError(utils.GenericError, err)
+
+	cmd.Client = cli.GetSkupperClient().SkupperV2alpha1()
+	cmd.Namespace = cli.Namespace
+}
+
+func (cmd *CmdNetworkStatus) ValidateInput(args []string) error {
+	var validationErrors []error
+	outputTypeValidator := validator.NewOptionValidator(common.OutputTypes)
+
+	if len(args) > 0 {
+		validationErrors = append(validationErrors, fmt.Errorf("this command does not need any arguments"))
+	}
+
+	if cmd.Flags != nil && cmd.Flags.Output != "" {
+		ok, err := outputTypeValidator.Evaluate(cmd.Flags.Output)
+		if !ok {
+			validationErrors = append(validationErrors, fmt.Errorf("output type is not valid: %s", err))
+		}
+	}
+
+	return errors.Join(validationErrors...)
+}
+
+func (cmd *CmdNetworkStatus) InputToOptions() {
+	if cmd.Flags != nil {
+		cmd.output = cmd.Flags.Output
+	}
+}
+
+func (cmd *CmdNetworkStatus) Run() error {
+
+	siteList, err := cmd.Client.Sites(cmd.Namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return utils.HandleMissingCrds(err)
+	}
+
+	if siteList == nil || len(siteList.Items) == 0 {
+		fmt.Println("There is no existing Skupper site resource")
+		return nil
+	}
+
+	// every site records the whole network, so any active one will do
+	site := siteList.Items[0]
+	for _, candidate := range siteList.Items {
+		if len(candidate.Status.Network) > 0 {
+			site = candidate
+			break
+		}
+	}
+
+	return utils.PrintApplicationNetwork(os.Stdout, network.NewApplicationNetwork(site.Status.Network), cmd.output)
+}
+
+func (cmd *CmdNetworkStatus) WaitUntil() error { return nil }
@@ -0,0 +1,139 @@
+package kube
+
+import (
+	"testing"
+
+	"github.com/skupperproject/skupper/internal/cmd/skupper/common"
+	"github.com/skupperproject/skupper/internal/cmd/skupper/common/testutils"
+	"github.com/skupperproject/skupper/internal/cmd/skupper/common/utils"
+	fakeclient "github.com/skupperproject/skupper/internal/kube/client/fake"
+	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+	"gotest.tools/v3/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestCmdNetworkStatus_ValidateInput(t *testing.T) {
+	type test struct {
+		name          string
+		args          []string
+		flags         *common.CommandNetworkStatusFlags
+		expectedError string
+	}
+
+	testTable := []test{
+		{
+			name:          "arguments were specified",
+			args:          []string{"my-site"},
+			flags:         &common.CommandNetworkStatusFlags{},
+			expectedError: "this command does not need any arguments",
+		},
+		{
+			name:          "bad output type",
+			flags:         &common.CommandNetworkStatusFlags{Output: "not-supported"},
+			expectedError: "output type is not valid: value not-supported not allowed. It should be one of this options: [json yaml]",
+		},
+		{
+			name:  "ok",
+			flags: &common.CommandNetworkStatusFlags{Output: "yaml"},
+		},
+	}
+
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			command := &CmdNetworkStatus{
+				Namespace: "test",
+				Flags:     test.flags,
+			}
+
+			fakeSkupperClient, err := fakeclient.NewFakeClient(command.Namespace, nil, nil, "")
+			assert.Assert(t, err)
+			command.Client = fakeSkupperClient.GetSkupperClient().SkupperV2alpha1()
+
+			testutils.CheckValidateInput(t, command, test.expectedError, test.args)
+		})
+	}
+}
+
+func TestCmdNetworkStatus_Run(t *testing.T) {
+	type test struct {
+		name           string
+		skupperObjects []runtime.Object
+		skupperError   string
+		output         string
+		errorMessage   string
+	}
+
+	site := &v2alpha1.Site{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "west",
+			Namespace: "test",
+		},
+		Status: v2alpha1.SiteStatus{
+			Network: []v2alpha1.SiteRecord{
+				{
+					Id:   "west-id",
+					Name: "west",
+					Links: []v2alpha1.LinkRecord{
+						{Name: "west-to-east", RemoteSiteId: "east-id", RemoteSiteName: "east", Operational: true, Cost: 1},
+					},
+					Services: []v2alpha1.ServiceRecord{
+						{RoutingKey: "backend", Listeners: []string{"backend"}},
+					},
+				},
+				{
+					Id:   "east-id",
+					Name: "east",
+					Services: []v2alpha1.ServiceRecord{
+						{RoutingKey: "backend", Connectors: []string{"10.0.0.1"}},
+					},
+				},
+			},
+		},
+	}
+
+	testTable := []test{
+		{
+			name:         "missing CRD",
+			skupperError: utils.CrdErr,
+			errorMessage: utils.CrdHelpErr,
+		},
+		{
+			name:         "run fails",
+			skupperError: "error",
+			errorMessage: "error",
+		},
+		{
+			name: "there is no existing skupper site",
+		},
+		{
+			name:           "runs ok",
+			skupperObjects: []runtime.Object{site},
+		},
+		{
+			name:           "runs ok with yaml output",
+			skupperObjects: []runtime.Object{site},
+			output:         "yaml",
+		},
+	}
+
+	for _, test := range testTable {
+		command := &CmdNetworkStatus{
+			Namespace: "test",
+			output:    test.output,
+		}
+
+		fakeSkupperClient, err := fakeclient.NewFakeClient(command.Namespace, nil, test.skupperObjects, test.skupperError)
+		assert.Assert(t, err)
+		command.Client = fakeSkupperClient.GetSkupperClient().SkupperV2alpha1()
+
+		t.Run(test.name, func(t *testing.T) {
+			err := command.Run()
+			if test.errorMessage != "" {
+				assert.Error(t, err, test.errorMessage)
+			} else {
+				assert.Assert(t, err)
+			}
+		})
+	}
+}
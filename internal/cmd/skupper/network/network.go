@@ -0,0 +1,49 @@
+package network
+
+import (
+	"github.com/skupperproject/skupper/internal/cmd/skupper/common"
+	"github.com/skupperproject/skupper/internal/cmd/skupper/network/kube"
+	"github.com/skupperproject/skupper/internal/cmd/skupper/network/nonkube"
+	"github.com/skupperproject/skupper/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdNetwork() *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "network",
+		Short: "An application network is a set of linked sites",
+		Long:  `An application network is a set of linked sites. Links carry application connections and requests between listeners and connectors in different sites.`,
+		Example: `skupper network status
+skupper network status -o yaml`,
+	}
+	platform := common.Platform(config.GetPlatform())
+	cmd.AddCommand(CmdNetworkStatusFactory(platform))
+
+	return cmd
+}
+
+func CmdNetworkStatusFactory(configuredPlatform common.Platform) *cobra.Command {
+	kubeCommand := kube.NewCmdNetworkStatus()
+	nonKubeCommand := nonkube.NewCmdNetworkStatus()
+
+	cmdNetworkStatusDesc := common.SkupperCmdDescription{
+		Use:   "status",
+		Short: "Display the status of the application network",
+		Long: `Display the sites in the application network, the links between them with their
+operational state and cost, and the sites with listeners and connectors for each routing key.`,
+		Example: "skupper network status -o json",
+	}
+
+	cmd := common.ConfigureCobraCommand(configuredPlatform, cmdNetworkStatusDesc, kubeCommand, nonKubeCommand)
+
+	cmdFlags := common.CommandNetworkStatusFlags{}
+	cmd.Flags().StringVarP(&cmdFlags.Output, common.FlagNameOutput, "o", "", common.FlagDescNetworkStatusOutput)
+
+	kubeCommand.CobraCmd = cmd
+	kubeCommand.Flags = &cmdFlags
+	nonKubeCommand.CobraCmd = cmd
+	nonKubeCommand.Flags = &cmdFlags
+
+	return cmd
+}
@@ -0,0 +1,136 @@
+package nonkube
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/skupperproject/skupper/internal/cmd/skupper/common"
+	"github.com/skupperproject/skupper/internal/cmd/skupper/common/testutils"
+	"github.com/skupperproject/skupper/internal/nonkube/client/fs"
+	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+	"github.com/skupperproject/skupper/pkg/nonkube/api"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCmdNetworkStatus_ValidateInput(t *testing.T) {
+	type test struct {
+		name          string
+		args          []string
+		flags         *common.CommandNetworkStatusFlags
+		expectedError string
+	}
+
+	testTable := []test{
+		{
+			name:          "arguments were specified",
+			args:          []string{"my-site"},
+			expectedError: "this command does not need any arguments",
+		},
+		{
+			name:          "bad output",
+			flags:         &common.CommandNetworkStatusFlags{Output: "yaml$"},
+			expectedError: "output type is not valid: value yaml$ not allowed. It should be one of this options: [json yaml]",
+		},
+		{
+			name:  "good flags",
+			flags: &common.CommandNetworkStatusFlags{Output: "json"},
+		},
+	}
+
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			command := &CmdNetworkStatus{Flags: test.flags}
+			testutils.CheckValidateInput(t, command, test.expectedError, test.args)
+		})
+	}
+}
+
+func TestCmdNetworkStatus_Run(t *testing.T) {
+	type test struct {
+		name         string
+		namespace    string
+		output       string
+		errorMessage string
+	}
+
+	if os.Getuid() == 0 {
+		api.DefaultRootDataHome = t.TempDir()
+	} else {
+		t.Setenv("XDG_DATA_HOME", t.TempDir())
+	}
+	tmpDir := api.GetDataHome()
+	path := filepath.Join(tmpDir, "/namespaces/no-site/", string(api.InputSiteStatePath))
+
+	testTable := []test{
+		{
+			name:         "runs fails no site",
+			namespace:    "no-site",
+			errorMessage: "failed to read directory: open " + path + ": no such file or directory",
+		},
+		{
+			name:      "runs ok",
+			namespace: "test",
+		},
+		{
+			name:      "runs ok yaml",
+			namespace: "test",
+			output:    "yaml",
+		},
+	}
+
+	// add site with network status in runtime directory
+	siteResource := v2alpha1.Site{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "skupper.io/v2alpha1",
+			Kind:       "Site",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-site",
+			Namespace: "test",
+		},
+		Status: v2alpha1.SiteStatus{
+			Network: []v2alpha1.SiteRecord{
+				{
+					Id:   "my-site-id",
+					Name: "my-site",
+					Links: []v2alpha1.LinkRecord{
+						{Name: "my-link", RemoteSiteId: "other-id", RemoteSiteName: "other-site", Operational: true, Cost: 1},
+					},
+					Services: []v2alpha1.ServiceRecord{
+						{RoutingKey: "backend", Connectors: []string{"127.0.0.1"}},
+					},
+				},
+				{
+					Id:   "other-id",
+					Name: "other-site",
+					Services: []v2alpha1.ServiceRecord{
+						{RoutingKey: "backend", Listeners: []string{"backend"}},
+					},
+				},
+			},
+		},
+	}
+	siteHandler := fs.NewSiteHandler("test")
+	content, err := siteHandler.EncodeToYaml(siteResource)
+	assert.Assert(t, err)
+	err = siteHandler.WriteFile(filepath.Join(tmpDir, "/namespaces/test/", string(api.RuntimeSiteStatePath)), "my-site.yaml", content, common.Sites)
+	assert.Assert(t, err)
+
+	for _, test := range testTable {
+		command := &CmdNetworkStatus{}
+		command.namespace = test.namespace
+		command.siteHandler = fs.NewSiteHandler(command.namespace)
+		command.output = test.output
+
+		t.Run(test.name, func(t *testing.T) {
+			err := command.Run()
+			if test.errorMessage != "" {
+				assert.Error(t, err, test.errorMessage)
+			} else {
+				assert.Assert(t, err)
+			}
+		})
+	}
+}
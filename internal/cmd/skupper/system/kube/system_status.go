@@ -0,0 +1,38 @@
+package kube
+
+import (
+	"fmt"
+
+	"github.com/skupperproject/skupper/internal/cmd/skupper/common"
+	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/generated/client/clientset/versioned/typed/skupper/v2alpha1"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+)
+
+type CmdSystemStatus struct {
+	Client     skupperv2alpha1.SkupperV2alpha1Interface
+	KubeClient kubernetes.Interface
+	CobraCmd   *cobra.Command
+	Flags      *common.CommandSystemStatusFlags
+	Namespace  string
+}
+
+func NewCmdSystemStatus() *CmdSystemStatus {
+
+	skupperCmd := CmdSystemStatus{}
+
+	return &skupperCmd
+}
+
+func (cmd *CmdSystemStatus) NewClient(cobraCommand *cobra.Command, args []string) {}
+
+func (cmd *CmdSystemStatus) ValidateInput(args []string) error { return nil }
+
+func (cmd *CmdSystemStatus) InputToOptions() {}
+
+func (cmd *CmdSystemStatus) Run() error {
+	fmt.Println("This command does not support kubernetes platforms.")
+	return nil
+}
+
+func (cmd *CmdSystemStatus) WaitUntil() error { return nil }
@@ -0,0 +1,36 @@
+package kube
+
+import (
+	"fmt"
+
+	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/generated/client/clientset/versioned/typed/skupper/v2alpha1"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+)
+
+type CmdSystemUnseal struct {
+	Client     skupperv2alpha1.SkupperV2alpha1Interface
+	KubeClient kubernetes.Interface
+	CobraCmd   *cobra.Command
+	Namespace  string
+}
+
+func NewCmdSystemUnseal() *CmdSystemUnseal {
+
+	skupperCmd := CmdSystemUnseal{}
+
+	return &skupperCmd
+}
+
+func (cmd *CmdSystemUnseal) NewClient(cobraCommand *cobra.Command, args []string) {}
+
+func (cmd *CmdSystemUnseal) ValidateInput(args []string) error { return nil }
+
+func (cmd *CmdSystemUnseal) InputToOptions() {}
+
+func (cmd *CmdSystemUnseal) Run() error {
+	fmt.Println("This command does not support kubernetes platforms.")
+	return nil
+}
+
+func (cmd *CmdSystemUnseal) WaitUntil() error { return nil }
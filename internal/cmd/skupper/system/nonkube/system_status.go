@@ -0,0 +1,456 @@
+package nonkube
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/skupperproject/skupper/internal/cmd/skupper/common"
+	"github.com/skupperproject/skupper/internal/cmd/skupper/common/utils"
+	internalclient "github.com/skupperproject/skupper/internal/nonkube/client/compat"
+	"github.com/skupperproject/skupper/internal/nonkube/client/runtime"
+	nonkubecommon "github.com/skupperproject/skupper/internal/nonkube/common"
+	"github.com/skupperproject/skupper/internal/utils/validator"
+	"github.com/skupperproject/skupper/pkg/container"
+	"github.com/skupperproject/skupper/pkg/nonkube/api"
+	"github.com/spf13/cobra"
+)
+
+const (
+	CheckStatusOK      = "OK"
+	CheckStatusWarning = "Warning"
+	CheckStatusError   = "Error"
+
+	SystemStatusHealthy   = "Healthy"
+	SystemStatusDegraded  = "Degraded"
+	SystemStatusUnhealthy = "Unhealthy"
+
+	// heartbeatStaleAfter is how long the router heartbeat recorded by
+	// the system controller remains valid; the controller refreshes it
+	// at least every 10 seconds while running
+	heartbeatStaleAfter = 30 * time.Second
+	// certificateExpiryWarning is how long before their expiry
+	// certificates are reported
+	certificateExpiryWarning = 30 * 24 * time.Hour
+)
+
+// SystemStatus is the health report of a non-kubernetes site
+type SystemStatus struct {
+	Namespace string              `json:"namespace"`
+	Platform  string              `json:"platform"`
+	Status    string              `json:"status"`
+	Checks    []SystemStatusCheck `json:"checks"`
+}
+
+type SystemStatusCheck struct {
+	Name    string     `json:"name"`
+	Status  string     `json:"status"`
+	Message string     `json:"message"`
+	Time    *time.Time `json:"time,omitempty"`
+}
+
+type CmdSystemStatus struct {
+	CobraCmd        *cobra.Command
+	Flags           *common.CommandSystemStatusFlags
+	Namespace       string
+	output          string
+	ServiceState    func(siteState *api.SiteState, platform string) (string, error)
+	RouterContainer func(platform string, name string) (*container.Container, error)
+	RouterListening func(namespace string) (string, error)
+	Now             func() time.Time
+}
+
+func NewCmdSystemStatus() *CmdSystemStatus {
+
+	skupperCmd := CmdSystemStatus{}
+
+	return &skupperCmd
+}
+
+func (cmd *CmdSystemStatus) NewClient(cobraCommand *cobra.Command, args []string) {
+	cmd.ServiceState = systemdServiceState
+	cmd.RouterContainer = inspectRouterContainer
+	cmd.RouterListening = dialLocalRouter
+	cmd.Now = time.Now
+	if cobraCommand.Flag(common.FlagNameNamespace) != nil {
+		cmd.Namespace = cobraCommand.Flag(common.FlagNameNamespace).Value.String()
+	}
+}
+
+func (cmd *CmdSystemStatus) ValidateInput(args []string) error {
+	var validationErrors []error
+	outputTypeValidator := validator.NewOptionValidator(common.OutputTypes)
+
+	if len(args) > 0 {
+		validationErrors = append(validationErrors, fmt.Errorf("this command does not accept arguments"))
+	}
+
+	if cmd.Flags != nil && cmd.Flags.Output != "" {
+		ok, err := outputTypeValidator.Evaluate(cmd.Flags.Output)
+		if !ok {
+			validationErrors = append(validationErrors, fmt.Errorf("output type is not valid: %s", err))
+		}
+	}
+
+	return errors.Join(validationErrors...)
+}
+
+func (cmd *CmdSystemStatus) InputToOptions() {
+	if cmd.Namespace == "" {
+		cmd.Namespace = "default"
+	}
+	if cmd.Flags != nil {
+		cmd.output = cmd.Flags.Output
+	}
+}
+
+func (cmd *CmdSystemStatus) Run() error {
+	status, err := cmd.status()
+	if err != nil {
+		return err
+	}
+
+	if cmd.output != "" {
+		encodedOutput, err := utils.Encode(cmd.output, status)
+		if err != nil {
+			return err
+		}
+		fmt.Println(encodedOutput)
+	} else {
+		fmt.Printf("Namespace: %s\n", status.Namespace)
+		fmt.Printf("Platform:  %s\n", status.Platform)
+		fmt.Printf("Status:    %s\n", status.Status)
+		writer := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', tabwriter.AlignRight)
+		fmt.Fprintln(writer, "CHECK\tSTATUS\tMESSAGE")
+		for _, check := range status.Checks {
+			fmt.Fprintf(writer, "%s\t%s\t%s", check.Name, check.Status, check.Message)
+			fmt.Fprintln(writer)
+		}
+		if err = writer.Flush(); err != nil {
+			return err
+		}
+	}
+	// an unhealthy site results in a non-zero exit code, so that the
+	// command can be used as a health check
+	if status.Status == SystemStatusUnhealthy {
+		return fmt.Errorf("site in namespace %q is unhealthy", status.Namespace)
+	}
+	return nil
+}
+
+func (cmd *CmdSystemStatus) WaitUntil() error { return nil }
+
+func (cmd *CmdSystemStatus) status() (*SystemStatus, error) {
+	runtimeSiteStateLoader := &nonkubecommon.FileSystemSiteStateLoader{
+		Path: api.GetInternalOutputPath(cmd.Namespace, api.RuntimeSiteStatePath),
+	}
+	siteState, err := runtimeSiteStateLoader.Load()
+	if err != nil {
+		return nil, fmt.Errorf("no site found in namespace %q: %w", cmd.Namespace, err)
+	}
+	platformLoader := &nonkubecommon.NamespacePlatformLoader{}
+	platform, err := platformLoader.Load(cmd.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &SystemStatus{
+		Namespace: cmd.Namespace,
+		Platform:  platform,
+	}
+	status.Checks = append(status.Checks, cmd.checkService(siteState, platform))
+	status.Checks = append(status.Checks, cmd.checkRouter(platform))
+	status.Checks = append(status.Checks, cmd.checkController())
+	status.Checks = append(status.Checks, cmd.checkCertificates()...)
+	status.Checks = append(status.Checks, cmd.checkDrift())
+	status.Checks = append(status.Checks, cmd.checkNetworkStatus())
+
+	status.Status = SystemStatusHealthy
+	for _, check := range status.Checks {
+		if check.Status == CheckStatusError {
+			status.Status = SystemStatusUnhealthy
+			break
+		} else if check.Status == CheckStatusWarning {
+			status.Status = SystemStatusDegraded
+		}
+	}
+	return status, nil
+}
+
+func (cmd *CmdSystemStatus) checkService(siteState *api.SiteState, platform string) SystemStatusCheck {
+	check := SystemStatusCheck{Name: "service"}
+	state, err := cmd.ServiceState(siteState, platform)
+	switch {
+	case err != nil:
+		check.Status = CheckStatusWarning
+		check.Message = err.Error()
+	case state == "active":
+		check.Status = CheckStatusOK
+		check.Message = fmt.Sprintf("skupper-%s.service is active", cmd.Namespace)
+	default:
+		check.Status = CheckStatusError
+		check.Message = fmt.Sprintf("skupper-%s.service is %s", cmd.Namespace, state)
+	}
+	return check
+}
+
+func (cmd *CmdSystemStatus) checkRouter(platform string) SystemStatusCheck {
+	check := SystemStatusCheck{Name: "router"}
+	if common.Platform(platform) == common.PlatformLinux {
+		address, err := cmd.RouterListening(cmd.Namespace)
+		if err != nil {
+			check.Status = CheckStatusError
+			check.Message = fmt.Sprintf("router is not accepting connections: %s", err)
+			return check
+		}
+		check.Status = CheckStatusOK
+		check.Message = fmt.Sprintf("router is accepting connections on %s", address)
+		return check
+	}
+	name := cmd.Namespace + "-skupper-router"
+	routerContainer, err := cmd.RouterContainer(platform, name)
+	if err != nil {
+		check.Status = CheckStatusError
+		check.Message = fmt.Sprintf("unable to inspect container %s: %s", name, err)
+		return check
+	}
+	if !routerContainer.Running {
+		check.Status = CheckStatusError
+		check.Message = fmt.Sprintf("container %s is not running (exit code %d)", name, routerContainer.ExitCode)
+		return check
+	}
+	check.Status = CheckStatusOK
+	check.Message = fmt.Sprintf("container %s is running", name)
+	if !routerContainer.StartedAt.IsZero() {
+		startedAt := routerContainer.StartedAt
+		check.Time = &startedAt
+		check.Message += " since " + formatTime(startedAt)
+	}
+	return check
+}
+
+func (cmd *CmdSystemStatus) checkController() SystemStatusCheck {
+	check := SystemStatusCheck{Name: "controller"}
+	heartbeat, err := nonkubecommon.ReadRouterHeartbeat(cmd.Namespace)
+	if err != nil {
+		check.Status = CheckStatusWarning
+		if os.IsNotExist(err) {
+			check.Message = "no router heartbeat recorded by the system controller"
+		} else {
+			check.Message = err.Error()
+		}
+		return check
+	}
+	if !heartbeat.LastHeartbeat.IsZero() {
+		lastHeartbeat := heartbeat.LastHeartbeat
+		check.Time = &lastHeartbeat
+	}
+	switch {
+	case cmd.Now().Sub(heartbeat.Updated) > heartbeatStaleAfter:
+		check.Status = CheckStatusError
+		check.Message = fmt.Sprintf("system controller has not reported since %s", formatTime(heartbeat.Updated))
+	case !heartbeat.RouterUp:
+		check.Status = CheckStatusError
+		check.Message = "router heartbeats are not being received"
+		if heartbeat.Reason != "" {
+			check.Message += ": " + heartbeat.Reason
+		}
+	default:
+		check.Status = CheckStatusOK
+		check.Message = fmt.Sprintf("last router heartbeat at %s", formatTime(heartbeat.LastHeartbeat))
+	}
+	return check
+}
+
+// checkCertificates reports the expiry of the certificates under
+// runtime/certs, using the ca.crt file of CA-only credentials
+func (cmd *CmdSystemStatus) checkCertificates() []SystemStatusCheck {
+	certsPath := api.GetInternalOutputPath(cmd.Namespace, api.CertificatesPath)
+	entries, err := os.ReadDir(certsPath)
+	if err != nil {
+		return []SystemStatusCheck{{
+			Name:    "certificates",
+			Status:  CheckStatusWarning,
+			Message: fmt.Sprintf("unable to read certificates: %s", err),
+		}}
+	}
+	var checks []SystemStatusCheck
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		check := SystemStatusCheck{Name: "certificate/" + entry.Name()}
+		notAfter, err := certificateExpiry(path.Join(certsPath, entry.Name()))
+		if err != nil {
+			check.Status = CheckStatusWarning
+			check.Message = err.Error()
+			checks = append(checks, check)
+			continue
+		}
+		check.Time = &notAfter
+		remaining := notAfter.Sub(cmd.Now())
+		switch {
+		case remaining <= 0:
+			check.Status = CheckStatusError
+			check.Message = fmt.Sprintf("expired on %s", formatTime(notAfter))
+		case remaining < certificateExpiryWarning:
+			check.Status = CheckStatusWarning
+			check.Message = fmt.Sprintf("expires on %s", formatTime(notAfter))
+		default:
+			check.Status = CheckStatusOK
+			check.Message = fmt.Sprintf("valid until %s", formatTime(notAfter))
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+func certificateExpiry(dir string) (time.Time, error) {
+	var data []byte
+	var err error
+	for _, name := range []string{"tls.crt", "ca.crt"} {
+		data, err = os.ReadFile(path.Join(dir, name))
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("no certificate found: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return time.Time{}, fmt.Errorf("invalid certificate in %s", dir)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid certificate in %s: %w", dir, err)
+	}
+	return cert.NotAfter, nil
+}
+
+// checkDrift compares the input resources with the copy of the resources
+// loaded into the running site, reporting the changes not yet applied
+func (cmd *CmdSystemStatus) checkDrift() SystemStatusCheck {
+	check := SystemStatusCheck{Name: "drift"}
+	load := func(internalPath api.InternalPath) (*api.SiteState, error) {
+		loader := &nonkubecommon.FileSystemSiteStateLoader{
+			Path: api.GetInternalOutputPath(cmd.Namespace, internalPath),
+		}
+		return loader.Load()
+	}
+	loaded, err := load(api.LoadedSiteStatePath)
+	if err != nil {
+		check.Status = CheckStatusWarning
+		check.Message = fmt.Sprintf("unable to load the loaded site state: %s", err)
+		return check
+	}
+	input, err := load(api.InputSiteStatePath)
+	if err != nil {
+		check.Status = CheckStatusWarning
+		check.Message = fmt.Sprintf("unable to load the input resources: %s", err)
+		return check
+	}
+	diff := api.DiffSiteStates(loaded, input)
+	if diff.Empty() {
+		check.Status = CheckStatusOK
+		check.Message = "input resources match the running site"
+		return check
+	}
+	check.Status = CheckStatusWarning
+	check.Message = "input resources have pending changes: " + driftSummary(diff)
+	return check
+}
+
+func driftSummary(diff *api.SiteStateDiff) string {
+	var changes []string
+	add := func(kind string, resources api.ResourceDiff) {
+		for _, change := range []struct {
+			action string
+			names  []string
+		}{
+			{"added", resources.Added},
+			{"updated", resources.Updated},
+			{"deleted", resources.Deleted},
+		} {
+			if len(change.names) > 0 {
+				names := slices.Sorted(slices.Values(change.names))
+				changes = append(changes, fmt.Sprintf("%s %s (%s)", kind, change.action, strings.Join(names, ", ")))
+			}
+		}
+	}
+	add("listeners", diff.Listeners)
+	add("connectors", diff.Connectors)
+	add("links", diff.Links)
+	if diff.Drain {
+		changes = append(changes, "site drain settings changed")
+	}
+	if diff.RequiresReload() {
+		changes = append(changes, "reload required ("+strings.Join(diff.ReloadReasons, ", ")+")")
+	}
+	return strings.Join(changes, "; ")
+}
+
+func (cmd *CmdSystemStatus) checkNetworkStatus() SystemStatusCheck {
+	check := SystemStatusCheck{Name: "network-status"}
+	fileName := path.Join(api.GetInternalOutputPath(cmd.Namespace, api.RuntimeSiteStatePath), "ConfigMap-skupper-network-status.yaml")
+	info, err := os.Stat(fileName)
+	if err != nil {
+		check.Status = CheckStatusWarning
+		check.Message = "network status has not been updated"
+		return check
+	}
+	updated := info.ModTime()
+	check.Status = CheckStatusOK
+	check.Message = fmt.Sprintf("last updated at %s", formatTime(updated))
+	check.Time = &updated
+	return check
+}
+
+func formatTime(t time.Time) string {
+	return t.Local().Format(time.RFC3339)
+}
+
+func systemdServiceState(siteState *api.SiteState, platform string) (string, error) {
+	service, err := nonkubecommon.NewSystemdServiceInfo(siteState, platform)
+	if err != nil {
+		return "", err
+	}
+	return service.State()
+}
+
+func inspectRouterContainer(platform string, name string) (*container.Container, error) {
+	endpoint := os.Getenv("CONTAINER_ENDPOINT")
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("unix://%s/podman/podman.sock", api.GetRuntimeDir())
+		if platform == "docker" {
+			endpoint = "unix:///run/docker.sock"
+		}
+	}
+	cli, err := internalclient.NewCompatClient(endpoint, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create container client: %v", err)
+	}
+	return cli.ContainerInspect(name)
+}
+
+func dialLocalRouter(namespace string) (string, error) {
+	port, err := runtime.GetLocalRouterPort(namespace)
+	if err != nil {
+		return "", err
+	}
+	address := fmt.Sprintf("127.0.0.1:%d", port)
+	conn, err := net.DialTimeout("tcp", address, 2*time.Second)
+	if err != nil {
+		return "", err
+	}
+	_ = conn.Close()
+	return address, nil
+}
@@ -0,0 +1,287 @@
+package nonkube
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/skupperproject/skupper/internal/cmd/skupper/common"
+	"github.com/skupperproject/skupper/internal/cmd/skupper/common/testutils"
+	nonkubecommon "github.com/skupperproject/skupper/internal/nonkube/common"
+	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+	"github.com/skupperproject/skupper/pkg/container"
+	"github.com/skupperproject/skupper/pkg/nonkube/api"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCmdSystemStatus_ValidateInput(t *testing.T) {
+	type test struct {
+		name          string
+		args          []string
+		flags         *common.CommandSystemStatusFlags
+		expectedError string
+	}
+
+	testTable := []test{
+		{
+			name:          "args-are-not-accepted",
+			args:          []string{"something"},
+			expectedError: "this command does not accept arguments",
+		},
+		{
+			name:          "invalid-output",
+			flags:         &common.CommandSystemStatusFlags{Output: "not-supported"},
+			expectedError: "output type is not valid: value not-supported not allowed. It should be one of this options: [json yaml]",
+		},
+		{
+			name:  "json-output",
+			flags: &common.CommandSystemStatusFlags{Output: "json"},
+		},
+	}
+
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+
+			command := &CmdSystemStatus{Flags: test.flags}
+
+			testutils.CheckValidateInput(t, command, test.expectedError, test.args)
+		})
+	}
+}
+
+func TestCmdSystemStatus_InputToOptions(t *testing.T) {
+	command := &CmdSystemStatus{Flags: &common.CommandSystemStatusFlags{Output: "yaml"}}
+	command.InputToOptions()
+	assert.Equal(t, command.Namespace, "default")
+	assert.Equal(t, command.output, "yaml")
+
+	command = &CmdSystemStatus{Namespace: "east"}
+	command.InputToOptions()
+	assert.Equal(t, command.Namespace, "east")
+	assert.Equal(t, command.output, "")
+}
+
+func TestCmdSystemStatus_Status(t *testing.T) {
+	now := time.Now()
+	type test struct {
+		name           string
+		platform       string
+		serviceState   string
+		routerRunning  bool
+		heartbeat      *nonkubecommon.RouterHeartbeat
+		certExpiry     time.Duration
+		inputChanged   bool
+		expectedStatus string
+		expectedChecks map[string]string
+	}
+
+	testTable := []test{
+		{
+			name:          "healthy",
+			platform:      "podman",
+			serviceState:  "active",
+			routerRunning: true,
+			heartbeat: &nonkubecommon.RouterHeartbeat{
+				RouterUp:      true,
+				LastHeartbeat: now,
+				Updated:       now,
+			},
+			certExpiry:     365 * 24 * time.Hour,
+			expectedStatus: SystemStatusHealthy,
+			expectedChecks: map[string]string{
+				"service":                  CheckStatusOK,
+				"router":                   CheckStatusOK,
+				"controller":               CheckStatusOK,
+				"certificate/skupper-site": CheckStatusOK,
+				"drift":                    CheckStatusOK,
+				"network-status":           CheckStatusOK,
+			},
+		},
+		{
+			name:          "pending-changes-and-expiring-certificate",
+			platform:      "linux",
+			serviceState:  "active",
+			routerRunning: true,
+			heartbeat: &nonkubecommon.RouterHeartbeat{
+				RouterUp:      true,
+				LastHeartbeat: now,
+				Updated:       now,
+			},
+			certExpiry:     24 * time.Hour,
+			inputChanged:   true,
+			expectedStatus: SystemStatusDegraded,
+			expectedChecks: map[string]string{
+				"service":                  CheckStatusOK,
+				"router":                   CheckStatusOK,
+				"controller":               CheckStatusOK,
+				"certificate/skupper-site": CheckStatusWarning,
+				"drift":                    CheckStatusWarning,
+				"network-status":           CheckStatusOK,
+			},
+		},
+		{
+			name:          "stopped",
+			platform:      "docker",
+			serviceState:  "failed",
+			routerRunning: false,
+			heartbeat: &nonkubecommon.RouterHeartbeat{
+				RouterUp: false,
+				Reason:   "unable to connect",
+				Updated:  now.Add(-time.Hour),
+			},
+			certExpiry:     -time.Hour,
+			expectedStatus: SystemStatusUnhealthy,
+			expectedChecks: map[string]string{
+				"service":                  CheckStatusError,
+				"router":                   CheckStatusError,
+				"controller":               CheckStatusError,
+				"certificate/skupper-site": CheckStatusError,
+				"drift":                    CheckStatusOK,
+				"network-status":           CheckStatusOK,
+			},
+		},
+		{
+			name:           "no-heartbeat",
+			platform:       "podman",
+			serviceState:   "active",
+			routerRunning:  true,
+			certExpiry:     365 * 24 * time.Hour,
+			expectedStatus: SystemStatusDegraded,
+			expectedChecks: map[string]string{
+				"service":                  CheckStatusOK,
+				"router":                   CheckStatusOK,
+				"controller":               CheckStatusWarning,
+				"certificate/skupper-site": CheckStatusOK,
+				"drift":                    CheckStatusOK,
+				"network-status":           CheckStatusOK,
+			},
+		},
+	}
+
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			if os.Getuid() == 0 {
+				api.DefaultRootDataHome = tempDir
+			} else {
+				t.Setenv("XDG_DATA_HOME", tempDir)
+			}
+			namespace := "test-system-status"
+			writeFakeSite(t, namespace, test.platform, now.Add(test.certExpiry), test.inputChanged)
+			if test.heartbeat != nil {
+				assert.Assert(t, nonkubecommon.WriteRouterHeartbeat(namespace, *test.heartbeat))
+			}
+
+			command := &CmdSystemStatus{
+				Namespace: namespace,
+				ServiceState: func(siteState *api.SiteState, platform string) (string, error) {
+					assert.Equal(t, platform, test.platform)
+					return test.serviceState, nil
+				},
+				RouterContainer: func(platform string, name string) (*container.Container, error) {
+					assert.Equal(t, name, "test-system-status-skupper-router")
+					return &container.Container{Name: name, Running: test.routerRunning, StartedAt: now}, nil
+				},
+				RouterListening: func(namespace string) (string, error) {
+					if !test.routerRunning {
+						return "", fmt.Errorf("connection refused")
+					}
+					return "127.0.0.1:5671", nil
+				},
+				Now: func() time.Time { return now },
+			}
+			status, err := command.status()
+			assert.Assert(t, err)
+			assert.Equal(t, status.Platform, test.platform)
+			assert.Equal(t, status.Status, test.expectedStatus)
+			checks := map[string]string{}
+			for _, check := range status.Checks {
+				checks[check.Name] = check.Status
+			}
+			assert.DeepEqual(t, checks, test.expectedChecks)
+			if test.expectedStatus == SystemStatusUnhealthy {
+				assert.Error(t, command.Run(), `site in namespace "test-system-status" is unhealthy`)
+			} else {
+				assert.Assert(t, command.Run())
+			}
+		})
+	}
+}
+
+func TestCmdSystemStatus_NoSite(t *testing.T) {
+	tempDir := t.TempDir()
+	if os.Getuid() == 0 {
+		api.DefaultRootDataHome = tempDir
+	} else {
+		t.Setenv("XDG_DATA_HOME", tempDir)
+	}
+	command := &CmdSystemStatus{Namespace: "missing"}
+	assert.ErrorContains(t, command.Run(), `no site found in namespace "missing"`)
+}
+
+func writeFakeSite(t *testing.T, namespace string, platform string, certExpiry time.Time, inputChanged bool) {
+	t.Helper()
+	siteState := &api.SiteState{
+		SiteId: "site-id",
+		Site: &v2alpha1.Site{
+			TypeMeta:   metav1.TypeMeta{Kind: "Site", APIVersion: "skupper.io/v2alpha1"},
+			ObjectMeta: metav1.ObjectMeta{Name: "site-name", Namespace: namespace},
+		},
+		Listeners: map[string]*v2alpha1.Listener{
+			"backend": {
+				TypeMeta:   metav1.TypeMeta{Kind: "Listener", APIVersion: "skupper.io/v2alpha1"},
+				ObjectMeta: metav1.ObjectMeta{Name: "backend", Namespace: namespace},
+				Spec:       v2alpha1.ListenerSpec{RoutingKey: "backend", Host: "backend", Port: 8080},
+			},
+		},
+	}
+	for _, internalPath := range []api.InternalPath{api.RuntimeSiteStatePath, api.LoadedSiteStatePath, api.InputSiteStatePath} {
+		outputPath := api.GetInternalOutputPath(namespace, internalPath)
+		assert.Assert(t, os.MkdirAll(outputPath, 0755))
+		if internalPath == api.InputSiteStatePath && inputChanged {
+			siteState.Listeners["backend"].Spec.Port = 9090
+		}
+		assert.Assert(t, api.MarshalSiteState(*siteState, outputPath))
+	}
+	runtimePath := api.GetInternalOutputPath(namespace, api.RuntimeSiteStatePath)
+	networkStatus := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: skupper-network-status
+  namespace: ` + namespace + `
+data:
+  NetworkStatus: "{}"
+`
+	assert.Assert(t, os.WriteFile(path.Join(runtimePath, "ConfigMap-skupper-network-status.yaml"), []byte(networkStatus), 0644))
+	internalPath := api.GetInternalOutputPath(namespace, api.InternalBasePath)
+	assert.Assert(t, os.WriteFile(path.Join(internalPath, "platform.yaml"), []byte("platform: "+platform), 0644))
+
+	certPath := path.Join(api.GetInternalOutputPath(namespace, api.CertificatesPath), "skupper-site")
+	assert.Assert(t, os.MkdirAll(certPath, 0755))
+	assert.Assert(t, os.WriteFile(path.Join(certPath, "tls.crt"), fakeCertificate(t, certExpiry), 0644))
+}
+
+func fakeCertificate(t *testing.T, notAfter time.Time) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Assert(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "skupper-site"},
+		NotBefore:    notAfter.Add(-2 * 365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.Assert(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
@@ -0,0 +1,54 @@
+package nonkube
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/skupperproject/skupper/internal/nonkube/bootstrap"
+	"github.com/spf13/cobra"
+)
+
+type CmdSystemUnseal struct {
+	CobraCmd  *cobra.Command
+	Unseal    func(namespace string) error
+	Namespace string
+}
+
+func NewCmdSystemUnseal() *CmdSystemUnseal {
+
+	skupperCmd := CmdSystemUnseal{}
+
+	return &skupperCmd
+}
+
+func (cmd *CmdSystemUnseal) NewClient(cobraCommand *cobra.Command, args []string) {
+	cmd.Unseal = bootstrap.Unseal
+	cmd.Namespace = cobraCommand.Flag("namespace").Value.String()
+}
+
+func (cmd *CmdSystemUnseal) ValidateInput(args []string) error {
+	if len(args) > 0 {
+		return errors.New("this command does not accept arguments")
+	}
+
+	return nil
+}
+
+func (cmd *CmdSystemUnseal) InputToOptions() {
+	if cmd.Namespace == "" {
+		cmd.Namespace = "default"
+	}
+}
+
+func (cmd *CmdSystemUnseal) Run() error {
+
+	err := cmd.Unseal(cmd.Namespace)
+
+	if err != nil {
+		return fmt.Errorf("Unable to decrypt secrets: %s", err)
+	}
+
+	return nil
+}
+
+func (cmd *CmdSystemUnseal) WaitUntil() error { return nil }
@@ -0,0 +1,88 @@
+package nonkube
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/skupperproject/skupper/internal/cmd/skupper/common"
+	"github.com/skupperproject/skupper/internal/cmd/skupper/common/testutils"
+	"gotest.tools/v3/assert"
+)
+
+func TestCmdSystemUnseal_ValidateInput(t *testing.T) {
+	type test struct {
+		name          string
+		args          []string
+		expectedError string
+	}
+
+	testTable := []test{
+		{
+			name:          "arg-not-accepted",
+			args:          []string{"namespace"},
+			expectedError: "this command does not accept arguments",
+		},
+		{
+			name: "no-args",
+		},
+	}
+
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+
+			command := &CmdSystemUnseal{}
+			command.CobraCmd = common.ConfigureCobraCommand(common.PlatformLinux, common.SkupperCmdDescription{}, command, nil)
+
+			testutils.CheckValidateInput(t, command, test.expectedError, test.args)
+		})
+	}
+}
+
+func TestCmdSystemUnseal_InputToOptions(t *testing.T) {
+	command := &CmdSystemUnseal{}
+	command.InputToOptions()
+	assert.Equal(t, command.Namespace, "default")
+
+	command = &CmdSystemUnseal{Namespace: "east"}
+	command.InputToOptions()
+	assert.Equal(t, command.Namespace, "east")
+}
+
+func TestCmdSystemUnseal_Run(t *testing.T) {
+	type test struct {
+		name         string
+		unsealError  error
+		errorMessage string
+	}
+
+	testTable := []test{
+		{
+			name: "runs-ok",
+		},
+		{
+			name:         "unseal-fails",
+			unsealError:  fmt.Errorf("fail"),
+			errorMessage: "Unable to decrypt secrets: fail",
+		},
+	}
+
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			var namespace string
+			command := &CmdSystemUnseal{
+				Namespace: "east",
+				Unseal: func(ns string) error {
+					namespace = ns
+					return test.unsealError
+				},
+			}
+			err := command.Run()
+			assert.Equal(t, namespace, "east")
+			if test.errorMessage != "" {
+				assert.Error(t, err, test.errorMessage)
+			} else {
+				assert.Assert(t, err)
+			}
+		})
+	}
+}
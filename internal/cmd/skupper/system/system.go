@@ -35,6 +35,8 @@ approach, which is based on the new set of Custom Resource Definitions (CRDs).`,
 	cmd.AddCommand(CmdSystemGenerateBundleFactory(platform))
 	cmd.AddCommand(CmdSystemApplyFactory(platform))
 	cmd.AddCommand(CmdSystemDeleteFactory(platform))
+	cmd.AddCommand(CmdSystemStatusFactory(platform))
+	cmd.AddCommand(CmdSystemUnsealFactory(platform))
 
 	return cmd
 }
@@ -224,3 +226,58 @@ func CmdSystemDeleteFactory(configuredPlatform common.Platform) *cobra.Command {
 
 	return cmd
 }
+
+func CmdSystemStatusFactory(configuredPlatform common.Platform) *cobra.Command {
+
+	//This implementation will warn the user that the command is not available for Kubernetes environments.
+	kubeCommand := kube.NewCmdSystemStatus()
+	nonKubeCommand := nonkube.NewCmdSystemStatus()
+
+	cmdSystemStatusDesc := common.SkupperCmdDescription{
+		Use:   "status",
+		Short: "Report the health of the site in the current namespace",
+		Long: `Report the health of the site in the current namespace: the state of its systemd service,
+the liveness of the router, the router heartbeats seen by the system controller, the expiry
+of its certificates, changes to the input resources not yet applied and the last network
+status update. The command exits with a non-zero code when the site is unhealthy.`,
+		Example: `skupper system status -n my-namespace
+skupper system status -o json`,
+	}
+
+	cmd := common.ConfigureCobraCommand(configuredPlatform, cmdSystemStatusDesc, kubeCommand, nonKubeCommand)
+
+	cmdFlags := common.CommandSystemStatusFlags{}
+
+	cmd.Flags().StringVarP(&cmdFlags.Output, common.FlagNameOutput, "o", "", common.FlagDescSystemStatusOutput)
+
+	kubeCommand.CobraCmd = cmd
+	kubeCommand.Flags = &cmdFlags
+	nonKubeCommand.CobraCmd = cmd
+	nonKubeCommand.Flags = &cmdFlags
+
+	return cmd
+}
+
+func CmdSystemUnsealFactory(configuredPlatform common.Platform) *cobra.Command {
+
+	//This implementation will warn the user that the command is not available for Kubernetes environments.
+	kubeCommand := kube.NewCmdSystemUnseal()
+	nonKubeCommand := nonkube.NewCmdSystemUnseal()
+
+	cmdSystemUnsealDesc := common.SkupperCmdDescription{
+		Use:   "unseal",
+		Short: "Decrypt the certificates of the site in the current namespace before its router starts",
+		Long: `Decrypt the certificates of a site with the secrets-encryption setting into the runtime
+directory, from where they are read by the router. It is run by the systemd service of the
+site before the router starts.`,
+		Example: "skupper system unseal -n my-namespace",
+	}
+
+	cmd := common.ConfigureCobraCommand(configuredPlatform, cmdSystemUnsealDesc, kubeCommand, nonKubeCommand)
+	cmd.Hidden = true
+
+	kubeCommand.CobraCmd = cmd
+	nonKubeCommand.CobraCmd = cmd
+
+	return cmd
+}
@@ -67,6 +67,18 @@ func TestCmdSystemFactory(t *testing.T) {
 			},
 			command: CmdSystemDeleteFactory(common.PlatformKubernetes),
 		},
+		{
+			name: "CmdSystemStatusFactory",
+			expectedFlagsWithDefaultValue: map[string]interface{}{
+				common.FlagNameOutput: "",
+			},
+			command: CmdSystemStatusFactory(common.PlatformPodman),
+		},
+		{
+			name:                          "CmdSystemUnsealFactory",
+			expectedFlagsWithDefaultValue: map[string]interface{}{},
+			command:                       CmdSystemUnsealFactory(common.PlatformPodman),
+		},
 	}
 
 	for _, test := range testTable {
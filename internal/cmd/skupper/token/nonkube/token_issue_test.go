@@ -1 +1,199 @@
 package nonkube
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/skupperproject/skupper/internal/cmd/skupper/common"
+	"github.com/skupperproject/skupper/internal/cmd/skupper/common/testutils"
+	"github.com/skupperproject/skupper/internal/nonkube/client/fs"
+	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+	"github.com/skupperproject/skupper/pkg/nonkube/api"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/serializer/json"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+func TestCmdTokenIssue_ValidateInput(t *testing.T) {
+	type test struct {
+		name          string
+		args          []string
+		flags         common.CommandTokenIssueFlags
+		linkAccess    bool
+		noSite        bool
+		expectedError string
+	}
+
+	validFlags := common.CommandTokenIssueFlags{
+		RedemptionsAllowed: 1,
+		ExpirationWindow:   15 * time.Minute,
+		Timeout:            60 * time.Second,
+		Cost:               "1",
+	}
+
+	testTable := []test{
+		{
+			name:          "file name is not specified",
+			args:          []string{},
+			flags:         validFlags,
+			linkAccess:    true,
+			expectedError: "file name must be configured",
+		},
+		{
+			name:          "more than one argument is specified",
+			args:          []string{"token.yaml", "other.yaml"},
+			flags:         validFlags,
+			linkAccess:    true,
+			expectedError: "only one argument is allowed for this command",
+		},
+		{
+			name:          "token file name is not valid",
+			args:          []string{"my new file"},
+			flags:         validFlags,
+			linkAccess:    true,
+			expectedError: "token file name is not valid: value does not match this regular expression: ^[A-Za-z0-9./~-]+$",
+		},
+		{
+			name:          "there is no site",
+			args:          []string{"/tmp/token-issue.yaml"},
+			flags:         validFlags,
+			noSite:        true,
+			expectedError: "there is no active skupper site in this namespace",
+		},
+		{
+			name:          "site without link access",
+			args:          []string{"/tmp/token-issue.yaml"},
+			flags:         validFlags,
+			expectedError: "You must enable link access for this site before you can create a token.",
+		},
+		{
+			name: "flags are not valid",
+			args: []string{"/tmp/token-issue.yaml"},
+			flags: common.CommandTokenIssueFlags{
+				RedemptionsAllowed: 0,
+				ExpirationWindow:   15 * time.Minute,
+				Timeout:            0,
+				Cost:               "cheap",
+			},
+			linkAccess: true,
+			expectedError: "number of redemptions is not valid\n" +
+				"timeout is not valid: duration must not be less than 10s; got 0s\n" +
+				"link cost is not valid: strconv.Atoi: parsing \"cheap\": invalid syntax",
+		},
+		{
+			name:       "flags all valid",
+			args:       []string{"/tmp/token-issue.yaml"},
+			flags:      validFlags,
+			linkAccess: true,
+		},
+	}
+
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			if os.Getuid() == 0 {
+				api.DefaultRootDataHome = t.TempDir()
+			} else {
+				t.Setenv("XDG_DATA_HOME", t.TempDir())
+			}
+			if !test.noSite {
+				writeRuntimeSite(t, "test", test.linkAccess)
+			}
+
+			command := &CmdTokenIssue{}
+			command.Namespace = "test"
+			command.Flags = &test.flags
+
+			testutils.CheckValidateInput(t, command, test.expectedError, test.args)
+			if test.expectedError == "" {
+				assert.Assert(t, strings.HasPrefix(command.grantName, "site-name-"))
+				assert.Equal(t, command.fileName, "/tmp/token-issue.yaml")
+				assert.Equal(t, command.cost, 1)
+			}
+		})
+	}
+}
+
+func TestCmdTokenIssue_RunAndWaitUntil(t *testing.T) {
+	if os.Getuid() == 0 {
+		api.DefaultRootDataHome = t.TempDir()
+	} else {
+		t.Setenv("XDG_DATA_HOME", t.TempDir())
+	}
+	tokenFile := filepath.Join(t.TempDir(), "token.yaml")
+
+	command := &CmdTokenIssue{
+		Namespace: "test",
+		Flags: &common.CommandTokenIssueFlags{
+			RedemptionsAllowed: 2,
+			ExpirationWindow:   30 * time.Minute,
+			Timeout:            5 * time.Second,
+			Cost:               "3",
+		},
+		accessGrantHandler: fs.NewAccessGrantHandler("test"),
+		grantName:          "site-name-grant",
+		fileName:           tokenFile,
+		cost:               3,
+	}
+	assert.Assert(t, command.Run())
+
+	grant, err := command.accessGrantHandler.Get("site-name-grant", fs.GetOptions{})
+	assert.Assert(t, err)
+	assert.Equal(t, grant.Spec.RedemptionsAllowed, 2)
+	assert.Equal(t, grant.Spec.ExpirationWindow, "30m0s")
+
+	// the grant is not ready until resolved by the system controller
+	command.Flags.Timeout = time.Second
+	assert.ErrorContains(t, command.WaitUntil(), `grant "site-name-grant" not ready yet`)
+
+	grant.Status.Url = "https://site.example.com:9090/grant-uid"
+	grant.Status.Ca = "my-ca"
+	grant.Status.Code = "my-code"
+	grant.SetProcessed(nil)
+	grant.SetResolved()
+	runtimePath := api.GetInternalOutputPath("test", api.RuntimeSiteStatePath)
+	assert.Assert(t, api.MarshalResource(runtimePath, "AccessGrant", grant.Name, grant))
+	command.Flags.Timeout = 5 * time.Second
+	assert.Assert(t, command.WaitUntil())
+
+	info, err := os.Stat(tokenFile)
+	assert.Assert(t, err)
+	assert.Equal(t, info.Mode().Perm(), os.FileMode(0600))
+	data, err := os.ReadFile(tokenFile)
+	assert.Assert(t, err)
+	var token v2alpha1.AccessToken
+	s := json.NewSerializerWithOptions(json.DefaultMetaFactory, scheme.Scheme, scheme.Scheme, json.SerializerOptions{Yaml: true})
+	_, _, err = s.Decode(data, nil, &token)
+	assert.Assert(t, err)
+	assert.Equal(t, token.Name, "site-name-grant")
+	assert.DeepEqual(t, token.Spec, v2alpha1.AccessTokenSpec{
+		Url:      "https://site.example.com:9090/grant-uid",
+		Ca:       "my-ca",
+		Code:     "my-code",
+		LinkCost: 3,
+	})
+}
+
+func writeRuntimeSite(t *testing.T, namespace string, linkAccess bool) {
+	t.Helper()
+	siteState := &api.SiteState{
+		Site: &v2alpha1.Site{
+			TypeMeta:   metav1.TypeMeta{Kind: "Site", APIVersion: "skupper.io/v2alpha1"},
+			ObjectMeta: metav1.ObjectMeta{Name: "site-name", Namespace: namespace},
+		},
+		RouterAccesses: map[string]*v2alpha1.RouterAccess{},
+	}
+	if linkAccess {
+		siteState.RouterAccesses["link-access"] = &v2alpha1.RouterAccess{
+			TypeMeta:   metav1.TypeMeta{Kind: "RouterAccess", APIVersion: "skupper.io/v2alpha1"},
+			ObjectMeta: metav1.ObjectMeta{Name: "link-access", Namespace: namespace},
+			Spec: v2alpha1.RouterAccessSpec{
+				Roles: []v2alpha1.RouterAccessRole{{Name: "inter-router", Port: 55671}},
+			},
+		}
+	}
+	assert.Assert(t, api.MarshalSiteState(*siteState, api.GetInternalOutputPath(namespace, api.RuntimeSiteStatePath)))
+}
@@ -1,17 +1,26 @@
 package adaptor
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"reflect"
+	"strconv"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	internalclient "github.com/skupperproject/skupper/internal/kube/client"
+	kubeqdr "github.com/skupperproject/skupper/internal/kube/qdr"
 	"github.com/skupperproject/skupper/internal/kube/watchers"
 	"github.com/skupperproject/skupper/internal/qdr"
 )
 
+// drainReportInterval is how often the flows still active on the
+// connectors of a draining router are counted
+const drainReportInterval = 10 * time.Second
+
 // Syncs the live router config with the configmap (bridge configuration,
 // secrets for services with TLS enabled, and secrets and connectors for links)
 type ConfigSync struct {
@@ -23,6 +32,7 @@ type ConfigSync struct {
 	secrets         *watchers.SecretWatcher
 	path            string
 	routerConfigMap string
+	draining        bool
 }
 
 func NewConfigSync(cli internalclient.Clients, namespace string, path string, routerConfigMap string) *ConfigSync {
@@ -125,6 +135,10 @@ func (c *ConfigSync) configEvent(key string, configmap *corev1.ConfigMap) error
 	if configmap == nil {
 		return nil
 	}
+	if _, ok := configmap.ObjectMeta.Annotations[kubeqdr.DrainAnnotation]; ok && !c.draining {
+		c.draining = true
+		c.controller.CallbackAfter(0, c.reportDrainFlows, c.routerConfigMap)
+	}
 	desired, err := qdr.GetRouterConfigFromConfigMap(configmap)
 	if err != nil {
 		return err
@@ -203,6 +217,9 @@ func syncRouterConfig(agent *qdr.Agent, desired *qdr.RouterConfig) error {
 	if err := syncListeners(agent, desired); err != nil {
 		return err
 	}
+	if err := syncAddresses(agent, desired); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -227,7 +244,7 @@ func syncListeners(agent *qdr.Agent, desired *qdr.RouterConfig) error {
 		return fmt.Errorf("Error retrieving local listeners: %s", err)
 	}
 
-	if differences := qdr.ListenersDifference(qdr.FilterListeners(actual, qdr.IsNotNormalListener), desired.GetMatchingListeners(qdr.IsNotNormalListener)); !differences.Empty() {
+	if differences := qdr.LinkListenersDifference(qdr.FilterListeners(actual, qdr.IsNotNormalListener), desired.GetMatchingListeners(qdr.IsNotNormalListener)); !differences.Empty() {
 		if err := agent.UpdateListenerConfig(differences); err != nil {
 			return fmt.Errorf("Error syncing listeners: %s", err)
 		}
@@ -235,6 +252,20 @@ func syncListeners(agent *qdr.Agent, desired *qdr.RouterConfig) error {
 	return nil
 }
 
+func syncAddresses(agent *qdr.Agent, desired *qdr.RouterConfig) error {
+	actual, err := agent.GetLocalAddresses()
+	if err != nil {
+		return fmt.Errorf("Error retrieving local addresses: %s", err)
+	}
+
+	if differences := qdr.AddressesDifference(actual, desired.Addresses); !differences.Empty() {
+		if err := agent.UpdateAddressConfig(differences); err != nil {
+			return fmt.Errorf("Error syncing addresses: %s", err)
+		}
+	}
+	return nil
+}
+
 func (c *ConfigSync) reloadSslProfileInRouter(sslProfileName string) error {
 	agent, err := c.agentPool.Get()
 	if err != nil {
@@ -282,6 +313,41 @@ func (c *ConfigSync) syncSslProfileCredentialsToDisk(profiles map[string]qdr.Ssl
 	return nil
 }
 
+// reportDrainFlows records the number of flows still active on the
+// connectors of the router in its config map while the site is draining
+func (c *ConfigSync) reportDrainFlows(name string) error {
+	configmap, err := c.config.Get(c.key(name))
+	if err != nil || configmap == nil || configmap.ObjectMeta.Annotations[kubeqdr.DrainAnnotation] == "" {
+		// reporting starts again on the next config event for a drain
+		c.draining = false
+		return err
+	}
+	defer c.controller.CallbackAfter(drainReportInterval, c.reportDrainFlows, name)
+	agent, err := c.agentPool.Get()
+	if err != nil {
+		log.Printf("CONFIG_SYNC: Could not get management agent to count active flows: %s", err)
+		return nil
+	}
+	flows, err := agent.CountConnectorFlows()
+	c.agentPool.Put(agent)
+	if err != nil {
+		log.Printf("CONFIG_SYNC: Error counting active flows: %s", err)
+		return nil
+	}
+	value := strconv.Itoa(flows)
+	if configmap.ObjectMeta.Annotations[kubeqdr.DrainActiveFlowsAnnotation] == value {
+		return nil
+	}
+	updated := configmap.DeepCopy()
+	updated.ObjectMeta.Annotations[kubeqdr.DrainActiveFlowsAnnotation] = value
+	if _, err := c.controller.GetKubeClient().CoreV1().ConfigMaps(c.namespace).Update(context.TODO(), updated, metav1.UpdateOptions{}); err != nil {
+		log.Printf("CONFIG_SYNC: Error reporting active flows: %s", err)
+		return nil
+	}
+	log.Printf("CONFIG_SYNC: %d flows still active on draining router", flows)
+	return nil
+}
+
 func (c *ConfigSync) recoverTracking() error {
 	configmap, err := c.config.Get(c.key(c.routerConfigMap))
 	if err != nil {
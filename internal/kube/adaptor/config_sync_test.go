@@ -0,0 +1,134 @@
+package adaptor
+
+import (
+	"context"
+	"flag"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fakeclient "github.com/skupperproject/skupper/internal/kube/client/fake"
+	"github.com/skupperproject/skupper/internal/kube/controller"
+	"github.com/skupperproject/skupper/internal/qdr"
+	"github.com/skupperproject/skupper/internal/qdr/fake"
+	"github.com/skupperproject/skupper/internal/site"
+	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+)
+
+// TestConfigSyncWithController runs the controller and the config sync
+// of the kube-adaptor against a fake router, checking that the
+// Listeners and Connectors created are configured on the router.
+func TestConfigSyncWithController(t *testing.T) {
+	router, err := fake.NewRouter(fake.Config{ID: "router-1", SiteID: "site-1"})
+	assert.Assert(t, err)
+	defer router.Close()
+
+	flags := &flag.FlagSet{}
+	config, err := controller.BoundConfig(flags)
+	assert.Assert(t, err)
+	assert.Assert(t, flags.Parse([]string{"-namespace", "test"}))
+	clients, err := fakeclient.NewFakeClient("test", nil, nil, "")
+	assert.Assert(t, err)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	ctrl, err := controller.NewController(clients, config)
+	assert.Assert(t, err)
+	go ctrl.Run(stopCh)
+
+	configSync := NewConfigSync(clients, "test", t.TempDir(), "skupper-router")
+	configSync.agentPool = qdr.NewAgentPool(router.URL(), nil)
+	assert.Assert(t, configSync.Start(stopCh))
+	defer configSync.Stop()
+
+	ctx := context.Background()
+	skupper := clients.GetSkupperClient().SkupperV2alpha1()
+	_, err = skupper.Sites("test").Create(ctx, &skupperv2alpha1.Site{
+		ObjectMeta: metav1.ObjectMeta{Name: "mysite", Namespace: "test"},
+	}, metav1.CreateOptions{})
+	assert.Assert(t, err)
+	_, err = skupper.Listeners("test").Create(ctx, &skupperv2alpha1.Listener{
+		ObjectMeta: metav1.ObjectMeta{Name: "mylistener", Namespace: "test"},
+		Spec:       skupperv2alpha1.ListenerSpec{RoutingKey: "backend", Host: "backend", Port: 8080},
+	}, metav1.CreateOptions{})
+	assert.Assert(t, err)
+	_, err = skupper.Connectors("test").Create(ctx, &skupperv2alpha1.Connector{
+		ObjectMeta: metav1.ObjectMeta{Name: "myconnector", Namespace: "test"},
+		Spec:       skupperv2alpha1.ConnectorSpec{RoutingKey: "backend", Host: "backend.svc", Port: 9090},
+	}, metav1.CreateOptions{})
+	assert.Assert(t, err)
+
+	waitFor := func(description string, condition func() bool) {
+		t.Helper()
+		timeout := time.After(10 * time.Second)
+		for !condition() {
+			select {
+			case <-timeout:
+				t.Fatalf("timed out waiting for %s", description)
+			case <-time.After(20 * time.Millisecond):
+			}
+		}
+	}
+	waitFor("listener and connector on router", func() bool {
+		return len(router.Entities("tcpListener")) == 1 && len(router.Entities("tcpConnector")) == 1
+	})
+	listener := router.Entities("tcpListener")["mylistener"]
+	assert.Equal(t, listener["address"], "backend")
+	connector := router.Entities("tcpConnector")["myconnector@backend.svc"]
+	assert.Assert(t, connector != nil, "connectors: %v", router.Entities("tcpConnector"))
+	assert.Equal(t, connector["address"], "backend")
+	assert.Equal(t, connector["host"], "backend.svc")
+	assert.Equal(t, connector["port"], "9090")
+
+	// changes and deletions are applied to the running router
+	existing, err := skupper.Connectors("test").Get(ctx, "myconnector", metav1.GetOptions{})
+	assert.Assert(t, err)
+	existing.Spec.Port = 9191
+	_, err = skupper.Connectors("test").Update(ctx, existing, metav1.UpdateOptions{})
+	assert.Assert(t, err)
+	assert.Assert(t, skupper.Listeners("test").Delete(ctx, "mylistener", metav1.DeleteOptions{}))
+	waitFor("connector update and listener removal on router", func() bool {
+		connectors := router.Entities("tcpConnector")
+		return len(router.Entities("tcpListener")) == 0 && len(connectors) == 1 && connectors["myconnector@backend.svc"]["port"] == "9191"
+	})
+}
+
+// TestSyncLinksWhileDraining checks that raising the cost of the
+// links of a draining site does not recreate the connectors and
+// listeners for them on the running router, which would drop the
+// links and the flows over them.
+func TestSyncLinksWhileDraining(t *testing.T) {
+	router, err := fake.NewRouter(fake.Config{ID: "router-1", SiteID: "site-1"})
+	assert.Assert(t, err)
+	defer router.Close()
+	agent, err := qdr.Connect(router.URL(), nil)
+	assert.Assert(t, err)
+	defer agent.Close()
+
+	config := qdr.InitialConfig("router-1", "site-1", "v2.0", false, 3)
+	config.AddConnector(qdr.Connector{Name: "link-1", Role: qdr.RoleInterRouter, Host: "peer", Port: "55671"})
+	config.AddListener(qdr.Listener{Name: "skupper-inter-router", Role: qdr.RoleInterRouter, Port: 55671})
+	assert.Assert(t, agent.Create("io.skupper.router.connector", "link-1", config.Connectors["link-1"]))
+	assert.Assert(t, agent.Create("io.skupper.router.listener", "skupper-inter-router", config.Listeners["skupper-inter-router"]))
+
+	drain := site.NewDrain()
+	drain.Update(&skupperv2alpha1.Site{Spec: skupperv2alpha1.SiteSpec{Settings: map[string]string{"drain": "true"}}})
+	assert.Assert(t, drain.Apply(&config))
+	assert.Assert(t, syncConnectors(agent, &config))
+	assert.Assert(t, syncListeners(agent, &config))
+
+	// the cost is only set when the link is created, so the
+	// existing link keeps the cost it was created with
+	_, ok := router.Entities("connector")["link-1"]["cost"]
+	assert.Assert(t, !ok, "connector was recreated")
+	_, ok = router.Entities("listener")["skupper-inter-router"]["cost"]
+	assert.Assert(t, !ok, "listener was recreated")
+
+	// new links are created with the raised cost
+	config.AddConnector(qdr.Connector{Name: "link-2", Role: qdr.RoleInterRouter, Host: "other", Port: "55671"})
+	assert.Assert(t, drain.Apply(&config))
+	assert.Assert(t, syncConnectors(agent, &config))
+	assert.Equal(t, router.Entities("connector")["link-2"]["cost"], int32(site.DrainLinkCost))
+}
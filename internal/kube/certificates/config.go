@@ -0,0 +1,71 @@
+package certificates
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/skupperproject/skupper/internal/certs"
+	iflag "github.com/skupperproject/skupper/internal/flag"
+)
+
+const (
+	defaultValidity    = time.Hour * 24 * 365 * 5
+	defaultRenewBefore = time.Hour * 24 * 30
+)
+
+// Config holds the controller wide defaults used when a Certificate
+// does not specify its own validity, renewal window or key options.
+type Config struct {
+	Validity     time.Duration
+	RenewBefore  time.Duration
+	KeyAlgorithm string
+	KeySize      int
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		Validity:    defaultValidity,
+		RenewBefore: defaultRenewBefore,
+	}
+}
+
+func (c *Config) Verify() error {
+	if c.Validity <= 0 {
+		return fmt.Errorf("Certificate validity must be positive.")
+	}
+	if c.RenewBefore < 0 {
+		return fmt.Errorf("Certificate renewal window must not be negative.")
+	}
+	if err := c.keyOptions().Verify(); err != nil {
+		return fmt.Errorf("Invalid certificate key options: %s", err)
+	}
+	return nil
+}
+
+func (c *Config) keyOptions() certs.KeyOptions {
+	return certs.KeyOptions{
+		Algorithm: certs.KeyAlgorithm(strings.ToLower(c.KeyAlgorithm)),
+		Size:      c.KeySize,
+	}
+}
+
+func BoundConfig(flags *flag.FlagSet) (*Config, error) {
+	c := &Config{}
+	var errors []string
+	if err := iflag.DurationVar(flags, &c.Validity, "certificate-validity", "SKUPPER_CERTIFICATE_VALIDITY", defaultValidity, "The default validity period for generated certificates."); err != nil {
+		errors = append(errors, err.Error())
+	}
+	if err := iflag.DurationVar(flags, &c.RenewBefore, "certificate-renew-before", "SKUPPER_CERTIFICATE_RENEW_BEFORE", defaultRenewBefore, "How long before expiry a generated certificate should be renewed."); err != nil {
+		errors = append(errors, err.Error())
+	}
+	iflag.StringVar(flags, &c.KeyAlgorithm, "certificate-key-algorithm", "SKUPPER_CERTIFICATE_KEY_ALGORITHM", "", fmt.Sprintf("The default algorithm for private keys of generated certificates, one of %v (if not set, certificates use the same algorithm as their CA and CAs use rsa).", certs.KeyAlgorithms))
+	if err := iflag.IntVar(flags, &c.KeySize, "certificate-key-size", "SKUPPER_CERTIFICATE_KEY_SIZE", 0, "The default size for private keys of generated certificates: the modulus length for rsa or the curve size (256, 384 or 521) for ecdsa."); err != nil {
+		errors = append(errors, err.Error())
+	}
+	if len(errors) > 0 {
+		return c, fmt.Errorf("Invalid environment variable(s): %s", strings.Join(errors, ", "))
+	}
+	return c, nil
+}
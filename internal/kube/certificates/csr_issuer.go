@@ -0,0 +1,172 @@
+package certificates
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+)
+
+// Name of a ConfigMap, in the namespace of the Certificate, from
+// which the CA bundle is read when the signer does not include the
+// CA in the issued chain.
+const IssuerCaBundleConfigMap = "skupper-issuer-ca-bundle"
+
+// The minimum expiration the CertificateSigningRequest API accepts.
+const minCsrExpiration = 10 * time.Minute
+
+// KubernetesCsrIssuer obtains certificates through the Kubernetes
+// CertificateSigningRequest API. The name of the issuer is used as
+// the signerName of the request. Approval and signing are the
+// responsibility of whatever is configured for that signer.
+type KubernetesCsrIssuer struct {
+	client kubernetes.Interface
+}
+
+func NewKubernetesCsrIssuer(client kubernetes.Interface) *KubernetesCsrIssuer {
+	return &KubernetesCsrIssuer{
+		client: client,
+	}
+}
+
+// The maximum length of the name of a CertificateSigningRequest.
+const maxCsrNameLength = 253
+
+// csrName returns the name of the cluster scoped request for a
+// Certificate. As namespaces and names may both contain dashes, a hash
+// of the two is appended to keep requests for different certificates
+// apart.
+func csrName(certificate *skupperv2alpha1.Certificate) string {
+	hash := sha256.Sum256([]byte(certificate.Namespace + "/" + certificate.Name))
+	suffix := fmt.Sprintf("-%x", hash[:4])
+	prefix := fmt.Sprintf("skupper-%s-%s", certificate.Namespace, certificate.Name)
+	if len(prefix)+len(suffix) > maxCsrNameLength {
+		prefix = strings.TrimRight(prefix[:maxCsrNameLength-len(suffix)], ".")
+	}
+	return prefix + suffix
+}
+
+// csrUsages returns the usages to request for a Certificate. Key
+// encipherment only applies to RSA keys.
+func csrUsages(certificate *skupperv2alpha1.Certificate, csr []byte) []certificatesv1.KeyUsage {
+	usages := []certificatesv1.KeyUsage{certificatesv1.UsageDigitalSignature}
+	if isRsaRequest(csr) {
+		usages = append(usages, certificatesv1.UsageKeyEncipherment)
+	}
+	if certificate.Spec.Server || !certificate.Spec.Client {
+		usages = append(usages, certificatesv1.UsageServerAuth)
+	}
+	if certificate.Spec.Client || !certificate.Spec.Server {
+		usages = append(usages, certificatesv1.UsageClientAuth)
+	}
+	return usages
+}
+
+func isRsaRequest(csr []byte) bool {
+	block, _ := pem.Decode(csr)
+	if block == nil {
+		return false
+	}
+	request, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return false
+	}
+	return request.PublicKeyAlgorithm == x509.RSA
+}
+
+func (i *KubernetesCsrIssuer) Submit(certificate *skupperv2alpha1.Certificate, issuer string, csr []byte, validity time.Duration) error {
+	if validity < minCsrExpiration {
+		validity = minCsrExpiration
+	}
+	expiration := int32(validity.Seconds())
+	request := &certificatesv1.CertificateSigningRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: csrName(certificate),
+			Labels: map[string]string{
+				"internal.skupper.io/certificate": "true",
+			},
+			Annotations: map[string]string{
+				"internal.skupper.io/certificate": certificate.Key(),
+			},
+		},
+		Spec: certificatesv1.CertificateSigningRequestSpec{
+			Request:           csr,
+			SignerName:        issuer,
+			Usages:            csrUsages(certificate, csr),
+			ExpirationSeconds: &expiration,
+		},
+	}
+	// any existing request is for a key that is no longer held
+	if err := i.Cancel(certificate, issuer); err != nil {
+		return err
+	}
+	if _, err := i.client.CertificatesV1().CertificateSigningRequests().Create(context.Background(), request, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("Error creating CertificateSigningRequest %s: %s", request.Name, err)
+	}
+	return nil
+}
+
+func (i *KubernetesCsrIssuer) Retrieve(certificate *skupperv2alpha1.Certificate, issuer string) (*SignedCertificate, error) {
+	name := csrName(certificate)
+	request, err := i.client.CertificatesV1().CertificateSigningRequests().Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("Error retrieving CertificateSigningRequest %s: %s", name, err)
+	}
+	for _, condition := range request.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case certificatesv1.CertificateDenied:
+			return nil, fmt.Errorf("CertificateSigningRequest %s was denied: %s", name, condition.Message)
+		case certificatesv1.CertificateFailed:
+			return nil, fmt.Errorf("CertificateSigningRequest %s failed: %s", name, condition.Message)
+		}
+	}
+	if len(request.Status.Certificate) == 0 {
+		return nil, nil
+	}
+	_, ca := splitChain(request.Status.Certificate)
+	if len(bytes.TrimSpace(ca)) == 0 {
+		if ca, err = i.caBundle(certificate.Namespace); err != nil {
+			return nil, err
+		}
+	}
+	return &SignedCertificate{
+		Certificate: request.Status.Certificate,
+		Ca:          ca,
+	}, nil
+}
+
+func (i *KubernetesCsrIssuer) caBundle(namespace string) ([]byte, error) {
+	cm, err := i.client.CoreV1().ConfigMaps(namespace).Get(context.Background(), IssuerCaBundleConfigMap, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("Signer did not return CA and could not read ConfigMap %s: %s", IssuerCaBundleConfigMap, err)
+	}
+	bundle, ok := cm.Data["ca.crt"]
+	if !ok {
+		return nil, fmt.Errorf("Signer did not return CA and ConfigMap %s has no ca.crt", IssuerCaBundleConfigMap)
+	}
+	return []byte(bundle), nil
+}
+
+func (i *KubernetesCsrIssuer) Cancel(certificate *skupperv2alpha1.Certificate, issuer string) error {
+	name := csrName(certificate)
+	err := i.client.CertificatesV1().CertificateSigningRequests().Delete(context.Background(), name, metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("Error deleting CertificateSigningRequest %s: %s", name, err)
+	}
+	return nil
+}
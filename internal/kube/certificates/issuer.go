@@ -0,0 +1,227 @@
+package certificates
+
+import (
+	"bytes"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/skupperproject/skupper/internal/certs"
+	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+)
+
+const (
+	// Scheme for issuer references that are signed through the
+	// Kubernetes CertificateSigningRequest API, e.g. csr:example.com/my-signer
+	IssuerSchemeKubernetesCsr = "csr"
+	// Scheme for issuer references that are signed by an external
+	// agent watching for request Secrets, e.g. secret:my-pki
+	IssuerSchemeSecret = "secret"
+
+	issuerPollInterval = 10 * time.Second
+)
+
+// An ExternalIssuer obtains signatures for certificates from outside
+// the controller. The private key never leaves the controller; only a
+// certificate signing request is submitted.
+type ExternalIssuer interface {
+	// Submit requests that the supplied PEM encoded certificate
+	// signing request be signed by the named issuer.
+	Submit(certificate *skupperv2alpha1.Certificate, issuer string, csr []byte, validity time.Duration) error
+	// Retrieve returns the signed certificate once it is
+	// available. It returns nil while the request is pending and
+	// an error if the request was rejected.
+	Retrieve(certificate *skupperv2alpha1.Certificate, issuer string) (*SignedCertificate, error)
+	// Cancel removes any outstanding request for the certificate.
+	Cancel(certificate *skupperv2alpha1.Certificate, issuer string) error
+}
+
+// SignedCertificate holds the PEM encoded certificate (and any
+// intermediates) returned by an ExternalIssuer along with the PEM
+// encoded CA bundle with which it can be verified.
+type SignedCertificate struct {
+	Certificate []byte
+	Ca          []byte
+}
+
+// ParseIssuer splits a reference to an issuer, as used in
+// CertificateSpec.Ca or SiteSpec.DefaultIssuer, into scheme and
+// name. References without a scheme identify a local CA Secret.
+func ParseIssuer(ref string) (string, string, bool) {
+	scheme, name, found := strings.Cut(ref, ":")
+	if !found || scheme == "" || name == "" {
+		return "", ref, false
+	}
+	return scheme, name, true
+}
+
+// IsExternalIssuer returns true if the supplied issuer reference
+// identifies an issuer other than a local CA Secret.
+func IsExternalIssuer(ref string) bool {
+	_, _, external := ParseIssuer(ref)
+	return external
+}
+
+type pendingError struct {
+	message string
+}
+
+func (e *pendingError) Error() string {
+	return e.message
+}
+
+func isPending(err error) bool {
+	_, ok := err.(*pendingError)
+	return ok
+}
+
+type pendingRequest struct {
+	spec    skupperv2alpha1.CertificateSpec
+	key     []byte
+	polling bool
+	err     error
+}
+
+// RegisterIssuer allows an ExternalIssuer to be used for issuer
+// references with the given scheme.
+func (m *CertificateManagerImpl) RegisterIssuer(scheme string, issuer ExternalIssuer) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.issuers[scheme] = issuer
+}
+
+// Returns the credentials signed by an external issuer for the
+// supplied Certificate, submitting a request if one is not already
+// outstanding. A pendingError is returned until the signed
+// certificate is available.
+func (m *CertificateManagerImpl) requestFromIssuer(certificate *skupperv2alpha1.Certificate, scheme string, name string) (*corev1.Secret, error) {
+	issuer, ok := m.issuers[scheme]
+	if !ok {
+		return nil, fmt.Errorf("Unsupported issuer %q", certificate.Spec.Ca)
+	}
+	key := certificate.Key()
+	if pending, ok := m.pending[key]; ok {
+		if cmp.Equal(pending.spec, certificate.Spec, compareSpecUnordered...) {
+			if pending.err != nil {
+				// don't resubmit a rejected request unless the certificate changes
+				return nil, pending.err
+			}
+			signed, err := issuer.Retrieve(certificate, name)
+			if err != nil {
+				pending.err = err
+				return nil, err
+			}
+			if signed == nil {
+				m.pollIssuer(key, pending)
+				return nil, &pendingError{message: fmt.Sprintf("Waiting for %s to sign certificate", certificate.Spec.Ca)}
+			}
+			delete(m.pending, key)
+			if err := issuer.Cancel(certificate, name); err != nil {
+				return nil, err
+			}
+			return signedSecret(certificate, signed, pending.key)
+		}
+		// certificate has changed since request was submitted
+		delete(m.pending, key)
+	}
+	validity, err := m.validity(certificate)
+	if err != nil {
+		return nil, err
+	}
+	keyOptions, err := m.keyOptions(certificate)
+	if err != nil {
+		return nil, err
+	}
+	csr, privateKey, err := certs.GenerateCertificateRequest(certificate.Spec.Subject, strings.Join(certificate.Spec.Hosts, ","), keyOptions)
+	if err != nil {
+		return nil, err
+	}
+	if err := issuer.Submit(certificate, name, csr, validity); err != nil {
+		return nil, err
+	}
+	pending := &pendingRequest{
+		spec: *certificate.Spec.DeepCopy(),
+		key:  privateKey,
+	}
+	m.pending[key] = pending
+	m.pollIssuer(key, pending)
+	return nil, &pendingError{message: fmt.Sprintf("Submitted request to %s", certificate.Spec.Ca)}
+}
+
+func (m *CertificateManagerImpl) cancelRequest(key string, certificate *skupperv2alpha1.Certificate) error {
+	if _, ok := m.pending[key]; !ok {
+		return nil
+	}
+	delete(m.pending, key)
+	if certificate == nil {
+		return nil
+	}
+	if scheme, name, ok := ParseIssuer(certificate.Spec.Ca); ok {
+		if issuer, ok := m.issuers[scheme]; ok {
+			return issuer.Cancel(certificate, name)
+		}
+	}
+	return nil
+}
+
+func (m *CertificateManagerImpl) pollIssuer(key string, pending *pendingRequest) {
+	if pending.polling {
+		return
+	}
+	pending.polling = true
+	m.callbackAfter(issuerPollInterval, m.checkRequest, key)
+}
+
+// Called by EventProcessor to check on an outstanding request to an
+// external issuer.
+func (m *CertificateManagerImpl) checkRequest(key string) error {
+	pending, ok := m.pending[key]
+	if !ok {
+		return nil
+	}
+	pending.polling = false
+	definition, ok := m.definitions[key]
+	if !ok {
+		delete(m.pending, key)
+		return nil
+	}
+	return m.checkCertificate(key, definition)
+}
+
+func signedSecret(certificate *skupperv2alpha1.Certificate, signed *SignedCertificate, key []byte) (*corev1.Secret, error) {
+	if _, err := certs.DecodeCertificate(signed.Certificate); err != nil {
+		return nil, fmt.Errorf("Invalid certificate returned by %s: %s", certificate.Spec.Ca, err)
+	}
+	if len(bytes.TrimSpace(signed.Ca)) == 0 {
+		return nil, fmt.Errorf("No CA returned by %s", certificate.Spec.Ca)
+	}
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: certificate.Name,
+		},
+		Type: "kubernetes.io/tls",
+		Data: map[string][]byte{
+			"tls.crt": signed.Certificate,
+			"tls.key": key,
+			"ca.crt":  signed.Ca,
+		},
+	}, nil
+}
+
+// Splits a PEM encoded chain into the first certificate and the rest.
+func splitChain(data []byte) ([]byte, []byte) {
+	block, rest := pem.Decode(data)
+	if block == nil {
+		return data, nil
+	}
+	return pem.EncodeToMemory(block), rest
+}
@@ -0,0 +1,283 @@
+package certificates
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/skupperproject/skupper/internal/certs"
+	internalclient "github.com/skupperproject/skupper/internal/kube/client"
+	fakeclient "github.com/skupperproject/skupper/internal/kube/client/fake"
+	"github.com/skupperproject/skupper/internal/kube/watchers"
+	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+	"gotest.tools/v3/assert"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestParseIssuer(t *testing.T) {
+	testTable := []struct {
+		ref      string
+		scheme   string
+		name     string
+		external bool
+	}{
+		{ref: "skupper-site-ca", name: "skupper-site-ca"},
+		{ref: "csr:example.com/my-signer", scheme: "csr", name: "example.com/my-signer", external: true},
+		{ref: "secret:my-pki", scheme: "secret", name: "my-pki", external: true},
+		{ref: ":my-pki", name: ":my-pki"},
+		{ref: "secret:", name: "secret:"},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.ref, func(t *testing.T) {
+			scheme, name, external := ParseIssuer(tt.ref)
+			assert.Equal(t, scheme, tt.scheme)
+			assert.Equal(t, name, tt.name)
+			assert.Equal(t, external, tt.external)
+			assert.Equal(t, IsExternalIssuer(tt.ref), tt.external)
+		})
+	}
+}
+
+func TestSecretIssuer(t *testing.T) {
+	testTable := []struct {
+		name           string
+		deny           bool
+		expectedReason string
+		expectedError  string
+	}{
+		{
+			name:           "signed",
+			expectedReason: "Ready",
+		},
+		{
+			name:           "denied",
+			deny:           true,
+			expectedReason: "Error",
+			expectedError:  "Request foo-csr was denied by my-pki: not allowed",
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			cert := certificate("foo", "test", "secret:my-pki", "my-subject", []string{"aaa"}, false, true, nil, nil)
+			client, mgr, stop := startIssuerTest(t, cert)
+			defer stop()
+
+			verifyCondition(t, client, "foo", metav1.ConditionFalse, "Pending", "Submitted request to secret:my-pki")
+			secrets := client.GetKubeClient().CoreV1().Secrets("test")
+			request, err := secrets.Get(context.Background(), "foo-csr", metav1.GetOptions{})
+			assert.Assert(t, err)
+			assert.Equal(t, request.Labels[CertificateRequestLabel], "true")
+			assert.Equal(t, request.Annotations[CertificateRequestIssuerAnnotation], "my-pki")
+			assert.Assert(t, len(request.Data["tls.csr"]) > 0)
+			_, err = secrets.Get(context.Background(), "foo", metav1.GetOptions{})
+			assert.Assert(t, err != nil)
+
+			// act as the external signer
+			ca := fixtureCASecret(t, "my-pki", "test")
+			if tt.deny {
+				request.Annotations[CertificateRequestDeniedAnnotation] = "not allowed"
+			} else {
+				request.Data["tls.crt"] = signRequest(t, ca, request.Data["tls.csr"])
+				request.Data["ca.crt"] = ca.Data["tls.crt"]
+			}
+			_, err = secrets.Update(context.Background(), request, metav1.UpdateOptions{})
+			assert.Assert(t, err)
+			assert.Assert(t, mgr.checkRequest("test/foo"))
+
+			if tt.expectedError != "" {
+				verifyCondition(t, client, "foo", metav1.ConditionFalse, tt.expectedReason, tt.expectedError)
+				return
+			}
+			verifyCondition(t, client, "foo", metav1.ConditionTrue, tt.expectedReason, "OK")
+			secret, err := secrets.Get(context.Background(), "foo", metav1.GetOptions{})
+			assert.Assert(t, err)
+			_, err = tls.X509KeyPair(secret.Data["tls.crt"], secret.Data["tls.key"])
+			assert.Assert(t, err)
+			assert.DeepEqual(t, secret.Data["ca.crt"], ca.Data["tls.crt"])
+			_, err = secrets.Get(context.Background(), "foo-csr", metav1.GetOptions{})
+			assert.Assert(t, err != nil, "request Secret should have been removed")
+		})
+	}
+}
+
+func TestKubernetesCsrIssuer(t *testing.T) {
+	requestName := csrName(&skupperv2alpha1.Certificate{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "test"}})
+	testTable := []struct {
+		name           string
+		deny           bool
+		includeCa      bool
+		caBundle       bool
+		expectedReason string
+		expectedError  string
+	}{
+		{
+			name:           "signed with chain",
+			includeCa:      true,
+			expectedReason: "Ready",
+		},
+		{
+			name:           "signed with ca bundle",
+			caBundle:       true,
+			expectedReason: "Ready",
+		},
+		{
+			name:           "signed without ca",
+			expectedReason: "Error",
+			expectedError:  "Signer did not return CA and could not read ConfigMap skupper-issuer-ca-bundle: configmaps \"skupper-issuer-ca-bundle\" not found",
+		},
+		{
+			name:           "denied",
+			deny:           true,
+			expectedReason: "Error",
+			expectedError:  "CertificateSigningRequest " + requestName + " was denied: not allowed",
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			cert := certificate("foo", "test", "csr:example.com/my-signer", "my-subject", []string{"aaa"}, true, false, nil, nil)
+			client, mgr, stop := startIssuerTest(t, cert)
+			defer stop()
+
+			verifyCondition(t, client, "foo", metav1.ConditionFalse, "Pending", "Submitted request to csr:example.com/my-signer")
+			csrs := client.GetKubeClient().CertificatesV1().CertificateSigningRequests()
+			request, err := csrs.Get(context.Background(), requestName, metav1.GetOptions{})
+			assert.Assert(t, err)
+			assert.Equal(t, request.Spec.SignerName, "example.com/my-signer")
+			assert.DeepEqual(t, request.Spec.Usages, []certificatesv1.KeyUsage{certificatesv1.UsageDigitalSignature, certificatesv1.UsageKeyEncipherment, certificatesv1.UsageClientAuth})
+
+			// act as the signer
+			ca := fixtureCASecret(t, "my-signer", "test")
+			if tt.deny {
+				request.Status.Conditions = append(request.Status.Conditions, certificatesv1.CertificateSigningRequestCondition{
+					Type:    certificatesv1.CertificateDenied,
+					Status:  corev1.ConditionTrue,
+					Message: "not allowed",
+				})
+			} else {
+				request.Status.Certificate = signRequest(t, ca, request.Spec.Request)
+				if tt.includeCa {
+					request.Status.Certificate = append(request.Status.Certificate, ca.Data["tls.crt"]...)
+				}
+			}
+			if tt.caBundle {
+				_, err := client.GetKubeClient().CoreV1().ConfigMaps("test").Create(context.Background(), &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: IssuerCaBundleConfigMap},
+					Data:       map[string]string{"ca.crt": string(ca.Data["tls.crt"])},
+				}, metav1.CreateOptions{})
+				assert.Assert(t, err)
+			}
+			_, err = csrs.UpdateStatus(context.Background(), request, metav1.UpdateOptions{})
+			assert.Assert(t, err)
+			assert.Assert(t, mgr.checkRequest("test/foo"))
+
+			if tt.expectedError != "" {
+				verifyCondition(t, client, "foo", metav1.ConditionFalse, tt.expectedReason, tt.expectedError)
+				return
+			}
+			verifyCondition(t, client, "foo", metav1.ConditionTrue, tt.expectedReason, "OK")
+			secret, err := client.GetKubeClient().CoreV1().Secrets("test").Get(context.Background(), "foo", metav1.GetOptions{})
+			assert.Assert(t, err)
+			_, err = tls.X509KeyPair(secret.Data["tls.crt"], secret.Data["tls.key"])
+			assert.Assert(t, err)
+			assert.DeepEqual(t, secret.Data["ca.crt"], ca.Data["tls.crt"])
+			_, err = csrs.Get(context.Background(), requestName, metav1.GetOptions{})
+			assert.Assert(t, err != nil, "CertificateSigningRequest should have been removed")
+		})
+	}
+}
+
+func TestCsrName(t *testing.T) {
+	name := func(namespace string, name string) string {
+		return csrName(&skupperv2alpha1.Certificate{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}})
+	}
+	assert.Assert(t, strings.HasPrefix(name("test", "foo"), "skupper-test-foo-"))
+	assert.Equal(t, name("test", "foo"), name("test", "foo"))
+	assert.Assert(t, name("a-b", "c") != name("a", "b-c"))
+	long := name(strings.Repeat("n", 63), strings.Repeat("x", 252)+".y")
+	assert.Assert(t, len(long) <= maxCsrNameLength)
+	assert.Assert(t, !strings.Contains(long, ".-"))
+}
+
+func TestCsrUsages(t *testing.T) {
+	testTable := []struct {
+		algorithm certs.KeyAlgorithm
+		expected  []certificatesv1.KeyUsage
+	}{
+		{
+			algorithm: certs.KeyAlgorithmRSA,
+			expected:  []certificatesv1.KeyUsage{certificatesv1.UsageDigitalSignature, certificatesv1.UsageKeyEncipherment, certificatesv1.UsageServerAuth},
+		},
+		{
+			algorithm: certs.KeyAlgorithmECDSA,
+			expected:  []certificatesv1.KeyUsage{certificatesv1.UsageDigitalSignature, certificatesv1.UsageServerAuth},
+		},
+		{
+			algorithm: certs.KeyAlgorithmEd25519,
+			expected:  []certificatesv1.KeyUsage{certificatesv1.UsageDigitalSignature, certificatesv1.UsageServerAuth},
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(string(tt.algorithm), func(t *testing.T) {
+			csr, _, err := certs.GenerateCertificateRequest("my-subject", "aaa", certs.KeyOptions{Algorithm: tt.algorithm})
+			assert.Assert(t, err)
+			cert := certificate("foo", "test", "csr:example.com/my-signer", "my-subject", []string{"aaa"}, false, true, nil, nil)
+			assert.DeepEqual(t, csrUsages(cert, csr), tt.expected)
+		})
+	}
+}
+
+func startIssuerTest(t *testing.T, cert *skupperv2alpha1.Certificate) (*internalclient.KubeClient, *CertificateManagerImpl, func()) {
+	client, err := fakeclient.NewFakeClient("test", nil, []runtime.Object{cert}, "")
+	assert.Assert(t, err)
+	processor := watchers.NewEventProcessor("Controller", client)
+	mgr := NewCertificateManager(processor)
+	mgr.Watch(metav1.NamespaceAll)
+	stopCh := make(chan struct{})
+	processor.StartWatchers(stopCh)
+	processor.WaitForCacheSync(stopCh)
+	mgr.Recover()
+	return client, mgr, func() { close(stopCh) }
+}
+
+func verifyCondition(t *testing.T, client *internalclient.KubeClient, name string, status metav1.ConditionStatus, reason string, message string) {
+	t.Helper()
+	actual, err := client.GetSkupperClient().SkupperV2alpha1().Certificates("test").Get(context.Background(), name, metav1.GetOptions{})
+	assert.Assert(t, err)
+	verifyStatus(t, skupperv2alpha1.Status{
+		Conditions: []metav1.Condition{condition(skupperv2alpha1.CONDITION_TYPE_READY, status, reason, message)},
+	}, actual.Status.Status)
+}
+
+func signRequest(t *testing.T, ca *corev1.Secret, data []byte) []byte {
+	t.Helper()
+	block, _ := pem.Decode(data)
+	assert.Assert(t, block != nil)
+	request, err := x509.ParseCertificateRequest(block.Bytes)
+	assert.Assert(t, err)
+	assert.Assert(t, request.CheckSignature())
+	signer, err := tls.X509KeyPair(ca.Data["tls.crt"], ca.Data["tls.key"])
+	assert.Assert(t, err)
+	caCert, err := certs.DecodeCertificate(ca.Data["tls.crt"])
+	assert.Assert(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      request.Subject,
+		DNSNames:     request.DNSNames,
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, request.PublicKey, signer.PrivateKey)
+	assert.Assert(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
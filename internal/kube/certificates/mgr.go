@@ -4,11 +4,15 @@
 package certificates
 
 import (
+	"bytes"
 	"context"
+	"crypto/x509"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"log"
 	"strings"
+	"sync"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -50,6 +54,11 @@ type CertificateManagerImpl struct {
 	secretWatcher      *watchers.SecretWatcher
 	processor          *watchers.EventProcessor
 	context            ControllerContext
+	config             *Config
+	renewals           map[string]time.Time
+	issuers            map[string]ExternalIssuer
+	pending            map[string]*pendingRequest
+	lock               sync.Mutex
 }
 
 // Returns a correctly initialised CertificateManager.
@@ -58,6 +67,13 @@ func NewCertificateManager(processor *watchers.EventProcessor) *CertificateManag
 		definitions: map[string]*skupperv2alpha1.Certificate{},
 		secrets:     map[string]*corev1.Secret{},
 		processor:   processor,
+		config:      DefaultConfig(),
+		renewals:    map[string]time.Time{},
+		issuers: map[string]ExternalIssuer{
+			IssuerSchemeKubernetesCsr: NewKubernetesCsrIssuer(processor.GetKubeClient()),
+			IssuerSchemeSecret:        NewSecretIssuer(processor.GetKubeClient()),
+		},
+		pending: map[string]*pendingRequest{},
 	}
 }
 
@@ -66,10 +82,39 @@ func (m *CertificateManagerImpl) SetControllerContext(context ControllerContext)
 	m.context = context
 }
 
+// Allows the default validity and renewal window for generated
+// certificates to be changed.
+func (m *CertificateManagerImpl) SetConfig(config *Config) {
+	m.config = config
+}
+
 // Causes the CertificateManager to start watching relevant resources.
 func (m *CertificateManagerImpl) Watch(watchNamespace string) {
-	m.certificateWatcher = m.processor.WatchCertificates(watchNamespace, watchers.FilterByNamespace(m.isControlled, m.checkCertificate))
-	m.secretWatcher = m.processor.WatchAllSecrets(watchNamespace, watchers.FilterByNamespace(m.isControlled, m.checkSecret))
+	m.certificateWatcher = m.processor.WatchCertificates(watchNamespace, watchers.FilterByNamespace(m.isControlled, m.certificateChanged))
+	m.secretWatcher = m.processor.WatchAllSecrets(watchNamespace, watchers.FilterByNamespace(m.isControlled, m.secretChanged))
+}
+
+// The CertificateManager may be used from more than one go routine
+// when events are processed for different namespaces in parallel, so
+// all entry points hold the lock.
+func (m *CertificateManagerImpl) certificateChanged(key string, certificate *skupperv2alpha1.Certificate) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.checkCertificate(key, certificate)
+}
+
+func (m *CertificateManagerImpl) secretChanged(key string, secret *corev1.Secret) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.checkSecret(key, secret)
+}
+
+func (m *CertificateManagerImpl) callbackAfter(delay time.Duration, callback watchers.Callback, key string) {
+	m.processor.CallbackAfter(delay, func(key string) error {
+		m.lock.Lock()
+		defer m.lock.Unlock()
+		return callback(key)
+	}, key)
 }
 
 func (m *CertificateManagerImpl) isControlled(namespace string) bool {
@@ -83,6 +128,8 @@ func (m *CertificateManagerImpl) isControlled(namespace string) bool {
 // correct internal state. This should only be called after Watch()
 // has been invoked.
 func (m *CertificateManagerImpl) Recover() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	for _, secret := range m.secretWatcher.List() {
 		if !m.isControlled(secret.Namespace) {
 			continue
@@ -99,10 +146,22 @@ func (m *CertificateManagerImpl) Recover() {
 	}
 }
 
+// Returns the Certificate resources currently known to the
+// CertificateManager. This should only be called after Watch() has
+// been invoked.
+func (m *CertificateManagerImpl) List() []*skupperv2alpha1.Certificate {
+	if m.certificateWatcher == nil {
+		return nil
+	}
+	return m.certificateWatcher.List()
+}
+
 // This method is called to ensure that a Certificate resource exists
 // to represent a CA (i.e. certificate issuer) with the properties
 // specified in the arguments.
 func (m *CertificateManagerImpl) EnsureCA(namespace string, name string, subject string, refs []metav1.OwnerReference) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	spec := skupperv2alpha1.CertificateSpec{
 		Subject: subject,
 		Signing: true,
@@ -119,6 +178,8 @@ func (m *CertificateManagerImpl) EnsureCA(namespace string, name string, subject
 // the certificate. This allows the same certificate to be used for
 // multiple resources such as Routes.
 func (m *CertificateManagerImpl) Ensure(namespace string, name string, ca string, subject string, hosts []string, client bool, server bool, refs []metav1.OwnerReference) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	spec := skupperv2alpha1.CertificateSpec{
 		Ca:      ca,
 		Subject: subject,
@@ -138,6 +199,17 @@ func (m *CertificateManagerImpl) ensure(namespace string, name string, spec skup
 	key := fmt.Sprintf("%s/%s", namespace, name)
 	if current, ok := m.definitions[key]; ok {
 		changed := false
+		// validity and renewal window are not set by callers of
+		// Ensure() so retain any value set directly on the resource:
+		if spec.Validity == "" {
+			spec.Validity = current.Spec.Validity
+		}
+		if spec.RenewBefore == "" {
+			spec.RenewBefore = current.Spec.RenewBefore
+		}
+		if spec.Settings == nil {
+			spec.Settings = current.Spec.Settings
+		}
 		if mergeOwnerReferences(current.ObjectMeta.OwnerReferences, refs) {
 			changed = true
 		}
@@ -235,7 +307,11 @@ func (m *CertificateManagerImpl) reconcile(key string, certificate *skupperv2alp
 }
 
 func (m *CertificateManagerImpl) certificateDeleted(key string) error {
+	if err := m.cancelRequest(key, m.definitions[key]); err != nil {
+		log.Printf("Error cancelling request for certificate %s: %s", key, err)
+	}
 	delete(m.definitions, key)
+	delete(m.renewals, key)
 	if secret, ok := m.secrets[key]; ok {
 		err := m.processor.GetKubeClient().CoreV1().Secrets(secret.Namespace).Delete(context.Background(), secret.Name, metav1.DeleteOptions{})
 		if err != nil {
@@ -253,7 +329,16 @@ func (m *CertificateManagerImpl) secretDeleted(key string) error {
 }
 
 func (m *CertificateManagerImpl) updateStatus(certificate *skupperv2alpha1.Certificate, err error) error {
-	if certificate.SetReady(err) {
+	var changed bool
+	if isPending(err) {
+		changed = certificate.SetPending(err.Error())
+	} else {
+		changed = certificate.SetReady(err)
+	}
+	if err == nil && m.updateValidity(certificate) {
+		changed = true
+	}
+	if changed {
 		latest, err := m.processor.GetSkupperClient().SkupperV2alpha1().Certificates(certificate.Namespace).UpdateStatus(context.TODO(), certificate, metav1.UpdateOptions{})
 		if err != nil {
 			return err
@@ -269,16 +354,28 @@ func (m *CertificateManagerImpl) updateStatus(certificate *skupperv2alpha1.Certi
 func (m *CertificateManagerImpl) updateSecret(key string, certificate *skupperv2alpha1.Certificate, secret *corev1.Secret) error {
 	changed := false
 	controlled := isSecretControlled(secret)
-	if !isSecretCorrect(certificate, secret) {
+	if !isSecretCorrect(certificate, secret) || (controlled && m.requiresReissue(certificate, secret)) {
 		if !controlled {
 			return errors.New("Secret exists but is not controlled by skupper")
 		}
 
 		regenerated, err := m.generateSecret(certificate)
-		if err != nil {
+		if isPending(err) && isSecretCorrect(certificate, secret) {
+			// keep using current credentials until the replacement is signed
+			log.Printf("Renewal of Secret %s/%s for Certificate %s pending: %s", certificate.Namespace, secret.Name, key, err)
+			return nil
+		} else if err != nil {
 			log.Printf("Error generating Secret %s/%s for Certificate %s", certificate.Namespace, secret.Name, key)
 			return err
 		}
+		if !certificate.Spec.Signing {
+			// keep trusting the previous CA until it expires, so
+			// that peers still presenting certificates it signed
+			// are not disconnected while they are rotated
+			if previous := previousCa(secret, regenerated); previous != nil {
+				regenerated.Data["ca.crt"] = append(regenerated.Data["ca.crt"], previous...)
+			}
+		}
 		changed = true
 		secret.Data = regenerated.Data
 		secret.Annotations["internal.skupper.io/hosts"] = strings.Join(certificate.Spec.Hosts, ",")
@@ -313,10 +410,26 @@ func (m *CertificateManagerImpl) updateSecret(key string, certificate *skupperv2
 
 func (m *CertificateManagerImpl) generateSecret(certificate *skupperv2alpha1.Certificate) (*corev1.Secret, error) {
 	var secret corev1.Secret
+	expiration, err := m.validity(certificate)
+	if err != nil {
+		return nil, err
+	}
+	key, err := m.keyOptions(certificate)
+	if err != nil {
+		return nil, err
+	}
 	if certificate.Spec.Signing {
-		secret = certs.GenerateSecret(certificate.Name, certificate.Spec.Subject, "", 0, nil)
+		secret, err = certs.GenerateSecretWithKey(certificate.Name, certificate.Spec.Subject, "", expiration, nil, key)
+		if err != nil {
+			return nil, err
+		}
+	} else if scheme, name, ok := ParseIssuer(certificate.Spec.Ca); ok {
+		signed, err := m.requestFromIssuer(certificate, scheme, name)
+		if err != nil {
+			return nil, err
+		}
+		secret = *signed
 	} else {
-		expiration := time.Hour * 24 * 365 * 5 // TODO: make this configurable (through controller setting or field on certificate?)
 		caKey := fmt.Sprintf("%s/%s", certificate.Namespace, certificate.Spec.Ca)
 		ca, ok := m.secrets[caKey]
 		if !ok {
@@ -324,7 +437,10 @@ func (m *CertificateManagerImpl) generateSecret(certificate *skupperv2alpha1.Cer
 			return nil, fmt.Errorf("CA %q not found", caKey)
 		}
 		// TODO: handle server and client roles properly
-		secret = certs.GenerateSecret(certificate.Name, certificate.Spec.Subject, strings.Join(certificate.Spec.Hosts, ","), expiration, ca)
+		secret, err = certs.GenerateSecretWithKey(certificate.Name, certificate.Spec.Subject, strings.Join(certificate.Spec.Hosts, ","), expiration, ca, key)
+		if err != nil {
+			return nil, err
+		}
 	}
 	secret.ObjectMeta.OwnerReferences = ownerReferences(certificate)
 	return &secret, nil
@@ -332,7 +448,10 @@ func (m *CertificateManagerImpl) generateSecret(certificate *skupperv2alpha1.Cer
 
 func (m *CertificateManagerImpl) createSecret(key string, certificate *skupperv2alpha1.Certificate) error {
 	secret, err := m.generateSecret(certificate)
-	if err != nil {
+	if isPending(err) {
+		log.Printf("Secret for Certificate %s pending: %s", key, err)
+		return err
+	} else if err != nil {
 		log.Printf("Error generating secret for Certificate %s: %s", key, err)
 		return err
 	}
@@ -365,11 +484,180 @@ func (m *CertificateManagerImpl) checkSecret(key string, secret *corev1.Secret)
 		return m.secretDeleted(key)
 	}
 	m.secrets[key] = secret
+	if name, ok := requestedCertificate(secret); ok {
+		// an external signer may have responded to a request
+		return m.checkRequest(fmt.Sprintf("%s/%s", secret.Namespace, name))
+	}
 	if definition, ok := m.definitions[key]; ok {
-		return m.reconcile(key, definition, secret)
+		if err := m.reconcile(key, definition, secret); err != nil {
+			return err
+		}
 	}
 
-	return nil
+	return m.reconcileDependents(key)
+}
+
+// When a CA is (re)issued, any certificates it signed need to be
+// re-signed. The updated Secrets are then picked up by the router's
+// config-sync, which reloads the corresponding sslProfiles.
+func (m *CertificateManagerImpl) reconcileDependents(caKey string) error {
+	var errs []error
+	for key, definition := range m.definitions {
+		if definition.Spec.Signing || definition.Spec.Ca == "" {
+			continue
+		}
+		if fmt.Sprintf("%s/%s", definition.Namespace, definition.Spec.Ca) != caKey {
+			continue
+		}
+		if err := m.checkCertificate(key, definition); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *CertificateManagerImpl) validity(certificate *skupperv2alpha1.Certificate) (time.Duration, error) {
+	if certificate.Spec.Validity == "" {
+		return m.config.Validity, nil
+	}
+	d, err := time.ParseDuration(certificate.Spec.Validity)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid validity %q: %s", certificate.Spec.Validity, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("Invalid validity %q: must be positive", certificate.Spec.Validity)
+	}
+	return d, nil
+}
+
+func (m *CertificateManagerImpl) keyOptions(certificate *skupperv2alpha1.Certificate) (certs.KeyOptions, error) {
+	key, err := certs.KeyOptionsFromSettings(certificate.Spec.Settings, m.config.keyOptions())
+	if err != nil {
+		return key, fmt.Errorf("Invalid key options: %s", err)
+	}
+	return key, nil
+}
+
+func (m *CertificateManagerImpl) renewBefore(certificate *skupperv2alpha1.Certificate) (time.Duration, error) {
+	if certificate.Spec.RenewBefore == "" {
+		return m.config.RenewBefore, nil
+	}
+	d, err := time.ParseDuration(certificate.Spec.RenewBefore)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid renewal window %q: %s", certificate.Spec.RenewBefore, err)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("Invalid renewal window %q: must not be negative", certificate.Spec.RenewBefore)
+	}
+	return d, nil
+}
+
+// Determines whether a Secret that is otherwise correct for the
+// supplied Certificate should nevertheless be regenerated, either
+// because it is due for renewal, because its key does not match the
+// requested key options or because it was not signed by the current
+// version of its CA.
+func (m *CertificateManagerImpl) requiresReissue(certificate *skupperv2alpha1.Certificate, secret *corev1.Secret) bool {
+	cert, err := certs.DecodeCertificate(secret.Data["tls.crt"])
+	if err != nil {
+		return true
+	}
+	if renewBefore, err := m.renewBefore(certificate); err == nil && !time.Now().Before(renewalTime(cert, renewBefore)) {
+		log.Printf("Certificate %s is due for renewal", certificate.Key())
+		return true
+	}
+	if key, err := m.keyOptions(certificate); err == nil && !certs.MatchesKeyOptions(cert, key) {
+		log.Printf("Key options for certificate %s have changed to %s", certificate.Key(), key)
+		return true
+	}
+	if certificate.Spec.Signing {
+		return false
+	}
+	ca, ok := m.secrets[fmt.Sprintf("%s/%s", certificate.Namespace, certificate.Spec.Ca)]
+	if !ok || len(ca.Data["tls.crt"]) == 0 {
+		return false
+	}
+	if !bytes.HasPrefix(secret.Data["ca.crt"], ca.Data["tls.crt"]) {
+		log.Printf("CA for certificate %s has changed", certificate.Key())
+		return true
+	}
+	return false
+}
+
+// Records the validity of the current Secret for the Certificate in
+// its status and schedules renewal where the Secret is controlled by
+// skupper.
+func (m *CertificateManagerImpl) updateValidity(certificate *skupperv2alpha1.Certificate) bool {
+	secret, ok := m.secrets[certificate.Key()]
+	if !ok {
+		return false
+	}
+	cert, err := certs.DecodeCertificate(secret.Data["tls.crt"])
+	if err != nil {
+		return false
+	}
+	var renewal time.Time
+	if isSecretControlled(secret) {
+		if renewBefore, err := m.renewBefore(certificate); err == nil {
+			renewal = renewalTime(cert, renewBefore)
+			m.scheduleRenewal(certificate.Key(), renewal)
+		}
+	}
+	return certificate.SetValidity(cert.NotBefore, cert.NotAfter, renewal)
+}
+
+func (m *CertificateManagerImpl) scheduleRenewal(key string, renewal time.Time) {
+	if scheduled, ok := m.renewals[key]; ok && scheduled.Equal(renewal) {
+		return
+	}
+	m.renewals[key] = renewal
+	m.callbackAfter(time.Until(renewal), m.renew, key)
+}
+
+// Called by EventProcessor when a scheduled renewal is due.
+func (m *CertificateManagerImpl) renew(key string) error {
+	renewal, ok := m.renewals[key]
+	if !ok || time.Now().Before(renewal) {
+		// renewal was cancelled or has been rescheduled
+		return nil
+	}
+	delete(m.renewals, key)
+	definition, ok := m.definitions[key]
+	if !ok {
+		return nil
+	}
+	log.Printf("Renewing certificate %s", key)
+	return m.checkCertificate(key, definition)
+}
+
+// Returns the time at which a certificate should be reissued. If the
+// renewal window is as long as the certificate's lifetime, the final
+// third of that lifetime is used instead.
+func renewalTime(cert *x509.Certificate, renewBefore time.Duration) time.Time {
+	lifetime := cert.NotAfter.Sub(cert.NotBefore)
+	if renewBefore >= lifetime {
+		renewBefore = lifetime / 3
+	}
+	return cert.NotAfter.Add(-renewBefore)
+}
+
+// Returns the PEM encoded CA certificate from the original Secret if
+// that differs from the CA in the regenerated Secret and has not yet
+// expired.
+func previousCa(original *corev1.Secret, regenerated *corev1.Secret) []byte {
+	data := original.Data["ca.crt"]
+	if len(data) == 0 || bytes.HasPrefix(data, regenerated.Data["ca.crt"]) {
+		return nil
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil || time.Now().After(cert.NotAfter) {
+		return nil
+	}
+	return pem.EncodeToMemory(block)
 }
 
 func isSecretCorrect(certificate *skupperv2alpha1.Certificate, secret *corev1.Secret) bool {
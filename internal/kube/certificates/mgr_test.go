@@ -2,6 +2,8 @@ package certificates
 
 import (
 	"context"
+	"crypto/x509"
+	"strings"
 	"testing"
 	"time"
 
@@ -520,7 +522,336 @@ func (c *Call) eventcount(events int) *Call {
 
 func fixtureCASecret(t *testing.T, name, namespace string) *corev1.Secret {
 	t.Helper()
-	secret := certs.GenerateSecret(name, "skupper test CA", "", time.Hour*8, nil)
+	secret, err := certs.GenerateSecret(name, "skupper test CA", "", time.Hour*8, nil)
+	assert.Assert(t, err)
 	secret.Namespace = namespace
 	return &secret
 }
+
+func TestCertificateRotation(t *testing.T) {
+	oldCa := fixtureCASecret(t, "my-ca", "test")
+	newCa := fixtureCASecret(t, "my-ca", "test")
+	signedByOldCa, err := certs.GenerateSecret("foo", "my-subject", "aaa,bbb", time.Hour, oldCa)
+	assert.Assert(t, err)
+	signedByOldCa.Namespace = "test"
+	signedByOldCa.Annotations = map[string]string{
+		"internal.skupper.io/controlled": "true",
+	}
+	withValidity := func(cert *skupperv2alpha1.Certificate, validity string, renewBefore string) *skupperv2alpha1.Certificate {
+		cert.Spec.Validity = validity
+		cert.Spec.RenewBefore = renewBefore
+		return cert
+	}
+	testTable := []struct {
+		name                string
+		k8sObjects          []runtime.Object
+		skupperObjects      []runtime.Object
+		config              *Config
+		expectedCa          *corev1.Secret
+		expectedPreviousCa  *corev1.Secret
+		expectedLifetime    time.Duration
+		expectedRenewBefore time.Duration
+		expectedStatus      skupperv2alpha1.Status
+	}{
+		{
+			name: "certificate signed by previous ca is reissued",
+			k8sObjects: []runtime.Object{
+				newCa,
+				&signedByOldCa,
+			},
+			skupperObjects: []runtime.Object{
+				certificate("foo", "test", "my-ca", "my-subject", []string{"aaa", "bbb"}, false, true, nil, nil),
+			},
+			expectedCa:          newCa,
+			expectedPreviousCa:  oldCa,
+			expectedLifetime:    defaultValidity,
+			expectedRenewBefore: defaultRenewBefore,
+			expectedStatus: skupperv2alpha1.Status{
+				Conditions: []metav1.Condition{condition(skupperv2alpha1.CONDITION_TYPE_READY, metav1.ConditionTrue, "Ready", "OK")},
+			},
+		},
+		{
+			name: "validity from certificate spec",
+			k8sObjects: []runtime.Object{
+				newCa,
+			},
+			skupperObjects: []runtime.Object{
+				withValidity(certificate("foo", "test", "my-ca", "my-subject", []string{"aaa", "bbb"}, false, true, nil, nil), "48h", "12h"),
+			},
+			expectedCa:          newCa,
+			expectedLifetime:    48 * time.Hour,
+			expectedRenewBefore: 12 * time.Hour,
+			expectedStatus: skupperv2alpha1.Status{
+				Conditions: []metav1.Condition{condition(skupperv2alpha1.CONDITION_TYPE_READY, metav1.ConditionTrue, "Ready", "OK")},
+			},
+		},
+		{
+			name: "validity from config",
+			k8sObjects: []runtime.Object{
+				newCa,
+			},
+			skupperObjects: []runtime.Object{
+				certificate("foo", "test", "my-ca", "my-subject", []string{"aaa", "bbb"}, false, true, nil, nil),
+			},
+			config: &Config{
+				Validity:    24 * time.Hour,
+				RenewBefore: 2 * time.Hour,
+			},
+			expectedCa:          newCa,
+			expectedLifetime:    24 * time.Hour,
+			expectedRenewBefore: 2 * time.Hour,
+			expectedStatus: skupperv2alpha1.Status{
+				Conditions: []metav1.Condition{condition(skupperv2alpha1.CONDITION_TYPE_READY, metav1.ConditionTrue, "Ready", "OK")},
+			},
+		},
+		{
+			name: "renewal window longer than validity",
+			k8sObjects: []runtime.Object{
+				newCa,
+			},
+			skupperObjects: []runtime.Object{
+				withValidity(certificate("foo", "test", "my-ca", "my-subject", []string{"aaa", "bbb"}, false, true, nil, nil), "3h", "10h"),
+			},
+			expectedCa:          newCa,
+			expectedLifetime:    3 * time.Hour,
+			expectedRenewBefore: time.Hour,
+			expectedStatus: skupperv2alpha1.Status{
+				Conditions: []metav1.Condition{condition(skupperv2alpha1.CONDITION_TYPE_READY, metav1.ConditionTrue, "Ready", "OK")},
+			},
+		},
+		{
+			name: "invalid validity",
+			k8sObjects: []runtime.Object{
+				newCa,
+			},
+			skupperObjects: []runtime.Object{
+				withValidity(certificate("foo", "test", "my-ca", "my-subject", []string{"aaa", "bbb"}, false, true, nil, nil), "forever", ""),
+			},
+			expectedStatus: skupperv2alpha1.Status{
+				Conditions: []metav1.Condition{condition(skupperv2alpha1.CONDITION_TYPE_READY, metav1.ConditionFalse, "Error", "Invalid validity \"forever\": time: invalid duration \"forever\"")},
+			},
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := fakeclient.NewFakeClient("test", tt.k8sObjects, tt.skupperObjects, "")
+			assert.Assert(t, err)
+			processor := watchers.NewEventProcessor("Controller", client)
+			mgr := NewCertificateManager(processor)
+			if tt.config != nil {
+				mgr.SetConfig(tt.config)
+			}
+			mgr.Watch(metav1.NamespaceAll)
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+			processor.StartWatchers(stopCh)
+			processor.WaitForCacheSync(stopCh)
+			mgr.Recover()
+
+			actual, err := client.GetSkupperClient().SkupperV2alpha1().Certificates("test").Get(context.Background(), "foo", metav1.GetOptions{})
+			assert.Assert(t, err)
+			verifyStatus(t, tt.expectedStatus, actual.Status.Status)
+			if tt.expectedCa == nil {
+				assert.Equal(t, actual.Status.NotAfter, "")
+				return
+			}
+
+			secret, err := client.GetKubeClient().CoreV1().Secrets("test").Get(context.Background(), "foo", metav1.GetOptions{})
+			assert.Assert(t, err)
+			cert, err := certs.DecodeCertificate(secret.Data["tls.crt"])
+			assert.Assert(t, err)
+			ca, err := certs.DecodeCertificate(tt.expectedCa.Data["tls.crt"])
+			assert.Assert(t, err)
+			assert.Assert(t, cert.CheckSignatureFrom(ca))
+			assert.Assert(t, strings.HasPrefix(string(secret.Data["ca.crt"]), string(tt.expectedCa.Data["tls.crt"])))
+			if tt.expectedPreviousCa != nil {
+				assert.Assert(t, cmp.Contains(string(secret.Data["ca.crt"]), string(tt.expectedPreviousCa.Data["tls.crt"])))
+			} else {
+				assert.DeepEqual(t, secret.Data["ca.crt"], tt.expectedCa.Data["tls.crt"])
+			}
+			assert.Equal(t, cert.NotAfter.Sub(cert.NotBefore), tt.expectedLifetime)
+
+			assert.Equal(t, actual.Status.NotBefore, cert.NotBefore.UTC().Format(time.RFC3339))
+			assert.Equal(t, actual.Status.NotAfter, cert.NotAfter.UTC().Format(time.RFC3339))
+			assert.Equal(t, actual.Status.Expiration, actual.Status.NotAfter)
+			assert.Equal(t, actual.Status.RenewalTime, cert.NotAfter.Add(-tt.expectedRenewBefore).UTC().Format(time.RFC3339))
+			assert.Assert(t, mgr.renewals["test/foo"].Equal(cert.NotAfter.Add(-tt.expectedRenewBefore)))
+		})
+	}
+}
+
+func TestCaRotationReissuesDependents(t *testing.T) {
+	client, err := fakeclient.NewFakeClient("test", nil, nil, "")
+	assert.Assert(t, err)
+	processor := watchers.NewEventProcessor("Controller", client)
+	mgr := NewCertificateManager(processor)
+	mgr.Watch(metav1.NamespaceAll)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	processor.StartWatchers(stopCh)
+	processor.WaitForCacheSync(stopCh)
+	mgr.Recover()
+
+	for _, c := range []*Call{
+		call("my-ca", "test").ensureCa("my-cas-subject"),
+		call("foo", "test").ensure("my-ca", "my-subject", []string{"aaa"}, false, true),
+	} {
+		assert.Assert(t, c.invoke(mgr))
+		processor.TestProcess()
+		processor.TestProcess()
+	}
+	original, err := client.GetKubeClient().CoreV1().Secrets("test").Get(context.Background(), "foo", metav1.GetOptions{})
+	assert.Assert(t, err)
+
+	// force the CA to be reissued by removing its certificate
+	ca, err := client.GetKubeClient().CoreV1().Secrets("test").Get(context.Background(), "my-ca", metav1.GetOptions{})
+	assert.Assert(t, err)
+	delete(ca.Data, "tls.crt")
+	assert.Assert(t, mgr.checkSecret("test/my-ca", ca))
+
+	rotated, err := client.GetKubeClient().CoreV1().Secrets("test").Get(context.Background(), "my-ca", metav1.GetOptions{})
+	assert.Assert(t, err)
+	reissued, err := client.GetKubeClient().CoreV1().Secrets("test").Get(context.Background(), "foo", metav1.GetOptions{})
+	assert.Assert(t, err)
+	assert.Assert(t, string(reissued.Data["tls.crt"]) != string(original.Data["tls.crt"]))
+	assert.Assert(t, strings.HasPrefix(string(reissued.Data["ca.crt"]), string(rotated.Data["tls.crt"])))
+	assert.Assert(t, cmp.Contains(string(reissued.Data["ca.crt"]), string(original.Data["ca.crt"])))
+}
+
+func TestRenewalTime(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name        string
+		lifetime    time.Duration
+		renewBefore time.Duration
+		expected    time.Duration
+	}{
+		{
+			name:        "within lifetime",
+			lifetime:    24 * time.Hour,
+			renewBefore: 6 * time.Hour,
+			expected:    18 * time.Hour,
+		},
+		{
+			name:        "zero window",
+			lifetime:    24 * time.Hour,
+			renewBefore: 0,
+			expected:    24 * time.Hour,
+		},
+		{
+			name:        "window exceeds lifetime",
+			lifetime:    9 * time.Hour,
+			renewBefore: 24 * time.Hour,
+			expected:    6 * time.Hour,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cert := &x509.Certificate{
+				NotBefore: now,
+				NotAfter:  now.Add(tt.lifetime),
+			}
+			assert.Equal(t, renewalTime(cert, tt.renewBefore), now.Add(tt.expected))
+		})
+	}
+}
+
+func TestCertificateKeyOptions(t *testing.T) {
+	ca := fixtureCASecret(t, "my-ca", "test")
+	existing, err := certs.GenerateSecret("foo", "my-subject", "aaa", time.Hour, ca)
+	assert.Assert(t, err)
+	existing.Namespace = "test"
+	existing.Annotations = map[string]string{
+		"internal.skupper.io/controlled": "true",
+	}
+	withSettings := func(cert *skupperv2alpha1.Certificate, settings map[string]string) *skupperv2alpha1.Certificate {
+		cert.Spec.Settings = settings
+		return cert
+	}
+	testTable := []struct {
+		name           string
+		k8sObjects     []runtime.Object
+		certificate    *skupperv2alpha1.Certificate
+		config         *Config
+		expectedKey    certs.KeyOptions
+		expectedStatus metav1.ConditionStatus
+		expectedReason string
+		expectedError  string
+	}{
+		{
+			name:           "inherited from ca",
+			k8sObjects:     []runtime.Object{ca},
+			certificate:    certificate("foo", "test", "my-ca", "my-subject", []string{"aaa"}, false, true, nil, nil),
+			expectedKey:    certs.KeyOptions{Algorithm: certs.KeyAlgorithmRSA, Size: 2048},
+			expectedStatus: metav1.ConditionTrue,
+			expectedReason: "Ready",
+		},
+		{
+			name:           "from settings",
+			k8sObjects:     []runtime.Object{ca},
+			certificate:    withSettings(certificate("foo", "test", "my-ca", "my-subject", []string{"aaa"}, false, true, nil, nil), map[string]string{"key-algorithm": "ecdsa", "key-size": "384"}),
+			expectedKey:    certs.KeyOptions{Algorithm: certs.KeyAlgorithmECDSA, Size: 384},
+			expectedStatus: metav1.ConditionTrue,
+			expectedReason: "Ready",
+		},
+		{
+			name:       "from config",
+			k8sObjects: []runtime.Object{ca},
+			config: &Config{
+				Validity:     time.Hour,
+				KeyAlgorithm: "ecdsa",
+			},
+			certificate:    certificate("foo", "test", "my-ca", "my-subject", []string{"aaa"}, false, true, nil, nil),
+			expectedKey:    certs.KeyOptions{Algorithm: certs.KeyAlgorithmECDSA, Size: 256},
+			expectedStatus: metav1.ConditionTrue,
+			expectedReason: "Ready",
+		},
+		{
+			name:           "existing secret reissued when key options change",
+			k8sObjects:     []runtime.Object{ca, &existing},
+			certificate:    withSettings(certificate("foo", "test", "my-ca", "my-subject", []string{"aaa"}, false, true, nil, nil), map[string]string{"key-algorithm": "ed25519"}),
+			expectedKey:    certs.KeyOptions{Algorithm: certs.KeyAlgorithmEd25519},
+			expectedStatus: metav1.ConditionTrue,
+			expectedReason: "Ready",
+		},
+		{
+			name:           "invalid settings",
+			k8sObjects:     []runtime.Object{ca},
+			certificate:    withSettings(certificate("foo", "test", "my-ca", "my-subject", []string{"aaa"}, false, true, nil, nil), map[string]string{"key-algorithm": "ecdsa", "key-size": "1024"}),
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: "Error",
+			expectedError:  "Invalid key options: unsupported ECDSA key size 1024, must be one of 256, 384 or 521",
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := fakeclient.NewFakeClient("test", tt.k8sObjects, []runtime.Object{tt.certificate}, "")
+			assert.Assert(t, err)
+			processor := watchers.NewEventProcessor("Controller", client)
+			mgr := NewCertificateManager(processor)
+			if tt.config != nil {
+				mgr.SetConfig(tt.config)
+			}
+			mgr.Watch(metav1.NamespaceAll)
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+			processor.StartWatchers(stopCh)
+			processor.WaitForCacheSync(stopCh)
+			mgr.Recover()
+
+			actual, err := client.GetSkupperClient().SkupperV2alpha1().Certificates("test").Get(context.Background(), "foo", metav1.GetOptions{})
+			assert.Assert(t, err)
+			verifyStatus(t, skupperv2alpha1.Status{
+				Conditions: []metav1.Condition{condition(skupperv2alpha1.CONDITION_TYPE_READY, tt.expectedStatus, tt.expectedReason, tt.expectedError)},
+			}, actual.Status.Status)
+			if tt.expectedError != "" {
+				return
+			}
+			secret, err := client.GetKubeClient().CoreV1().Secrets("test").Get(context.Background(), "foo", metav1.GetOptions{})
+			assert.Assert(t, err)
+			cert, err := certs.DecodeCertificate(secret.Data["tls.crt"])
+			assert.Assert(t, err)
+			assert.Assert(t, certs.MatchesKeyOptions(cert, tt.expectedKey), "expected %s key", tt.expectedKey)
+		})
+	}
+}
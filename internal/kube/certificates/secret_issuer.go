@@ -0,0 +1,126 @@
+package certificates
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+)
+
+const (
+	// Label identifying request Secrets for an external signer.
+	CertificateRequestLabel = "skupper.io/certificate-request"
+	// Annotation on request Secrets naming the requested issuer.
+	CertificateRequestIssuerAnnotation = "skupper.io/issuer"
+	// Annotation on request Secrets giving the requested validity.
+	CertificateRequestValidityAnnotation = "skupper.io/validity"
+	// Annotation a signer can set on a request Secret to reject it.
+	CertificateRequestDeniedAnnotation = "skupper.io/denied"
+
+	requestCertificateAnnotation = "internal.skupper.io/certificate"
+)
+
+// SecretIssuer obtains certificates through a handshake with an
+// external signer using Secrets. For each Certificate a request
+// Secret, named after the Certificate with a -csr suffix, is created
+// holding the PEM encoded request under tls.csr. The signer is
+// expected to write the signed certificate to tls.crt and its CA
+// bundle to ca.crt in that same Secret, or to reject the request by
+// setting the skupper.io/denied annotation.
+type SecretIssuer struct {
+	client kubernetes.Interface
+}
+
+func NewSecretIssuer(client kubernetes.Interface) *SecretIssuer {
+	return &SecretIssuer{
+		client: client,
+	}
+}
+
+func requestSecretName(certificate *skupperv2alpha1.Certificate) string {
+	return certificate.Name + "-csr"
+}
+
+func (i *SecretIssuer) Submit(certificate *skupperv2alpha1.Certificate, issuer string, csr []byte, validity time.Duration) error {
+	request := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: requestSecretName(certificate),
+			Labels: map[string]string{
+				CertificateRequestLabel: "true",
+			},
+			Annotations: map[string]string{
+				CertificateRequestIssuerAnnotation:   issuer,
+				CertificateRequestValidityAnnotation: validity.String(),
+				requestCertificateAnnotation:         certificate.Name,
+			},
+			OwnerReferences: ownerReferences(certificate),
+		},
+		Data: map[string][]byte{
+			"tls.csr": csr,
+		},
+	}
+	secrets := i.client.CoreV1().Secrets(certificate.Namespace)
+	if existing, err := secrets.Get(context.Background(), request.Name, metav1.GetOptions{}); err == nil {
+		// replace any previous request, including any response to it
+		existing.Labels = request.Labels
+		existing.Annotations = request.Annotations
+		existing.OwnerReferences = request.OwnerReferences
+		existing.Data = request.Data
+		if _, err := secrets.Update(context.Background(), existing, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("Error updating request Secret %s: %s", request.Name, err)
+		}
+		return nil
+	} else if !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("Error retrieving request Secret %s: %s", request.Name, err)
+	}
+	if _, err := secrets.Create(context.Background(), request, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("Error creating request Secret %s: %s", request.Name, err)
+	}
+	return nil
+}
+
+func (i *SecretIssuer) Retrieve(certificate *skupperv2alpha1.Certificate, issuer string) (*SignedCertificate, error) {
+	name := requestSecretName(certificate)
+	request, err := i.client.CoreV1().Secrets(certificate.Namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("Error retrieving request Secret %s: %s", name, err)
+	}
+	if reason, ok := request.Annotations[CertificateRequestDeniedAnnotation]; ok {
+		return nil, fmt.Errorf("Request %s was denied by %s: %s", name, issuer, reason)
+	}
+	if len(request.Data["tls.crt"]) == 0 {
+		return nil, nil
+	}
+	return &SignedCertificate{
+		Certificate: request.Data["tls.crt"],
+		Ca:          request.Data["ca.crt"],
+	}, nil
+}
+
+func (i *SecretIssuer) Cancel(certificate *skupperv2alpha1.Certificate, issuer string) error {
+	name := requestSecretName(certificate)
+	err := i.client.CoreV1().Secrets(certificate.Namespace).Delete(context.Background(), name, metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("Error deleting request Secret %s: %s", name, err)
+	}
+	return nil
+}
+
+// Returns the name of the Certificate a request Secret was created for.
+func requestedCertificate(secret *corev1.Secret) (string, bool) {
+	if secret.Labels[CertificateRequestLabel] != "true" {
+		return "", false
+	}
+	name, ok := secret.Annotations[requestCertificateAnnotation]
+	return name, ok
+}
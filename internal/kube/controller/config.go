@@ -2,10 +2,12 @@ package controller
 
 import (
 	"flag"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	iflag "github.com/skupperproject/skupper/internal/flag"
+	"github.com/skupperproject/skupper/internal/kube/certificates"
 	"github.com/skupperproject/skupper/internal/kube/grants"
 	"github.com/skupperproject/skupper/internal/kube/securedaccess"
 )
@@ -13,11 +15,16 @@ import (
 type Config struct {
 	GrantConfig            *grants.GrantConfig
 	SecuredAccessConfig    *securedaccess.Config
+	CertificateConfig      *certificates.Config
+	LeaderElection         *LeaderElectionConfig
 	Namespace              string
 	Kubeconfig             string
 	WatchNamespace         string
 	Name                   string
 	RequireExplicitControl bool
+	MetricsAddress         string
+	Workers                int
+	RedriveInterval        time.Duration
 }
 
 func (c *Config) WatchingAllNamespaces() bool {
@@ -39,14 +46,31 @@ func BoundConfig(flags *flag.FlagSet) (*Config, error) {
 	} else if err := securedAccessConfig.Verify(); err != nil {
 		return nil, err
 	}
+	certificateConfig, err := certificates.BoundConfig(flags)
+	if err != nil {
+		return nil, err
+	}
+	leaderElection, err := BoundLeaderElectionConfig(flags)
+	if err != nil {
+		return nil, err
+	}
 	c := &Config{
 		GrantConfig:         grantConfig,
 		SecuredAccessConfig: securedAccessConfig,
+		CertificateConfig:   certificateConfig,
+		LeaderElection:      leaderElection,
 	}
 	iflag.StringVar(flags, &c.Namespace, "namespace", "NAMESPACE", "", "The Kubernetes namespace scope for the controller")
 	iflag.StringVar(flags, &c.Kubeconfig, "kubeconfig", "KUBECONFIG", "", "A path to the kubeconfig file to use")
 	iflag.StringVar(flags, &c.WatchNamespace, "watch-namespace", "WATCH_NAMESPACE", metav1.NamespaceAll, "The Kubernetes namespace the controller should monitor for controlled resources (will monitor all if not specified)")
 	iflag.StringVar(flags, &c.Name, "name", "CONTROLLER_NAME", "", "A name identifying the controller. If not specified it will be deduced from the hostname.")
 	iflag.BoolVar(flags, &c.RequireExplicitControl, "require-explicit-control", "REQUIRE_EXPLICIT_CONTROL", false, "If set, this controller instance will only process resources in which there is a ConfigMap named skupper with an entry 'controller' whose value matches the controller's namespace qualified name. Controllers watching a single namespace require that ConfigMap regardless of this setting.")
+	if err := iflag.IntVar(flags, &c.Workers, "workers", "SKUPPER_WORKERS", 1, "The number of go routines on which events are processed. Events are sharded across them by namespace, so events for the same namespace are always processed in order."); err != nil {
+		return nil, err
+	}
+	if err := iflag.DurationVar(flags, &c.RedriveInterval, "redrive-interval", "SKUPPER_REDRIVE_INTERVAL", 5*time.Minute, "The interval at which events that have repeatedly failed to be processed are retried."); err != nil {
+		return nil, err
+	}
+	iflag.StringVar(flags, &c.MetricsAddress, "metrics-address", "SKUPPER_METRICS_ADDRESS", ":9191", "The address on which /metrics, /healthz and /readyz are served. If empty, they are not served.")
 	return c, nil
 }
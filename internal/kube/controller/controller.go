@@ -8,12 +8,16 @@ import (
 	"log/slog"
 	"os"
 	"regexp"
+	"sync"
+	"sync/atomic"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/informers/internalinterfaces"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 
 	"github.com/skupperproject/skupper/internal/kube/certificates"
 	internalclient "github.com/skupperproject/skupper/internal/kube/client"
@@ -26,6 +30,7 @@ import (
 	"github.com/skupperproject/skupper/internal/network"
 	"github.com/skupperproject/skupper/internal/version"
 	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+	skupperscheme "github.com/skupperproject/skupper/pkg/generated/client/clientset/versioned/scheme"
 )
 
 type Controller struct {
@@ -38,8 +43,10 @@ type Controller struct {
 	listenerWatcher      *watchers.ListenerWatcher
 	connectorWatcher     *watchers.ConnectorWatcher
 	linkAccessWatcher    *watchers.RouterAccessWatcher
+	linkWatcher          *watchers.LinkWatcher
 	grantWatcher         *watchers.AccessGrantWatcher
 	sites                map[string]*site.Site
+	sitesLock            sync.Mutex
 	startGrantServer     func()
 	accessMgr            *securedaccess.SecuredAccessManager
 	accessRecovery       *securedaccess.SecuredAccessResourceWatcher
@@ -51,6 +58,9 @@ type Controller struct {
 	attachableConnectors map[string]*skupperv2alpha1.AttachedConnector
 	log                  *slog.Logger
 	namespaces           *NamespaceConfig
+	leaderElection       *LeaderElection
+	synced               atomic.Bool
+	events               record.EventBroadcaster
 }
 
 func skupperNetworkStatus() internalinterfaces.TweakListOptionsFunc {
@@ -65,6 +75,12 @@ func listenerServices() internalinterfaces.TweakListOptionsFunc {
 	}
 }
 
+func routerConfig() internalinterfaces.TweakListOptionsFunc {
+	return func(options *metav1.ListOptions) {
+		options.LabelSelector = "internal.skupper.io/router-config"
+	}
+}
+
 func skupperSiteSizingConfig() internalinterfaces.TweakListOptionsFunc {
 	return func(options *metav1.ListOptions) {
 		options.LabelSelector = sizing.SiteSizingLabel
@@ -79,7 +95,7 @@ func labelling() internalinterfaces.TweakListOptionsFunc {
 
 func NewController(cli internalclient.Clients, config *Config) (*Controller, error) {
 	controller := &Controller{
-		eventProcessor:       watchers.NewEventProcessor("Controller", cli),
+		eventProcessor:       watchers.NewShardedEventProcessor("Controller", cli, config.Workers),
 		sites:                map[string]*site.Site{},
 		siteSizing:           sizing.NewRegistry(),
 		labelling:            labels.NewLabelsAndAnnotations(config.Namespace),
@@ -106,28 +122,56 @@ func NewController(cli internalclient.Clients, config *Config) (*Controller, err
 			name = owner.Name
 		}
 	}
+	controller.events = record.NewBroadcaster()
+	degraded := &degradedResources{
+		client:   cli.GetSkupperClient().SkupperV2alpha1(),
+		recorder: controller.events.NewRecorder(skupperscheme.Scheme, corev1.EventSource{Component: "skupper-controller"}),
+		log:      controller.log,
+		pending:  controller.eventProcessor.HasDeadLetters,
+		recorded: map[watchers.ResourceChange]bool{},
+	}
+	controller.eventProcessor.SetDeadLetterHandler(degraded, config.RedriveInterval)
 	controller.namespaces = newNamespaceConfig(config.Namespace+"/"+name, config.requireExplicitControl(), newControlLogging(config.WatchingAllNamespaces(), controller.log))
 	controller.self.Name = name
 	controller.self.Namespace = config.Namespace
 	controller.self.Version = version.Version
 
+	if config.LeaderElection != nil && config.LeaderElection.Enabled {
+		if err := config.LeaderElection.Verify(); err != nil {
+			return nil, err
+		}
+		identity := hostname
+		if identity == "" {
+			identity, _ = os.Hostname()
+		}
+		controller.leaderElection = newLeaderElection(cli.GetKubeClient(), config.Namespace, name, identity, config.LeaderElection, controller.log)
+	}
+
 	controller.siteWatcher = controller.eventProcessor.WatchSites(config.WatchNamespace, filter(controller, controller.checkSite))
+	degraded.sites = controller.siteWatcher
 	controller.listenerWatcher = controller.eventProcessor.WatchListeners(config.WatchNamespace, filter(controller, controller.checkListener))
 	controller.eventProcessor.WatchServices(listenerServices(), config.WatchNamespace, filter(controller, controller.checkListenerService))
 	controller.connectorWatcher = controller.eventProcessor.WatchConnectors(config.WatchNamespace, filter(controller, controller.checkConnector))
 	controller.linkAccessWatcher = controller.eventProcessor.WatchRouterAccesses(config.WatchNamespace, filter(controller, controller.checkRouterAccess))
 	controller.eventProcessor.WatchAttachedConnectors(config.WatchNamespace, filter(controller, controller.checkAttachedConnector))
 	controller.eventProcessor.WatchAttachedConnectorBindings(config.WatchNamespace, filter(controller, controller.checkAttachedConnectorBinding))
-	controller.eventProcessor.WatchLinks(config.WatchNamespace, filter(controller, controller.checkLink))
+	controller.linkWatcher = controller.eventProcessor.WatchLinks(config.WatchNamespace, filter(controller, controller.checkLink))
 	controller.eventProcessor.WatchConfigMaps(skupperNetworkStatus(), config.WatchNamespace, filter(controller, controller.networkStatusUpdate))
 	controller.eventProcessor.WatchAccessTokens(config.WatchNamespace, filter(controller, controller.checkAccessToken))
 	controller.eventProcessor.WatchPods("skupper.io/component=router,skupper.io/type=site", config.WatchNamespace, filter(controller, controller.routerPodEvent))
+	controller.eventProcessor.WatchConfigMaps(routerConfig(), config.WatchNamespace, filter(controller, controller.routerConfigEvent))
 	controller.siteSizingWatcher = controller.eventProcessor.WatchConfigMaps(skupperSiteSizingConfig(), config.Namespace, filter(controller, controller.siteSizing.Update))
 	controller.namespaces.watch(controller.eventProcessor, config.WatchNamespace)
 	controller.labellingWatcher = controller.eventProcessor.WatchConfigMaps(labelling(), config.WatchNamespace, controller.labelling.Update)
 
 	controller.certMgr = certificates.NewCertificateManager(controller.eventProcessor)
 	controller.certMgr.SetControllerContext(controller)
+	if config.CertificateConfig != nil {
+		if err := config.CertificateConfig.Verify(); err != nil {
+			return nil, err
+		}
+		controller.certMgr.SetConfig(config.CertificateConfig)
+	}
 	controller.certMgr.Watch(config.WatchNamespace)
 
 	controller.accessMgr = securedaccess.NewSecuredAccessManager(controller.eventProcessor, controller.certMgr, config.SecuredAccessConfig, controller)
@@ -136,6 +180,10 @@ func NewController(cli internalclient.Clients, config *Config) (*Controller, err
 	controller.accessRecovery.WatchSecuredAccesses(controller.eventProcessor, config.WatchNamespace, controller.checkSecuredAccess)
 	controller.accessRecovery.WatchGateway(controller.eventProcessor, config.Namespace)
 
+	if controller.leaderElection != nil && config.GrantConfig != nil {
+		// only the leader serves grants, see grants.ServingLabel
+		config.GrantConfig.LeaderElection = true
+	}
 	controller.startGrantServer = grants.Initialise(controller.eventProcessor, config.Namespace, config.WatchNamespace, config.GrantConfig, controller.generateLinkConfig, controller.IsControlled)
 
 	controller.eventProcessor.WatchConfigMaps(skupperLogConfig(), config.Namespace, controller.logConfigUpdate)
@@ -181,14 +229,42 @@ func (c *Controller) getDeploymentForPod(podName string, namespace string) (*app
 	return deployment, nil
 }
 
+// Run starts the informers and processes events until stopCh is
+// closed. If leader election is enabled, informer caches are kept in
+// sync while waiting for the lease, but recovery and event processing
+// only begin once it has been acquired.
 func (c *Controller) Run(stopCh <-chan struct{}) error {
-	if err := c.init(stopCh); err != nil {
+	if c.leaderElection == nil {
+		if err := c.init(stopCh); err != nil {
+			return err
+		}
+		return c.start(stopCh)
+	}
+	if err := c.sync(stopCh); err != nil {
 		return err
 	}
-	return c.start(stopCh)
+	return c.leaderElection.Run(stopCh, func(leaderCh <-chan struct{}) {
+		c.recover()
+		c.start(leaderCh)
+	})
+}
+
+// IsLeader returns true if this controller is responsible for
+// processing resources, i.e. leader election is disabled or this
+// replica holds the lease.
+func (c *Controller) IsLeader() bool {
+	return c.leaderElection == nil || c.leaderElection.IsLeader()
 }
 
 func (c *Controller) init(stopCh <-chan struct{}) error {
+	if err := c.sync(stopCh); err != nil {
+		return err
+	}
+	c.recover()
+	return nil
+}
+
+func (c *Controller) sync(stopCh <-chan struct{}) error {
 	c.log.Info("Starting informers")
 	c.eventProcessor.StartWatchers(stopCh)
 	c.stopCh = stopCh
@@ -197,6 +273,11 @@ func (c *Controller) init(stopCh <-chan struct{}) error {
 	if ok := c.eventProcessor.WaitForCacheSync(stopCh); !ok {
 		return fmt.Errorf("Failed to wait for caches to sync")
 	}
+	c.synced.Store(true)
+	return nil
+}
+
+func (c *Controller) recover() {
 	c.namespaces.recover()
 
 	for _, config := range c.siteSizingWatcher.List() {
@@ -296,11 +377,12 @@ func (c *Controller) init(stopCh <-chan struct{}) error {
 	if c.startGrantServer != nil {
 		c.startGrantServer()
 	}
-	return nil
 }
 
 func (c *Controller) start(stopCh <-chan struct{}) error {
 	c.log.Info("Starting event loop")
+	c.events.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: c.eventProcessor.GetKubeClient().CoreV1().Events("")})
+	defer c.events.Shutdown()
 	c.eventProcessor.Start(stopCh)
 	<-stopCh
 	c.log.Info("Shutting down")
@@ -308,6 +390,8 @@ func (c *Controller) start(stopCh <-chan struct{}) error {
 }
 
 func (c *Controller) getSite(namespace string) *site.Site {
+	c.sitesLock.Lock()
+	defer c.sitesLock.Unlock()
 	if existing, ok := c.sites[namespace]; ok {
 		return existing
 	}
@@ -339,7 +423,9 @@ func (c *Controller) checkSite(key string, site *skupperv2alpha1.Site) error {
 		s := c.getSite(namespace)
 		if s.NameMatches(name) {
 			s.Deleted()
+			c.sitesLock.Lock()
 			delete(c.sites, namespace)
+			c.sitesLock.Unlock()
 		}
 	}
 	return nil
@@ -398,6 +484,14 @@ func (c *Controller) routerPodEvent(key string, pod *corev1.Pod) error {
 	return c.getSite(namespace).RouterPodEvent(key, pod)
 }
 
+func (c *Controller) routerConfigEvent(key string, cm *corev1.ConfigMap) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+	return c.getSite(namespace).RouterConfigUpdated(name, cm)
+}
+
 func (c *Controller) generateLinkConfig(namespace string, name string, subject string, writer io.Writer) error {
 	site := c.getSite(namespace).GetSite()
 	if site == nil {
@@ -407,7 +501,10 @@ func (c *Controller) generateLinkConfig(namespace string, name string, subject s
 	if err != nil {
 		return err
 	}
-	token := generator.NewCertToken(name, subject)
+	token, err := generator.NewCertToken(name, subject)
+	if err != nil {
+		return err
+	}
 	return token.Write(writer)
 }
 
@@ -434,17 +531,37 @@ func (c *Controller) checkAttachedConnectorBinding(key string, binding *skupperv
 
 func (c *Controller) checkAttachedConnector(key string, connector *skupperv2alpha1.AttachedConnector) error {
 	if connector == nil {
-		if previous, ok := c.attachableConnectors[key]; ok {
-			delete(c.attachableConnectors, key)
-			return c.getSite(previous.Spec.SiteNamespace).AttachedConnectorDeleted(previous.Namespace, previous.Name)
-		} else {
+		c.sitesLock.Lock()
+		previous, ok := c.attachableConnectors[key]
+		delete(c.attachableConnectors, key)
+		c.sitesLock.Unlock()
+		if !ok {
 			return nil
 		}
+		return c.inNamespace(previous.Spec.SiteNamespace, previous.Namespace, key, func() error {
+			return c.getSite(previous.Spec.SiteNamespace).AttachedConnectorDeleted(previous.Namespace, previous.Name)
+		})
 	} else {
-		return c.getSite(connector.Spec.SiteNamespace).AttachedConnectorUpdated(connector)
+		return c.inNamespace(connector.Spec.SiteNamespace, connector.Namespace, key, func() error {
+			return c.getSite(connector.Spec.SiteNamespace).AttachedConnectorUpdated(connector)
+		})
 	}
 }
 
+// inNamespace invokes the supplied function on the worker processing
+// events for the target namespace, so that a site is only ever
+// accessed from a single go routine. If that worker is the one
+// handling the current event, the function is invoked immediately.
+func (c *Controller) inNamespace(target string, current string, context string, f func() error) error {
+	if c.eventProcessor.SameWorker(target, current) {
+		return f()
+	}
+	c.eventProcessor.NamespacedCallbackAfter(target, 0, func(string) error {
+		return f()
+	}, context)
+	return nil
+}
+
 func (c *Controller) networkStatusUpdate(key string, cm *corev1.ConfigMap) error {
 	if cm == nil {
 		return nil
@@ -8,6 +8,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"gotest.tools/v3/assert"
@@ -672,6 +673,48 @@ func TestUpdate(t *testing.T) {
 	}
 }
 
+func TestWorkers(t *testing.T) {
+	namespaces := []string{"east", "west", "north", "south", "centre"}
+	flags := &flag.FlagSet{}
+	config, err := BoundConfig(flags)
+	assert.Assert(t, err)
+	flags.Parse([]string{"-workers", "4"})
+	clients, err := fakeclient.NewFakeClient(config.Namespace, nil, nil, "")
+	assert.Assert(t, err)
+	enableSSA(clients.GetDynamicClient())
+	controller, err := NewController(clients, config)
+	assert.Assert(t, err)
+	assert.Equal(t, controller.eventProcessor.Workers(), 4)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	err = controller.init(stopCh)
+	assert.Assert(t, err)
+	controller.eventProcessor.Start(stopCh)
+	defer controller.eventProcessor.Stop()
+
+	for _, ns := range namespaces {
+		_, err := clients.GetSkupperClient().SkupperV2alpha1().Sites(ns).Create(context.Background(), f.site("mysite", ns, "", false, false), metav1.CreateOptions{})
+		assert.Assert(t, err)
+		_, err = clients.GetSkupperClient().SkupperV2alpha1().Listeners(ns).Create(context.Background(), f.listener("mylistener", ns, "mysvc", 8080), metav1.CreateOptions{})
+		assert.Assert(t, err)
+	}
+	timeout := time.After(10 * time.Second)
+	for _, ns := range namespaces {
+		for _, check := range []WaitFunction{
+			isSiteStatusConditionTrue("mysite", ns, skupperv2alpha1.CONDITION_TYPE_CONFIGURED),
+			isListenerStatusConditionTrue("mylistener", ns, skupperv2alpha1.CONDITION_TYPE_CONFIGURED),
+		} {
+			for !check(t, clients) {
+				select {
+				case <-timeout:
+					t.Fatalf("timed out waiting for resources in %s to be configured", ns)
+				case <-time.After(10 * time.Millisecond):
+				}
+			}
+		}
+	}
+}
+
 func verifyStatus(t *testing.T, expected skupperv2alpha1.Status, actual skupperv2alpha1.Status) {
 	t.Helper()
 	assert.Equal(t, expected.StatusType, actual.StatusType, actual.Message)
@@ -0,0 +1,230 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/skupperproject/skupper/internal/kube/watchers"
+	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+	skupperclient "github.com/skupperproject/skupper/pkg/generated/client/clientset/versioned/typed/skupper/v2alpha1"
+)
+
+// degradedResources is notified by the EventProcessor when an event
+// exhausts its retries. It records a Degraded condition, and emits a
+// Warning Event, on the resource the event relates to or, where that
+// is not a skupper resource, on the Site in the same namespace. The
+// condition is removed once the event is handled successfully, or
+// once no such event remains for the namespace in the case of the
+// Site. Where the condition was not recorded by this instance, the
+// cached resources are checked for it before the API is consulted.
+type degradedResources struct {
+	client   skupperclient.SkupperV2alpha1Interface
+	recorder record.EventRecorder
+	log      *slog.Logger
+	sites    *watchers.SiteWatcher
+	// pending returns true if any event for the namespace that
+	// matches the supplied function has exhausted its retries
+	pending func(namespace string, match func(watchers.ResourceChange) bool) bool
+	lock    sync.Mutex
+	// recorded holds the events for which this instance has recorded
+	// a Degraded condition that has not yet been cleared
+	recorded map[watchers.ResourceChange]bool
+}
+
+func (d *degradedResources) RetriesExhausted(event watchers.ResourceChange, err error) {
+	d.setRecorded(event, true)
+	message := fmt.Sprintf("Failed to process %s: %s", event.Handler.Describe(event), err)
+	d.update(event, errors.New(message), false, func(obj runtime.Object, changed bool) {
+		d.recorder.Event(obj, corev1.EventTypeWarning, "RetriesExhausted", message)
+	})
+}
+
+func (d *degradedResources) Recovered(event watchers.ResourceChange) {
+	// the cache may not yet reflect a condition recorded by this
+	// instance, so it is only relied on for conditions recorded
+	// elsewhere, e.g. by a previous leader
+	useCache := !d.setRecorded(event, false)
+	message := fmt.Sprintf("Processed %s successfully", event.Handler.Describe(event))
+	d.update(event, nil, useCache, func(obj runtime.Object, changed bool) {
+		if changed {
+			d.recorder.Event(obj, corev1.EventTypeNormal, "Recovered", message)
+		}
+	})
+}
+
+// setRecorded records whether a Degraded condition is recorded for
+// the event, returning whether one was previously.
+func (d *degradedResources) setRecorded(event watchers.ResourceChange, recorded bool) bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	previous := d.recorded[event]
+	if recorded {
+		d.recorded[event] = true
+	} else {
+		delete(d.recorded, event)
+	}
+	return previous
+}
+
+// update sets or clears the Degraded condition on the resources
+// affected by the event, passing each of those found to the supplied
+// function along with whether its condition was changed. If useCache
+// is true, a condition is only cleared if the cached resource has one.
+func (d *degradedResources) update(event watchers.ResourceChange, err error, useCache bool, updated func(obj runtime.Object, changed bool)) {
+	namespace, name, keyErr := cache.SplitMetaNamespaceKey(event.Key)
+	if keyErr != nil || namespace == "" {
+		return
+	}
+	var obj runtime.Object
+	var changed bool
+	var updateErr error
+	switch h := event.Handler.(type) {
+	case *watchers.SiteWatcher:
+		if err == nil && d.sitePending(namespace) {
+			return
+		}
+		obj, changed, updateErr = setDegraded(d.client.Sites(namespace), cached(h.Get, event.Key, useCache), name, err, func(o *skupperv2alpha1.Site) *skupperv2alpha1.Status { return &o.Status.Status })
+	case *watchers.ListenerWatcher:
+		obj, changed, updateErr = setDegraded(d.client.Listeners(namespace), cached(h.Get, event.Key, useCache), name, err, func(o *skupperv2alpha1.Listener) *skupperv2alpha1.Status { return &o.Status.Status })
+	case *watchers.ConnectorWatcher:
+		obj, changed, updateErr = setDegraded(d.client.Connectors(namespace), cached(h.Get, event.Key, useCache), name, err, func(o *skupperv2alpha1.Connector) *skupperv2alpha1.Status { return &o.Status.Status })
+	case *watchers.LinkWatcher:
+		obj, changed, updateErr = setDegraded(d.client.Links(namespace), cached(h.Get, event.Key, useCache), name, err, func(o *skupperv2alpha1.Link) *skupperv2alpha1.Status { return &o.Status.Status })
+	case *watchers.AccessTokenWatcher:
+		obj, changed, updateErr = setDegraded(d.client.AccessTokens(namespace), cached(h.Get, event.Key, useCache), name, err, func(o *skupperv2alpha1.AccessToken) *skupperv2alpha1.Status { return &o.Status.Status })
+	case *watchers.AccessGrantWatcher:
+		obj, changed, updateErr = setDegraded(d.client.AccessGrants(namespace), cached(h.Get, event.Key, useCache), name, err, func(o *skupperv2alpha1.AccessGrant) *skupperv2alpha1.Status { return &o.Status.Status })
+	case *watchers.SecuredAccessWatcher:
+		obj, changed, updateErr = setDegraded(d.client.SecuredAccesses(namespace), cached(h.Get, event.Key, useCache), name, err, func(o *skupperv2alpha1.SecuredAccess) *skupperv2alpha1.Status { return &o.Status.Status })
+	case *watchers.CertificateWatcher:
+		obj, changed, updateErr = setDegraded(d.client.Certificates(namespace), cached(h.Get, event.Key, useCache), name, err, func(o *skupperv2alpha1.Certificate) *skupperv2alpha1.Status { return &o.Status.Status })
+	case *watchers.RouterAccessWatcher:
+		obj, changed, updateErr = setDegraded(d.client.RouterAccesses(namespace), cached(h.Get, event.Key, useCache), name, err, func(o *skupperv2alpha1.RouterAccess) *skupperv2alpha1.Status { return &o.Status.Status })
+	case *watchers.AttachedConnectorWatcher:
+		obj, changed, updateErr = setDegraded(d.client.AttachedConnectors(namespace), cached(h.Get, event.Key, useCache), name, err, func(o *skupperv2alpha1.AttachedConnector) *skupperv2alpha1.Status { return &o.Status.Status })
+	case *watchers.AttachedConnectorBindingWatcher:
+		obj, changed, updateErr = setDegraded(d.client.AttachedConnectorBindings(namespace), cached(h.Get, event.Key, useCache), name, err, func(o *skupperv2alpha1.AttachedConnectorBinding) *skupperv2alpha1.Status { return &o.Status.Status })
+	default:
+		d.updateSites(namespace, err, useCache, updated)
+		return
+	}
+	if updateErr != nil {
+		d.log.Error("Failed to update Degraded condition",
+			slog.String("key", event.Key),
+			slog.Any("error", updateErr))
+	}
+	if obj != nil {
+		updated(obj, changed)
+	}
+}
+
+// updateSites sets the Degraded condition on the Sites in the
+// namespace or, if no event recorded on them has still exhausted its
+// retries, clears it.
+func (d *degradedResources) updateSites(namespace string, err error, useCache bool, updated func(obj runtime.Object, changed bool)) {
+	if err == nil && d.sitePending(namespace) {
+		return
+	}
+	for _, site := range d.sites.List() {
+		if site.Namespace != namespace {
+			continue
+		}
+		if useCache && !hasDegradedCondition(&site.Status.Status) {
+			continue
+		}
+		obj, changed, updateErr := setDegraded(d.client.Sites(namespace), nil, site.Name, err, func(o *skupperv2alpha1.Site) *skupperv2alpha1.Status { return &o.Status.Status })
+		if updateErr != nil {
+			d.log.Error("Failed to update Degraded condition",
+				slog.String("key", namespace+"/"+site.Name),
+				slog.Any("error", updateErr))
+		}
+		if obj != nil {
+			updated(obj, changed)
+		}
+	}
+}
+
+// sitePending returns true if any event whose failure is recorded on
+// the Sites in the namespace has still exhausted its retries.
+func (d *degradedResources) sitePending(namespace string) bool {
+	return d.pending != nil && d.pending(namespace, recordedOnSite)
+}
+
+// recordedOnSite returns true for events whose failure is recorded on
+// a Site, i.e. those for the Site itself or for resources other than
+// skupper resources.
+func recordedOnSite(event watchers.ResourceChange) bool {
+	switch event.Handler.(type) {
+	case *watchers.ListenerWatcher, *watchers.ConnectorWatcher, *watchers.LinkWatcher,
+		*watchers.AccessTokenWatcher, *watchers.AccessGrantWatcher, *watchers.SecuredAccessWatcher,
+		*watchers.CertificateWatcher, *watchers.RouterAccessWatcher, *watchers.AttachedConnectorWatcher,
+		*watchers.AttachedConnectorBindingWatcher:
+		return false
+	default:
+		return true
+	}
+}
+
+// cached returns a function retrieving the resource with the supplied
+// key from the cache, or nil if the cache is not to be used.
+func cached[T any](get func(key string) (T, error), key string, useCache bool) func() (T, error) {
+	if !useCache {
+		return nil
+	}
+	return func() (T, error) {
+		return get(key)
+	}
+}
+
+func hasDegradedCondition(status *skupperv2alpha1.Status) bool {
+	return meta.FindStatusCondition(status.Conditions, skupperv2alpha1.CONDITION_TYPE_DEGRADED) != nil
+}
+
+type statusClient[T any] interface {
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (T, error)
+	UpdateStatus(ctx context.Context, obj T, opts metav1.UpdateOptions) (T, error)
+}
+
+type degradable[S any] interface {
+	*S
+	runtime.Object
+	GetGeneration() int64
+}
+
+// setDegraded retrieves the named resource and updates its Degraded
+// condition if required, returning whether it was changed. It returns
+// nil if the resource no longer exists. When clearing the condition,
+// the resource is first retrieved from the cache, if supplied, and if
+// it has no Degraded condition there the API is not consulted.
+func setDegraded[S any, T degradable[S]](client statusClient[T], cached func() (T, error), name string, err error, status func(T) *skupperv2alpha1.Status) (runtime.Object, bool, error) {
+	if err == nil && cached != nil {
+		if obj, cacheErr := cached(); cacheErr == nil && (obj == nil || !hasDegradedCondition(status(obj))) {
+			return nil, false, nil
+		}
+	}
+	obj, getErr := client.Get(context.TODO(), name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(getErr) {
+		return nil, false, nil
+	} else if getErr != nil {
+		return nil, false, getErr
+	}
+	if !status(obj).SetDegraded(err, obj.GetGeneration()) {
+		return obj, false, nil
+	}
+	updated, updateErr := client.UpdateStatus(context.TODO(), obj, metav1.UpdateOptions{})
+	if updateErr != nil {
+		return obj, false, updateErr
+	}
+	return updated, true, nil
+}
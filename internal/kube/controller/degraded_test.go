@@ -0,0 +1,175 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	fakeclient "github.com/skupperproject/skupper/internal/kube/client/fake"
+	"github.com/skupperproject/skupper/internal/kube/watchers"
+	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+	skupperclientfake "github.com/skupperproject/skupper/pkg/generated/client/clientset/versioned/fake"
+)
+
+func TestDegradedResources(t *testing.T) {
+	testTable := []struct {
+		name              string
+		event             func(listeners *watchers.ListenerWatcher) watchers.ResourceChange
+		expectedKind      string
+		expectedName      string
+		expectedMessage   string
+		expectedNoChanges bool
+	}{
+		{
+			name: "listener",
+			event: func(listeners *watchers.ListenerWatcher) watchers.ResourceChange {
+				return watchers.ResourceChange{Handler: listeners, Key: "test/mylistener"}
+			},
+			expectedKind:    "Listener",
+			expectedName:    "mylistener",
+			expectedMessage: "Failed to process Listener test/mylistener: broken",
+		},
+		{
+			name: "configmap recorded on site",
+			event: func(*watchers.ListenerWatcher) watchers.ResourceChange {
+				return watchers.ResourceChange{Handler: &watchers.ConfigMapWatcher{}, Key: "test/skupper-network-status"}
+			},
+			expectedKind:    "Site",
+			expectedName:    "mysite",
+			expectedMessage: "Failed to process ConfigMap test/skupper-network-status: broken",
+		},
+		{
+			name: "deleted listener",
+			event: func(listeners *watchers.ListenerWatcher) watchers.ResourceChange {
+				return watchers.ResourceChange{Handler: listeners, Key: "test/other"}
+			},
+			expectedNoChanges: true,
+		},
+		{
+			name: "cluster scoped",
+			event: func(*watchers.ListenerWatcher) watchers.ResourceChange {
+				return watchers.ResourceChange{Handler: &watchers.NodeWatcher{}, Key: "mynode"}
+			},
+			expectedNoChanges: true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			degraded, recorder, listeners, _ := newTestDegradedResources(t,
+				f.site("mysite", "test", "", false, false),
+				f.listener("mylistener", "test", "mysvc", 8080),
+			)
+			event := tt.event(listeners)
+			degraded.RetriesExhausted(event, errors.New("broken"))
+			if tt.expectedNoChanges {
+				assert.Equal(t, len(recorder.Events), 0)
+				assert.Assert(t, getConditions(t, degraded, "Site", "mysite") == nil)
+				assert.Assert(t, getConditions(t, degraded, "Listener", "mylistener") == nil)
+				return
+			}
+			condition := meta.FindStatusCondition(getConditions(t, degraded, tt.expectedKind, tt.expectedName), skupperv2alpha1.CONDITION_TYPE_DEGRADED)
+			assert.Assert(t, condition != nil)
+			assert.Equal(t, condition.Status, metav1.ConditionTrue)
+			assert.Equal(t, condition.Reason, "RetriesExhausted")
+			assert.Equal(t, condition.Message, tt.expectedMessage)
+			assert.Equal(t, <-recorder.Events, "Warning RetriesExhausted "+tt.expectedMessage)
+
+			degraded.Recovered(event)
+			assert.Assert(t, meta.FindStatusCondition(getConditions(t, degraded, tt.expectedKind, tt.expectedName), skupperv2alpha1.CONDITION_TYPE_DEGRADED) == nil)
+			assert.Equal(t, <-recorder.Events, "Normal Recovered Processed "+event.Handler.Describe(event)+" successfully")
+
+			// no event is emitted when there is no condition to clear
+			degraded.Recovered(event)
+			assert.Equal(t, len(recorder.Events), 0)
+		})
+	}
+}
+
+func TestDegradedResourcesStaleCondition(t *testing.T) {
+	stale := f.listener("stale", "test", "mysvc", 8080)
+	stale.Status.SetDegraded(errors.New("Failed to process Listener test/stale: broken"), stale.Generation)
+	site := f.site("mysite", "test", "", false, false)
+	site.Status.SetDegraded(errors.New("Failed to process ConfigMap test/skupper-router: broken"), site.Generation)
+	degraded, recorder, listeners, api := newTestDegradedResources(t,
+		site,
+		stale,
+		f.listener("mylistener", "test", "mysvc", 8080),
+	)
+
+	// conditions recorded before the controller started, e.g. by a
+	// previous leader, are cleared when the resource is first handled
+	staleEvent := watchers.ResourceChange{Handler: listeners, Key: "test/stale"}
+	degraded.Recovered(staleEvent)
+	assert.Assert(t, meta.FindStatusCondition(getConditions(t, degraded, "Listener", "stale"), skupperv2alpha1.CONDITION_TYPE_DEGRADED) == nil)
+	assert.Equal(t, <-recorder.Events, "Normal Recovered Processed "+staleEvent.Handler.Describe(staleEvent)+" successfully")
+
+	// the API is not consulted for resources whose cached copy has
+	// no condition to clear
+	api.ClearActions()
+	degraded.Recovered(watchers.ResourceChange{Handler: listeners, Key: "test/mylistener"})
+	degraded.Recovered(watchers.ResourceChange{Handler: listeners, Key: "test/other"})
+	assert.Equal(t, len(recorder.Events), 0)
+	assert.Equal(t, len(api.Actions()), 0)
+
+	// the site is only cleared once no event recorded on it remains
+	pending := true
+	degraded.pending = func(namespace string, match func(watchers.ResourceChange) bool) bool {
+		return pending && namespace == "test" && match(watchers.ResourceChange{Handler: &watchers.ConfigMapWatcher{}, Key: "test/skupper-router"})
+	}
+	configMapEvent := watchers.ResourceChange{Handler: &watchers.ConfigMapWatcher{}, Key: "test/skupper-network-status"}
+	siteEvent := watchers.ResourceChange{Handler: degraded.sites, Key: "test/mysite"}
+	degraded.Recovered(configMapEvent)
+	degraded.Recovered(siteEvent)
+	assert.Assert(t, meta.FindStatusCondition(getConditions(t, degraded, "Site", "mysite"), skupperv2alpha1.CONDITION_TYPE_DEGRADED) != nil)
+	assert.Equal(t, len(recorder.Events), 0)
+
+	pending = false
+	degraded.Recovered(configMapEvent)
+	assert.Assert(t, meta.FindStatusCondition(getConditions(t, degraded, "Site", "mysite"), skupperv2alpha1.CONDITION_TYPE_DEGRADED) == nil)
+	assert.Equal(t, <-recorder.Events, "Normal Recovered Processed "+configMapEvent.Handler.Describe(configMapEvent)+" successfully")
+	degraded.Recovered(configMapEvent)
+	assert.Equal(t, len(recorder.Events), 0)
+}
+
+func newTestDegradedResources(t *testing.T, objects ...runtime.Object) (*degradedResources, *record.FakeRecorder, *watchers.ListenerWatcher, *skupperclientfake.Clientset) {
+	clients, err := fakeclient.NewFakeClient("test", nil, objects, "")
+	assert.Assert(t, err)
+	processor := watchers.NewEventProcessor("test", clients)
+	sites := processor.WatchSites("test", func(string, *skupperv2alpha1.Site) error { return nil })
+	listeners := processor.WatchListeners("test", func(string, *skupperv2alpha1.Listener) error { return nil })
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	processor.StartWatchers(stopCh)
+	assert.Assert(t, processor.WaitForCacheSync(stopCh))
+	recorder := record.NewFakeRecorder(10)
+	degraded := &degradedResources{
+		client:   clients.GetSkupperClient().SkupperV2alpha1(),
+		recorder: recorder,
+		log:      slog.Default(),
+		sites:    sites,
+		recorded: map[watchers.ResourceChange]bool{},
+	}
+	return degraded, recorder, listeners, clients.GetSkupperClient().(*skupperclientfake.Clientset)
+}
+
+func getConditions(t *testing.T, d *degradedResources, kind string, name string) []metav1.Condition {
+	switch kind {
+	case "Site":
+		site, err := d.client.Sites("test").Get(context.Background(), name, metav1.GetOptions{})
+		assert.Assert(t, err)
+		return site.Status.Conditions
+	case "Listener":
+		listener, err := d.client.Listeners("test").Get(context.Background(), name, metav1.GetOptions{})
+		assert.Assert(t, err)
+		return listener.Status.Conditions
+	}
+	t.Fatalf("unexpected kind %s", kind)
+	return nil
+}
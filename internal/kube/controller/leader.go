@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+
+	iflag "github.com/skupperproject/skupper/internal/flag"
+)
+
+type LeaderElectionConfig struct {
+	Enabled       bool
+	LeaseName     string
+	LeaseDuration time.Duration
+	RenewDeadline time.Duration
+	RetryPeriod   time.Duration
+}
+
+func (c *LeaderElectionConfig) Verify() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.LeaseDuration <= c.RenewDeadline {
+		return fmt.Errorf("leader election lease duration (%s) must be greater than renew deadline (%s)", c.LeaseDuration, c.RenewDeadline)
+	}
+	if c.RenewDeadline <= c.RetryPeriod {
+		return fmt.Errorf("leader election renew deadline (%s) must be greater than retry period (%s)", c.RenewDeadline, c.RetryPeriod)
+	}
+	if c.RetryPeriod <= 0 {
+		return fmt.Errorf("leader election retry period must be positive")
+	}
+	return nil
+}
+
+func BoundLeaderElectionConfig(flags *flag.FlagSet) (*LeaderElectionConfig, error) {
+	c := &LeaderElectionConfig{}
+	if err := iflag.BoolVar(flags, &c.Enabled, "enable-leader-election", "SKUPPER_ENABLE_LEADER_ELECTION", false, "If set, only the replica holding the leader election lease will process resources. Other replicas keep their caches in sync and take over if the leader stops."); err != nil {
+		return nil, err
+	}
+	iflag.StringVar(flags, &c.LeaseName, "leader-election-lease-name", "SKUPPER_LEADER_ELECTION_LEASE_NAME", "", "The name of the Lease used for leader election. Defaults to the controller name with a '-leader' suffix.")
+	if err := iflag.DurationVar(flags, &c.LeaseDuration, "leader-election-lease-duration", "SKUPPER_LEADER_ELECTION_LEASE_DURATION", 15*time.Second, "The duration a standby replica waits before taking over a lease that has not been renewed."); err != nil {
+		return nil, err
+	}
+	if err := iflag.DurationVar(flags, &c.RenewDeadline, "leader-election-renew-deadline", "SKUPPER_LEADER_ELECTION_RENEW_DEADLINE", 10*time.Second, "The duration the leader retries renewing the lease before giving up leadership."); err != nil {
+		return nil, err
+	}
+	if err := iflag.DurationVar(flags, &c.RetryPeriod, "leader-election-retry-period", "SKUPPER_LEADER_ELECTION_RETRY_PERIOD", 2*time.Second, "The interval between attempts to acquire or renew the lease."); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// LeaderElection runs a function only while the lease it manages is
+// held. The lease is released on shutdown so that a standby replica
+// can take over without waiting for it to expire.
+type LeaderElection struct {
+	config   LeaderElectionConfig
+	lock     resourcelock.Interface
+	identity string
+	leading  atomic.Bool
+	log      *slog.Logger
+}
+
+func newLeaderElection(client kubernetes.Interface, namespace string, name string, identity string, config *LeaderElectionConfig, log *slog.Logger) *LeaderElection {
+	leaseName := config.LeaseName
+	if leaseName == "" {
+		leaseName = name + "-leader"
+	}
+	return &LeaderElection{
+		config: *config,
+		lock: &resourcelock.LeaseLock{
+			LeaseMeta: metav1.ObjectMeta{
+				Name:      leaseName,
+				Namespace: namespace,
+			},
+			Client: client.CoordinationV1(),
+			LockConfig: resourcelock.ResourceLockConfig{
+				Identity: identity,
+			},
+		},
+		identity: identity,
+		log:      log,
+	}
+}
+
+// IsLeader returns true if this replica currently holds the lease.
+func (l *LeaderElection) IsLeader() bool {
+	return l.leading.Load()
+}
+
+// Run blocks until stopCh is closed or leadership is lost, invoking
+// lead once the lease is acquired. Losing the lease for any reason
+// other than shutdown is reported as an error, as the caller should
+// exit rather than continue processing alongside a new leader.
+func (l *LeaderElection) Run(stopCh <-chan struct{}, lead func(stopCh <-chan struct{})) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	begin := time.Now()
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            l.lock,
+		ReleaseOnCancel: true,
+		LeaseDuration:   l.config.LeaseDuration,
+		RenewDeadline:   l.config.RenewDeadline,
+		RetryPeriod:     l.config.RetryPeriod,
+		Name:            l.lock.Describe(),
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				l.log.Info("Acquired leader election lease",
+					slog.String("lease", l.lock.Describe()),
+					slog.String("identity", l.identity),
+					slog.Duration("waited", time.Since(begin)),
+				)
+				l.leading.Store(true)
+				lead(ctx.Done())
+			},
+			OnStoppedLeading: func() {
+				l.leading.Store(false)
+				l.log.Info("Released leader election lease",
+					slog.String("lease", l.lock.Describe()),
+					slog.String("identity", l.identity),
+				)
+			},
+			OnNewLeader: func(identity string) {
+				if identity == l.identity {
+					return
+				}
+				l.log.Info("Standing by for current leader",
+					slog.String("lease", l.lock.Describe()),
+					slog.String("leader", identity),
+				)
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	l.log.Info("Waiting to acquire leader election lease",
+		slog.String("lease", l.lock.Describe()),
+		slog.String("identity", l.identity),
+	)
+	elector.Run(ctx)
+	select {
+	case <-stopCh:
+		return nil
+	default:
+		return fmt.Errorf("lost leader election lease %s", l.lock.Describe())
+	}
+}
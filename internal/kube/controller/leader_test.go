@@ -0,0 +1,246 @@
+package controller
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	internalclient "github.com/skupperproject/skupper/internal/kube/client"
+	fakeclient "github.com/skupperproject/skupper/internal/kube/client/fake"
+	"github.com/skupperproject/skupper/internal/kube/grants"
+)
+
+func TestLeaderElectionConfigVerify(t *testing.T) {
+	testTable := []struct {
+		name          string
+		config        LeaderElectionConfig
+		expectedError string
+	}{
+		{
+			name:   "disabled",
+			config: LeaderElectionConfig{},
+		},
+		{
+			name: "valid",
+			config: LeaderElectionConfig{
+				Enabled:       true,
+				LeaseDuration: 15 * time.Second,
+				RenewDeadline: 10 * time.Second,
+				RetryPeriod:   2 * time.Second,
+			},
+		},
+		{
+			name: "lease duration too short",
+			config: LeaderElectionConfig{
+				Enabled:       true,
+				LeaseDuration: 10 * time.Second,
+				RenewDeadline: 10 * time.Second,
+				RetryPeriod:   2 * time.Second,
+			},
+			expectedError: "leader election lease duration (10s) must be greater than renew deadline (10s)",
+		},
+		{
+			name: "renew deadline too short",
+			config: LeaderElectionConfig{
+				Enabled:       true,
+				LeaseDuration: 15 * time.Second,
+				RenewDeadline: time.Second,
+				RetryPeriod:   2 * time.Second,
+			},
+			expectedError: "leader election renew deadline (1s) must be greater than retry period (2s)",
+		},
+		{
+			name: "no retry period",
+			config: LeaderElectionConfig{
+				Enabled:       true,
+				LeaseDuration: 15 * time.Second,
+				RenewDeadline: 10 * time.Second,
+			},
+			expectedError: "leader election retry period must be positive",
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Verify()
+			if tt.expectedError == "" {
+				assert.Assert(t, err)
+			} else {
+				assert.Error(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestLeaderElectionFailover(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	config := &LeaderElectionConfig{
+		Enabled:       true,
+		LeaseDuration: 2 * time.Second,
+		RenewDeadline: time.Second,
+		RetryPeriod:   100 * time.Millisecond,
+	}
+	log := slog.Default()
+	first := newLeaderElection(client, "test", "skupper-controller", "pod-a", config, log)
+	second := newLeaderElection(client, "test", "skupper-controller", "pod-b", config, log)
+
+	firstStop := make(chan struct{})
+	firstLeading := make(chan struct{})
+	firstDone := make(chan error)
+	go func() {
+		firstDone <- first.Run(firstStop, func(stopCh <-chan struct{}) {
+			close(firstLeading)
+			<-stopCh
+		})
+	}()
+	waitFor(t, firstLeading)
+	assert.Assert(t, first.IsLeader())
+
+	lease, err := client.CoordinationV1().Leases("test").Get(context.Background(), "skupper-controller-leader", metav1.GetOptions{})
+	assert.Assert(t, err)
+	assert.Equal(t, *lease.Spec.HolderIdentity, "pod-a")
+
+	secondStop := make(chan struct{})
+	defer close(secondStop)
+	secondLeading := make(chan struct{})
+	go second.Run(secondStop, func(stopCh <-chan struct{}) {
+		close(secondLeading)
+		<-stopCh
+	})
+	select {
+	case <-secondLeading:
+		t.Fatal("standby acquired lease held by leader")
+	case <-time.After(500 * time.Millisecond):
+	}
+	assert.Assert(t, !second.IsLeader())
+
+	// the lease is released on shutdown, so the standby need not
+	// wait for it to expire
+	close(firstStop)
+	select {
+	case err := <-firstDone:
+		assert.Assert(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for leader to stop")
+	}
+	assert.Assert(t, !first.IsLeader())
+	waitFor(t, secondLeading)
+	assert.Assert(t, second.IsLeader())
+}
+
+func TestLeaderElectionGrantServer(t *testing.T) {
+	pod := func(name string, hash string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "test",
+				Labels: map[string]string{
+					"app":               "skupper-controller",
+					"pod-template-hash": hash,
+				},
+			},
+		}
+	}
+	clients, err := fakeclient.NewFakeClient("test", []runtime.Object{pod("controller-a", "aaa"), pod("controller-b", "bbb")}, nil, "")
+	assert.Assert(t, err)
+	newController := func(name string) *Controller {
+		t.Setenv("HOSTNAME", name)
+		flags := &flag.FlagSet{}
+		config, err := BoundConfig(flags)
+		assert.Assert(t, err)
+		assert.Assert(t, flags.Parse([]string{
+			"-namespace", "test",
+			"-name", "skupper-controller",
+			"-enable-leader-election",
+			"-leader-election-lease-duration", "2s",
+			"-leader-election-renew-deadline", "1s",
+			"-leader-election-retry-period", "100ms",
+			"-enable-grants",
+			"-grant-server-autoconfigure",
+			"-grant-server-port", "0",
+			"-grant-server-podname", name,
+		}))
+		controller, err := NewController(clients, config)
+		assert.Assert(t, err)
+		return controller
+	}
+	poll := func(description string, condition func() bool) {
+		t.Helper()
+		timeout := time.After(10 * time.Second)
+		for !condition() {
+			select {
+			case <-timeout:
+				t.Fatalf("timed out waiting for %s", description)
+			case <-time.After(50 * time.Millisecond):
+			}
+		}
+	}
+
+	first := newController("controller-a")
+	firstStop := make(chan struct{})
+	firstDone := make(chan error)
+	go func() {
+		firstDone <- first.Run(firstStop)
+	}()
+	poll("first controller to lead", first.IsLeader)
+
+	second := newController("controller-b")
+	secondStop := make(chan struct{})
+	defer close(secondStop)
+	go second.Run(secondStop)
+
+	// only the leader is selected by the grant server service
+	poll("leader pod to be labelled", func() bool {
+		return servingPods(t, clients) == "controller-a"
+	})
+	sa, err := clients.GetSkupperClient().SkupperV2alpha1().SecuredAccesses("test").Get(context.Background(), "skupper-grant-server", metav1.GetOptions{})
+	assert.Assert(t, err)
+	assert.DeepEqual(t, sa.Spec.Selector, map[string]string{
+		"app":               "skupper-controller",
+		grants.ServingLabel: "true",
+	})
+	time.Sleep(500 * time.Millisecond)
+	assert.Assert(t, !second.IsLeader())
+	assert.Equal(t, servingPods(t, clients), "controller-a")
+
+	close(firstStop)
+	select {
+	case err := <-firstDone:
+		assert.Assert(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for leader to stop")
+	}
+	poll("standby to take over", second.IsLeader)
+	poll("new leader pod to be labelled", func() bool {
+		return servingPods(t, clients) == "controller-b"
+	})
+}
+
+// servingPods returns the names of the pods labelled as serving grants
+func servingPods(t *testing.T, clients internalclient.Clients) string {
+	t.Helper()
+	pods, err := clients.GetKubeClient().CoreV1().Pods("test").List(context.Background(), metav1.ListOptions{LabelSelector: grants.ServingLabel + "=true"})
+	assert.Assert(t, err)
+	var names []string
+	for _, pod := range pods.Items {
+		names = append(names, pod.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+func waitFor(t *testing.T, ch <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-ch:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for leader election")
+	}
+}
@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/skupperproject/skupper/internal/kube/grants"
+	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+)
+
+var (
+	certificateExpiryDesc = prometheus.NewDesc(
+		"skupper_controller_certificate_expiry_seconds",
+		"Time remaining until a Certificate expires",
+		[]string{"namespace", "name"}, nil,
+	)
+	linksDesc = prometheus.NewDesc(
+		"skupper_controller_links",
+		"Number of Links, by namespace, status and whether the router reports them operational",
+		[]string{"namespace", "status", "operational"}, nil,
+	)
+)
+
+// resourceMetrics reports the state of resources held in the
+// controller's informer caches each time it is collected.
+type resourceMetrics struct {
+	certificates func() []*skupperv2alpha1.Certificate
+	links        func() []*skupperv2alpha1.Link
+	now          func() time.Time
+}
+
+func (m *resourceMetrics) Describe(ch chan<- *prometheus.Desc) {
+	ch <- certificateExpiryDesc
+	ch <- linksDesc
+}
+
+func (m *resourceMetrics) Collect(ch chan<- prometheus.Metric) {
+	now := m.now()
+	for _, cert := range m.certificates() {
+		notAfter, err := time.Parse(time.RFC3339, cert.Status.NotAfter)
+		if err != nil {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(certificateExpiryDesc, prometheus.GaugeValue, notAfter.Sub(now).Seconds(), cert.Namespace, cert.Name)
+	}
+	type linkState struct {
+		namespace   string
+		status      skupperv2alpha1.StatusType
+		operational bool
+	}
+	links := map[linkState]int{}
+	for _, link := range m.links() {
+		status := link.Status.StatusType
+		if status == "" {
+			status = skupperv2alpha1.StatusPending
+		}
+		links[linkState{link.Namespace, status, link.IsOperational()}]++
+	}
+	for state, count := range links {
+		ch <- prometheus.MustNewConstMetric(linksDesc, prometheus.GaugeValue, float64(count), state.namespace, string(state.status), strconv.FormatBool(state.operational))
+	}
+}
+
+// RegisterMetrics registers metrics describing event processing,
+// AccessGrant redemptions and the state of Certificates and Links.
+func (c *Controller) RegisterMetrics(reg prometheus.Registerer) error {
+	if err := c.eventProcessor.RegisterMetrics(reg); err != nil {
+		return err
+	}
+	if err := grants.RegisterMetrics(reg); err != nil {
+		return err
+	}
+	return reg.Register(&resourceMetrics{
+		certificates: c.certMgr.List,
+		links:        c.linkWatcher.List,
+		now:          time.Now,
+	})
+}
@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+)
+
+func TestResourceMetrics(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	certificate := func(name string, notAfter string) *skupperv2alpha1.Certificate {
+		return &skupperv2alpha1.Certificate{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+			Status:     skupperv2alpha1.CertificateStatus{NotAfter: notAfter},
+		}
+	}
+	link := func(name string, status skupperv2alpha1.StatusType, operational bool) *skupperv2alpha1.Link {
+		l := &skupperv2alpha1.Link{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+		}
+		if operational {
+			l.SetOperational(true, "remote-site-id", "remote-site")
+		}
+		l.Status.StatusType = status
+		return l
+	}
+	metrics := &resourceMetrics{
+		certificates: func() []*skupperv2alpha1.Certificate {
+			return []*skupperv2alpha1.Certificate{
+				certificate("skupper-site-ca", now.Add(time.Hour).Format(time.RFC3339)),
+				certificate("skupper-site-server", now.Add(-time.Minute).Format(time.RFC3339)),
+				certificate("pending", ""),
+			}
+		},
+		links: func() []*skupperv2alpha1.Link {
+			return []*skupperv2alpha1.Link{
+				link("one", skupperv2alpha1.StatusReady, true),
+				link("two", skupperv2alpha1.StatusReady, true),
+				link("three", skupperv2alpha1.StatusError, false),
+				link("four", "", false),
+				// the router may report a link lost before its status is updated
+				link("five", skupperv2alpha1.StatusReady, false),
+			}
+		},
+		now: func() time.Time { return now },
+	}
+	expected := `
+# HELP skupper_controller_certificate_expiry_seconds Time remaining until a Certificate expires
+# TYPE skupper_controller_certificate_expiry_seconds gauge
+skupper_controller_certificate_expiry_seconds{name="skupper-site-ca",namespace="test"} 3600
+skupper_controller_certificate_expiry_seconds{name="skupper-site-server",namespace="test"} -60
+# HELP skupper_controller_links Number of Links, by namespace, status and whether the router reports them operational
+# TYPE skupper_controller_links gauge
+skupper_controller_links{namespace="test",operational="false",status="Error"} 1
+skupper_controller_links{namespace="test",operational="false",status="Pending"} 1
+skupper_controller_links{namespace="test",operational="false",status="Ready"} 1
+skupper_controller_links{namespace="test",operational="true",status="Ready"} 2
+`
+	assert.Assert(t, testutil.CollectAndCompare(metrics, strings.NewReader(expected)))
+}
+
+func TestHandler(t *testing.T) {
+	c := &Controller{}
+	handler := c.Handler(prometheus.NewRegistry())
+	get := func(path string) int {
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, path, nil))
+		return res.Code
+	}
+	assert.Equal(t, get("/healthz"), http.StatusOK)
+	assert.Equal(t, get("/readyz"), http.StatusServiceUnavailable)
+	assert.Equal(t, get("/metrics"), http.StatusOK)
+	c.synced.Store(true)
+	assert.Equal(t, get("/readyz"), http.StatusOK)
+}
@@ -3,6 +3,7 @@ package controller
 import (
 	"log/slog"
 	"strings"
+	"sync"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -21,6 +22,7 @@ type NamespaceConfig struct {
 	controllerName         string
 	requireExplicitControl bool
 	logging                ControlLogging
+	lock                   sync.RWMutex
 }
 
 func newNamespaceConfig(controllerName string, requireExplicitControl bool, logging ControlLogging) *NamespaceConfig {
@@ -33,6 +35,8 @@ func newNamespaceConfig(controllerName string, requireExplicitControl bool, logg
 }
 
 func (c *NamespaceConfig) update(key string, cm *corev1.ConfigMap) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
 	if cm == nil {
 		delete(c.config, key)
 		return nil
@@ -67,6 +71,8 @@ func (c *NamespaceConfig) isControlled(namespace string) bool {
 }
 
 func (c *NamespaceConfig) get(namespace string, setting string) (string, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
 	key := namespace + "/" + namespaceConfigName
 	cm, ok := c.config[key]
 	if !ok {
@@ -98,6 +104,7 @@ type ControlLogging interface {
 type OneTimeControlLogging struct {
 	logged map[string]string
 	impl   ControlLogging
+	lock   sync.Mutex
 }
 
 type ClusterScopedControlLogging struct {
@@ -135,6 +142,8 @@ func (l *NamespaceScopedControlLogging) NamespaceNotControlled(namespace string,
 }
 
 func (l *OneTimeControlLogging) NamespaceNotControlled(namespace string, actualControllerName string) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
 	if controller, ok := l.logged[namespace]; !ok || controller != actualControllerName {
 		l.logged[namespace] = actualControllerName
 		l.impl.NamespaceNotControlled(namespace, actualControllerName)
@@ -19,6 +19,7 @@ type AutoConfigure struct {
 	tlsCredentialsSecret string
 	ownerRefs            []metav1.OwnerReference
 	selector             map[string]string
+	leaderElection       bool
 }
 
 func (s *AutoConfigure) getConfigurationFromPod(clients internalclient.Clients, namespace string) error {
@@ -34,7 +35,7 @@ func (s *AutoConfigure) getConfigurationFromPod(clients internalclient.Clients,
 			UID:        or.UID,
 		})
 	}
-	s.selector = pod.ObjectMeta.Labels
+	s.selector = servingSelector(pod.ObjectMeta.Labels, s.leaderElection)
 	return nil
 }
 
@@ -144,6 +145,7 @@ func newAutoConfigure(handler watchers.SecuredAccessHandler, eventProcessor *wat
 		port:                 config.Port,
 		tlsCredentialsSecret: config.TlsCredentialsSecret,
 		podname:              config.Hostname,
+		leaderElection:       config.LeaderElection,
 	}
 	if ac.tlsCredentialsSecret == "" {
 		//TODO: should setting TlsCredentialsSecret be allowed when auto configure is enabled?
@@ -15,6 +15,9 @@ type GrantConfig struct {
 	Port                 int
 	TlsCredentialsSecret string
 	Hostname             string
+	// LeaderElection is set when only the controller replica holding
+	// the leader election lease serves AccessGrants
+	LeaderElection bool
 }
 
 func BoundGrantConfig(flags *flag.FlagSet) (*GrantConfig, error) {
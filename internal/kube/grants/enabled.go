@@ -17,6 +17,16 @@ func enabled(controller *watchers.EventProcessor, currentNamespace string, watch
 		grants: newGrants(controller, generator, config.scheme(), config.BaseUrl),
 	}
 	gc.server = newServer(config.addr(), config.tlsEnabled(), gc.grants)
+	if config.LeaderElection && config.Hostname != "" {
+		gc.serving = &servingPod{
+			clients:   controller,
+			namespace: currentNamespace,
+			name:      config.Hostname,
+		}
+		if err := gc.serving.release(); err != nil {
+			log.Printf("Could not remove label %s from pod %s: %s", ServingLabel, config.Hostname, err)
+		}
+	}
 
 	gc.grantWatcher = controller.WatchAccessGrants(watchNamespace, watchers.FilterByNamespace(filter, gc.grants.checkGrant))
 	gc.secretWatcher = controller.WatchSecrets(watchers.ByName(config.TlsCredentialsSecret), watchNamespace, watchers.FilterByNamespace(filter, gc.tlsCredentialsUpdated))
@@ -37,6 +47,7 @@ type GrantsEnabled struct {
 	grantWatcher  *watchers.AccessGrantWatcher
 	secretWatcher *watchers.SecretWatcher
 	autoConfigure *AutoConfigure
+	serving       *servingPod
 	started       bool
 	filter        NamespaceFilter
 }
@@ -47,6 +58,11 @@ func (c *GrantsEnabled) Start() {
 	if c.autoConfigure == nil {
 		c.server.start()
 	}
+	if c.serving != nil {
+		if err := c.serving.claim(); err != nil {
+			log.Printf("Could not set label %s on pod %s: %s", ServingLabel, c.serving.name, err)
+		}
+	}
 }
 
 func (c *GrantsEnabled) recoverGrants() {
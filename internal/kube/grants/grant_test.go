@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"gotest.tools/v3/assert"
 	meta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -313,8 +314,13 @@ func Test_ServeHttp(t *testing.T) {
 			}
 			req := httptest.NewRequest(tt.method, tt.path, tt.body)
 			res := httptest.NewRecorder()
+			redeemed := testutil.ToFloat64(redemptions.WithLabelValues("test"))
 			registry.ServeHTTP(res, req)
 			assert.Equal(t, res.Code, tt.expectedCode)
+			if tt.expectedCode == http.StatusOK {
+				redeemed++
+			}
+			assert.Equal(t, testutil.ToFloat64(redemptions.WithLabelValues("test")), redeemed)
 		})
 	}
 
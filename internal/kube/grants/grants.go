@@ -303,6 +303,7 @@ func (g *Grants) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	redemptions.WithLabelValues(grant.Namespace).Inc()
 	log.Printf("Redemption of access token %s/%s succeeded", grant.Namespace, grant.Name)
 }
 
@@ -0,0 +1,18 @@
+package grants
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var redemptions = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "skupper",
+	Subsystem: "controller",
+	Name:      "grants_redeemed_total",
+	Help:      "Count of AccessGrant redemptions that returned a link, by namespace",
+}, []string{"namespace"})
+
+// RegisterMetrics registers the metrics recorded by the AccessGrant
+// server.
+func RegisterMetrics(reg prometheus.Registerer) error {
+	return reg.Register(redemptions)
+}
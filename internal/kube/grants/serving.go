@@ -0,0 +1,81 @@
+package grants
+
+import (
+	"context"
+	"encoding/json"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	internalclient "github.com/skupperproject/skupper/internal/kube/client"
+)
+
+// ServingLabel is set on the pod of the controller replica serving
+// AccessGrants when leader election is enabled, so that the Service
+// for the grant server selects only that replica.
+const ServingLabel = "internal.skupper.io/grant-server"
+
+// servingPod manages the ServingLabel on the pod of this replica.
+type servingPod struct {
+	clients   internalclient.Clients
+	namespace string
+	name      string
+}
+
+// claim labels the pod of this replica and removes the label from
+// any other, e.g. that of a previous leader that did not release it.
+func (p *servingPod) claim() error {
+	pods, err := p.clients.GetKubeClient().CoreV1().Pods(p.namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: ServingLabel})
+	if err != nil {
+		return err
+	}
+	for _, pod := range pods.Items {
+		if pod.Name == p.name {
+			continue
+		}
+		if err := p.label(pod.Name, nil); err != nil {
+			return err
+		}
+	}
+	value := "true"
+	return p.label(p.name, &value)
+}
+
+// release removes the label from the pod of this replica, which may
+// have been left by a previous run of the controller in the same pod.
+func (p *servingPod) release() error {
+	return p.label(p.name, nil)
+}
+
+func (p *servingPod) label(name string, value *string) error {
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"labels": map[string]*string{ServingLabel: value},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = p.clients.GetKubeClient().CoreV1().Pods(p.namespace).Patch(context.TODO(), name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// servingSelector returns the selector for the grant server Service
+// given the labels of the pod of this replica. When leader election
+// is enabled, the pod-template-hash of the replica is dropped, as the
+// leader may belong to a different revision of the Deployment, and
+// the ServingLabel is required instead.
+func servingSelector(labels map[string]string, leaderElection bool) map[string]string {
+	if !leaderElection {
+		return labels
+	}
+	selector := map[string]string{}
+	for key, value := range labels {
+		if key == "pod-template-hash" || key == ServingLabel {
+			continue
+		}
+		selector[key] = value
+	}
+	selector[ServingLabel] = "true"
+	return selector
+}
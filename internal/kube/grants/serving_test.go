@@ -0,0 +1,51 @@
+package grants
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/skupperproject/skupper/internal/kube/client/fake"
+)
+
+func Test_servingPod(t *testing.T) {
+	client, err := fake.NewFakeClient("test", []runtime.Object{
+		tf.pod("old-leader", "test", map[string]string{"app": "controller", ServingLabel: "true"}, nil),
+		tf.pod("new-leader", "test", map[string]string{"app": "controller"}, nil),
+		tf.pod("standby", "test", map[string]string{"app": "controller"}, nil),
+	}, nil, "")
+	assert.Assert(t, err)
+	labelled := func() []string {
+		pods, err := client.GetKubeClient().CoreV1().Pods("test").List(context.Background(), metav1.ListOptions{LabelSelector: ServingLabel})
+		assert.Assert(t, err)
+		var names []string
+		for _, pod := range pods.Items {
+			assert.Equal(t, pod.Labels["app"], "controller")
+			names = append(names, pod.Name)
+		}
+		return names
+	}
+
+	serving := &servingPod{clients: client, namespace: "test", name: "new-leader"}
+	assert.Assert(t, serving.claim())
+	assert.DeepEqual(t, labelled(), []string{"new-leader"})
+	assert.Assert(t, serving.release())
+	assert.Assert(t, labelled() == nil)
+	// releasing an unlabelled pod is not an error
+	assert.Assert(t, serving.release())
+}
+
+func Test_servingSelector(t *testing.T) {
+	labels := map[string]string{
+		"app":               "controller",
+		"pod-template-hash": "abcde",
+	}
+	assert.DeepEqual(t, servingSelector(labels, false), labels)
+	assert.DeepEqual(t, servingSelector(labels, true), map[string]string{
+		"app":        "controller",
+		ServingLabel: "true",
+	})
+}
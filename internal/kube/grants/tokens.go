@@ -14,6 +14,7 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 
 	"github.com/skupperproject/skupper/internal/certs"
+	"github.com/skupperproject/skupper/internal/kube/certificates"
 	internalclient "github.com/skupperproject/skupper/internal/kube/client"
 	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
 )
@@ -43,6 +44,10 @@ func NewTokenGenerator(site *skupperv2alpha1.Site, clients internalclient.Client
 		namespace: site.Namespace,
 		clients:   clients,
 	}
+	if certificates.IsExternalIssuer(site.DefaultIssuer()) {
+		log.Printf("Cannot issue client certificates for site %s in %s: default issuer %s is external", site.Name, site.Namespace, site.DefaultIssuer())
+		return nil, fmt.Errorf("Cannot issue certificates for links: default issuer %s is external", site.DefaultIssuer())
+	}
 	if err := generator.loadCA(site.DefaultIssuer()); err != nil {
 		log.Printf("Error retrieving default issuer %s for site %s in %s: %s", site.DefaultIssuer(), site.Name, site.Namespace, err)
 		return nil, errors.New("Could not get issuer for requested certificate")
@@ -88,8 +93,11 @@ func (g *TokenGenerator) setValidHostsFromSite(site *skupperv2alpha1.Site) bool
 	return true
 }
 
-func (g *TokenGenerator) NewCertToken(name string, subject string) Token {
-	cert := certs.GenerateSecret(name, subject, strings.Join(g.hosts, ","), 0, g.ca)
+func (g *TokenGenerator) NewCertToken(name string, subject string) (Token, error) {
+	cert, err := certs.GenerateSecret(name, subject, strings.Join(g.hosts, ","), 0, g.ca)
+	if err != nil {
+		return nil, err
+	}
 	token := &CertToken{
 		tlsCredentials: &cert,
 	}
@@ -113,7 +121,7 @@ func (g *TokenGenerator) NewCertToken(name string, subject string) Token {
 		}
 		token.links = append(token.links, link)
 	}
-	return token
+	return token, nil
 }
 
 func (t *CertToken) Write(writer io.Writer) error {
@@ -0,0 +1,11 @@
+package qdr
+
+const (
+	// DrainAnnotation is set on the router config map of a draining
+	// site, to the time the drain started
+	DrainAnnotation = "internal.skupper.io/drain-started"
+	// DrainActiveFlowsAnnotation is set on the router config map by the
+	// kube-adaptor of a draining router, to the number of flows still
+	// active on its connectors
+	DrainActiveFlowsAnnotation = "internal.skupper.io/drain-active-flows"
+)
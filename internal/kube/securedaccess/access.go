@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"reflect"
+	"sync"
 
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
@@ -47,6 +48,7 @@ type SecuredAccessManager struct {
 	defaultAccessType  string
 	gatewayInit        func() error
 	context            ControllerContext
+	lock               sync.Mutex
 }
 
 func NewSecuredAccessManager(clients internalclient.Clients, certMgr certificates.CertificateManager, config *Config, context ControllerContext) *SecuredAccessManager {
@@ -96,6 +98,8 @@ func (m *SecuredAccessManager) IsValidAccessType(accessType string) bool {
 }
 
 func (m *SecuredAccessManager) Ensure(namespace string, name string, spec skupperv2alpha1.SecuredAccessSpec, annotations map[string]string, refs []metav1.OwnerReference) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	key := fmt.Sprintf("%s/%s", namespace, name)
 	if current, ok := m.definitions[key]; ok {
 		if current.ObjectMeta.Labels == nil {
@@ -164,6 +168,8 @@ func (m *SecuredAccessManager) Ensure(namespace string, name string, spec skuppe
 }
 
 func (m *SecuredAccessManager) Delete(namespace string, name string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	key := fmt.Sprintf("%s/%s", namespace, name)
 	if _, ok := m.definitions[key]; ok {
 		if err := m.clients.GetSkupperClient().SkupperV2alpha1().SecuredAccesses(namespace).Delete(context.Background(), name, metav1.DeleteOptions{}); err != nil {
@@ -202,6 +208,8 @@ func (m *SecuredAccessManager) checkLabelsAndAnnotations(key string, current *sk
 }
 
 func (m *SecuredAccessManager) SecuredAccessChanged(key string, current *skupperv2alpha1.SecuredAccess) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	current = m.checkLabelsAndAnnotations(key, current)
 	m.definitions[key] = current
 	return m.reconcile(current)
@@ -351,6 +359,8 @@ func (m *SecuredAccessManager) createService(sa *skupperv2alpha1.SecuredAccess)
 }
 
 func (m *SecuredAccessManager) SecuredAccessDeleted(key string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	if _, ok := m.definitions[key]; ok {
 		//any resources created for this secured access
 		//instance should have owner references set to this
@@ -362,26 +372,36 @@ func (m *SecuredAccessManager) SecuredAccessDeleted(key string) error {
 }
 
 func (m *SecuredAccessManager) RecoverRoute(route *routev1.Route) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	key := fmt.Sprintf("%s/%s", route.Namespace, route.Name)
 	m.routes[key] = route
 }
 
 func (m *SecuredAccessManager) RecoverHttpProxy(o *unstructured.Unstructured) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	key := fmt.Sprintf("%s/%s", o.GetNamespace(), o.GetName())
 	m.httpProxies[key] = o
 }
 
 func (m *SecuredAccessManager) RecoverTlsRoute(o *unstructured.Unstructured) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	key := fmt.Sprintf("%s/%s", o.GetNamespace(), o.GetName())
 	m.tlsRoutes[key] = o
 }
 
 func (m *SecuredAccessManager) RecoverIngress(ingress *networkingv1.Ingress) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	key := fmt.Sprintf("%s/%s", ingress.Namespace, ingress.Name)
 	m.ingresses[key] = ingress
 }
 
 func (m *SecuredAccessManager) RecoverService(svc *corev1.Service) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	key := fmt.Sprintf("%s/%s", svc.Namespace, svc.Name)
 	m.services[key] = svc
 }
@@ -399,6 +419,8 @@ func (m *SecuredAccessManager) getDefinitionForPortQualifiedResourceKey(qualifie
 }
 
 func (m *SecuredAccessManager) CheckRoute(key string, route *routev1.Route) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	sa := m.getDefinitionForPortQualifiedResourceKey(key, ACCESS_TYPE_ROUTE)
 	if route == nil {
 		delete(m.routes, key)
@@ -419,6 +441,8 @@ func (m *SecuredAccessManager) CheckRoute(key string, route *routev1.Route) erro
 }
 
 func (m *SecuredAccessManager) CheckHttpProxy(key string, o *unstructured.Unstructured) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	sa := m.getDefinitionForPortQualifiedResourceKey(key, ACCESS_TYPE_CONTOUR_HTTP_PROXY)
 	if o == nil {
 		delete(m.httpProxies, key)
@@ -436,6 +460,8 @@ func (m *SecuredAccessManager) CheckHttpProxy(key string, o *unstructured.Unstru
 }
 
 func (m *SecuredAccessManager) CheckTlsRoute(key string, o *unstructured.Unstructured) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	sa := m.getDefinitionForPortQualifiedResourceKey(key, ACCESS_TYPE_GATEWAY)
 	if o == nil {
 		delete(m.tlsRoutes, key)
@@ -453,6 +479,8 @@ func (m *SecuredAccessManager) CheckTlsRoute(key string, o *unstructured.Unstruc
 }
 
 func (m *SecuredAccessManager) CheckIngress(key string, ingress *networkingv1.Ingress) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	sa, ok := m.definitions[key]
 	if ingress == nil {
 		delete(m.ingresses, key)
@@ -474,6 +502,8 @@ func (m *SecuredAccessManager) CheckIngress(key string, ingress *networkingv1.In
 }
 
 func (m *SecuredAccessManager) CheckGateway(key string, o *unstructured.Unstructured) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	if m.gatewayInit == nil {
 		return nil
 	}
@@ -481,6 +511,8 @@ func (m *SecuredAccessManager) CheckGateway(key string, o *unstructured.Unstruct
 }
 
 func (m *SecuredAccessManager) CheckService(key string, svc *corev1.Service) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	if svc == nil {
 		delete(m.services, key)
 		if sa, ok := m.definitions[key]; ok {
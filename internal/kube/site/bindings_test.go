@@ -282,7 +282,11 @@ func TestBindingAdaptor_updateBridgeConfigForConnector(t *testing.T) {
 			},
 			expected: expected{
 				config: qdr.BridgeConfig{
-					TcpListeners: map[string]qdr.TcpEndpoint{},
+					UdpListeners:   map[string]qdr.UdpEndpoint{},
+					UdpConnectors:  map[string]qdr.UdpEndpoint{},
+					HttpListeners:  map[string]qdr.HttpEndpoint{},
+					HttpConnectors: map[string]qdr.HttpEndpoint{},
+					TcpListeners:   map[string]qdr.TcpEndpoint{},
 					TcpConnectors: map[string]qdr.TcpEndpoint{
 						"backend@192.168.1.1": qdr.TcpEndpoint{
 							Name:   "backend@192.168.1.1",
@@ -323,7 +327,11 @@ func TestBindingAdaptor_updateBridgeConfigForConnector(t *testing.T) {
 			},
 			expected: expected{
 				config: qdr.BridgeConfig{
-					TcpListeners: map[string]qdr.TcpEndpoint{},
+					UdpListeners:   map[string]qdr.UdpEndpoint{},
+					UdpConnectors:  map[string]qdr.UdpEndpoint{},
+					HttpListeners:  map[string]qdr.HttpEndpoint{},
+					HttpConnectors: map[string]qdr.HttpEndpoint{},
+					TcpListeners:   map[string]qdr.TcpEndpoint{},
 					TcpConnectors: map[string]qdr.TcpEndpoint{
 						"backend@10.244.0.9": qdr.TcpEndpoint{
 							Name:      "backend@10.244.0.9",
@@ -358,8 +366,12 @@ func TestBindingAdaptor_updateBridgeConfigForConnector(t *testing.T) {
 			},
 			expected: expected{
 				config: qdr.BridgeConfig{
-					TcpListeners:  map[string]qdr.TcpEndpoint{},
-					TcpConnectors: map[string]qdr.TcpEndpoint{},
+					UdpListeners:   map[string]qdr.UdpEndpoint{},
+					UdpConnectors:  map[string]qdr.UdpEndpoint{},
+					HttpListeners:  map[string]qdr.HttpEndpoint{},
+					HttpConnectors: map[string]qdr.HttpEndpoint{},
+					TcpListeners:   map[string]qdr.TcpEndpoint{},
+					TcpConnectors:  map[string]qdr.TcpEndpoint{},
 				},
 			},
 		},
@@ -383,8 +395,12 @@ func TestBindingAdaptor_updateBridgeConfigForConnector(t *testing.T) {
 			},
 			expected: expected{
 				config: qdr.BridgeConfig{
-					TcpListeners:  map[string]qdr.TcpEndpoint{},
-					TcpConnectors: map[string]qdr.TcpEndpoint{},
+					UdpListeners:   map[string]qdr.UdpEndpoint{},
+					UdpConnectors:  map[string]qdr.UdpEndpoint{},
+					HttpListeners:  map[string]qdr.HttpEndpoint{},
+					HttpConnectors: map[string]qdr.HttpEndpoint{},
+					TcpListeners:   map[string]qdr.TcpEndpoint{},
+					TcpConnectors:  map[string]qdr.TcpEndpoint{},
 				},
 			},
 		},
@@ -590,6 +606,10 @@ func TestBindingAdaptor_updateBridgeConfigForListener(t *testing.T) {
 			},
 			expected: expected{
 				config: qdr.BridgeConfig{
+					UdpListeners:   map[string]qdr.UdpEndpoint{},
+					UdpConnectors:  map[string]qdr.UdpEndpoint{},
+					HttpListeners:  map[string]qdr.HttpEndpoint{},
+					HttpConnectors: map[string]qdr.HttpEndpoint{},
 					TcpListeners: map[string]qdr.TcpEndpoint{
 						"backend": qdr.TcpEndpoint{
 
@@ -0,0 +1,139 @@
+package site
+
+import (
+	"log/slog"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	kubeqdr "github.com/skupperproject/skupper/internal/kube/qdr"
+	"github.com/skupperproject/skupper/internal/qdr"
+	"github.com/skupperproject/skupper/internal/site"
+	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+)
+
+// routerConfigLabelling sets the start time of a drain as an annotation
+// on the router config maps, in addition to any configured labels and
+// annotations, so that the kube-adaptor of each router reports the flows
+// still active on its connectors
+type routerConfigLabelling struct {
+	labelling Labelling
+	drain     *site.Drain
+}
+
+func (l *routerConfigLabelling) SetLabels(namespace string, name string, kind string, labels map[string]string) bool {
+	if l.labelling == nil {
+		return false
+	}
+	return l.labelling.SetLabels(namespace, name, kind, labels)
+}
+
+func (l *routerConfigLabelling) SetAnnotations(namespace string, name string, kind string, annotations map[string]string) bool {
+	changed := false
+	if l.labelling != nil && l.labelling.SetAnnotations(namespace, name, kind, annotations) {
+		changed = true
+	}
+	if l.drain.Active() {
+		started := l.drain.Started().UTC().Format(time.RFC3339)
+		if annotations[kubeqdr.DrainAnnotation] != started {
+			annotations[kubeqdr.DrainAnnotation] = started
+			delete(annotations, kubeqdr.DrainActiveFlowsAnnotation)
+			changed = true
+		}
+		return changed
+	}
+	for _, key := range []string{kubeqdr.DrainAnnotation, kubeqdr.DrainActiveFlowsAnnotation} {
+		if _, ok := annotations[key]; ok {
+			delete(annotations, key)
+			changed = true
+		}
+	}
+	return changed
+}
+
+func (s *Site) routerConfigLabelling() kubeqdr.Labelling {
+	return &routerConfigLabelling{
+		labelling: s.labelling,
+		drain:     s.drain,
+	}
+}
+
+// drainUpdated applies the given update to the router config after the
+// site has started or stopped draining, and arranges for the drain to be
+// marked as timed out if flows are still active by then
+func (s *Site) drainUpdated(update qdr.ConfigUpdate) error {
+	clear(s.drainFlows)
+	if s.drain.Active() {
+		s.logger.Info("Draining site",
+			slog.String("namespace", s.namespace),
+			slog.String("name", s.name),
+			slog.Duration("remaining", s.drain.Remaining(time.Now())))
+		s.clients.NamespacedCallbackAfter(s.namespace, s.drain.Remaining(time.Now())+time.Second, s.checkDrain, s.name)
+	} else {
+		s.logger.Info("Site no longer draining",
+			slog.String("namespace", s.namespace),
+			slog.String("name", s.name))
+	}
+	return s.updateRouterConfig(update)
+}
+
+// restoreConfig reapplies the bindings and links of the site, restoring
+// the connectors and link costs a drain removed or raised
+func (s *Site) restoreConfig() qdr.ConfigUpdate {
+	update := ConfigUpdateList{s, s.bindings}
+	for _, link := range s.links {
+		update = append(update, link)
+	}
+	return update
+}
+
+// drainStatus combines the flows reported by the routers of the site.
+// The drain is not complete until every router has reported.
+func (s *Site) drainStatus() *skupperv2alpha1.DrainStatus {
+	if !s.drain.Active() {
+		return nil
+	}
+	flows := 0
+	reported := true
+	for _, group := range s.groups() {
+		if count, ok := s.drainFlows[group]; ok {
+			flows += count
+		} else {
+			reported = false
+		}
+	}
+	status := s.drain.Status(flows, time.Now())
+	if !reported && !status.TimedOut {
+		status.Phase = skupperv2alpha1.DrainPhaseDraining
+	}
+	return status
+}
+
+func (s *Site) checkDrain(name string) error {
+	if s.site == nil || s.site.Name != name {
+		return nil
+	}
+	if s.site.SetDrainStatus(s.drainStatus()) {
+		return s.updateSiteStatus()
+	}
+	return nil
+}
+
+// RouterConfigUpdated records the flows still active on the connectors of
+// a draining router, as reported by its kube-adaptor
+func (s *Site) RouterConfigUpdated(name string, cm *corev1.ConfigMap) error {
+	if s.site == nil || !s.drain.Active() {
+		return nil
+	}
+	delete(s.drainFlows, name)
+	// only counts reported for the current drain are used
+	if cm != nil && cm.ObjectMeta.Annotations[kubeqdr.DrainAnnotation] == s.drain.Started().UTC().Format(time.RFC3339) {
+		if value, ok := cm.ObjectMeta.Annotations[kubeqdr.DrainActiveFlowsAnnotation]; ok {
+			if count, err := strconv.Atoi(value); err == nil {
+				s.drainFlows[name] = count
+			}
+		}
+	}
+	return s.checkDrain(s.site.Name)
+}
@@ -259,6 +259,7 @@ func (b *ExtendedBindings) Apply(config *qdr.RouterConfig) bool {
 	}
 	b.bindings.AddSslProfiles(config)
 	config.UpdateBridgeConfig(desired)
+	site.UpdateAddresses(config, b.bindings.Addresses())
 	config.RemoveUnreferencedSslProfiles()
 	return true //TODO: can optimise by indicating if no change was required
 }
@@ -2,6 +2,7 @@ package labels
 
 import (
 	"strings"
+	"sync"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/tools/cache"
@@ -10,6 +11,7 @@ import (
 type LabelsAndAnnotations struct {
 	namespaces          map[string]*Registry
 	controllerNamespace string
+	lock                sync.RWMutex
 }
 
 func NewLabelsAndAnnotations(controllerNamespace string) *LabelsAndAnnotations {
@@ -23,6 +25,8 @@ func NewLabelsAndAnnotations(controllerNamespace string) *LabelsAndAnnotations {
 }
 
 func (l *LabelsAndAnnotations) Update(key string, cm *corev1.ConfigMap) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
 	namespace, _, _ := cache.SplitMetaNamespaceKey(key)
 	if existing, ok := l.namespaces[namespace]; ok {
 		return existing.update(key, cm)
@@ -35,6 +39,8 @@ func (l *LabelsAndAnnotations) Update(key string, cm *corev1.ConfigMap) error {
 }
 
 func (l *LabelsAndAnnotations) SetLabels(namespace string, name string, kind string, labels map[string]string) bool {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
 	desired := map[string]string{}
 	if registry, ok := l.namespaces[namespace]; ok {
 		registry.setLabels(name, kind, desired)
@@ -48,6 +54,8 @@ func (l *LabelsAndAnnotations) SetLabels(namespace string, name string, kind str
 }
 
 func (l *LabelsAndAnnotations) SetAnnotations(namespace string, name string, kind string, annotations map[string]string) bool {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
 	desired := map[string]string{}
 	if registry, ok := l.namespaces[namespace]; ok {
 		registry.setAnnotations(name, kind, desired)
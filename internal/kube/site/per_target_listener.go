@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/skupperproject/skupper/internal/qdr"
+	"github.com/skupperproject/skupper/internal/site"
 	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
 )
 
@@ -127,7 +128,8 @@ func (p *PerTargetListener) unexpose(target string, mapping *qdr.PortMapping, ex
 
 func (p *PerTargetListener) updateBridgeConfig(siteId string, config *qdr.BridgeConfig) {
 	for target, port := range p.targets {
-		if p.definition.Spec.Type == "tcp" || p.definition.Spec.Type == "" {
+		switch p.definition.Spec.Type {
+		case "tcp", "":
 			config.AddTcpListener(qdr.TcpEndpoint{
 				Name:       p.definition.Name + "@" + target,
 				SiteId:     siteId,
@@ -135,6 +137,22 @@ func (p *PerTargetListener) updateBridgeConfig(siteId string, config *qdr.Bridge
 				Address:    p.address(target),
 				SslProfile: p.definition.Spec.TlsCredentials,
 			})
+		case "udp":
+			config.AddUdpListener(qdr.UdpEndpoint{
+				Name:    p.definition.Name + "@" + target,
+				SiteId:  siteId,
+				Port:    strconv.Itoa(port),
+				Address: p.address(target),
+			})
+		case "http", "http2":
+			config.AddHttpListener(qdr.HttpEndpoint{
+				Name:            p.definition.Name + "@" + target,
+				SiteId:          siteId,
+				Port:            strconv.Itoa(port),
+				Address:         p.address(target),
+				ProtocolVersion: site.HttpProtocolVersion(p.definition.Spec.Type),
+				SslProfile:      p.definition.Spec.TlsCredentials,
+			})
 		}
 	}
 }
@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"reflect"
 	"strings"
+	"time"
 
 	internalnetwork "github.com/skupperproject/skupper/internal/network"
 	corev1 "k8s.io/api/core/v1"
@@ -47,6 +48,7 @@ type Site struct {
 	clients       *watchers.EventProcessor
 	bindings      *ExtendedBindings
 	links         map[string]*site.Link
+	linkChecks    map[string]bool
 	errors        map[string]string
 	linkAccess    site.RouterAccessMap
 	certs         certificates.CertificateManager
@@ -56,6 +58,8 @@ type Site struct {
 	logger        *slog.Logger
 	currentGroups []string
 	labelling     Labelling
+	drain         *site.Drain
+	drainFlows    map[string]int
 }
 
 func NewSite(namespace string, eventProcessor *watchers.EventProcessor, certs certificates.CertificateManager, access SecuredAccessFactory, sizes *sizing.Registry, labelling Labelling) *Site {
@@ -64,6 +68,7 @@ func NewSite(namespace string, eventProcessor *watchers.EventProcessor, certs ce
 		namespace:  namespace,
 		clients:    eventProcessor,
 		links:      map[string]*site.Link{},
+		linkChecks: map[string]bool{},
 		linkAccess: site.RouterAccessMap{},
 		certs:      certs,
 		access:     access,
@@ -72,7 +77,9 @@ func NewSite(namespace string, eventProcessor *watchers.EventProcessor, certs ce
 		logger: slog.New(slog.Default().Handler()).With(
 			slog.String("component", "kube.site.site"),
 		),
-		labelling: labelling,
+		labelling:  labelling,
+		drain:      site.NewDrain(),
+		drainFlows: map[string]int{},
 	}
 }
 
@@ -96,6 +103,13 @@ func (s *Site) isEdge() bool {
 	return s.routerMode() == qdr.ModeEdge
 }
 
+func (s *Site) linkRole() qdr.Role {
+	if s.isEdge() {
+		return qdr.RoleEdge
+	}
+	return qdr.RoleInterRouter
+}
+
 func (s *Site) routerMode() qdr.Mode {
 	if s.site != nil && s.site.Spec.Edge {
 		return qdr.ModeEdge
@@ -124,6 +138,7 @@ func (s *Site) reconcile(siteDef *skupperv2alpha1.Site, inRecovery bool) error {
 	if err := s.verifySiteSpec(siteDef); err != nil {
 		return err
 	}
+	drainChanged := s.drain.Update(siteDef)
 	// ensure necessary resources:
 	// 1. skupper-internal configmap
 	if !s.initialised {
@@ -145,6 +160,13 @@ func (s *Site) reconcile(siteDef *skupperv2alpha1.Site, inRecovery bool) error {
 		s.bindings.SetSite(s)
 		s.setBindingsConfiguredStatus(nil)
 		s.checkSecuredAccess()
+		if drainChanged {
+			// bindings and links are not yet all known, so only
+			// the drain itself is applied
+			if err := s.drainUpdated(s); err != nil {
+				return err
+			}
+		}
 	} else if len(s.currentGroups) != len(s.groups()) {
 		s.logger.Info("EnableHA setting changed for site",
 			slog.String("namespace", siteDef.Namespace),
@@ -159,6 +181,15 @@ func (s *Site) reconcile(siteDef *skupperv2alpha1.Site, inRecovery bool) error {
 		if err := s.checkSecuredAccess(); err != nil {
 			return err
 		}
+		if drainChanged {
+			if err := s.drainUpdated(s.restoreConfig()); err != nil {
+				return err
+			}
+		}
+	} else if drainChanged {
+		if err := s.drainUpdated(s.restoreConfig()); err != nil {
+			return err
+		}
 	} else {
 		if err := s.updateRouterConfig(s); err != nil {
 			return err
@@ -177,9 +208,12 @@ func (s *Site) reconcile(siteDef *skupperv2alpha1.Site, inRecovery bool) error {
 			return err
 		}
 	}
-	// CAs for local and site access
-	if err := s.certs.EnsureCA(s.namespace, "skupper-site-ca", fmt.Sprintf("%s site CA", s.name), s.ownerReferences()); err != nil {
-		return err
+	// CAs for local and site access (site access certificates may
+	// instead be signed by an external issuer)
+	if !certificates.IsExternalIssuer(s.site.DefaultIssuer()) {
+		if err := s.certs.EnsureCA(s.namespace, "skupper-site-ca", fmt.Sprintf("%s site CA", s.name), s.ownerReferences()); err != nil {
+			return err
+		}
 	}
 	if err := s.certs.EnsureCA(s.namespace, "skupper-local-ca", fmt.Sprintf("%s local CA", s.name), s.ownerReferences()); err != nil {
 		return err
@@ -270,7 +304,7 @@ func (s *Site) checkDefaultRouterAccess(ctxt context.Context, site *skupperv2alp
 		Spec: skupperv2alpha1.RouterAccessSpec{
 			AccessType:             accessType,
 			TlsCredentials:         "skupper-site-server",
-			Issuer:                 "skupper-site-ca", //TODO: can rely ondefault here
+			Issuer:                 site.DefaultIssuer(),
 			GenerateTlsCredentials: true,
 			Roles: []skupperv2alpha1.RouterAccessRole{
 				{
@@ -662,8 +696,8 @@ func (s *Site) recoverRouterConfig(update bool) ([]*qdr.RouterConfig, error) {
 	for i, group := range groups {
 		if config, ok := byName[group]; ok {
 			if update {
-				op := ConfigUpdateList{s.bindings, s, s.linkAccess.DesiredConfig(groups[:i], SSL_PROFILE_PATH)}
-				if err := kubeqdr.UpdateRouterConfig(s.clients.GetKubeClient(), group, s.namespace, context.TODO(), op, s.labelling); err != nil {
+				op := ConfigUpdateList{s.bindings, s, s.linkAccess.DesiredConfig(groups[:i], SSL_PROFILE_PATH), s.drain}
+				if err := kubeqdr.UpdateRouterConfig(s.clients.GetKubeClient(), group, s.namespace, context.TODO(), op, s.routerConfigLabelling()); err != nil {
 					s.logger.Error("Failed to update router config map",
 						slog.String("namespace", s.namespace),
 						slog.String("name", group),
@@ -676,6 +710,7 @@ func (s *Site) recoverRouterConfig(update bool) ([]*qdr.RouterConfig, error) {
 			routerConfig := s.initialRouterConfig()
 			s.bindings.Apply(routerConfig)
 			s.linkAccess.DesiredConfig(groups[:i], SSL_PROFILE_PATH).Apply(routerConfig)
+			s.drain.Apply(routerConfig)
 			if err := s.createRouterConfigForGroup(group, routerConfig); err != nil {
 				s.logger.Error("Failed to create router config map",
 					slog.String("namespace", s.namespace),
@@ -749,10 +784,9 @@ func (s *Site) createRouterConfigForGroup(group string, config *qdr.RouterConfig
 		},
 		Data: data,
 	}
-	if s.labelling != nil {
-		s.labelling.SetLabels(s.namespace, group, "ConfigMap", cm.ObjectMeta.Labels)
-		s.labelling.SetAnnotations(s.namespace, group, "ConfigMap", cm.ObjectMeta.Annotations)
-	}
+	labelling := s.routerConfigLabelling()
+	labelling.SetLabels(s.namespace, group, "ConfigMap", cm.ObjectMeta.Labels)
+	labelling.SetAnnotations(s.namespace, group, "ConfigMap", cm.ObjectMeta.Annotations)
 	if _, err = s.clients.GetKubeClient().CoreV1().ConfigMaps(s.namespace).Create(context.TODO(), cm, metav1.CreateOptions{}); err != nil {
 		s.logger.Error("Failed to create config map",
 			slog.String("namespace", s.namespace),
@@ -782,7 +816,9 @@ func (s *Site) updateRouterConfigForGroup(update qdr.ConfigUpdate, group string)
 	if !s.initialised {
 		return nil
 	}
-	if err := kubeqdr.UpdateRouterConfig(s.clients.GetKubeClient(), group, s.namespace, context.TODO(), update, s.labelling); err != nil {
+	// the drain is applied last, so that it overrides any connectors
+	// or link costs set by the update
+	if err := kubeqdr.UpdateRouterConfig(s.clients.GetKubeClient(), group, s.namespace, context.TODO(), ConfigUpdateList{update, s.drain}, s.routerConfigLabelling()); err != nil {
 		return err
 	}
 	return nil
@@ -812,7 +848,7 @@ func (s *Site) updateConnectorConfiguredStatusWithSelectedPods(connector *skuppe
 			slog.String("name", connector.Name))
 		err = fmt.Errorf("No pods match selector")
 	} else {
-
+		err = site.CheckConnectorType(connector)
 	}
 	if connector.SetConfigured(err) || connector.SetSelectedPods(selected) {
 		return s.updateConnectorStatus(connector)
@@ -835,7 +871,7 @@ func (s *Site) CheckConnector(name string, connector *skupperv2alpha1.Connector)
 	if connector == nil {
 		return err
 	}
-	return s.updateConnectorConfiguredStatus(connector, err)
+	return s.updateConnectorConfiguredStatus(connector, stderrors.Join(site.CheckConnectorType(connector), err))
 }
 
 func (s *Site) updateListenerStatus(listener *skupperv2alpha1.Listener, err error) error {
@@ -894,12 +930,12 @@ func (s *Site) CheckListener(name string, listener *skupperv2alpha1.Listener) er
 	if listener == nil {
 		return stderrors.Join(err1, err2)
 	}
-	return s.updateListenerStatus(listener, stderrors.Join(err1, err2))
+	return s.updateListenerStatus(listener, stderrors.Join(site.CheckListenerType(listener), err1, err2))
 }
 
 func (s *Site) setBindingsConfiguredStatus(err error) {
 	lf := func(listener *skupperv2alpha1.Listener) *skupperv2alpha1.Listener {
-		if listener.SetConfigured(nil) {
+		if listener.SetConfigured(site.CheckListenerType(listener)) {
 			updated, err := s.clients.GetSkupperClient().SkupperV2alpha1().Listeners(listener.ObjectMeta.Namespace).UpdateStatus(context.TODO(), listener, metav1.UpdateOptions{})
 			if err == nil {
 				return updated
@@ -913,7 +949,7 @@ func (s *Site) setBindingsConfiguredStatus(err error) {
 		return nil
 	}
 	cf := func(connector *skupperv2alpha1.Connector) *skupperv2alpha1.Connector {
-		if connector.SetConfigured(nil) {
+		if connector.SetConfigured(site.CheckConnectorType(connector)) {
 			updated, err := s.clients.GetSkupperClient().SkupperV2alpha1().Connectors(connector.ObjectMeta.Namespace).UpdateStatus(context.TODO(), connector, metav1.UpdateOptions{})
 			if err == nil {
 				return updated
@@ -960,7 +996,17 @@ func (s *Site) link(linkconfig *skupperv2alpha1.Link) error {
 				slog.String("namespace", s.namespace),
 				slog.String("token", linkconfig.ObjectMeta.Name))
 			err := s.updateRouterConfig(config)
-			return s.updateLinkConfiguredCondition(linkconfig, err)
+			if err == nil {
+				s.scheduleLinkFailoverCheck(config)
+			}
+			changed := s.setLinkActiveEndpoint(config)
+			if linkconfig.SetConfigured(err) {
+				changed = true
+			}
+			if changed {
+				return s.updateLinkStatus(linkconfig)
+			}
+			return nil
 		} else {
 			s.logger.Debug("No update to router config required for link",
 				slog.String("namespace", linkconfig.ObjectMeta.Namespace),
@@ -988,6 +1034,55 @@ func (s *Site) unlink(name string) error {
 	return nil
 }
 
+// scheduleLinkFailoverCheck arranges for the link to be checked once the
+// failover threshold has passed since it went down, if it has more than
+// one candidate endpoint
+func (s *Site) scheduleLinkFailoverCheck(link *site.Link) {
+	name := link.Definition().ObjectMeta.Name
+	if s.linkChecks[name] || !link.HasFailover(s.linkRole()) {
+		return
+	}
+	s.linkChecks[name] = true
+	now := time.Now()
+	link.RecordOperational(link.Definition().IsOperational(), now)
+	s.clients.NamespacedCallbackAfter(s.namespace, link.NextFailoverCheck(now), s.checkLinkFailover, name)
+}
+
+func (s *Site) checkLinkFailover(name string) error {
+	delete(s.linkChecks, name)
+	link, ok := s.links[name]
+	if !ok || !s.initialised {
+		return nil
+	}
+	role := s.linkRole()
+	if link.CheckOperational(role, link.Definition().IsOperational(), time.Now()) {
+		endpoint, _ := link.ActiveEndpoint(role)
+		s.logger.Info("Link not operational, failing over to next endpoint",
+			slog.String("namespace", s.namespace),
+			slog.String("link", name),
+			slog.String("host", endpoint.Host),
+			slog.String("port", endpoint.Port))
+		if err := s.updateRouterConfig(link); err != nil {
+			return err
+		}
+		if s.setLinkActiveEndpoint(link) {
+			if err := s.updateLinkStatus(link.Definition()); err != nil {
+				return err
+			}
+		}
+	}
+	s.scheduleLinkFailoverCheck(link)
+	return nil
+}
+
+func (s *Site) setLinkActiveEndpoint(link *site.Link) bool {
+	var active *skupperv2alpha1.Endpoint
+	if endpoint, ok := link.ActiveEndpoint(s.linkRole()); ok {
+		active = &endpoint
+	}
+	return link.Definition().SetActiveEndpoint(active)
+}
+
 func (s *Site) updateLinkConfiguredCondition(link *skupperv2alpha1.Link, err error) error {
 	if link == nil {
 		return nil
@@ -1028,6 +1123,9 @@ func (s *Site) updateConfigured(err error) error {
 	if s.setDefaultIssuerInStatus() {
 		changed = true
 	}
+	if s.site.SetDrainStatus(s.drainStatus()) {
+		changed = true
+	}
 	if s.site.SetConfigured(err) {
 		changed = true
 		if err != nil {
@@ -1083,6 +1181,7 @@ func (s *Site) NetworkStatusUpdated(network []skupperv2alpha1.SiteRecord) error
 	linkRecords := internalnetwork.GetLinkRecordsForSite(s.site.GetSiteId(), network)
 	for _, linkRecord := range linkRecords {
 		if link, ok := s.links[linkRecord.Name]; ok {
+			link.RecordOperational(linkRecord.Operational, time.Now())
 			if err := s.updateLinkOperationalCondition(link.Definition(), linkRecord.Operational, linkRecord.RemoteSiteId, linkRecord.RemoteSiteName); err != nil {
 				s.logger.Error("Error updating operational status of link",
 					slog.String("namespace", s.site.Namespace),
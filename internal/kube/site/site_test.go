@@ -3,10 +3,13 @@ package site
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/skupperproject/skupper/internal/kube/certificates"
 	fakeclient "github.com/skupperproject/skupper/internal/kube/client/fake"
+	kubeqdr "github.com/skupperproject/skupper/internal/kube/qdr"
 	"github.com/skupperproject/skupper/internal/kube/securedaccess"
+	"github.com/skupperproject/skupper/internal/kube/site/sizing"
 	"github.com/skupperproject/skupper/internal/kube/watchers"
 	"github.com/skupperproject/skupper/internal/qdr"
 	site1 "github.com/skupperproject/skupper/internal/site"
@@ -697,6 +700,143 @@ func TestSite_CheckLink(t *testing.T) {
 	}
 }
 
+func TestSite_CheckLinkFailover(t *testing.T) {
+	link := &skupperv2alpha1.Link{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "link1",
+			Namespace: "test",
+		},
+		Spec: skupperv2alpha1.LinkSpec{
+			Endpoints: []skupperv2alpha1.Endpoint{
+				{
+					Name: string(qdr.RoleInterRouter),
+					Host: "lb.example.com",
+					Port: "55671",
+				},
+				{
+					Name: string(qdr.RoleInterRouter),
+					Host: "route.example.com",
+					Port: "443",
+				},
+			},
+			Settings: map[string]string{
+				site1.LinkFailoverThresholdSetting: "500ms",
+			},
+		},
+	}
+	threshold := 500 * time.Millisecond
+	s, err := newSiteMocks("test", nil, []runtime.Object{link.DeepCopy()}, "", false)
+	assert.Assert(t, err)
+	s.initialised = true
+	assert.Assert(t, createRouterConfigMock(s))
+
+	// the link is down from the time it is configured
+	assert.Assert(t, s.CheckLink(link.Name, link))
+	assert.Assert(t, s.linkChecks[link.Name])
+	assert.Equal(t, s.links[link.Name].Definition().Status.ActiveEndpoint.Host, "lb.example.com")
+	assert.Assert(t, s.links[link.Name].NextFailoverCheck(time.Now()) <= threshold)
+
+	// an early check does not fail over, but is rescheduled for the
+	// remaining time
+	assert.Assert(t, s.checkLinkFailover(link.Name))
+	assert.Equal(t, s.links[link.Name].Definition().Status.ActiveEndpoint.Host, "lb.example.com")
+	assert.Assert(t, s.links[link.Name].NextFailoverCheck(time.Now()) < threshold)
+
+	// the check once the threshold has passed since the link was
+	// configured fails over
+	time.Sleep(s.links[link.Name].NextFailoverCheck(time.Now()))
+	assert.Assert(t, s.checkLinkFailover(link.Name))
+	assert.Equal(t, s.links[link.Name].Definition().Status.ActiveEndpoint.Host, "route.example.com")
+	assert.Assert(t, s.linkChecks[link.Name])
+
+	cm, err := s.clients.GetKubeClient().CoreV1().ConfigMaps("test").Get(context.TODO(), "skupper-router", v1.GetOptions{})
+	assert.Assert(t, err)
+	routerConfig, err := qdr.GetRouterConfigFromConfigMap(cm)
+	assert.Assert(t, err)
+	assert.Equal(t, routerConfig.Connectors[link.Name].Host, "route.example.com")
+
+	assert.Assert(t, s.unlink(link.Name))
+	assert.Assert(t, s.checkLinkFailover(link.Name))
+	assert.Assert(t, !s.linkChecks[link.Name])
+}
+
+func TestSite_Drain(t *testing.T) {
+	link := &skupperv2alpha1.Link{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "link1",
+			Namespace: "test",
+		},
+		Spec: skupperv2alpha1.LinkSpec{
+			Endpoints: []skupperv2alpha1.Endpoint{
+				{
+					Name: string(qdr.RoleInterRouter),
+					Host: "peer.example.com",
+					Port: "55671",
+				},
+			},
+			Cost: 2,
+		},
+	}
+	s, err := newSiteMocks("test", nil, []runtime.Object{link.DeepCopy()}, "", false)
+	assert.Assert(t, err)
+	s.initialised = true
+	s.currentGroups = s.groups()
+	s.sizes = sizing.NewRegistry()
+	assert.Assert(t, createRouterConfigMock(s))
+	assert.Assert(t, s.CheckLink(link.Name, link))
+	assert.Assert(t, s.updateRouterConfig(s.bindings.UpdateConnector("backend", &skupperv2alpha1.Connector{
+		ObjectMeta: v1.ObjectMeta{Name: "backend", Namespace: "test"},
+		Spec:       skupperv2alpha1.ConnectorSpec{RoutingKey: "backend", Host: "backend", Port: 8080},
+	})))
+
+	routerConfig := func() (*qdr.RouterConfig, *corev1.ConfigMap) {
+		t.Helper()
+		cm, err := s.clients.GetKubeClient().CoreV1().ConfigMaps("test").Get(context.TODO(), "skupper-router", v1.GetOptions{})
+		assert.Assert(t, err)
+		config, err := qdr.GetRouterConfigFromConfigMap(cm)
+		assert.Assert(t, err)
+		return config, cm
+	}
+	config, _ := routerConfig()
+	assert.Equal(t, len(config.Bridges.TcpConnectors), 1)
+
+	draining := s.site.DeepCopy()
+	draining.Spec.Settings = map[string]string{"drain": "true"}
+	assert.Assert(t, s.Reconcile(draining))
+	config, cm := routerConfig()
+	assert.Equal(t, len(config.Bridges.TcpConnectors), 0)
+	assert.Equal(t, config.Connectors[link.Name].Cost, int32(site1.DrainLinkCost))
+	started := cm.ObjectMeta.Annotations[kubeqdr.DrainAnnotation]
+	assert.Assert(t, started != "")
+	// no flows have been reported by the router yet
+	assert.Equal(t, s.site.Status.Drain.Phase, skupperv2alpha1.DrainPhaseDraining)
+	assert.Equal(t, s.site.Status.Drain.StartTime, started)
+
+	// updates to bindings and links do not undo the drain
+	assert.Assert(t, s.updateRouterConfig(s.restoreConfig()))
+	config, _ = routerConfig()
+	assert.Equal(t, len(config.Bridges.TcpConnectors), 0)
+	assert.Equal(t, config.Connectors[link.Name].Cost, int32(site1.DrainLinkCost))
+
+	cm.ObjectMeta.Annotations[kubeqdr.DrainActiveFlowsAnnotation] = "3"
+	assert.Assert(t, s.RouterConfigUpdated("skupper-router", cm))
+	assert.Equal(t, s.site.Status.Drain.Phase, skupperv2alpha1.DrainPhaseDraining)
+	assert.Equal(t, s.site.Status.Drain.ActiveFlows, 3)
+	cm.ObjectMeta.Annotations[kubeqdr.DrainActiveFlowsAnnotation] = "0"
+	assert.Assert(t, s.RouterConfigUpdated("skupper-router", cm))
+	assert.Equal(t, s.site.Status.Drain.Phase, skupperv2alpha1.DrainPhaseDrained)
+
+	undrained := s.site.DeepCopy()
+	undrained.Spec.Settings = nil
+	assert.Assert(t, s.Reconcile(undrained))
+	config, cm = routerConfig()
+	assert.Equal(t, len(config.Bridges.TcpConnectors), 1)
+	assert.Equal(t, config.Connectors[link.Name].Cost, int32(2))
+	_, ok := cm.ObjectMeta.Annotations[kubeqdr.DrainAnnotation]
+	assert.Assert(t, !ok)
+	assert.Assert(t, s.site.Status.Drain == nil)
+}
+
 func TestSite_CheckRouterAccess(t *testing.T) {
 	type args struct {
 		name string
@@ -1042,6 +1182,7 @@ func newSiteMocks(namespace string, k8sObjects []runtime.Object, skupperObjects
 		clients:    controller,
 		bindings:   NewExtendedBindings(controller, ""),
 		links:      make(map[string]*site1.Link),
+		linkChecks: make(map[string]bool),
 		errors:     make(map[string]string),
 		linkAccess: make(map[string]*skupperv2alpha1.RouterAccess),
 		certs:      certificates.NewCertificateManager(controller),
@@ -1050,6 +1191,8 @@ func newSiteMocks(namespace string, k8sObjects []runtime.Object, skupperObjects
 		logger: slog.New(slog.Default().Handler()).With(
 			slog.String("component", "kube.site.site"),
 		),
+		drain:      site1.NewDrain(),
+		drainFlows: make(map[string]int),
 	}
 	newSite.bindings.init(NewMockBindingContext(map[string]TargetSelection{}), &qdr.RouterConfig{})
 
@@ -3,6 +3,7 @@ package sizing
 import (
 	"errors"
 	"fmt"
+	"sync"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -19,6 +20,7 @@ type Registry struct {
 	sizes       map[string]*corev1.ConfigMap //keyed on size name
 	names       map[string]string            //ConfigMap key -> size name
 	defaultSize string
+	lock        sync.RWMutex
 }
 
 func NewRegistry() *Registry {
@@ -29,6 +31,8 @@ func NewRegistry() *Registry {
 }
 
 func (r *Registry) Update(key string, cm *corev1.ConfigMap) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
 	if name, ok := getSizeName(cm); ok {
 		if existing, ok := r.names[key]; ok {
 			delete(r.sizes, existing)
@@ -78,6 +82,8 @@ func desiredSize(site *skupperv2alpha1.Site) string {
 }
 
 func (r *Registry) getSizeConfiguration(name string) *corev1.ConfigMap {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
 	if conf, ok := r.sizes[name]; ok {
 		return conf
 	}
@@ -0,0 +1,148 @@
+package watchers
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/tools/cache"
+)
+
+// DeadLetter describes an event that could not be handled after
+// repeated retries and is now only retried periodically.
+type DeadLetter struct {
+	Key     string
+	Handler string
+	Error   string
+	Since   time.Time
+}
+
+// deadLetters tracks events that have exhausted their retries, until
+// they are handled successfully, and the resources for which events
+// have been handled successfully since the processor started.
+type deadLetters struct {
+	lock    sync.Mutex
+	events  map[ResourceChange]*DeadLetter
+	handled map[resourceKey]bool
+	deleted map[resourceKey]bool
+	handler DeadLetterHandler
+}
+
+// resourceKey identifies the resource an event relates to
+type resourceKey struct {
+	handler string
+	key     string
+}
+
+func newDeadLetters() *deadLetters {
+	return &deadLetters{
+		events:  map[ResourceChange]*DeadLetter{},
+		handled: map[resourceKey]bool{},
+		deleted: map[resourceKey]bool{},
+	}
+}
+
+func (d *deadLetters) add(evt ResourceChange, err error) {
+	d.lock.Lock()
+	if existing, ok := d.events[evt]; ok {
+		existing.Error = err.Error()
+	} else {
+		d.events[evt] = &DeadLetter{
+			Key:     evt.Key,
+			Handler: handlerName(evt.Handler),
+			Error:   err.Error(),
+			Since:   time.Now(),
+		}
+	}
+	d.lock.Unlock()
+	if d.handler != nil {
+		d.handler.RetriesExhausted(evt, err)
+	}
+}
+
+// remove is called when an event is handled successfully. The handler
+// is notified if the event had exhausted its retries or if this is the
+// first time an event for the resource was handled, as it may have
+// exhausted its retries before the processor was started (e.g. by a
+// previous leader). Callbacks are not resources, so only the former
+// applies to them.
+func (d *deadLetters) remove(evt ResourceChange) {
+	d.lock.Lock()
+	_, ok := d.events[evt]
+	delete(d.events, evt)
+	first := false
+	if _, callback := evt.Handler.(*CallbackHandler); !callback {
+		resource := resourceKey{handler: handlerName(evt.Handler), key: evt.Key}
+		if d.deleted[resource] {
+			delete(d.deleted, resource)
+			delete(d.handled, resource)
+		} else {
+			first = !d.handled[resource]
+			d.handled[resource] = true
+		}
+	}
+	d.lock.Unlock()
+	if (ok || first) && d.handler != nil {
+		d.handler.Recovered(evt)
+	}
+}
+
+// resourceDeleted is called when the resource an event relates to is
+// deleted, so that it is no longer tracked once the deletion has been
+// handled.
+func (d *deadLetters) resourceDeleted(evt ResourceChange) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	resource := resourceKey{handler: handlerName(evt.Handler), key: evt.Key}
+	if d.handled[resource] {
+		d.deleted[resource] = true
+	}
+}
+
+// pending returns true if any event for the namespace that matches
+// the supplied function has exhausted its retries.
+func (d *deadLetters) pending(namespace string, match func(ResourceChange) bool) bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	for evt := range d.events {
+		if ns, _, err := cache.SplitMetaNamespaceKey(evt.Key); err == nil && ns == namespace && match(evt) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *deadLetters) len() int {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return len(d.events)
+}
+
+func (d *deadLetters) list() []DeadLetter {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	var results []DeadLetter
+	for _, letter := range d.events {
+		results = append(results, *letter)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Key == results[j].Key {
+			return results[i].Handler < results[j].Handler
+		}
+		return results[i].Key < results[j].Key
+	})
+	return results
+}
+
+// DeadLetters returns the events that have exhausted their retries
+// and have not since been handled successfully.
+func (c *EventProcessor) DeadLetters() []DeadLetter {
+	return c.deadLetters.list()
+}
+
+// HasDeadLetters returns true if any event for the namespace that
+// matches the supplied function has exhausted its retries and has not
+// since been handled successfully.
+func (c *EventProcessor) HasDeadLetters(namespace string, match func(ResourceChange) bool) bool {
+	return c.deadLetters.pending(namespace, match)
+}
@@ -0,0 +1,109 @@
+package watchers
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// maxRequeues is the number of times a failed event is retried before
+// it is treated as a dead letter.
+const maxRequeues = 5
+
+type processorMetrics struct {
+	reconcileDuration *prometheus.HistogramVec
+	reconcileErrors   *prometheus.CounterVec
+	retriesExhausted  *prometheus.CounterVec
+}
+
+func (m *processorMetrics) observe(handler string, duration time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	m.reconcileDuration.WithLabelValues(handler).Observe(duration.Seconds())
+	if err != nil {
+		m.reconcileErrors.WithLabelValues(handler).Inc()
+	}
+}
+
+func (m *processorMetrics) exhausted(handler string) {
+	if m == nil {
+		return
+	}
+	m.retriesExhausted.WithLabelValues(handler).Inc()
+}
+
+// RegisterMetrics registers metrics describing the work queue and the
+// handling of events by this EventProcessor. Metrics are labelled
+// with the name the EventProcessor was created with and, where
+// relevant, the kind of resource whose event was handled.
+func (c *EventProcessor) RegisterMetrics(reg prometheus.Registerer) error {
+	labels := prometheus.Labels{"processor": c.name}
+	queueDepth := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace:   "skupper",
+		Subsystem:   "controller",
+		Name:        "queue_depth",
+		Help:        "Number of events waiting to be processed",
+		ConstLabels: labels,
+	}, func() float64 {
+		return float64(c.len())
+	})
+	deadLetters := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace:   "skupper",
+		Subsystem:   "controller",
+		Name:        "dead_letters",
+		Help:        "Number of events that have exhausted their retries and are only retried periodically",
+		ConstLabels: labels,
+	}, func() float64 {
+		return float64(c.deadLetters.len())
+	})
+	metrics := &processorMetrics{
+		reconcileDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   "skupper",
+			Subsystem:   "controller",
+			Name:        "reconcile_duration_seconds",
+			Help:        "Time taken to handle an event, by resource kind",
+			ConstLabels: labels,
+			Buckets:     prometheus.ExponentialBuckets(0.001, 4, 9),
+		}, []string{"handler"}),
+		reconcileErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   "skupper",
+			Subsystem:   "controller",
+			Name:        "reconcile_errors_total",
+			Help:        "Count of events for which the handler returned an error, by resource kind",
+			ConstLabels: labels,
+		}, []string{"handler"}),
+		retriesExhausted: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   "skupper",
+			Subsystem:   "controller",
+			Name:        "retries_exhausted_total",
+			Help:        "Count of events dropped after repeatedly failing to be handled, by resource kind",
+			ConstLabels: labels,
+		}, []string{"handler"}),
+	}
+	for _, collector := range []prometheus.Collector{
+		queueDepth,
+		deadLetters,
+		metrics.reconcileDuration,
+		metrics.reconcileErrors,
+		metrics.retriesExhausted,
+	} {
+		if err := reg.Register(collector); err != nil {
+			return err
+		}
+	}
+	c.metrics = metrics
+	return nil
+}
+
+// handlerName returns a low cardinality name for a handler, derived
+// from its type, e.g. "Site" for a *SiteWatcher.
+func handlerName(handler ResourceChangeHandler) string {
+	t := reflect.TypeOf(handler)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return strings.TrimSuffix(t.Name(), "Watcher")
+}
@@ -0,0 +1,98 @@
+package watchers
+
+import (
+	"hash/fnv"
+
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// namespaceOf returns the namespace an item on the work queue relates
+// to. Cluster scoped resources, and callbacks not associated with a
+// namespace, all share the empty namespace.
+func namespaceOf(item interface{}) string {
+	evt, ok := item.(ResourceChange)
+	if !ok {
+		return ""
+	}
+	namespace, _, err := cache.SplitMetaNamespaceKey(evt.Key)
+	if err != nil {
+		return ""
+	}
+	return namespace
+}
+
+// shardFor returns the index of the shard responsible for events
+// in the given namespace. All events for a namespace are handled by
+// the same shard, so they are processed in order.
+func shardFor(namespace string, shards int) int {
+	if shards <= 1 {
+		return 0
+	}
+	h := fnv.New32a()
+	h.Write([]byte(namespace))
+	return int(h.Sum32() % uint32(shards))
+}
+
+// fairQueue is the underlying store for a shard's work queue. Items
+// are held in a FIFO per namespace and the namespaces with pending
+// items are served in turn, so that a namespace generating a lot of
+// events does not delay processing of the others sharing the shard.
+//
+// Synchronisation is provided by the work queue wrapping it.
+type fairQueue struct {
+	pending map[string][]interface{}
+	order   []string
+	size    int
+}
+
+func newFairQueue() workqueue.Queue[any] {
+	return &fairQueue{
+		pending: map[string][]interface{}{},
+	}
+}
+
+func (q *fairQueue) Touch(item interface{}) {}
+
+func (q *fairQueue) Push(item interface{}) {
+	namespace := namespaceOf(item)
+	if len(q.pending[namespace]) == 0 {
+		q.order = append(q.order, namespace)
+	}
+	q.pending[namespace] = append(q.pending[namespace], item)
+	q.size++
+}
+
+func (q *fairQueue) Len() int {
+	return q.size
+}
+
+func (q *fairQueue) Pop() interface{} {
+	namespace := q.order[0]
+	q.order[0] = ""
+	q.order = q.order[1:]
+	items := q.pending[namespace]
+	item := items[0]
+	items[0] = nil
+	if len(items) > 1 {
+		q.pending[namespace] = items[1:]
+		q.order = append(q.order, namespace)
+	} else {
+		delete(q.pending, namespace)
+	}
+	q.size--
+	return item
+}
+
+func newShardQueue(name string) workqueue.RateLimitingInterface {
+	return workqueue.NewRateLimitingQueueWithConfig(workqueue.DefaultControllerRateLimiter(), workqueue.RateLimitingQueueConfig{
+		Name: name,
+		DelayingQueue: workqueue.NewDelayingQueueWithConfig(workqueue.DelayingQueueConfig{
+			Name: name,
+			Queue: workqueue.NewTypedWithConfig(workqueue.TypedQueueConfig[any]{
+				Name:  name,
+				Queue: newFairQueue(),
+			}),
+		}),
+	})
+}
@@ -59,6 +59,18 @@ type ResourceChangeHandler interface {
 	Describe(event ResourceChange) string
 }
 
+// A DeadLetterHandler is informed when an event could still not be
+// handled after repeated retries, and when such an event is later
+// handled successfully. Recovered is also invoked the first time an
+// event for each resource is handled successfully, as it may have
+// exhausted its retries before the EventProcessor was started. Both
+// methods are invoked on the go routine processing events for the
+// namespace of the event.
+type DeadLetterHandler interface {
+	RetriesExhausted(event ResourceChange, err error)
+	Recovered(event ResourceChange)
+}
+
 // The Watcher interface allows the EventProcessor to interact with
 // different informers on startup.
 type Watcher interface {
@@ -67,32 +79,70 @@ type Watcher interface {
 }
 
 // A EventProcessor provides a way to handle events from multiple
-// different informers on the same go routine. It does this using a
-// single work queue into which the events are added as instances of the
-// ResourceChange struct.
+// different informers. It does this using work queues into which the
+// events are added as instances of the ResourceChange struct. Events
+// are sharded by namespace across one or more work queues, each
+// processed on its own go routine, so that events for any given
+// namespace are always handled in order on the same go routine.
 type EventProcessor struct {
+	name            string
 	errorKey        string
 	client          kubernetes.Interface
 	routeClient     openshiftroute.Interface
 	dynamicClient   dynamic.Interface
 	discoveryClient discovery.DiscoveryInterface
 	skupperClient   skupperclient.Interface
-	queue           workqueue.RateLimitingInterface
+	queues          []workqueue.RateLimitingInterface
 	resync          time.Duration
 	watchers        []Watcher
+	metrics         *processorMetrics
+	redrive         time.Duration
+	deadLetters     *deadLetters
 }
 
-// Creates a properly initialised EventProcessor instance.
+// Creates a properly initialised EventProcessor instance that
+// handles all events on a single go routine.
 func NewEventProcessor(name string, clients internalclient.Clients) *EventProcessor {
+	return NewShardedEventProcessor(name, clients, 1)
+}
+
+// Creates a properly initialised EventProcessor instance that handles
+// events on the specified number of go routines, sharded by
+// namespace.
+func NewShardedEventProcessor(name string, clients internalclient.Clients, workers int) *EventProcessor {
+	if workers < 1 {
+		workers = 1
+	}
+	queues := make([]workqueue.RateLimitingInterface, workers)
+	for i := range queues {
+		if workers == 1 {
+			queues[i] = newShardQueue(name)
+		} else {
+			queues[i] = newShardQueue(fmt.Sprintf("%s-%d", name, i))
+		}
+	}
 	return &EventProcessor{
+		name:            name,
 		errorKey:        name + "Error",
 		client:          clients.GetKubeClient(),
 		routeClient:     clients.GetRouteInterface(),
 		discoveryClient: clients.GetDiscoveryClient(),
 		dynamicClient:   clients.GetDynamicClient(),
 		skupperClient:   clients.GetSkupperClient(),
-		queue:           workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), name),
+		queues:          queues,
 		resync:          time.Minute * 5,
+		redrive:         time.Minute * 5,
+		deadLetters:     newDeadLetters(),
+	}
+}
+
+// SetDeadLetterHandler configures the handler to be notified of
+// events that exhaust their retries, and the interval after which
+// such events are retried again.
+func (c *EventProcessor) SetDeadLetterHandler(handler DeadLetterHandler, redrive time.Duration) {
+	c.deadLetters.handler = handler
+	if redrive > 0 {
+		c.redrive = redrive
 	}
 }
 
@@ -135,61 +185,114 @@ func (c *EventProcessor) GetSkupperClient() skupperclient.Interface {
 	return c.skupperClient
 }
 
-// Starts the event processing loop in a new go routine.
+// Starts the event processing loops, each in a new go routine.
 func (c *EventProcessor) Start(stopCh <-chan struct{}) {
-	go wait.Until(c.run, time.Second, stopCh)
+	for _, queue := range c.queues {
+		go wait.Until(func() {
+			for c.process(queue) {
+			}
+		}, time.Second, stopCh)
+	}
+}
+
+// Workers returns the number of go routines on which events are
+// processed.
+func (c *EventProcessor) Workers() int {
+	return len(c.queues)
+}
+
+// SameWorker returns true if events in the two namespaces are
+// processed on the same go routine.
+func (c *EventProcessor) SameWorker(namespace string, other string) bool {
+	return shardFor(namespace, len(c.queues)) == shardFor(other, len(c.queues))
+}
+
+func (c *EventProcessor) queueFor(evt ResourceChange) workqueue.RateLimitingInterface {
+	return c.queues[shardFor(namespaceOf(evt), len(c.queues))]
 }
 
-func (c *EventProcessor) run() {
-	for c.process() {
+func (c *EventProcessor) len() int {
+	total := 0
+	for _, queue := range c.queues {
+		total += queue.Len()
 	}
+	return total
 }
 
 // This is a convenience function for tests that use the EventProcessor,
 // which may wish to process events more granularly.
 func (c *EventProcessor) TestProcess() bool {
-	return c.process()
+	if len(c.queues) == 1 {
+		return c.process(c.queues[0])
+	}
+	for {
+		for _, queue := range c.queues {
+			if queue.ShuttingDown() {
+				return false
+			}
+			if queue.Len() > 0 {
+				return c.process(queue)
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
 }
 
 // This is a convenience function for tests that use the EventProcessor.
 func (c *EventProcessor) TestProcessAll() {
-	for c.queue.Len() > 0 {
-		c.process()
+	for c.len() > 0 {
+		c.TestProcess()
 	}
 }
 
 // The process method is the heart of the event processing loop.
-func (c *EventProcessor) process() bool {
-	obj, shutdown := c.queue.Get()
+func (c *EventProcessor) process(queue workqueue.RateLimitingInterface) bool {
+	obj, shutdown := queue.Get()
 
 	if shutdown {
 		return false
 	}
 
-	retry := false
-	defer c.queue.Done(obj)
-	if evt, ok := obj.(ResourceChange); ok {
-		err := evt.Handler.Handle(evt)
+	var err error
+	defer queue.Done(obj)
+	evt, ok := obj.(ResourceChange)
+	if ok {
+		start := time.Now()
+		err = evt.Handler.Handle(evt)
+		c.metrics.observe(handlerName(evt.Handler), time.Since(start), err)
 		if err != nil {
-			retry = true
 			log.Printf("[%s] Error while handling %s: %s", c.errorKey, evt.Handler.Describe(evt), err)
 		}
 	} else {
 		log.Printf("Invalid object on event queue for %q: %#v", c.errorKey, obj)
 	}
 
-	if retry && c.queue.NumRequeues(obj) < 5 {
-		c.queue.AddRateLimited(obj)
+	if err != nil {
+		if queue.NumRequeues(obj) < maxRequeues {
+			queue.AddRateLimited(obj)
+			return true
+		}
+		log.Printf("[%s] Giving up on %s after %d retries, will try again in %s", c.errorKey, evt.Handler.Describe(evt), maxRequeues, c.redrive)
+		c.metrics.exhausted(handlerName(evt.Handler))
+		queue.Forget(obj)
+		c.deadLetters.add(evt, err)
+		// the event is retried periodically, rather than dropped,
+		// so that the resource converges once the cause is fixed
+		queue.AddAfter(obj, c.redrive)
 		return true
+	} else if ok {
+		c.deadLetters.remove(evt)
 	}
-	c.queue.Forget(obj)
+	queue.Forget(obj)
 
 	return true
 }
 
 // Stops event processing.
 func (c *EventProcessor) Stop() {
-	c.queue.ShutDown()
+	for _, queue := range c.queues {
+		queue.ShutDown()
+	}
 }
 
 // Creates an event handler that will take handle events from an
@@ -206,7 +309,7 @@ func (c *EventProcessor) newEventHandler(handler ResourceChangeHandler) *cache.R
 				utilruntime.HandleError(err)
 			} else {
 				evt.Key = key
-				c.queue.Add(evt)
+				c.queueFor(evt).Add(evt)
 			}
 		},
 		UpdateFunc: func(old, new interface{}) {
@@ -215,7 +318,7 @@ func (c *EventProcessor) newEventHandler(handler ResourceChangeHandler) *cache.R
 				utilruntime.HandleError(err)
 			} else {
 				evt.Key = key
-				c.queue.Add(evt)
+				c.queueFor(evt).Add(evt)
 			}
 		},
 		DeleteFunc: func(obj interface{}) {
@@ -224,7 +327,8 @@ func (c *EventProcessor) newEventHandler(handler ResourceChangeHandler) *cache.R
 				utilruntime.HandleError(err)
 			} else {
 				evt.Key = key
-				c.queue.Add(evt)
+				c.deadLetters.resourceDeleted(evt)
+				c.queueFor(evt).Add(evt)
 			}
 		},
 	}
@@ -674,15 +778,32 @@ func (c *CallbackHandler) Describe(event ResourceChange) string {
 }
 
 // Allows triggering of a callback on the event processing
-// thread. This method call be called on any thread/goroutine.
+// thread. This method call be called on any thread/goroutine. If the
+// context is a namespace qualified key, the callback is invoked on
+// the go routine processing events for that namespace.
 func (c *EventProcessor) CallbackAfter(delay time.Duration, callback Callback, context string) {
 	evt := ResourceChange{
 		Handler: &CallbackHandler{
 			callback: callback,
 			context:  context,
 		},
+		Key: context,
+	}
+	c.queueFor(evt).AddAfter(evt, delay)
+}
+
+// Allows triggering of a callback on the go routine processing events
+// for the specified namespace. This method call be called on any
+// thread/goroutine.
+func (c *EventProcessor) NamespacedCallbackAfter(namespace string, delay time.Duration, callback Callback, context string) {
+	evt := ResourceChange{
+		Handler: &CallbackHandler{
+			callback: callback,
+			context:  context,
+		},
+		Key: namespace + "/" + context,
 	}
-	c.queue.AddAfter(evt, delay)
+	c.queueFor(evt).AddAfter(evt, delay)
 }
 
 func (c *EventProcessor) WatchNamespaces(options internalinterfaces.TweakListOptionsFunc, handler NamespaceHandler) *NamespaceWatcher {
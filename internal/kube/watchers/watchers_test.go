@@ -4,14 +4,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	fakeclient "github.com/skupperproject/skupper/internal/kube/client/fake"
 	"gotest.tools/v3/assert"
 	"gotest.tools/v3/assert/cmp"
@@ -384,14 +389,246 @@ func (stubErrResourceChangeHandler) Describe(e ResourceChange) string {
 func TestProcessRequeueLimit(t *testing.T) {
 	client, _ := fakeclient.NewFakeClient("test", nil, nil, "")
 	processor := NewEventProcessor("tester", client)
-	processor.queue = workqueue.NewNamedRateLimitingQueue(workqueue.NewItemFastSlowRateLimiter(0, time.Microsecond, 10), "testing")
+	processor.queues[0] = workqueue.NewNamedRateLimitingQueue(workqueue.NewItemFastSlowRateLimiter(0, time.Microsecond, 10), "testing")
 	stubHandler := stubErrResourceChangeHandler{}
 	eventsIn := processor.newEventHandler(&stubHandler)
 	eventsIn.AddFunc(node("test"))
 	callCount := 0 // set upper bound on how long test will run
-	for processor.queue.Len() > 0 && callCount < 1_000 {
+	for processor.len() > 0 && callCount < 1_000 {
 		processor.TestProcess()
 		callCount++
 	}
 	assert.Equal(t, stubHandler.CallCount, 6, "Should Requeue 5 times + 1 for the initial event")
 }
+
+func TestProcessMetrics(t *testing.T) {
+	client, _ := fakeclient.NewFakeClient("test", nil, nil, "")
+	processor := NewEventProcessor("tester", client)
+	processor.queues[0] = workqueue.NewNamedRateLimitingQueue(workqueue.NewItemFastSlowRateLimiter(0, time.Microsecond, 10), "testing")
+	reg := prometheus.NewRegistry()
+	assert.Assert(t, processor.RegisterMetrics(reg))
+	stubHandler := stubErrResourceChangeHandler{}
+	eventsIn := processor.newEventHandler(&stubHandler)
+	eventsIn.AddFunc(node("test"))
+	queueDepth, err := testutil.GatherAndCount(reg, "skupper_controller_queue_depth")
+	assert.Assert(t, err)
+	assert.Equal(t, queueDepth, 1)
+	for processor.len() > 0 {
+		processor.TestProcess()
+	}
+	handler := "stubErrResourceChangeHandler"
+	assert.Equal(t, testutil.ToFloat64(processor.metrics.reconcileErrors.WithLabelValues(handler)), float64(6))
+	assert.Equal(t, testutil.ToFloat64(processor.metrics.retriesExhausted.WithLabelValues(handler)), float64(1))
+	assert.Equal(t, testutil.CollectAndCount(processor.metrics.reconcileDuration), 1)
+	assert.Assert(t, processor.RegisterMetrics(reg) != nil, "metrics should not be registered twice")
+}
+
+type flakyResourceChangeHandler struct {
+	failures int
+}
+
+func (s *flakyResourceChangeHandler) Handle(e ResourceChange) error {
+	if s.failures > 0 {
+		s.failures--
+		return fmt.Errorf("still failing")
+	}
+	return nil
+}
+
+func (flakyResourceChangeHandler) Describe(e ResourceChange) string {
+	return fmt.Sprintf("FlakyHandler:%s", e.Key)
+}
+
+type recordingDeadLetterHandler struct {
+	exhausted []string
+	recovered []string
+}
+
+func (h *recordingDeadLetterHandler) RetriesExhausted(e ResourceChange, err error) {
+	h.exhausted = append(h.exhausted, e.Key+": "+err.Error())
+}
+
+func (h *recordingDeadLetterHandler) Recovered(e ResourceChange) {
+	h.recovered = append(h.recovered, e.Key)
+}
+
+func TestDeadLetters(t *testing.T) {
+	client, _ := fakeclient.NewFakeClient("test", nil, nil, "")
+	processor := NewEventProcessor("tester", client)
+	processor.queues[0] = workqueue.NewNamedRateLimitingQueue(workqueue.NewItemFastSlowRateLimiter(0, time.Microsecond, 10), "testing")
+	deadLetterHandler := &recordingDeadLetterHandler{}
+	processor.SetDeadLetterHandler(deadLetterHandler, 10*time.Millisecond)
+	handler := &flakyResourceChangeHandler{failures: 8}
+	eventsIn := processor.newEventHandler(handler)
+	eventsIn.AddFunc(node("test"))
+	for processor.len() > 0 {
+		processor.TestProcess()
+	}
+	assert.DeepEqual(t, deadLetterHandler.exhausted, []string{"test: still failing"})
+	assert.Assert(t, deadLetterHandler.recovered == nil)
+	letters := processor.DeadLetters()
+	assert.Equal(t, len(letters), 1)
+	assert.Equal(t, letters[0].Key, "test")
+	assert.Equal(t, letters[0].Handler, "flakyResourceChangeHandler")
+	assert.Equal(t, letters[0].Error, "still failing")
+
+	// the event is re-driven after the configured interval and, once
+	// handled successfully, is no longer considered dead
+	for len(deadLetterHandler.recovered) == 0 {
+		processor.TestProcess()
+	}
+	assert.Equal(t, handler.failures, 0)
+	assert.DeepEqual(t, deadLetterHandler.exhausted, []string{"test: still failing"})
+	assert.DeepEqual(t, deadLetterHandler.recovered, []string{"test"})
+	assert.Equal(t, len(processor.DeadLetters()), 0)
+
+	// the handler is notified the first time any other event is handled
+	// successfully, as it may have exhausted its retries before the
+	// processor was started, but not subsequently
+	for _, name := range []string{"other", "other", "test"} {
+		eventsIn.AddFunc(node(name))
+		for processor.len() > 0 {
+			processor.TestProcess()
+		}
+	}
+	assert.DeepEqual(t, deadLetterHandler.recovered, []string{"test", "other"})
+
+	// once a resource is deleted it is no longer tracked, so the next
+	// event for a resource of the same name is treated as the first
+	eventsIn.DeleteFunc(node("other"))
+	for processor.len() > 0 {
+		processor.TestProcess()
+	}
+	assert.Equal(t, len(processor.deadLetters.handled), 1)
+	eventsIn.AddFunc(node("other"))
+	for processor.len() > 0 {
+		processor.TestProcess()
+	}
+	assert.DeepEqual(t, deadLetterHandler.recovered, []string{"test", "other", "other"})
+}
+
+func TestDeadLettersCallbacks(t *testing.T) {
+	client, _ := fakeclient.NewFakeClient("test", nil, nil, "")
+	processor := NewEventProcessor("tester", client)
+	deadLetterHandler := &recordingDeadLetterHandler{}
+	processor.SetDeadLetterHandler(deadLetterHandler, 10*time.Millisecond)
+	callback := func(context string) error { return nil }
+	for i := 0; i < 3; i++ {
+		processor.deadLetters.remove(ResourceChange{Handler: &CallbackHandler{callback: callback, context: "test"}, Key: "test"})
+	}
+	assert.Assert(t, deadLetterHandler.recovered == nil)
+	assert.Equal(t, len(processor.deadLetters.handled), 0)
+
+	// a callback that had exhausted its retries is still reported as
+	// recovered
+	evt := ResourceChange{Handler: &CallbackHandler{callback: callback, context: "test"}, Key: "test"}
+	processor.deadLetters.add(evt, errors.New("broken"))
+	processor.deadLetters.remove(evt)
+	assert.DeepEqual(t, deadLetterHandler.recovered, []string{"test"})
+}
+
+func TestHasDeadLetters(t *testing.T) {
+	client, _ := fakeclient.NewFakeClient("test", nil, nil, "")
+	processor := NewEventProcessor("tester", client)
+	handler := &stubErrResourceChangeHandler{}
+	processor.deadLetters.add(ResourceChange{Handler: handler, Key: "test/a"}, errors.New("broken"))
+	processor.deadLetters.add(ResourceChange{Handler: handler, Key: "other/a"}, errors.New("broken"))
+	all := func(ResourceChange) bool { return true }
+	assert.Assert(t, processor.HasDeadLetters("test", all))
+	assert.Assert(t, !processor.HasDeadLetters("test", func(ResourceChange) bool { return false }))
+	assert.Assert(t, !processor.HasDeadLetters("another", all))
+	processor.deadLetters.remove(ResourceChange{Handler: handler, Key: "test/a"})
+	assert.Assert(t, !processor.HasDeadLetters("test", all))
+	assert.Assert(t, processor.HasDeadLetters("other", all))
+}
+
+func TestFairQueue(t *testing.T) {
+	q := newFairQueue()
+	handler := &stubErrResourceChangeHandler{}
+	for _, key := range []string{"noisy/a", "noisy/b", "noisy/c", "quiet/a", "cluster-scoped", "quiet/b"} {
+		q.Push(ResourceChange{Handler: handler, Key: key})
+	}
+	assert.Equal(t, q.Len(), 6)
+	var keys []string
+	for q.Len() > 0 {
+		keys = append(keys, q.Pop().(ResourceChange).Key)
+	}
+	assert.DeepEqual(t, keys, []string{"noisy/a", "quiet/a", "cluster-scoped", "noisy/b", "quiet/b", "noisy/c"})
+}
+
+type recordingHandler struct {
+	lock    sync.Mutex
+	handled map[string][]string
+	blocked string
+	release chan struct{}
+	done    chan string
+}
+
+func (h *recordingHandler) Handle(e ResourceChange) error {
+	namespace, name, _ := cache.SplitMetaNamespaceKey(e.Key)
+	if namespace == h.blocked {
+		<-h.release
+	}
+	h.lock.Lock()
+	h.handled[namespace] = append(h.handled[namespace], name)
+	h.lock.Unlock()
+	h.done <- e.Key
+	return nil
+}
+
+func (h *recordingHandler) Describe(e ResourceChange) string {
+	return e.Key
+}
+
+func TestShardedProcessing(t *testing.T) {
+	client, _ := fakeclient.NewFakeClient("test", nil, nil, "")
+	processor := NewShardedEventProcessor("tester", client, 4)
+	assert.Equal(t, processor.Workers(), 4)
+	// find a namespace handled by a different worker than "slow"
+	other := ""
+	for i := 0; other == ""; i++ {
+		if candidate := fmt.Sprintf("fast-%d", i); !processor.SameWorker("slow", candidate) {
+			other = candidate
+		}
+	}
+	handler := &recordingHandler{
+		handled: map[string][]string{},
+		blocked: "slow",
+		release: make(chan struct{}),
+		done:    make(chan string, 20),
+	}
+	eventsIn := processor.newEventHandler(handler)
+	for _, namespace := range []string{"slow", other} {
+		for i := 0; i < 5; i++ {
+			eventsIn.AddFunc(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: fmt.Sprintf("cm-%d", i)}})
+		}
+	}
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	processor.Start(stopCh)
+	defer processor.Stop()
+
+	// events for other namespaces are not held up by a slow one
+	for i := 0; i < 5; i++ {
+		select {
+		case key := <-handler.done:
+			assert.Assert(t, strings.HasPrefix(key, other+"/"))
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for events")
+		}
+	}
+	close(handler.release)
+	for i := 0; i < 5; i++ {
+		select {
+		case <-handler.done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for events")
+		}
+	}
+	// order within a namespace is retained
+	expected := []string{"cm-0", "cm-1", "cm-2", "cm-3", "cm-4"}
+	handler.lock.Lock()
+	defer handler.lock.Unlock()
+	assert.DeepEqual(t, handler.handled["slow"], expected)
+	assert.DeepEqual(t, handler.handled[other], expected)
+}
@@ -1,8 +1,11 @@
 package network
 
 import (
+	"cmp"
 	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
@@ -214,6 +217,7 @@ func ExtractSiteRecords(status NetworkStatusInfo) []v2alpha1.SiteRecord {
 						RemoteSiteId:   site,
 						RemoteSiteName: siteNames[site],
 						Operational:    strings.EqualFold(link.Status, "up"),
+						Cost:           link.LinkCost,
 					})
 				}
 			}
@@ -261,6 +265,51 @@ func GetLinkRecordsForSite(siteId string, network []v2alpha1.SiteRecord) []v2alp
 	return nil
 }
 
+// ApplicationNetwork is a view of the whole application network,
+// built from the SiteRecords held in the status of any site in it.
+type ApplicationNetwork struct {
+	Sites    []v2alpha1.SiteRecord `json:"sites,omitempty"`
+	Services []ServiceSites        `json:"services,omitempty"`
+}
+
+// ServiceSites identifies the sites with listeners and the sites
+// with connectors for a routing key.
+type ServiceSites struct {
+	RoutingKey     string   `json:"routingKey"`
+	ListenerSites  []string `json:"listenerSites,omitempty"`
+	ConnectorSites []string `json:"connectorSites,omitempty"`
+}
+
+func NewApplicationNetwork(records []v2alpha1.SiteRecord) ApplicationNetwork {
+	sites := slices.Clone(records)
+	slices.SortFunc(sites, func(a, b v2alpha1.SiteRecord) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.Id, b.Id))
+	})
+	services := map[string]*ServiceSites{}
+	for _, site := range sites {
+		for _, service := range site.Services {
+			entry, ok := services[service.RoutingKey]
+			if !ok {
+				entry = &ServiceSites{RoutingKey: service.RoutingKey}
+				services[service.RoutingKey] = entry
+			}
+			if len(service.Listeners) > 0 && !slices.Contains(entry.ListenerSites, site.Name) {
+				entry.ListenerSites = append(entry.ListenerSites, site.Name)
+			}
+			if len(service.Connectors) > 0 && !slices.Contains(entry.ConnectorSites, site.Name) {
+				entry.ConnectorSites = append(entry.ConnectorSites, site.Name)
+			}
+		}
+	}
+	network := ApplicationNetwork{
+		Sites: sites,
+	}
+	for _, key := range slices.Sorted(maps.Keys(services)) {
+		network.Services = append(network.Services, *services[key])
+	}
+	return network
+}
+
 func HasMatchingPair(networkStatus NetworkStatusInfo, address string) bool {
 	for _, addressInfo := range networkStatus.Addresses {
 		if addressInfo.Name == address {
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
 	"gotest.tools/v3/assert"
 )
 
@@ -208,3 +209,35 @@ func TestHasMatchingPair(t *testing.T) {
 		assert.Equal(t, scenario.expectedMatch, HasMatchingPair(networkStatus, scenario.address))
 	}
 }
+
+func TestNewApplicationNetwork(t *testing.T) {
+	records := []v2alpha1.SiteRecord{
+		{
+			Id:   "west-id",
+			Name: "west",
+			Links: []v2alpha1.LinkRecord{
+				{Name: "west-to-east", RemoteSiteId: "east-id", RemoteSiteName: "east", Operational: true, Cost: 1},
+			},
+			Services: []v2alpha1.ServiceRecord{
+				{RoutingKey: "backend", Listeners: []string{"backend"}},
+				{RoutingKey: "frontend", Connectors: []string{"10.0.0.1"}},
+			},
+		},
+		{
+			Id:   "east-id",
+			Name: "east",
+			Services: []v2alpha1.ServiceRecord{
+				{RoutingKey: "backend", Connectors: []string{"10.0.0.2", "10.0.0.3"}, Listeners: []string{"backend"}},
+			},
+		},
+	}
+	network := NewApplicationNetwork(records)
+	assert.Equal(t, len(network.Sites), 2)
+	assert.Equal(t, network.Sites[0].Name, "east")
+	assert.Equal(t, network.Sites[1].Name, "west")
+	assert.Equal(t, records[0].Name, "west", "records must not be reordered")
+	assert.DeepEqual(t, network.Services, []ServiceSites{
+		{RoutingKey: "backend", ListenerSites: []string{"east", "west"}, ConnectorSites: []string{"east"}},
+		{RoutingKey: "frontend", ConnectorSites: []string{"west"}},
+	})
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/skupperproject/skupper/internal/nonkube/common"
 	"github.com/skupperproject/skupper/internal/nonkube/compat"
 	"github.com/skupperproject/skupper/internal/nonkube/linux"
+	"github.com/skupperproject/skupper/internal/nonkube/secrets"
 	"github.com/skupperproject/skupper/internal/utils"
 	internalutils "github.com/skupperproject/skupper/internal/utils"
 	"github.com/skupperproject/skupper/pkg/nonkube/api"
@@ -109,6 +110,23 @@ func Bootstrap(config *Config) (*api.SiteState, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to load site state: %v", err)
 	}
+	// encrypted resources can only be loaded once the secrets
+	// configuration is known from the site settings
+	secretStore, err := newSecretStore(config, siteState)
+	if err != nil {
+		return nil, err
+	}
+	if secretStore.Enabled() {
+		siteStateLoader = &common.FileSystemSiteStateLoader{
+			Path:   config.InputPath,
+			Bundle: config.IsBundle,
+			Store:  secretStore,
+		}
+		siteState, err = siteStateLoader.Load()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load site state: %v", err)
+		}
+	}
 	// if sources are being consume from namespace sources, they must be properly set
 	crNamespace := siteState.GetNamespace()
 	targetNamespace := utils.DefaultStr(config.Namespace, "default")
@@ -128,10 +146,13 @@ func Bootstrap(config *Config) (*api.SiteState, error) {
 			FileName: config.BundleName,
 		}
 	} else if config.Platform == types.PlatformLinux {
-		siteStateRenderer = &linux.SiteStateRenderer{}
+		siteStateRenderer = &linux.SiteStateRenderer{
+			SecretStore: secretStore,
+		}
 	} else {
 		siteStateRenderer = &compat.SiteStateRenderer{
-			Platform: config.Platform,
+			Platform:    config.Platform,
+			SecretStore: secretStore,
 		}
 	}
 	err = siteStateRenderer.Render(siteState, reloadExisting)
@@ -141,6 +162,36 @@ func Bootstrap(config *Config) (*api.SiteState, error) {
 	return siteState, nil
 }
 
+func newSecretStore(config *Config, siteState *api.SiteState) (secrets.Store, error) {
+	secretsConfig := secrets.ConfigFromSettings(siteState.Site.Spec.Settings)
+	if secretsConfig.Enabled() {
+		if config.IsBundle {
+			return nil, fmt.Errorf("%s is not supported by bundles", secrets.EncryptionSetting)
+		}
+		if api.IsRunningInContainer() {
+			return nil, fmt.Errorf("%s is not supported when bootstrapping from a container", secrets.EncryptionSetting)
+		}
+	}
+	store, err := secrets.NewStore(secretsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid site settings: %w", err)
+	}
+	return store, nil
+}
+
+// Unseal decrypts the certificates of the site in the given namespace
+// into the runtime directory, from where they are read by the router
+func Unseal(namespace string) error {
+	store, err := secrets.StoreForNamespace(namespace)
+	if err != nil {
+		return err
+	}
+	if !store.Enabled() {
+		return fmt.Errorf("secrets are not encrypted in namespace %q", namespace)
+	}
+	return common.UnsealCertificates(namespace, store)
+}
+
 func PostBootstrap(config *Config, siteState *api.SiteState) {
 	var bundleSuffix string
 	if config.IsBundle {
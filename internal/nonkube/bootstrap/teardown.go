@@ -46,6 +46,11 @@ func removeDefinition(namespace string) error {
 		return err
 	}
 
+	// decrypted secrets must not outlive the site
+	if err = os.RemoveAll(api.GetUnsealedOutputPath(namespace)); err != nil {
+		return err
+	}
+
 	return os.RemoveAll(api.GetHostNamespaceHome(namespace))
 }
 
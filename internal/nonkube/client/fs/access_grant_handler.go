@@ -0,0 +1,71 @@
+package fs
+
+import (
+	"os"
+
+	"github.com/skupperproject/skupper/internal/cmd/skupper/common"
+	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+)
+
+type AccessGrantHandler struct {
+	BaseCustomResourceHandler
+	pathProvider PathProvider
+}
+
+func NewAccessGrantHandler(namespace string) *AccessGrantHandler {
+	return &AccessGrantHandler{
+		pathProvider: PathProvider{
+			Namespace: namespace,
+		},
+	}
+}
+
+func (a *AccessGrantHandler) Add(resource v2alpha1.AccessGrant) error {
+
+	fileName := resource.Name + ".yaml"
+	content, err := a.EncodeToYaml(resource)
+	if err != nil {
+		return err
+	}
+
+	err = a.WriteSecretFile(a.pathProvider.Namespace, a.pathProvider.GetNamespace(), fileName, content, common.AccessGrants)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (a *AccessGrantHandler) Get(name string, opt GetOptions) (*v2alpha1.AccessGrant, error) {
+	var grant v2alpha1.AccessGrant
+	fileName := name + ".yaml"
+
+	// the status of a grant is found in the runtime directory, once it
+	// has been resolved by the system controller
+	var file []byte
+	err := os.ErrNotExist
+	if opt.RuntimeFirst {
+		err, file = a.ReadSecretFile(a.pathProvider.Namespace, a.pathProvider.GetRuntimeNamespace(), fileName, common.AccessGrants)
+	}
+	if err != nil {
+		err, file = a.ReadSecretFile(a.pathProvider.Namespace, a.pathProvider.GetNamespace(), fileName, common.AccessGrants)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := a.DecodeYaml(file, &grant); err != nil {
+		return nil, err
+	}
+
+	return &grant, nil
+}
+
+func (a *AccessGrantHandler) Delete(name string) error {
+	fileName := name + ".yaml"
+
+	if err := a.DeleteFile(a.pathProvider.GetNamespace(), fileName, common.AccessGrants); err != nil {
+		return err
+	}
+
+	return nil
+}
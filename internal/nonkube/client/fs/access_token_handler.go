@@ -26,7 +26,7 @@ func (a *AccessTokenHandler) Add(resource v2alpha1.AccessToken) error {
 		return err
 	}
 
-	err = a.WriteFile(a.pathProvider.GetNamespace(), fileName, content, common.AccessTokens)
+	err = a.WriteSecretFile(a.pathProvider.Namespace, a.pathProvider.GetNamespace(), fileName, content, common.AccessTokens)
 	if err != nil {
 		return err
 	}
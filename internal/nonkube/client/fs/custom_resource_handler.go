@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/skupperproject/skupper/internal/cmd/skupper/common/utils"
+	"github.com/skupperproject/skupper/internal/nonkube/secrets"
 	"github.com/skupperproject/skupper/pkg/nonkube/api"
 
 	"sigs.k8s.io/yaml"
@@ -81,6 +82,39 @@ func (b *BaseCustomResourceHandler) WriteFile(path string, name string, content
 	return nil
 }
 
+// WriteSecretFile is like WriteFile for resources holding secrets, which
+// are encrypted before being written when the secrets of the namespace
+// are encrypted
+func (b *BaseCustomResourceHandler) WriteSecretFile(namespace string, path string, name string, content string, kind string) error {
+	store, err := secrets.StoreForNamespace(namespace)
+	if err != nil {
+		return err
+	}
+	sealed, err := store.Seal([]byte(content))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt file: %s", err)
+	}
+	return b.WriteFile(path, name, string(sealed), kind)
+}
+
+// ReadSecretFile is like ReadFile for resources holding secrets, which
+// are decrypted once read when the secrets of the namespace are encrypted
+func (b *BaseCustomResourceHandler) ReadSecretFile(namespace string, path string, name string, kind string) (error, []byte) {
+	err, file := b.ReadFile(path, name, kind)
+	if err != nil {
+		return err, nil
+	}
+	store, err := secrets.StoreForNamespace(namespace)
+	if err != nil {
+		return err, nil
+	}
+	opened, err := store.Open(file)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt file: %s", err), nil
+	}
+	return nil, opened
+}
+
 func (b *BaseCustomResourceHandler) ReadFile(path string, name string, kind string) (error, []byte) {
 
 	completeFilePath := filepath.Join(path, fmt.Sprintf("%s-%s", kind, name))
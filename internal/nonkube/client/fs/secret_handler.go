@@ -26,7 +26,7 @@ func (s *SecretHandler) Add(resource v1.Secret) error {
 		return err
 	}
 
-	err = s.WriteFile(s.pathProvider.GetNamespace(), fileName, content, common.Secrets)
+	err = s.WriteSecretFile(s.pathProvider.Namespace, s.pathProvider.GetNamespace(), fileName, content, common.Secrets)
 	if err != nil {
 		return err
 	}
@@ -3,6 +3,7 @@ package fs
 import (
 	"bytes"
 	"fmt"
+	"github.com/skupperproject/skupper/internal/nonkube/secrets"
 	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
 	"os"
 )
@@ -49,9 +50,13 @@ func (s *TokenHandler) List(opts GetOptions) ([]string, error) {
 	if tokens == nil || len(tokens) == 0 {
 		return nil, fmt.Errorf("there are no links created")
 	}
+	store, err := secrets.StoreForNamespace(s.pathProvider.Namespace)
+	if err != nil {
+		return nil, err
+	}
 	for _, token := range tokens {
 		if !token.IsDir() {
-			tokenFile, errFile := os.ReadFile(path + "/" + token.Name())
+			tokenFile, errFile := secrets.ReadFile(store, path+"/"+token.Name())
 			if errFile != nil {
 				return nil, fmt.Errorf("error reading file %s: %s", path+"/"+token.Name(), errFile)
 			}
@@ -51,6 +51,12 @@ func GetRuntimeTlsCert(namespace, certificateName string) *TlsCert {
 		namespacesPath = api.GetDefaultOutputNamespacesPath()
 	}
 	tlsCertPath := path.Join(namespacesPath, namespace, string(api.CertificatesPath), certificateName)
+	// certificates of sites with encrypted secrets are read from the
+	// copy decrypted when the router started
+	unsealedCertPath := path.Join(api.GetUnsealedOutputPath(namespace), string(api.CertificatesPath), certificateName)
+	if _, err := os.Stat(unsealedCertPath); err == nil {
+		tlsCertPath = unsealedCertPath
+	}
 	tlsCert := &TlsCert{
 		CaPath:   path.Join(tlsCertPath, "ca.crt"),
 		CertPath: path.Join(tlsCertPath, "tls.crt"),
@@ -38,7 +38,8 @@ func TestTlsConfigRetriever(t *testing.T) {
 	t.Run("tls-config-retriever-valid-certs", func(t *testing.T) {
 		validity, err := time.ParseDuration("1h")
 		assert.Assert(t, err)
-		ca := certs.GenerateSecret("my-ca", "my-ca", "my-ca.host", validity, nil)
+		ca, err := certs.GenerateSecret("my-ca", "my-ca", "my-ca.host", validity, nil)
+		assert.Assert(t, err)
 
 		certPath := path.Join(baseCertsPath, "valid-certificate")
 		assert.Assert(t, os.MkdirAll(certPath, 0755))
@@ -54,4 +55,24 @@ func TestTlsConfigRetriever(t *testing.T) {
 		assert.Assert(t, err)
 		assert.Assert(t, tlsCfg != nil)
 	})
+
+	for _, algorithm := range []certs.KeyAlgorithm{certs.KeyAlgorithmECDSA, certs.KeyAlgorithmEd25519} {
+		t.Run("tls-config-retriever-"+string(algorithm)+"-certs", func(t *testing.T) {
+			ca, err := certs.GenerateSecretWithKey("my-ca", "my-ca", "", time.Hour, nil, certs.KeyOptions{Algorithm: algorithm})
+			assert.Assert(t, err)
+			client, err := certs.GenerateSecret("my-client", "my-client", "", time.Hour, &ca)
+			assert.Assert(t, err)
+
+			certName := string(algorithm) + "-certificate"
+			certPath := path.Join(baseCertsPath, certName)
+			assert.Assert(t, os.MkdirAll(certPath, 0755))
+			assert.Assert(t, os.WriteFile(path.Join(certPath, "ca.crt"), client.Data["ca.crt"], 0644))
+			assert.Assert(t, os.WriteFile(path.Join(certPath, "tls.crt"), client.Data["tls.crt"], 0644))
+			assert.Assert(t, os.WriteFile(path.Join(certPath, "tls.key"), client.Data["tls.key"], 0644))
+
+			tlsCfg, err := GetRuntimeTlsCert("default", certName).GetTlsConfig()
+			assert.Assert(t, err)
+			assert.Equal(t, len(tlsCfg.Certificates), 1)
+		})
+	}
 }
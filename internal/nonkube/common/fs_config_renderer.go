@@ -11,6 +11,7 @@ import (
 	"syscall"
 
 	"github.com/skupperproject/skupper/internal/certs"
+	"github.com/skupperproject/skupper/internal/nonkube/secrets"
 	"github.com/skupperproject/skupper/internal/qdr"
 	"github.com/skupperproject/skupper/internal/utils"
 	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
@@ -60,7 +61,10 @@ type FileSystemConfigurationRenderer struct {
 	RouterConfig       qdr.RouterConfig
 	Platform           string
 	Bundle             bool
-	customOutputPath   string
+	// SecretStore seals private keys, static links and secret resources
+	// before they are written, when set
+	SecretStore      secrets.Store
+	customOutputPath string
 }
 
 func NewFileSystemConfigurationRenderer(outputPath string) *FileSystemConfigurationRenderer {
@@ -205,19 +209,22 @@ func (c *FileSystemConfigurationRenderer) MarshalSiteStates(loadedSiteState, run
 		}
 		if len(existingInputSources) == 0 {
 			logger.Debug("creating input sources", slog.String("path", inputSourcesPath))
-			if err := api.MarshalSiteState(*loadedSiteState, inputSourcesPath); err != nil {
+			if err := secrets.MarshalSiteState(c.SecretStore, *loadedSiteState, inputSourcesPath); err != nil {
 				return err
 			}
+		} else if err := secrets.SealResources(c.SecretStore, inputSourcesPath); err != nil {
+			// input sources provided before encryption was enabled
+			return fmt.Errorf("unable to encrypt secrets in %s: %w", inputSourcesPath, err)
 		}
 		logger.Debug("saving loaded state", slog.String("path", sourcesPath))
-		if err := api.MarshalSiteState(*loadedSiteState, sourcesPath); err != nil {
+		if err := secrets.MarshalSiteState(c.SecretStore, *loadedSiteState, sourcesPath); err != nil {
 			return err
 		}
 	}
 	outputPath := c.GetOutputPath(runtimeSiteState)
 	runtimeStatePath := path.Join(outputPath, string(api.RuntimeSiteStatePath))
 	logger.Debug("saving runtime state", slog.String("path", runtimeStatePath))
-	if err := api.MarshalSiteState(*runtimeSiteState, runtimeStatePath); err != nil {
+	if err := secrets.MarshalSiteState(c.SecretStore, *runtimeSiteState, runtimeStatePath); err != nil {
 		return err
 	}
 	return nil
@@ -297,7 +304,7 @@ func (c *FileSystemConfigurationRenderer) createTokens(siteState *api.SiteState)
 			return fmt.Errorf("unable to marshal static link: %v", err)
 		}
 		logger.Debug("writing static link", slog.String("path", tokenPath))
-		if err := os.WriteFile(tokenPath, tokenYaml, 0644); err != nil {
+		if err := secrets.WriteFile(c.SecretStore, tokenPath, tokenYaml, 0644); err != nil {
 			return fmt.Errorf("unable to create static link file %s: %v", tokenPath, err)
 		}
 	}
@@ -351,11 +358,22 @@ func (c *FileSystemConfigurationRenderer) createTlsCertificates(siteState *api.S
 				_ = certFile.Close()
 				if ignoreExisting {
 					logger.Warn("certificate will not be overwritten", slog.String("path", certFileName))
+					// keys written before encryption was enabled are
+					// sealed in place
+					if secrets.IsSecretFile(fileName) && c.SecretStore != nil && c.SecretStore.Enabled() {
+						if err = secrets.SealFile(c.SecretStore, certFileName); err != nil {
+							return fmt.Errorf("error encrypting %s: %v", certFileName, err)
+						}
+					}
 					continue
 				}
 			}
 			logger.Debug("writing certificate", slog.String("path", certFileName))
-			err = os.WriteFile(certFileName, data, 0640)
+			if secrets.IsSecretFile(fileName) {
+				err = secrets.WriteFile(c.SecretStore, certFileName, data, 0640)
+			} else {
+				err = os.WriteFile(certFileName, data, 0640)
+			}
 			if err != nil {
 				return fmt.Errorf("error writing %s: %v", certFileName, err)
 			}
@@ -371,7 +389,14 @@ func (c *FileSystemConfigurationRenderer) createTlsCertificates(siteState *api.S
 		if certificate.Spec.Signing == false {
 			continue
 		}
-		secret := certs.GenerateSecret(name, certificate.Spec.Subject, "", 0, nil)
+		key, err := certs.KeyOptionsFromSettings(certificate.Spec.Settings, certs.KeyOptions{})
+		if err != nil {
+			return fmt.Errorf("invalid key options for %s: %v", name, err)
+		}
+		secret, err := certs.GenerateSecretWithKey(name, certificate.Spec.Subject, "", 0, nil, key)
+		if err != nil {
+			return fmt.Errorf("unable to generate CA %s: %v", name, err)
+		}
 
 		ignoreExisting := true
 		userCaSecret, err := c.loadUserCertAsSecret(siteState, "ca", name)
@@ -398,18 +423,25 @@ func (c *FileSystemConfigurationRenderer) createTlsCertificates(siteState *api.S
 				return fmt.Errorf("unable to load CA secret %s: %v", certificate.Spec.Ca, err)
 			}
 		}
+		if !certificate.Spec.Client && !certificate.Spec.Server {
+			continue
+		}
+		key, err := certs.KeyOptionsFromSettings(certificate.Spec.Settings, certs.KeyOptions{})
+		if err != nil {
+			return fmt.Errorf("invalid key options for %s: %v", name, err)
+		}
+		secret, err = certs.GenerateSecretWithKey(name, certificate.Spec.Subject, strings.Join(certificate.Spec.Hosts, ","), 0, caSecret, key)
+		if err != nil {
+			return fmt.Errorf("unable to generate certificate %s: %v", name, err)
+		}
 		if certificate.Spec.Client {
 			purpose = "client"
-			secret = certs.GenerateSecret(name, certificate.Spec.Subject, strings.Join(certificate.Spec.Hosts, ","), 0, caSecret)
 			// TODO Not sure if connect.json is needed (probably need to get rid of it)
 			if connectJson := c.connectJson(siteState); connectJson != nil {
 				secret.Data["connect.json"] = []byte(*connectJson)
 			}
-		} else if certificate.Spec.Server {
-			purpose = "server"
-			secret = certs.GenerateSecret(name, certificate.Spec.Subject, strings.Join(certificate.Spec.Hosts, ","), 0, caSecret)
 		} else {
-			continue
+			purpose = "server"
 		}
 		userSecret, err := c.loadUserCertAsSecret(siteState, purpose, name)
 		if userSecret != nil && err == nil {
@@ -548,7 +580,7 @@ func (c *FileSystemConfigurationRenderer) loadCertAsSecretFrom(basePath string,
 	}
 	for _, file := range files {
 		fileName := path.Join(certPath, file.Name())
-		fileContent, err := os.ReadFile(fileName)
+		fileContent, err := secrets.ReadFile(c.SecretStore, fileName)
 		if err != nil {
 			return nil, fmt.Errorf("error reading %s: %v", fileName, err)
 		}
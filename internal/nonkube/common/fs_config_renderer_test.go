@@ -8,6 +8,7 @@ import (
 
 	"github.com/skupperproject/skupper/api/types"
 	"github.com/skupperproject/skupper/internal/certs"
+	"github.com/skupperproject/skupper/internal/nonkube/secrets"
 	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
 	"github.com/skupperproject/skupper/pkg/nonkube/api"
 	"gotest.tools/v3/assert"
@@ -94,6 +95,96 @@ func testFileSystemConfigurationRendererRender(t *testing.T, addInputCertificate
 	}
 }
 
+func TestFileSystemConfigurationRendererWithSecretStore_Render(t *testing.T) {
+	ss := fakeSiteState()
+	ss.CreateLinkAccessesCertificates()
+	ss.CreateBridgeCertificates()
+	store := newTestSecretStore(t)
+	fsConfigRenderer := &FileSystemConfigurationRenderer{
+		SecretStore:      store,
+		customOutputPath: t.TempDir(),
+	}
+	assert.Assert(t, fsConfigRenderer.Render(ss))
+	assert.Assert(t, fsConfigRenderer.MarshalSiteStates(ss, ss))
+	outputPath := fsConfigRenderer.GetOutputPath(ss)
+	sealedFiles := []string{
+		string(api.IssuersPath) + "/skupper-site-ca/tls.key",
+		string(api.CertificatesPath) + "/link-access-one/tls.key",
+		string(api.CertificatesPath) + "/link-one-profile/tls.key",
+		string(api.RuntimeTokenPath) + "/link-link-access-one-127.0.0.1.yaml",
+		string(api.LoadedSiteStatePath) + "/Secret-link-one.yaml",
+		string(api.InputSiteStatePath) + "/Secret-link-one.yaml",
+		string(api.RuntimeSiteStatePath) + "/Secret-link-one.yaml",
+	}
+	for _, fileName := range sealedFiles {
+		data, err := os.ReadFile(path.Join(outputPath, fileName))
+		assert.Assert(t, err)
+		assert.Assert(t, secrets.IsSealed(data), fileName)
+		_, err = store.Open(data)
+		assert.Assert(t, err)
+	}
+	for _, fileName := range []string{
+		string(api.CertificatesPath) + "/link-access-one/tls.crt",
+		string(api.CertificatesPath) + "/link-access-one/ca.crt",
+		string(api.RuntimeSiteStatePath) + "/Site-site-name.yaml",
+	} {
+		data, err := os.ReadFile(path.Join(outputPath, fileName))
+		assert.Assert(t, err)
+		assert.Assert(t, !secrets.IsSealed(data), fileName)
+	}
+	// sealed certificates are loaded back in plain text
+	secret, err := fsConfigRenderer.loadCertAsSecret(ss, "server", "link-access-one")
+	assert.Assert(t, err)
+	assert.Assert(t, !secrets.IsSealed(secret.Data["tls.key"]))
+}
+
+func TestFileSystemConfigurationRendererWithSecretStore_ExistingSite(t *testing.T) {
+	ss := fakeSiteState()
+	ss.CreateLinkAccessesCertificates()
+	ss.CreateBridgeCertificates()
+	outputDir := t.TempDir()
+	plainRenderer := &FileSystemConfigurationRenderer{
+		customOutputPath: outputDir,
+	}
+	assert.Assert(t, plainRenderer.Render(ss))
+	assert.Assert(t, plainRenderer.MarshalSiteStates(ss, ss))
+	outputPath := plainRenderer.GetOutputPath(ss)
+	caKey := path.Join(outputPath, string(api.IssuersPath), "skupper-site-ca", "tls.key")
+	caKeyData, err := os.ReadFile(caKey)
+	assert.Assert(t, err)
+	assert.Assert(t, !secrets.IsSealed(caKeyData))
+
+	// enabling encryption seals the existing CA, which is not
+	// regenerated, and the existing input resources
+	store := newTestSecretStore(t)
+	sealedRenderer := &FileSystemConfigurationRenderer{
+		SecretStore:      store,
+		customOutputPath: outputDir,
+	}
+	assert.Assert(t, sealedRenderer.Render(ss))
+	assert.Assert(t, sealedRenderer.MarshalSiteStates(ss, ss))
+	for _, fileName := range []string{
+		string(api.IssuersPath) + "/skupper-site-ca/tls.key",
+		string(api.InputSiteStatePath) + "/Secret-link-one.yaml",
+	} {
+		data, err := os.ReadFile(path.Join(outputPath, fileName))
+		assert.Assert(t, err)
+		assert.Assert(t, secrets.IsSealed(data), fileName)
+	}
+	data, err := secrets.ReadFile(store, caKey)
+	assert.Assert(t, err)
+	assert.DeepEqual(t, data, caKeyData)
+}
+
+func newTestSecretStore(t *testing.T) secrets.Store {
+	t.Helper()
+	keyFile := path.Join(t.TempDir(), "site.key")
+	assert.Assert(t, os.WriteFile(keyFile, []byte("0123456789abcdef0123456789abcdef"), 0600))
+	store, err := secrets.NewStore(secrets.Config{Provider: secrets.ProviderKeyFile, KeyFile: keyFile})
+	assert.Assert(t, err)
+	return store
+}
+
 func compareCertificates(t *testing.T, customOutputPath string) {
 	caPath := path.Join(customOutputPath, string(api.IssuersPath), "skupper-site-ca")
 	serverPath := path.Join(customOutputPath, string(api.CertificatesPath), "link-access-one")
@@ -123,9 +214,12 @@ func compareCertificates(t *testing.T, customOutputPath string) {
 func createInputCertificates(t *testing.T, customOutputPath string) {
 	// preparing certificates
 	fakeHosts := "10.0.0.1,10.0.0.2,fake.domain"
-	ca := certs.GenerateSecret("fake-ca", "fake-ca", "", 0, nil)
-	server := certs.GenerateSecret("fake-server-cert", "fake-server-cert", fakeHosts, 0, &ca)
-	client := certs.GenerateSecret("fake-client-cert", "fake-client-cert", "", 0, &ca)
+	ca, err := certs.GenerateSecret("fake-ca", "fake-ca", "", 0, nil)
+	assert.Assert(t, err)
+	server, err := certs.GenerateSecret("fake-server-cert", "fake-server-cert", fakeHosts, 0, &ca)
+	assert.Assert(t, err)
+	client, err := certs.GenerateSecret("fake-client-cert", "fake-client-cert", "", 0, &ca)
+	assert.Assert(t, err)
 
 	// paths for each provided certificate
 	caPath := path.Join(customOutputPath, "namespaces/default", string(api.InputIssuersPath), "skupper-site-ca")
@@ -0,0 +1,50 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/skupperproject/skupper/pkg/nonkube/api"
+)
+
+// RouterHeartbeat is the availability of the router as last seen by the
+// system controller through the router heartbeats. It is persisted so
+// that the health of a site can be reported from outside the controller.
+type RouterHeartbeat struct {
+	RouterUp      bool      `json:"routerUp"`
+	Reason        string    `json:"reason,omitempty"`
+	LastHeartbeat time.Time `json:"lastHeartbeat,omitempty"`
+	Updated       time.Time `json:"updated"`
+}
+
+func WriteRouterHeartbeat(namespace string, heartbeat RouterHeartbeat) error {
+	data, err := json.Marshal(heartbeat)
+	if err != nil {
+		return err
+	}
+	fileName := api.GetInternalOutputPath(namespace, api.RouterHeartbeatPath)
+	if err = os.MkdirAll(path.Dir(fileName), 0755); err != nil {
+		return err
+	}
+	// written through a temporary file so readers never see partial content
+	tmpFileName := fileName + ".tmp"
+	if err = os.WriteFile(tmpFileName, data, 0644); err != nil {
+		return fmt.Errorf("unable to write router heartbeat: %w", err)
+	}
+	return os.Rename(tmpFileName, fileName)
+}
+
+func ReadRouterHeartbeat(namespace string) (*RouterHeartbeat, error) {
+	data, err := os.ReadFile(api.GetInternalOutputPath(namespace, api.RouterHeartbeatPath))
+	if err != nil {
+		return nil, err
+	}
+	heartbeat := &RouterHeartbeat{}
+	if err = json.Unmarshal(data, heartbeat); err != nil {
+		return nil, fmt.Errorf("invalid router heartbeat: %w", err)
+	}
+	return heartbeat, nil
+}
@@ -2,6 +2,7 @@ package common
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"io"
 	"log/slog"
@@ -9,6 +10,7 @@ import (
 	"path"
 	"strings"
 
+	"github.com/skupperproject/skupper/internal/nonkube/secrets"
 	"github.com/skupperproject/skupper/internal/qdr"
 	"github.com/skupperproject/skupper/internal/utils"
 	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
@@ -25,6 +27,8 @@ import (
 type FileSystemSiteStateLoader struct {
 	Path   string
 	Bundle bool
+	// Store opens encrypted resources, which are skipped when not set
+	Store secrets.Store
 }
 
 func (f *FileSystemSiteStateLoader) Load() (*api.SiteState, error) {
@@ -39,11 +43,20 @@ func (f *FileSystemSiteStateLoader) Load() (*api.SiteState, error) {
 	}
 	// Reading all yaml files found
 	for _, yamlFileName := range yamlFileNames {
-		yamlFile, err := os.Open(yamlFileName)
+		data, err := os.ReadFile(yamlFileName)
 		if err != nil {
 			return nil, err
 		}
-		reader := bufio.NewReader(yamlFile)
+		if secrets.IsSealed(data) {
+			if f.Store == nil || !f.Store.Enabled() {
+				slog.Debug("skipping encrypted resource", slog.String("path", yamlFileName))
+				continue
+			}
+			if data, err = f.Store.Open(data); err != nil {
+				return siteState, fmt.Errorf("error decrypting %q: %v", yamlFileName, err)
+			}
+		}
+		reader := bufio.NewReader(bytes.NewReader(data))
 		err = LoadIntoSiteState(reader, siteState)
 		if err != nil {
 			return siteState, fmt.Errorf("error loading %q: %v", yamlFileName, err)
@@ -5,6 +5,7 @@ import (
 	"path"
 	"testing"
 
+	"github.com/skupperproject/skupper/internal/nonkube/secrets"
 	"github.com/skupperproject/skupper/pkg/nonkube/api"
 	"gotest.tools/v3/assert"
 )
@@ -29,3 +30,28 @@ func TestFileSystemSiteStateLoder(t *testing.T) {
 	assert.Assert(t, loadedSiteState != nil)
 	assert.Equal(t, string(loadedSiteState.Site.ObjectMeta.UID), loadedSiteState.SiteId)
 }
+
+func TestFileSystemSiteStateLoaderWithSecretStore(t *testing.T) {
+	outputPath := t.TempDir()
+	ss := fakeSiteState()
+	assert.Assert(t, api.MarshalSiteState(*ss, outputPath))
+	store := newTestSecretStore(t)
+	assert.Assert(t, secrets.SealResources(store, outputPath))
+
+	// encrypted resources are skipped without a store
+	fsStateLoader := &FileSystemSiteStateLoader{
+		Path: outputPath,
+	}
+	loadedSiteState, err := fsStateLoader.Load()
+	assert.Assert(t, err)
+	assert.Equal(t, len(loadedSiteState.Secrets), 0)
+	assert.Equal(t, len(loadedSiteState.Links), len(ss.Links))
+
+	fsStateLoader.Store = store
+	loadedSiteState, err = fsStateLoader.Load()
+	assert.Assert(t, err)
+	assert.Equal(t, len(loadedSiteState.Secrets), len(ss.Secrets))
+	for name, secret := range ss.Secrets {
+		assert.DeepEqual(t, loadedSiteState.Secrets[name].Data, secret.Data)
+	}
+}
@@ -2,7 +2,9 @@ package common
 
 import (
 	"fmt"
+	"path"
 
+	"github.com/skupperproject/skupper/internal/nonkube/secrets"
 	"github.com/skupperproject/skupper/internal/utils"
 	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
 	"github.com/skupperproject/skupper/pkg/nonkube/api"
@@ -81,3 +83,14 @@ func CreateRouterAccess(siteState *api.SiteState) error {
 	}
 	return nil
 }
+
+// UnsealCertificates decrypts the certificates of a namespace into the
+// runtime directory, from where they are read by the router
+func UnsealCertificates(namespace string, store secrets.Store) error {
+	certsPath := api.GetInternalOutputPath(namespace, api.CertificatesPath)
+	unsealedCertsPath := path.Join(api.GetUnsealedOutputPath(namespace), string(api.CertificatesPath))
+	if err := secrets.Unseal(store, certsPath, unsealedCertsPath); err != nil {
+		return fmt.Errorf("unable to decrypt certificates: %w", err)
+	}
+	return nil
+}
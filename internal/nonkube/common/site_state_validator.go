@@ -5,6 +5,8 @@ import (
 	"net"
 	"regexp"
 
+	"github.com/skupperproject/skupper/internal/nonkube/grants"
+	"github.com/skupperproject/skupper/internal/nonkube/secrets"
 	"github.com/skupperproject/skupper/internal/utils"
 	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
 	"github.com/skupperproject/skupper/pkg/nonkube/api"
@@ -65,6 +67,12 @@ func (s *SiteStateValidator) validateSite(site *v2alpha1.Site) error {
 	if err := ValidateName(site.Name); err != nil {
 		return fmt.Errorf("invalid site name: %w", err)
 	}
+	if err := secrets.ConfigFromSettings(site.Spec.Settings).Validate(); err != nil {
+		return fmt.Errorf("invalid site settings: %w", err)
+	}
+	if _, err := grants.ServerPort(site.Spec.Settings); err != nil {
+		return fmt.Errorf("invalid site settings: %w", err)
+	}
 	return nil
 }
 
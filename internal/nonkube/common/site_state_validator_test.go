@@ -25,6 +25,26 @@ func TestSiteStateValidator_Validate(t *testing.T) {
 			valid:         false,
 			errorContains: "invalid site name:",
 		},
+		{
+			info: "invalid-site-secrets-encryption",
+			siteState: customize(func(siteState *api.SiteState) {
+				siteState.Site.Spec.Settings = map[string]string{
+					"secrets-encryption": "keyfile",
+				}
+			}),
+			valid:         false,
+			errorContains: "invalid site settings: secrets-key-file is required",
+		},
+		{
+			info: "invalid-site-grant-server-port",
+			siteState: customize(func(siteState *api.SiteState) {
+				siteState.Site.Spec.Settings = map[string]string{
+					"grant-server-port": "99999",
+				}
+			}),
+			valid:         false,
+			errorContains: "invalid site settings: invalid grant-server-port",
+		},
 		{
 			info: "invalid-link-access-name",
 			siteState: customize(func(siteState *api.SiteState) {
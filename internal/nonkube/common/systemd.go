@@ -9,9 +9,11 @@ import (
 	"os/exec"
 	"path"
 	"path/filepath"
+	"strings"
 	"text/template"
 
 	"github.com/skupperproject/skupper/api/types"
+	"github.com/skupperproject/skupper/internal/nonkube/secrets"
 	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
 	"github.com/skupperproject/skupper/pkg/nonkube/api"
 )
@@ -27,11 +29,18 @@ const (
 	rootSystemdBasePath = "/etc/systemd/system"
 )
 
+var (
+	// executable returns the path of the skupper binary used to decrypt
+	// the secrets of a site before its router starts
+	executable = os.Executable
+)
+
 type SystemdService interface {
 	GetServiceName() string
 	Create() error
 	Remove() error
 	GetServiceFile() string
+	State() (string, error)
 }
 
 type SystemdGlobal interface {
@@ -49,6 +58,9 @@ type systemdServiceInfo struct {
 	SiteConfigPath      string
 	SiteHomePath        string
 	RuntimeDir          string
+	UnsealCommand       string
+	Credential          string
+	CredentialName      string
 	getUid              api.IdGetter
 	command             CommandExecutor
 	rootSystemdBasePath string
@@ -71,7 +83,7 @@ func NewSystemdServiceInfo(siteState *api.SiteState, platform string) (SystemdSe
 	if namespace == "" {
 		namespace = "default"
 	}
-	return &systemdServiceInfo{
+	service := &systemdServiceInfo{
 		Site:                site,
 		SiteId:              siteState.SiteId,
 		Namespace:           namespace,
@@ -82,7 +94,21 @@ func NewSystemdServiceInfo(siteState *api.SiteState, platform string) (SystemdSe
 		command:             exec.Command,
 		rootSystemdBasePath: rootSystemdBasePath,
 		platform:            platform,
-	}, nil
+	}
+	// secrets are decrypted before the router starts
+	secretsConfig := secrets.ConfigFromSettings(site.Spec.Settings)
+	if secretsConfig.Enabled() {
+		skupperBinary, err := executable()
+		if err != nil {
+			return nil, fmt.Errorf("unable to determine the skupper binary used to decrypt secrets: %w", err)
+		}
+		service.UnsealCommand = fmt.Sprintf("%s system unseal --namespace %s --platform %s", skupperBinary, namespace, platform)
+		if secretsConfig.Provider == secrets.ProviderSystemdCreds {
+			service.Credential = secretsConfig.Credential
+			service.CredentialName = secretsConfig.CredentialName()
+		}
+	}
+	return service, nil
 }
 
 func (s *systemdServiceInfo) GetServiceName() string {
@@ -185,6 +211,23 @@ func (s *systemdServiceInfo) Remove() error {
 	return nil
 }
 
+// State returns the active state of the systemd service, as reported
+// by systemctl is-active (i.e. active, inactive, failed)
+func (s *systemdServiceInfo) State() (string, error) {
+	cmd := s.getCmdIsActiveSystemdService(s.GetServiceName())
+	out, err := cmd.Output()
+	// is-active exits with a non-zero code when the service is not
+	// active, but still prints its state
+	state := strings.TrimSpace(string(out))
+	if state == "" {
+		if err == nil {
+			err = fmt.Errorf("empty state returned")
+		}
+		return "", fmt.Errorf("unable to retrieve state of service %s: %w", s.GetServiceName(), err)
+	}
+	return state, nil
+}
+
 func (s *systemdServiceInfo) enableService(serviceName string) error {
 	// Enabling systemd user service
 	cmd := s.getCmdEnableSystemdService(serviceName)
@@ -252,6 +295,13 @@ func (s *systemdServiceInfo) getCmdResetFailedSystemService(serviceName string)
 	return s.command("systemctl", "--user", "reset-failed", serviceName)
 }
 
+func (s *systemdServiceInfo) getCmdIsActiveSystemdService(serviceName string) *exec.Cmd {
+	if s.getUid() == 0 {
+		return s.command("systemctl", "is-active", serviceName)
+	}
+	return s.command("systemctl", "--user", "is-active", serviceName)
+}
+
 func (s *systemdServiceInfo) getCmdIsSystemdEnabled() *exec.Cmd {
 	if s.getUid() == 0 {
 		return s.command("systemctl", []string{"list-units", "--no-pager"}...)
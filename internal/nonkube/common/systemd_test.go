@@ -8,6 +8,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/skupperproject/skupper/internal/nonkube/secrets"
 	"github.com/skupperproject/skupper/internal/utils"
 	"github.com/skupperproject/skupper/pkg/nonkube/api"
 	"gotest.tools/v3/assert"
@@ -65,9 +66,55 @@ func TestSystemdService(t *testing.T) {
 				assert.Assert(t, strings.Contains(string(serviceFile), startCmd))
 				assert.Assert(t, strings.Contains(string(serviceFile), stopCmd))
 			})
+			t.Run(fmt.Sprintf("state-systemd-%s-as-uid-%d", platform, uid), func(t *testing.T) {
+				state, err := systemdService.State()
+				assert.Assert(t, err)
+				assert.Equal(t, state, "mock")
+			})
 			assert.Assert(t, systemdService.Remove())
 			_, err = os.ReadFile(systemdServiceImpl.GetServiceFile())
 			assert.Assert(t, err != nil)
 		}
 	}
 }
+
+func TestSystemdServiceWithEncryptedSecrets(t *testing.T) {
+	outputPath := t.TempDir()
+	t.Setenv("SKUPPER_OUTPUT_PATH", outputPath)
+	t.Setenv("XDG_CONFIG_HOME", outputPath)
+	executable = func() (string, error) {
+		return "/usr/bin/skupper", nil
+	}
+	defer func() {
+		executable = os.Executable
+	}()
+	siteState := fakeSiteState()
+	siteState.Site.Spec.Settings = map[string]string{
+		secrets.EncryptionSetting: secrets.ProviderSystemdCreds,
+		secrets.CredentialSetting: "/etc/credstore.encrypted/skupper-key.cred",
+	}
+	for _, platform := range []string{"linux", "podman"} {
+		t.Run(platform, func(t *testing.T) {
+			systemdService, err := NewSystemdServiceInfo(siteState, platform)
+			assert.Assert(t, err)
+			systemdServiceImpl := systemdService.(*systemdServiceInfo)
+			systemdServiceImpl.command = func(name string, arg ...string) *exec.Cmd {
+				return exec.Command("echo", "mock")
+			}
+			systemdServiceImpl.getUid = func() int {
+				return 1000
+			}
+			assert.Assert(t, systemdService.Create())
+			serviceFile, err := os.ReadFile(systemdServiceImpl.GetServiceFile())
+			assert.Assert(t, err)
+			expected := []string{
+				"LoadCredentialEncrypted=skupper-key:/etc/credstore.encrypted/skupper-key.cred",
+				"ExecStartPre=/usr/bin/skupper system unseal --namespace default --platform " + platform,
+			}
+			for _, line := range expected {
+				assert.Assert(t, strings.Contains(string(serviceFile), line), string(serviceFile))
+			}
+			assert.Assert(t, systemdService.Remove())
+		})
+	}
+}
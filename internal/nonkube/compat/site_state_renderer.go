@@ -13,6 +13,7 @@ import (
 	"github.com/skupperproject/skupper/internal/images"
 	internalclient "github.com/skupperproject/skupper/internal/nonkube/client/compat"
 	"github.com/skupperproject/skupper/internal/nonkube/common"
+	"github.com/skupperproject/skupper/internal/nonkube/secrets"
 	"github.com/skupperproject/skupper/internal/utils"
 	"github.com/skupperproject/skupper/pkg/container"
 	"github.com/skupperproject/skupper/pkg/nonkube/api"
@@ -25,7 +26,9 @@ type SiteStateRenderer struct {
 	containers        map[string]container.Container
 	stoppedContainers map[string]string
 	Platform          types.Platform
-	cli               *internalclient.CompatClient
+	// SecretStore encrypts the secrets of the site, when enabled
+	SecretStore secrets.Store
+	cli         *internalclient.CompatClient
 }
 
 func (s *SiteStateRenderer) Render(loadedSiteState *api.SiteState, reload bool) error {
@@ -132,7 +135,8 @@ func (s *SiteStateRenderer) Render(loadedSiteState *api.SiteState, reload bool)
 		platform = types.PlatformDocker
 	}
 	s.configRenderer = &common.FileSystemConfigurationRenderer{
-		Platform: string(platform),
+		Platform:    string(platform),
+		SecretStore: s.SecretStore,
 	}
 	err = s.configRenderer.Render(s.siteState)
 	if err != nil {
@@ -143,6 +147,9 @@ func (s *SiteStateRenderer) Render(loadedSiteState *api.SiteState, reload bool)
 	if err = s.configRenderer.MarshalSiteStates(loadedSiteState, s.siteState); err != nil {
 		return err
 	}
+	if err = secrets.SaveConfig(s.siteState.GetNamespace(), secrets.ConfigFromSettings(s.siteState.Site.Spec.Settings)); err != nil {
+		return fmt.Errorf("failed to save secrets configuration: %w", err)
+	}
 
 	ctx, cn := context.WithTimeout(context.Background(), time.Minute*10)
 	defer cn()
@@ -152,6 +159,11 @@ func (s *SiteStateRenderer) Render(loadedSiteState *api.SiteState, reload bool)
 	if err = s.pullImages(ctx); err != nil {
 		return err
 	}
+	if s.secretsEncrypted() {
+		if err = common.UnsealCertificates(s.siteState.GetNamespace(), s.SecretStore); err != nil {
+			return err
+		}
+	}
 	if err = s.createContainers(); err != nil {
 		return err
 	}
@@ -207,8 +219,18 @@ func (s *SiteStateRenderer) routerContainerName() string {
 	return fmt.Sprintf("%s-skupper-router", s.siteState.GetNamespace())
 }
 
+func (s *SiteStateRenderer) secretsEncrypted() bool {
+	return s.SecretStore != nil && s.SecretStore.Enabled()
+}
+
 func (s *SiteStateRenderer) prepareContainers() error {
 	siteConfigPath := api.GetHostSiteHome(s.siteState.Site)
+	// certificates are decrypted outside of the site home before the
+	// router starts
+	certsPath := path.Join(siteConfigPath, string(api.CertificatesPath))
+	if s.secretsEncrypted() {
+		certsPath = path.Join(api.GetUnsealedOutputPath(s.siteState.GetNamespace()), string(api.CertificatesPath))
+	}
 	s.containers = make(map[string]container.Container)
 	s.containers[types.RouterComponent] = container.Container{
 		Name:  s.routerContainerName(),
@@ -231,7 +253,7 @@ func (s *SiteStateRenderer) prepareContainers() error {
 				Options:     []string{"z"},
 			},
 			{
-				Source:      path.Join(siteConfigPath, string(api.CertificatesPath)),
+				Source:      certsPath,
 				Destination: "/etc/skupper-router/runtime/certs",
 				Options:     []string{"z"},
 			},
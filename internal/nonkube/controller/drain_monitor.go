@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/skupperproject/skupper/internal/nonkube/client/runtime"
+	"github.com/skupperproject/skupper/internal/nonkube/common"
+	"github.com/skupperproject/skupper/internal/qdr"
+	"github.com/skupperproject/skupper/internal/site"
+	"github.com/skupperproject/skupper/pkg/nonkube/api"
+)
+
+const (
+	drainMonitorInterval = 10 * time.Second
+)
+
+// DrainMonitor reports the progress of a drain in the status of the site
+// in the runtime site state, counting the flows still active on the
+// connectors of the router.
+type DrainMonitor struct {
+	namespace string
+	logger    *slog.Logger
+	interval  time.Duration
+	drain     *site.Drain
+	flows     func(namespace string) (int, error)
+}
+
+func NewDrainMonitor(namespace string) *DrainMonitor {
+	return &DrainMonitor{
+		namespace: namespace,
+		logger: slog.Default().
+			With("component", "drain.monitor").
+			With("namespace", namespace),
+		interval: drainMonitorInterval,
+		drain:    site.NewDrain(),
+		flows:    countConnectorFlows,
+	}
+}
+
+func (m *DrainMonitor) Start(stopCh <-chan struct{}) {
+	go m.run(stopCh)
+}
+
+func (m *DrainMonitor) run(stopCh <-chan struct{}) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		if err := m.check(); err != nil {
+			m.logger.Warn("Unable to update drain status", slog.Any("error", err))
+		}
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *DrainMonitor) check() error {
+	lock := runtimeSiteStateLock(m.namespace)
+	lock.Lock()
+	defer lock.Unlock()
+
+	runtimeSiteStatePath := api.GetInternalOutputPath(m.namespace, api.RuntimeSiteStatePath)
+	loader := &common.FileSystemSiteStateLoader{
+		Path: runtimeSiteStatePath,
+	}
+	siteState, err := loader.Load()
+	if err != nil {
+		return fmt.Errorf("unable to load runtime site state: %w", err)
+	}
+	if siteState.Site == nil {
+		return nil
+	}
+	if m.drain.Update(siteState.Site) {
+		m.logger.Info("Drain updated", slog.Bool("draining", m.drain.Active()))
+	}
+	if !m.drain.Active() && siteState.Site.Status.Drain == nil {
+		return nil
+	}
+	flows := 0
+	if m.drain.Active() {
+		flows, err = m.flows(m.namespace)
+		if err != nil {
+			return err
+		}
+	}
+	if !siteState.Site.SetDrainStatus(m.drain.Status(flows, time.Now())) {
+		return nil
+	}
+	return api.MarshalSiteState(*siteState, runtimeSiteStatePath)
+}
+
+func countConnectorFlows(namespace string) (int, error) {
+	url, err := runtime.GetLocalRouterAddress(namespace)
+	if err != nil {
+		return 0, err
+	}
+	agent, err := qdr.Connect(url, runtime.GetRuntimeTlsCert(namespace, "skupper-local-client"))
+	if err != nil {
+		return 0, fmt.Errorf("unable to connect to router: %w", err)
+	}
+	defer agent.Close()
+	return agent.CountConnectorFlows()
+}
@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"os"
+	"testing"
+
+	"github.com/skupperproject/skupper/internal/nonkube/common"
+	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+	"github.com/skupperproject/skupper/pkg/nonkube/api"
+	"gotest.tools/v3/assert"
+)
+
+func TestDrainMonitor(t *testing.T) {
+	api.DefaultRootDataHome = t.TempDir()
+	if os.Getuid() != 0 {
+		t.Setenv("XDG_DATA_HOME", api.DefaultRootDataHome)
+	}
+	namespace := "test-drain-monitor"
+	runtimePath := api.GetInternalOutputPath(namespace, api.RuntimeSiteStatePath)
+	siteState := fakeInputSiteState(namespace)
+	assert.Assert(t, api.MarshalSiteState(*siteState, runtimePath))
+
+	flows := 0
+	queried := false
+	monitor := NewDrainMonitor(namespace)
+	monitor.flows = func(string) (int, error) {
+		queried = true
+		return flows, nil
+	}
+	status := func() *v2alpha1.DrainStatus {
+		t.Helper()
+		runtimeState, err := (&common.FileSystemSiteStateLoader{Path: runtimePath}).Load()
+		assert.Assert(t, err)
+		return runtimeState.Site.Status.Drain
+	}
+
+	// not draining, the router is not queried
+	assert.Assert(t, monitor.check())
+	assert.Assert(t, !queried)
+	assert.Assert(t, status() == nil)
+
+	siteState.Site.Spec.Settings = map[string]string{"drain": "true"}
+	assert.Assert(t, api.MarshalSiteState(*siteState, runtimePath))
+	flows = 2
+	assert.Assert(t, monitor.check())
+	assert.Assert(t, queried)
+	draining := status()
+	assert.Assert(t, draining != nil)
+	assert.Equal(t, draining.Phase, v2alpha1.DrainPhaseDraining)
+	assert.Equal(t, draining.ActiveFlows, 2)
+	assert.Assert(t, draining.StartTime != "")
+
+	flows = 0
+	assert.Assert(t, monitor.check())
+	drained := status()
+	assert.Equal(t, drained.Phase, v2alpha1.DrainPhaseDrained)
+	assert.Equal(t, drained.ActiveFlows, 0)
+	assert.Equal(t, drained.StartTime, draining.StartTime)
+	assert.Assert(t, !drained.TimedOut)
+
+	runtimeState, err := (&common.FileSystemSiteStateLoader{Path: runtimePath}).Load()
+	assert.Assert(t, err)
+	runtimeState.Site.Spec.Settings = nil
+	assert.Assert(t, api.MarshalSiteState(*runtimeState, runtimePath))
+	assert.Assert(t, monitor.check())
+	assert.Assert(t, status() == nil)
+}
@@ -0,0 +1,186 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/skupperproject/skupper/internal/nonkube/common"
+	"github.com/skupperproject/skupper/internal/nonkube/grants"
+	"github.com/skupperproject/skupper/internal/nonkube/secrets"
+	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+	"github.com/skupperproject/skupper/pkg/nonkube/api"
+)
+
+// GrantsHandler runs the grant server of a site while its router is
+// configured, serving the AccessGrants from the runtime site state.
+// The status of the AccessGrants is written to the runtime site state,
+// and to the input resources, so that it is kept when the site is
+// reloaded.
+type GrantsHandler struct {
+	namespace string
+	logger    *slog.Logger
+	mutex     sync.Mutex
+	grants    *grants.Grants
+	server    *grants.Server
+}
+
+func NewGrantsHandler(namespace string) *GrantsHandler {
+	return &GrantsHandler{
+		namespace: namespace,
+		logger: slog.Default().
+			With("component", "grants.handler").
+			With("namespace", namespace),
+	}
+}
+
+func (h *GrantsHandler) Start(stopCh <-chan struct{}) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if h.server != nil {
+		return
+	}
+	if err := h.startServer(); err != nil {
+		if errors.Is(err, grants.ErrNoLinkAccess) {
+			h.logger.Info("Grant server not started, site has no link access")
+		} else {
+			h.logger.Error("Unable to start grant server", slog.Any("error", err))
+		}
+		return
+	}
+	h.update()
+}
+
+func (h *GrantsHandler) startServer() error {
+	siteState, err := h.loadRuntimeSiteState()
+	if err != nil {
+		return err
+	}
+	config, err := grants.ConfigFromSiteState(siteState)
+	if err != nil {
+		return err
+	}
+	store, err := secrets.StoreForNamespace(h.namespace)
+	if err != nil {
+		return err
+	}
+	ca, err := config.LoadIssuer(h.namespace, store)
+	if err != nil {
+		return err
+	}
+	generator := grants.NewTokenGenerator(config, ca)
+	cert, err := generator.ServerCertificate()
+	if err != nil {
+		return fmt.Errorf("unable to issue grant server certificate: %w", err)
+	}
+	h.grants = grants.NewGrants(config.Url(), generator.CA(), generator.Generate, h.updateStatus)
+	h.server = grants.NewServer(config.Address(), cert, h.grants)
+	if err = h.server.Start(); err != nil {
+		h.grants = nil
+		h.server = nil
+		return err
+	}
+	return nil
+}
+
+func (h *GrantsHandler) Stop() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if h.server == nil {
+		return
+	}
+	h.logger.Info("Stopping grant server")
+	if err := h.server.Stop(); err != nil {
+		h.logger.Warn("Error stopping grant server", slog.Any("error", err))
+	}
+	h.server = nil
+	h.grants = nil
+}
+
+func (h *GrantsHandler) Id() string {
+	return "grants.handler"
+}
+
+func (h *GrantsHandler) OnBasePathAdded(basePath string) {
+}
+
+func (h *GrantsHandler) OnCreate(name string) {
+	h.sync()
+}
+
+func (h *GrantsHandler) OnUpdate(name string) {
+	h.sync()
+}
+
+func (h *GrantsHandler) OnRemove(name string) {
+	h.sync()
+}
+
+func (h *GrantsHandler) Filter(name string) bool {
+	return strings.HasPrefix(filepath.Base(name), "AccessGrant-")
+}
+
+func (h *GrantsHandler) sync() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.update()
+}
+
+// update must be called with the mutex held
+func (h *GrantsHandler) update() {
+	if h.grants == nil {
+		return
+	}
+	siteState, err := h.loadRuntimeSiteState()
+	if err != nil {
+		h.logger.Warn("Unable to load AccessGrants", slog.Any("error", err))
+		return
+	}
+	if err = h.grants.Update(siteState.Grants); err != nil {
+		h.logger.Warn("Unable to update AccessGrants", slog.Any("error", err))
+	}
+}
+
+func (h *GrantsHandler) loadRuntimeSiteState() (*api.SiteState, error) {
+	store, err := secrets.StoreForNamespace(h.namespace)
+	if err != nil {
+		return nil, err
+	}
+	lock := runtimeSiteStateLock(h.namespace)
+	lock.Lock()
+	defer lock.Unlock()
+	loader := &common.FileSystemSiteStateLoader{
+		Path:  api.GetInternalOutputPath(h.namespace, api.RuntimeSiteStatePath),
+		Store: store,
+	}
+	siteState, err := loader.Load()
+	if err != nil {
+		return nil, fmt.Errorf("unable to load runtime site state: %w", err)
+	}
+	return siteState, nil
+}
+
+func (h *GrantsHandler) updateStatus(grant *v2alpha1.AccessGrant) error {
+	store, err := secrets.StoreForNamespace(h.namespace)
+	if err != nil {
+		return err
+	}
+	lock := runtimeSiteStateLock(h.namespace)
+	lock.Lock()
+	defer lock.Unlock()
+	runtimeSiteStatePath := api.GetInternalOutputPath(h.namespace, api.RuntimeSiteStatePath)
+	if err := secrets.MarshalResource(store, runtimeSiteStatePath, "AccessGrant", grant.Name, grant); err != nil {
+		return err
+	}
+	// grants removed from the input resources are not written back
+	inputSiteStatePath := api.GetInternalOutputPath(h.namespace, api.InputSiteStatePath)
+	if _, err := os.Stat(path.Join(inputSiteStatePath, fmt.Sprintf("AccessGrant-%s.yaml", grant.Name))); err != nil {
+		return nil
+	}
+	return secrets.MarshalResource(store, inputSiteStatePath, "AccessGrant", grant.Name, grant)
+}
@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"strconv"
+	"testing"
+
+	"github.com/skupperproject/skupper/internal/certs"
+	"github.com/skupperproject/skupper/internal/nonkube/common"
+	"github.com/skupperproject/skupper/internal/nonkube/grants"
+	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+	"github.com/skupperproject/skupper/pkg/nonkube/api"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGrantsHandler(t *testing.T) {
+	api.DefaultRootDataHome = t.TempDir()
+	if os.Getuid() != 0 {
+		t.Setenv("XDG_DATA_HOME", api.DefaultRootDataHome)
+	}
+	namespace := "test-grants-handler"
+	inputPath := api.GetInternalOutputPath(namespace, api.InputSiteStatePath)
+	runtimePath := api.GetInternalOutputPath(namespace, api.RuntimeSiteStatePath)
+
+	port := freePort(t)
+	siteState := fakeSiteState()
+	siteState.SetNamespace(namespace)
+	siteState.Site.Spec.Settings = map[string]string{grants.ServerPortSetting: strconv.Itoa(port)}
+	siteState.Grants["grant-one"] = &v2alpha1.AccessGrant{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "AccessGrant",
+			APIVersion: "skupper.io/v2alpha1",
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: "grant-one", Namespace: namespace},
+		Spec: v2alpha1.AccessGrantSpec{
+			RedemptionsAllowed: 1,
+			ExpirationWindow:   "15m",
+		},
+	}
+	assert.Assert(t, api.MarshalSiteState(*siteState, inputPath))
+	assert.Assert(t, api.MarshalSiteState(*siteState, runtimePath))
+
+	ca, err := certs.GenerateSecret("skupper-site-ca", "skupper-site-ca", "", 0, nil)
+	assert.Assert(t, err)
+	issuerPath := path.Join(api.GetInternalOutputPath(namespace, api.IssuersPath), "skupper-site-ca")
+	assert.Assert(t, os.MkdirAll(issuerPath, 0755))
+	for _, key := range []string{"tls.crt", "tls.key"} {
+		assert.Assert(t, os.WriteFile(path.Join(issuerPath, key), ca.Data[key], 0644))
+	}
+
+	handler := NewGrantsHandler(namespace)
+	assert.Assert(t, handler.Filter(path.Join(runtimePath, "AccessGrant-grant-one.yaml")))
+	assert.Assert(t, !handler.Filter(path.Join(runtimePath, "Listener-listener-one.yaml")))
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	handler.Start(stopCh)
+	defer handler.Stop()
+	assert.Assert(t, handler.server != nil)
+	assert.Equal(t, handler.server.Port(), port)
+
+	// the status is written to the runtime and input resources
+	for _, statePath := range []string{runtimePath, inputPath} {
+		state, err := (&common.FileSystemSiteStateLoader{Path: statePath}).Load()
+		assert.Assert(t, err)
+		grant, ok := state.Grants["grant-one"]
+		assert.Assert(t, ok)
+		assert.Assert(t, grant.IsReady())
+		assert.Equal(t, grant.Status.Url, fmt.Sprintf("https://localhost:%d/%s", port, grant.UID))
+		assert.Equal(t, grant.Status.Ca, string(ca.Data["tls.crt"]))
+		assert.Assert(t, grant.Status.Code != "")
+	}
+
+	// grants removed from the input resources are not written back
+	assert.Assert(t, os.Remove(path.Join(inputPath, "AccessGrant-grant-one.yaml")))
+	grant := siteState.Grants["grant-one"].DeepCopy()
+	grant.Status.Redemptions = 1
+	assert.Assert(t, handler.updateStatus(grant))
+	_, err = os.Stat(path.Join(inputPath, "AccessGrant-grant-one.yaml"))
+	assert.Assert(t, os.IsNotExist(err))
+
+	handler.Stop()
+	assert.Assert(t, handler.server == nil)
+}
+
+func TestGrantsHandlerNoLinkAccess(t *testing.T) {
+	api.DefaultRootDataHome = t.TempDir()
+	if os.Getuid() != 0 {
+		t.Setenv("XDG_DATA_HOME", api.DefaultRootDataHome)
+	}
+	namespace := "test-grants-handler"
+	siteState := fakeSiteState()
+	siteState.SetNamespace(namespace)
+	delete(siteState.RouterAccesses, "link-access-one")
+	assert.Assert(t, api.MarshalSiteState(*siteState, api.GetInternalOutputPath(namespace, api.RuntimeSiteStatePath)))
+
+	handler := NewGrantsHandler(namespace)
+	handler.Start(make(chan struct{}))
+	assert.Assert(t, handler.server == nil)
+	// changes are ignored while the server is not running
+	handler.OnUpdate("AccessGrant-grant-one.yaml")
+}
+
+func freePort(t *testing.T) int {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Assert(t, err)
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port
+}
@@ -0,0 +1,487 @@
+package controller
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/skupperproject/skupper/api/types"
+	"github.com/skupperproject/skupper/internal/nonkube/bootstrap"
+	"github.com/skupperproject/skupper/internal/nonkube/client/runtime"
+	"github.com/skupperproject/skupper/internal/nonkube/common"
+	"github.com/skupperproject/skupper/internal/nonkube/secrets"
+	"github.com/skupperproject/skupper/internal/qdr"
+	"github.com/skupperproject/skupper/pkg/nonkube/api"
+)
+
+const (
+	inputResourcesDebounce = 2 * time.Second
+)
+
+// RouterUpdater applies the bridges, inter-router connectors and
+// inter-router listeners from the desired router configuration to a
+// running router.
+type RouterUpdater interface {
+	Update(desired *qdr.RouterConfig) error
+}
+
+// InputResourcesHandler watches the input resources of a namespace and
+// reconciles Listeners, Connectors, Links and the drain settings of the
+// site with the running router. Changes that cannot be applied to a
+// running router cause the whole site to be reloaded.
+type InputResourcesHandler struct {
+	namespace string
+	logger    *slog.Logger
+	debounce  time.Duration
+	updater   RouterUpdater
+	reload    func(namespace string) error
+	mutex     sync.Mutex
+	timer     *time.Timer
+	snapshot  *api.SiteState
+}
+
+func NewInputResourcesHandler(namespace string) *InputResourcesHandler {
+	return &InputResourcesHandler{
+		namespace: namespace,
+		logger: slog.Default().
+			With("component", "input.resources.handler").
+			With("namespace", namespace),
+		debounce: inputResourcesDebounce,
+		updater:  &agentRouterUpdater{namespace: namespace},
+		reload:   reloadSite,
+	}
+}
+
+// OnBasePathAdded takes the snapshot the input resources are compared
+// with from the loaded site state, the copy of the input resources that
+// were last rendered or applied, so that changes made while the
+// controller was not running are reconciled as well
+func (h *InputResourcesHandler) OnBasePathAdded(basePath string) {
+	h.mutex.Lock()
+	if h.snapshot != nil {
+		h.mutex.Unlock()
+		return
+	}
+	input, err := h.loadInput()
+	if err != nil {
+		h.mutex.Unlock()
+		h.logger.Warn("Unable to load input resources", slog.Any("error", err))
+		return
+	}
+	h.snapshot = input
+	loaded, err := h.loadSiteState(api.LoadedSiteStatePath)
+	if err != nil {
+		h.mutex.Unlock()
+		h.logger.Warn("Unable to load rendered input resources", slog.Any("error", err))
+		return
+	}
+	h.snapshot = loaded
+	pending := !api.DiffSiteStates(loaded, input).Empty()
+	h.mutex.Unlock()
+	if pending {
+		h.logger.Info("Input resources changed while the controller was stopped")
+		h.schedule()
+	}
+}
+
+func (h *InputResourcesHandler) OnCreate(name string) {
+	h.schedule()
+}
+
+func (h *InputResourcesHandler) OnUpdate(name string) {
+	h.schedule()
+}
+
+func (h *InputResourcesHandler) OnRemove(name string) {
+	h.schedule()
+}
+
+func (h *InputResourcesHandler) Filter(name string) bool {
+	return strings.HasSuffix(name, ".yaml") || strings.HasSuffix(name, ".yml")
+}
+
+// schedule delays the reconciliation, so that a set of files changed
+// together is handled at once
+func (h *InputResourcesHandler) schedule() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if h.timer != nil {
+		h.timer.Stop()
+	}
+	h.timer = time.AfterFunc(h.debounce, func() {
+		if err := h.reconcile(); err != nil {
+			h.logger.Error("Unable to reconcile input resources", slog.Any("error", err))
+		}
+	})
+}
+
+func (h *InputResourcesHandler) loadSiteState(internalPath api.InternalPath) (*api.SiteState, error) {
+	store, err := secrets.StoreForNamespace(h.namespace)
+	if err != nil {
+		return nil, err
+	}
+	loader := &common.FileSystemSiteStateLoader{
+		Path:  api.GetInternalOutputPath(h.namespace, internalPath),
+		Store: store,
+	}
+	return loader.Load()
+}
+
+func (h *InputResourcesHandler) loadInput() (*api.SiteState, error) {
+	siteState, err := h.loadSiteState(api.InputSiteStatePath)
+	if err != nil {
+		return nil, err
+	}
+	validator := &common.SiteStateValidator{}
+	if err = validator.Validate(siteState); err != nil {
+		return nil, fmt.Errorf("invalid input resources: %w", err)
+	}
+	return siteState, nil
+}
+
+func (h *InputResourcesHandler) reconcile() error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	desired, err := h.loadInput()
+	if err != nil {
+		return err
+	}
+	if h.snapshot == nil {
+		h.snapshot = desired
+		return nil
+	}
+	diff := api.DiffSiteStates(h.snapshot, desired)
+	if diff.Empty() {
+		return nil
+	}
+	if diff.RequiresReload() {
+		return h.reloadSite(desired, diff.ReloadReasons...)
+	}
+	if diff.RouterChanged() {
+		h.logger.Info("Applying input resources to running router",
+			slog.Any("listeners", diff.Listeners),
+			slog.Any("connectors", diff.Connectors),
+			slog.Any("links", diff.Links),
+			slog.Bool("drain", diff.Drain))
+		missing, err := h.apply(desired, diff)
+		if err != nil {
+			return err
+		}
+		if len(missing) > 0 {
+			return h.reloadSite(desired, fmt.Sprintf("sslProfiles not found: %s", strings.Join(missing, ", ")))
+		}
+	}
+	if !diff.Grants.Empty() {
+		h.logger.Info("Applying AccessGrants", slog.Any("grants", diff.Grants))
+		if err = h.applyGrants(desired, diff); err != nil {
+			return err
+		}
+	}
+	if err = updateLoadedSiteState(h.namespace, desired, diff); err != nil {
+		h.logger.Warn("Unable to update loaded site state", slog.Any("error", err))
+	}
+	h.snapshot = desired
+	return nil
+}
+
+// updateLoadedSiteState keeps the copy of the loaded input resources in
+// line with the changes applied to the running site, so that it only
+// differs from the input resources while changes are pending
+func updateLoadedSiteState(namespace string, desired *api.SiteState, diff *api.SiteStateDiff) error {
+	loadedSiteStatePath := api.GetInternalOutputPath(namespace, api.LoadedSiteStatePath)
+	removeResourceFiles(loadedSiteStatePath, "Listener", diff.Listeners.Deleted)
+	removeResourceFiles(loadedSiteStatePath, "Connector", diff.Connectors.Deleted)
+	removeResourceFiles(loadedSiteStatePath, "Link", diff.Links.Deleted)
+	removeResourceFiles(loadedSiteStatePath, "AccessGrant", diff.Grants.Deleted)
+	store, err := secrets.StoreForNamespace(namespace)
+	if err != nil {
+		return err
+	}
+	return secrets.MarshalSiteState(store, *desired, loadedSiteStatePath)
+}
+
+func (h *InputResourcesHandler) reloadSite(desired *api.SiteState, reasons ...string) error {
+	h.logger.Info("Reloading site", slog.Any("reasons", reasons))
+	if err := h.reload(h.namespace); err != nil {
+		return fmt.Errorf("unable to reload site: %w", err)
+	}
+	h.snapshot = desired
+	return nil
+}
+
+// apply updates the running router, the router configuration and the
+// runtime site state, unless the desired bridges or connectors reference
+// sslProfiles that are not yet configured, which are returned instead
+func (h *InputResourcesHandler) apply(desired *api.SiteState, diff *api.SiteStateDiff) ([]string, error) {
+	lock := runtimeSiteStateLock(h.namespace)
+	lock.Lock()
+	defer lock.Unlock()
+
+	runtimeSiteStatePath := api.GetInternalOutputPath(h.namespace, api.RuntimeSiteStatePath)
+	loader := &common.FileSystemSiteStateLoader{
+		Path: runtimeSiteStatePath,
+	}
+	siteState, err := loader.Load()
+	if err != nil {
+		return nil, fmt.Errorf("unable to load runtime site state: %w", err)
+	}
+	actual, err := common.LoadRouterConfig(h.namespace)
+	if err != nil {
+		return nil, err
+	}
+	delete(siteState.ConfigMaps, "skupper-network-status")
+	// keep the status computed for existing resources until the
+	// next network status update
+	for name, listener := range desired.Listeners {
+		if existing, ok := siteState.Listeners[name]; ok {
+			listener.Status = existing.Status
+		}
+	}
+	for name, connector := range desired.Connectors {
+		if existing, ok := siteState.Connectors[name]; ok {
+			connector.Status = existing.Status
+		}
+	}
+	for name, link := range desired.Links {
+		if existing, ok := siteState.Links[name]; ok {
+			link.Status = existing.Status
+		}
+	}
+	siteState.Listeners = desired.Listeners
+	siteState.Connectors = desired.Connectors
+	siteState.Links = desired.Links
+	// any change to the site other than its drain settings
+	// requires a reload
+	if siteState.Site != nil && desired.Site != nil {
+		siteState.Site.Spec = desired.Site.Spec
+	}
+	siteState.SetNamespace(h.namespace)
+
+	// only bridges, addresses, inter-router connectors and the cost of
+	// inter-router listeners are taken from the new configuration,
+	// sslProfile paths are kept as rendered
+	routerConfig := siteState.ToRouterConfig(common.DefaultSslProfileBasePath, "")
+	config := *actual
+	config.Bridges = routerConfig.Bridges
+	config.Connectors = routerConfig.Connectors
+	config.Addresses = routerConfig.Addresses
+	for name, listener := range config.Listeners {
+		if updated, ok := routerConfig.Listeners[name]; ok && listener.Role == qdr.RoleInterRouter {
+			listener.Cost = updated.Cost
+			config.Listeners[name] = listener
+		}
+	}
+	if missing := missingSslProfiles(&config); len(missing) > 0 {
+		return missing, nil
+	}
+	if err = h.updater.Update(&config); err != nil {
+		// the configuration is still persisted below and used
+		// when the router is restarted
+		h.logger.Warn("Unable to update running router", slog.Any("error", err))
+	}
+
+	routerConfigData, err := qdr.MarshalRouterConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	routerConfigFile := path.Join(api.GetInternalOutputPath(h.namespace, api.RouterConfigPath), "skrouterd.json")
+	if err = os.WriteFile(routerConfigFile, []byte(routerConfigData), 0644); err != nil {
+		return nil, fmt.Errorf("unable to write router configuration: %w", err)
+	}
+	removeResourceFiles(runtimeSiteStatePath, "Listener", diff.Listeners.Deleted)
+	removeResourceFiles(runtimeSiteStatePath, "Connector", diff.Connectors.Deleted)
+	removeResourceFiles(runtimeSiteStatePath, "Link", diff.Links.Deleted)
+	if err = api.MarshalSiteState(*siteState, runtimeSiteStatePath); err != nil {
+		return nil, fmt.Errorf("unable to update runtime site state: %w", err)
+	}
+	return nil, nil
+}
+
+// applyGrants updates the AccessGrants of the runtime site state, which
+// are served by the grants handler, keeping the status of existing ones
+func (h *InputResourcesHandler) applyGrants(desired *api.SiteState, diff *api.SiteStateDiff) error {
+	lock := runtimeSiteStateLock(h.namespace)
+	lock.Lock()
+	defer lock.Unlock()
+
+	store, err := secrets.StoreForNamespace(h.namespace)
+	if err != nil {
+		return err
+	}
+	runtimeSiteStatePath := api.GetInternalOutputPath(h.namespace, api.RuntimeSiteStatePath)
+	loader := &common.FileSystemSiteStateLoader{
+		Path:  runtimeSiteStatePath,
+		Store: store,
+	}
+	siteState, err := loader.Load()
+	if err != nil {
+		return fmt.Errorf("unable to load runtime site state: %w", err)
+	}
+	removeResourceFiles(runtimeSiteStatePath, "AccessGrant", diff.Grants.Deleted)
+	for _, name := range append(diff.Grants.Added, diff.Grants.Updated...) {
+		grant := desired.Grants[name].DeepCopy()
+		if existing, ok := siteState.Grants[name]; ok && existing.UID != "" {
+			grant.UID = existing.UID
+			grant.Status = existing.Status
+		}
+		grant.Namespace = h.namespace
+		if err = secrets.MarshalResource(store, runtimeSiteStatePath, "AccessGrant", name, grant); err != nil {
+			return fmt.Errorf("unable to update runtime site state: %w", err)
+		}
+	}
+	return nil
+}
+
+func missingSslProfiles(config *qdr.RouterConfig) []string {
+	var missing []string
+	check := func(name string) {
+		if name == "" {
+			return
+		}
+		if _, ok := config.SslProfiles[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	for _, listener := range config.Bridges.TcpListeners {
+		check(listener.SslProfile)
+	}
+	for _, connector := range config.Bridges.TcpConnectors {
+		check(connector.SslProfile)
+	}
+	for _, listener := range config.Bridges.HttpListeners {
+		check(listener.SslProfile)
+	}
+	for _, connector := range config.Bridges.HttpConnectors {
+		check(connector.SslProfile)
+	}
+	for _, connector := range config.Connectors {
+		check(connector.SslProfile)
+	}
+	return missing
+}
+
+func removeResourceFiles(outputDirectory, resourceType string, names []string) {
+	for _, name := range names {
+		_ = os.Remove(path.Join(outputDirectory, fmt.Sprintf("%s-%s.yaml", resourceType, name)))
+	}
+}
+
+type agentRouterUpdater struct {
+	namespace string
+	// connect overrides the connection to the local router of the site
+	connect func() (*qdr.Agent, error)
+}
+
+func (u *agentRouterUpdater) agent() (*qdr.Agent, error) {
+	if u.connect != nil {
+		return u.connect()
+	}
+	url, err := runtime.GetLocalRouterAddress(u.namespace)
+	if err != nil {
+		return nil, err
+	}
+	return qdr.Connect(url, runtime.GetRuntimeTlsCert(u.namespace, "skupper-local-client"))
+}
+
+func (u *agentRouterUpdater) Update(desired *qdr.RouterConfig) error {
+	agent, err := u.agent()
+	if err != nil {
+		return fmt.Errorf("unable to connect to router: %w", err)
+	}
+	defer agent.Close()
+
+	actualBridges, err := agent.GetLocalBridgeConfig()
+	if err != nil {
+		return fmt.Errorf("error retrieving bridges: %w", err)
+	}
+	bridgesDiff := actualBridges.Difference(&desired.Bridges)
+	if !bridgesDiff.Empty() {
+		if err = agent.UpdateLocalBridgeConfig(bridgesDiff); err != nil {
+			return fmt.Errorf("error syncing bridges: %w", err)
+		}
+	}
+
+	actualConnectors, err := agent.GetLocalConnectors()
+	if err != nil {
+		return fmt.Errorf("error retrieving connectors: %w", err)
+	}
+	connectorsDiff := qdr.ConnectorsDifference(actualConnectors, desired, nil)
+	if !connectorsDiff.Empty() {
+		if err = agent.UpdateConnectorConfig(connectorsDiff); err != nil {
+			return fmt.Errorf("error syncing connectors: %w", err)
+		}
+	}
+
+	actualAddresses, err := agent.GetLocalAddresses()
+	if err != nil {
+		return fmt.Errorf("error retrieving addresses: %w", err)
+	}
+	if addressesDiff := qdr.AddressesDifference(actualAddresses, desired.Addresses); !addressesDiff.Empty() {
+		if err = agent.UpdateAddressConfig(addressesDiff); err != nil {
+			return fmt.Errorf("error syncing addresses: %w", err)
+		}
+	}
+
+	actualListeners, err := agent.GetLocalListeners()
+	if err != nil {
+		return fmt.Errorf("error retrieving listeners: %w", err)
+	}
+	if listenersDiff := listenerCostChanges(actualListeners, desired); !listenersDiff.Empty() {
+		if err = agent.UpdateListenerConfig(listenersDiff); err != nil {
+			return fmt.Errorf("error syncing listeners: %w", err)
+		}
+	}
+	return nil
+}
+
+// listenerCostChanges returns the inter-router listeners whose cost
+// differs from the running router, as any other change to them requires
+// the site to be reloaded
+func listenerCostChanges(actual map[string]qdr.Listener, desired *qdr.RouterConfig) *qdr.ListenerDifference {
+	cost := func(listener qdr.Listener) int32 {
+		if listener.Cost <= 0 {
+			return 1
+		}
+		return listener.Cost
+	}
+	diff := &qdr.ListenerDifference{}
+	for name, listener := range desired.GetMatchingListeners(qdr.IsNotNormalListener) {
+		if current, ok := actual[name]; ok && cost(current) != cost(listener) {
+			// handled as delete then add, as listeners cannot be updated in place
+			diff.Deleted = append(diff.Deleted, current)
+			diff.Added = append(diff.Added, listener)
+		}
+	}
+	return diff
+}
+
+// reloadSite renders the site again from its input resources,
+// as done by "skupper system reload"
+func reloadSite(namespace string) error {
+	platformLoader := &common.NamespacePlatformLoader{}
+	platform, err := platformLoader.Load(namespace)
+	if err != nil {
+		return err
+	}
+	config := &bootstrap.Config{
+		Namespace: namespace,
+		InputPath: api.GetInternalOutputPath(namespace, api.InputSiteStatePath),
+		Platform:  types.Platform(platform),
+	}
+	_, err = bootstrap.Bootstrap(config)
+	return err
+}
+
+var runtimeSiteStateLocks sync.Map
+
+// runtimeSiteStateLock serializes the handlers that read and
+// write the runtime site state of a namespace
+func runtimeSiteStateLock(namespace string) *sync.Mutex {
+	lock, _ := runtimeSiteStateLocks.LoadOrStore(namespace, &sync.Mutex{})
+	return lock.(*sync.Mutex)
+}
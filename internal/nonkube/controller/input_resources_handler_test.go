@@ -0,0 +1,297 @@
+package controller
+
+import (
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/skupperproject/skupper/internal/nonkube/common"
+	"github.com/skupperproject/skupper/internal/qdr"
+	"github.com/skupperproject/skupper/internal/qdr/fake"
+	"github.com/skupperproject/skupper/internal/site"
+	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+	"github.com/skupperproject/skupper/pkg/nonkube/api"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestInputResourcesHandler(t *testing.T) {
+	tests := []struct {
+		name             string
+		modify           func(ss *api.SiteState)
+		expectReload     bool
+		expectUpdate     bool
+		expectDrain      bool
+		expectedListener string
+		expectedGrant    string
+		removedFiles     []string
+	}{
+		{
+			name:   "unchanged",
+			modify: func(ss *api.SiteState) {},
+		},
+		{
+			name: "listener updated",
+			modify: func(ss *api.SiteState) {
+				ss.Listeners["listener-one"].Spec.Port = 8080
+			},
+			expectUpdate:     true,
+			expectedListener: "listener-one",
+		},
+		{
+			name: "listener added and connector deleted",
+			modify: func(ss *api.SiteState) {
+				ss.Listeners["listener-three"] = &v2alpha1.Listener{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "Listener",
+						APIVersion: "skupper.io/v2alpha1",
+					},
+					ObjectMeta: metav1.ObjectMeta{Name: "listener-three", Namespace: ss.GetNamespace()},
+					Spec: v2alpha1.ListenerSpec{
+						RoutingKey: "listener-three-key",
+						Host:       "listener-three-host",
+						Port:       8080,
+						Type:       "tcp",
+					},
+				}
+				delete(ss.Connectors, "connector-one")
+			},
+			expectUpdate:     true,
+			expectedListener: "listener-three",
+			removedFiles:     []string{"Connector-connector-one.yaml"},
+		},
+		{
+			name: "listener with new tls credentials",
+			modify: func(ss *api.SiteState) {
+				ss.Listeners["listener-one"].Spec.TlsCredentials = "new-credentials"
+			},
+			expectReload: true,
+		},
+		{
+			name: "site draining",
+			modify: func(ss *api.SiteState) {
+				ss.Site.Spec.Settings = map[string]string{"drain": "true"}
+			},
+			expectUpdate:     true,
+			expectDrain:      true,
+			expectedListener: "listener-one",
+		},
+		{
+			name: "grant added",
+			modify: func(ss *api.SiteState) {
+				ss.Grants["grant-one"] = &v2alpha1.AccessGrant{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "AccessGrant",
+						APIVersion: "skupper.io/v2alpha1",
+					},
+					ObjectMeta: metav1.ObjectMeta{Name: "grant-one", Namespace: ss.GetNamespace()},
+					Spec: v2alpha1.AccessGrantSpec{
+						RedemptionsAllowed: 1,
+						ExpirationWindow:   "15m",
+					},
+				}
+			},
+			expectedGrant: "grant-one",
+		},
+		{
+			name: "site updated",
+			modify: func(ss *api.SiteState) {
+				ss.Site.Spec.Edge = true
+			},
+			expectReload: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			api.DefaultRootDataHome = t.TempDir()
+			if os.Getuid() != 0 {
+				t.Setenv("XDG_DATA_HOME", api.DefaultRootDataHome)
+			}
+			namespace := "test-input-resources-handler"
+			inputPath := api.GetInternalOutputPath(namespace, api.InputSiteStatePath)
+			runtimePath := api.GetInternalOutputPath(namespace, api.RuntimeSiteStatePath)
+
+			routerConfig := writeInputResourcesTestSite(t, namespace)
+
+			updater := &fakeRouterUpdater{}
+			reloaded := false
+			handler := NewInputResourcesHandler(namespace)
+			handler.updater = updater
+			handler.reload = func(namespace string) error {
+				reloaded = true
+				return nil
+			}
+			handler.OnBasePathAdded(inputPath)
+			assert.Assert(t, handler.snapshot != nil)
+			assert.Assert(t, handler.timer == nil)
+
+			desired := fakeInputSiteState(namespace)
+			test.modify(desired)
+			for _, name := range test.removedFiles {
+				assert.Assert(t, os.Remove(path.Join(inputPath, name)))
+			}
+			assert.Assert(t, api.MarshalSiteState(*desired, inputPath))
+			assert.Assert(t, handler.reconcile())
+
+			assert.Equal(t, reloaded, test.expectReload)
+			assert.Equal(t, updater.desired != nil, test.expectUpdate)
+			if test.expectedGrant != "" {
+				runtimeState, err := (&common.FileSystemSiteStateLoader{Path: runtimePath}).Load()
+				assert.Assert(t, err)
+				grant, ok := runtimeState.Grants[test.expectedGrant]
+				assert.Assert(t, ok)
+				assert.DeepEqual(t, grant.Spec, desired.Grants[test.expectedGrant].Spec)
+			}
+			if !test.expectUpdate {
+				return
+			}
+			actual, err := common.LoadRouterConfig(namespace)
+			assert.Assert(t, err)
+			assert.DeepEqual(t, actual.Bridges, updater.desired.Bridges)
+			assert.DeepEqual(t, actual.SslProfiles, routerConfig.SslProfiles)
+			listener := desired.Listeners[test.expectedListener]
+			tcpListener, ok := actual.Bridges.TcpListeners[test.expectedListener]
+			assert.Assert(t, ok)
+			assert.Equal(t, tcpListener.Port, strconv.Itoa(listener.Spec.Port))
+			assert.Equal(t, tcpListener.Address, listener.Spec.RoutingKey)
+
+			runtimeState, err := (&common.FileSystemSiteStateLoader{Path: runtimePath}).Load()
+			assert.Assert(t, err)
+			assert.DeepEqual(t, runtimeState.Listeners[test.expectedListener].Spec, listener.Spec)
+			assert.Equal(t, runtimeState.Site.Spec.IsDraining(), test.expectDrain)
+			if test.expectDrain {
+				assert.Equal(t, len(actual.Bridges.TcpConnectors), 0)
+			}
+			for _, connector := range actual.Connectors {
+				assert.Equal(t, connector.Cost == site.DrainLinkCost, test.expectDrain)
+			}
+			for _, name := range test.removedFiles {
+				_, err = os.Stat(path.Join(runtimePath, name))
+				assert.Assert(t, os.IsNotExist(err))
+			}
+
+			// the loaded copy of the input resources has no pending changes
+			loadedPath := api.GetInternalOutputPath(namespace, api.LoadedSiteStatePath)
+			loadedState, err := (&common.FileSystemSiteStateLoader{Path: loadedPath}).Load()
+			assert.Assert(t, err)
+			inputState, err := (&common.FileSystemSiteStateLoader{Path: inputPath}).Load()
+			assert.Assert(t, err)
+			assert.Assert(t, api.DiffSiteStates(loadedState, inputState).Empty())
+		})
+	}
+}
+
+func TestInputResourcesHandlerChangedWhileStopped(t *testing.T) {
+	api.DefaultRootDataHome = t.TempDir()
+	if os.Getuid() != 0 {
+		t.Setenv("XDG_DATA_HOME", api.DefaultRootDataHome)
+	}
+	namespace := "test-input-resources-handler"
+	inputPath := api.GetInternalOutputPath(namespace, api.InputSiteStatePath)
+	writeInputResourcesTestSite(t, namespace)
+
+	// the input resources are changed while the controller is stopped
+	desired := fakeInputSiteState(namespace)
+	desired.Listeners["listener-one"].Spec.Port = 8080
+	assert.Assert(t, api.MarshalSiteState(*desired, inputPath))
+
+	updater := &fakeRouterUpdater{}
+	handler := NewInputResourcesHandler(namespace)
+	handler.updater = updater
+	handler.debounce = time.Hour
+	handler.OnBasePathAdded(inputPath)
+	assert.Assert(t, handler.timer != nil, "reconcile should have been scheduled")
+	handler.timer.Stop()
+	assert.Equal(t, handler.snapshot.Listeners["listener-one"].Spec.Port, 1234)
+
+	assert.Assert(t, handler.reconcile())
+	assert.Assert(t, updater.desired != nil)
+	tcpListener, ok := updater.desired.Bridges.TcpListeners["listener-one"]
+	assert.Assert(t, ok)
+	assert.Equal(t, tcpListener.Port, "8080")
+}
+
+func TestInputResourcesHandlerWithRouter(t *testing.T) {
+	api.DefaultRootDataHome = t.TempDir()
+	if os.Getuid() != 0 {
+		t.Setenv("XDG_DATA_HOME", api.DefaultRootDataHome)
+	}
+	namespace := "test-input-resources-handler"
+	inputPath := api.GetInternalOutputPath(namespace, api.InputSiteStatePath)
+	routerConfig := writeInputResourcesTestSite(t, namespace)
+
+	// the router is running with the rendered configuration
+	router, err := fake.NewRouter(fake.Config{ID: "router-1", SiteID: "site-1"})
+	assert.Assert(t, err)
+	defer router.Close()
+	agent, err := qdr.Connect(router.URL(), nil)
+	assert.Assert(t, err)
+	defer agent.Close()
+	running := qdr.NewBridgeConfig()
+	assert.Assert(t, agent.UpdateLocalBridgeConfig(running.Difference(&routerConfig.Bridges)))
+	for name, connector := range routerConfig.Connectors {
+		assert.Assert(t, agent.Create("io.skupper.router.connector", name, connector))
+	}
+	handler := NewInputResourcesHandler(namespace)
+	handler.updater = &agentRouterUpdater{
+		namespace: namespace,
+		connect: func() (*qdr.Agent, error) {
+			return qdr.Connect(router.URL(), nil)
+		},
+	}
+	handler.OnBasePathAdded(inputPath)
+
+	desired := fakeInputSiteState(namespace)
+	desired.Listeners["listener-one"].Spec.Port = 8080
+	delete(desired.Connectors, "connector-one")
+	assert.Assert(t, os.Remove(path.Join(inputPath, "Connector-connector-one.yaml")))
+	assert.Assert(t, api.MarshalSiteState(*desired, inputPath))
+	assert.Assert(t, handler.reconcile())
+
+	listeners := router.Entities("tcpListener")
+	assert.Equal(t, len(listeners), len(desired.Listeners))
+	assert.Equal(t, listeners["listener-one"]["port"], "8080")
+	assert.Equal(t, listeners["listener-one"]["address"], desired.Listeners["listener-one"].Spec.RoutingKey)
+	for name := range router.Entities("tcpConnector") {
+		assert.Assert(t, !strings.HasPrefix(name, "connector-one"), "connector-one should have been removed")
+	}
+	assert.Equal(t, len(router.Entities("tcpConnector")), len(desired.Connectors))
+}
+
+// writeInputResourcesTestSite writes the input, loaded and runtime site
+// states and the router configuration of a rendered site
+func writeInputResourcesTestSite(t *testing.T, namespace string) qdr.RouterConfig {
+	t.Helper()
+	routerConfigPath := api.GetInternalOutputPath(namespace, api.RouterConfigPath)
+	assert.Assert(t, os.MkdirAll(routerConfigPath, 0755))
+	siteState := fakeInputSiteState(namespace)
+	for _, internalPath := range []api.InternalPath{api.InputSiteStatePath, api.LoadedSiteStatePath, api.RuntimeSiteStatePath} {
+		assert.Assert(t, api.MarshalSiteState(*siteState, api.GetInternalOutputPath(namespace, internalPath)))
+	}
+	routerConfig := siteState.ToRouterConfig(common.DefaultSslProfileBasePath, "podman")
+	routerConfigData, err := qdr.MarshalRouterConfig(routerConfig)
+	assert.Assert(t, err)
+	assert.Assert(t, os.WriteFile(path.Join(routerConfigPath, "skrouterd.json"), []byte(routerConfigData), 0644))
+	return routerConfig
+}
+
+func fakeInputSiteState(namespace string) *api.SiteState {
+	siteState := fakeSiteState()
+	siteState.SetNamespace(namespace)
+	// the local router access is only part of the runtime site state
+	delete(siteState.RouterAccesses, "skupper-local")
+	siteState.Links["link-one"].Spec.TlsCredentials = "link-one-profile"
+	return siteState
+}
+
+type fakeRouterUpdater struct {
+	desired *qdr.RouterConfig
+}
+
+func (f *fakeRouterUpdater) Update(desired *qdr.RouterConfig) error {
+	f.desired = desired
+	return nil
+}
@@ -39,8 +39,13 @@ func (w *NamespaceController) Start() {
 		routerConfigHandler.AddCallback(routerStateHandler)
 		collectorLifecycleHandler := NewCollectorLifecycleHandler(w.ns)
 		routerStateHandler.SetCallback(collectorLifecycleHandler)
+		grantsHandler := NewGrantsHandler(w.ns)
+		routerConfigHandler.AddCallback(grantsHandler)
 		w.watcher.Add(api.GetInternalOutputPath(w.ns, api.RouterConfigPath), routerConfigHandler)
 		w.watcher.Add(api.GetInternalOutputPath(w.ns, api.RuntimeSiteStatePath), NewNetworkStatusHandler(w.ns))
+		w.watcher.Add(api.GetInternalOutputPath(w.ns, api.RuntimeSiteStatePath), grantsHandler)
+		w.watcher.Add(api.GetInternalOutputPath(w.ns, api.InputSiteStatePath), NewInputResourcesHandler(w.ns))
+		NewDrainMonitor(w.ns).Start(w.stopCh)
 	} else {
 		w.prepare()
 	}
@@ -67,6 +67,9 @@ func (n *NetworkStatusHandler) processEvents(done chan struct{}) {
 }
 
 func (n *NetworkStatusHandler) updateRuntimeSiteState(networkStatusInfo network.NetworkStatusInfo) {
+	lock := runtimeSiteStateLock(n.Namespace)
+	lock.Lock()
+	defer lock.Unlock()
 	runtimeSiteStatePath := api.GetInternalOutputPath(n.Namespace, api.RuntimeSiteStatePath)
 	siteStateLoader := &common.FileSystemSiteStateLoader{
 		Path: runtimeSiteStatePath,
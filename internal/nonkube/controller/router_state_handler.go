@@ -8,6 +8,7 @@ import (
 
 	"github.com/skupperproject/skupper/internal/messaging"
 	"github.com/skupperproject/skupper/internal/nonkube/client/runtime"
+	"github.com/skupperproject/skupper/internal/nonkube/common"
 	"github.com/skupperproject/skupper/internal/qdr"
 )
 
@@ -81,6 +82,11 @@ func (h *RouterStateHandler) handleParentStop(stopCh <-chan struct{}) {
 	h.logger.Info("Stopped")
 }
 
+// heartbeatRecordInterval is how often the router availability is
+// persisted while it does not change, so readers can tell whether the
+// controller is still running
+const heartbeatRecordInterval = 10 * time.Second
+
 func newHeartBeatsClient(namespace string) *heartBeatsClient {
 	c := &heartBeatsClient{
 		Namespace: namespace,
@@ -102,6 +108,7 @@ type heartBeatsClient struct {
 	callback   ActivationCallback
 	receiver   messaging.Receiver
 	factory    func(string, qdr.TlsConfigRetriever) messaging.ConnectionFactory
+	record     common.RouterHeartbeat
 }
 
 func (h *heartBeatsClient) Start(stopCh <-chan struct{}, callback ActivationCallback) {
@@ -136,6 +143,7 @@ func (h *heartBeatsClient) routerDown(reason string) {
 		h.isRouterUp = false
 		h.callback.Stop()
 	}
+	h.recordHeartbeat(reason)
 }
 
 func (h *heartBeatsClient) routerUp(stopCh <-chan struct{}) {
@@ -146,6 +154,24 @@ func (h *heartBeatsClient) routerUp(stopCh <-chan struct{}) {
 		h.isRouterUp = true
 		h.callback.Start(stopCh)
 	}
+	h.record.LastHeartbeat = time.Now()
+	h.recordHeartbeat("")
+}
+
+// recordHeartbeat persists the router availability when it changes or
+// when the last record is older than heartbeatRecordInterval. It must be
+// called with the mutex held.
+func (h *heartBeatsClient) recordHeartbeat(reason string) {
+	now := time.Now()
+	if h.record.RouterUp == h.isRouterUp && h.record.Reason == reason && now.Sub(h.record.Updated) < heartbeatRecordInterval {
+		return
+	}
+	h.record.RouterUp = h.isRouterUp
+	h.record.Reason = reason
+	h.record.Updated = now
+	if err := common.WriteRouterHeartbeat(h.Namespace, h.record); err != nil {
+		h.logger.Warn("Unable to record router heartbeat", slog.Any("error", err))
+	}
 }
 
 func (h *heartBeatsClient) run(stopCh <-chan struct{}) {
@@ -10,6 +10,7 @@ import (
 
 	amqp "github.com/interconnectedcloud/go-amqp"
 	"github.com/skupperproject/skupper/internal/messaging"
+	"github.com/skupperproject/skupper/internal/nonkube/common"
 	"github.com/skupperproject/skupper/internal/qdr"
 	"github.com/skupperproject/skupper/internal/utils"
 	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
@@ -101,6 +102,10 @@ func TestRouterStateHandler(t *testing.T) {
 		assert.Equal(t, len(mockFactory.connection.receivers), 1)
 		assert.Equal(t, routerLogHandler.Count("Starting"), 1)
 		assert.Equal(t, heartbeatLogHandler.Count("Starting"), 1)
+		heartbeat, err := common.ReadRouterHeartbeat(namespace)
+		assert.Assert(t, err)
+		assert.Assert(t, heartbeat.RouterUp)
+		assert.Assert(t, !heartbeat.Updated.IsZero())
 	})
 
 	t.Run("force-receiver-error", func(t *testing.T) {
@@ -6,12 +6,18 @@ import (
 	"fmt"
 	"log"
 	"log/slog"
+	"maps"
+	"os"
+	"slices"
 
 	"github.com/skupperproject/skupper/api/types"
 	"github.com/skupperproject/skupper/internal/flow"
+	"github.com/skupperproject/skupper/internal/nonkube/client/compat"
 	"github.com/skupperproject/skupper/internal/nonkube/client/fs"
 	"github.com/skupperproject/skupper/internal/nonkube/client/runtime"
 	"github.com/skupperproject/skupper/internal/nonkube/common"
+	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+	"github.com/skupperproject/skupper/pkg/nonkube/api"
 	"github.com/skupperproject/skupper/pkg/vanflow/session"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -86,6 +92,12 @@ func siteCollector(ctx context.Context, namespace string) error {
 	}
 	statusSync := flow.NewStatusSync(factory, nil, statusSyncClient, types.NetworkStatusConfigMapName)
 	go statusSync.Run(ctx)
+	processes, err := newProcessController(namespace, platform, factory)
+	if err != nil {
+		slog.Default().Warn("process discovery disabled", slog.String("namespace", namespace), slog.Any("error", err))
+	} else {
+		go processes.Run(ctx)
+	}
 	go func() {
 		<-ctx.Done()
 		_ = client.Delete(cm.Name, true)
@@ -93,6 +105,58 @@ func siteCollector(ctx context.Context, namespace string) error {
 	return nil
 }
 
+func newProcessController(namespace string, platform string, factory session.ContainerFactory) (*ProcessController, error) {
+	siteState, err := loadRuntimeSiteState(namespace)
+	if err != nil {
+		return nil, err
+	}
+	hostname, _ := os.Hostname()
+	var resolver ProcessResolver
+	switch types.Platform(platform) {
+	case types.PlatformPodman, types.PlatformDocker:
+		endpoint := os.Getenv("CONTAINER_ENDPOINT")
+		if endpoint == "" {
+			endpoint = fmt.Sprintf("unix://%s/podman/podman.sock", api.GetRuntimeDir())
+			if types.Platform(platform) == types.PlatformDocker {
+				endpoint = "unix:///run/docker.sock"
+			}
+		}
+		cli, err := compat.NewCompatClient(endpoint, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create container client: %w", err)
+		}
+		if version, err := cli.Version(); err == nil && version.Hostname != "" {
+			hostname = version.Hostname
+		}
+		resolver = &ContainerProcessResolver{Client: cli}
+	case types.PlatformLinux:
+		resolver = &SocketProcessResolver{Hostname: hostname}
+	default:
+		return nil, fmt.Errorf("unsupported platform: %s", platform)
+	}
+	return NewProcessController(ProcessControllerConfig{
+		Factory:  factory,
+		SiteID:   siteState.SiteId,
+		Platform: platform,
+		Hostname: hostname,
+		Resolver: resolver,
+		Connectors: func() ([]*v2alpha1.Connector, error) {
+			siteState, err := loadRuntimeSiteState(namespace)
+			if err != nil {
+				return nil, err
+			}
+			return slices.Collect(maps.Values(siteState.Connectors)), nil
+		},
+	}), nil
+}
+
+func loadRuntimeSiteState(namespace string) (*api.SiteState, error) {
+	loader := &common.FileSystemSiteStateLoader{
+		Path: api.GetInternalOutputPath(namespace, api.RuntimeSiteStatePath),
+	}
+	return loader.Load()
+}
+
 func getLocalTLSConfig(namespace string) (*tls.Config, error) {
 	tlsCert := runtime.GetRuntimeTlsCert(namespace, "skupper-local-client")
 	config, err := tlsCert.GetTlsConfig()
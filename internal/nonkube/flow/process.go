@@ -0,0 +1,179 @@
+package flow
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	"github.com/skupperproject/skupper/pkg/vanflow/eventsource"
+	"github.com/skupperproject/skupper/pkg/vanflow/session"
+	"github.com/skupperproject/skupper/pkg/vanflow/store"
+)
+
+var (
+	modeExternal = "external"
+	modeInternal = "internal"
+)
+
+// ProcessResolver finds the processes serving the connectors of a site
+type ProcessResolver interface {
+	// Resolve returns a ProcessRecord for each process found to be the target
+	// of one or more connectors. The SourceHost of each record is set to the
+	// connector host it was matched by.
+	Resolve(connectors []*v2alpha1.Connector) ([]vanflow.ProcessRecord, error)
+}
+
+type ProcessControllerConfig struct {
+	Factory session.ContainerFactory
+	SiteID  string
+	// Platform is reported as the provider of the host record
+	Platform string
+	// Hostname of the machine the site runs on
+	Hostname string
+	Resolver ProcessResolver
+	// Connectors returns the current connectors of the site
+	Connectors func() ([]*v2alpha1.Connector, error)
+	// Interval between process discovery runs. Defaults to 30 seconds.
+	Interval time.Duration
+}
+
+// ProcessController publishes ProcessRecords and a HostRecord for a nonkube
+// site so that the workloads behind its connectors can be identified.
+type ProcessController struct {
+	cfg          ProcessControllerConfig
+	container    session.Container
+	source       store.SourceRef
+	processStore store.Interface
+	manager      *eventsource.Manager
+	logger       *slog.Logger
+}
+
+func NewProcessController(cfg ProcessControllerConfig) *ProcessController {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 30 * time.Second
+	}
+	source := store.SourceRef{
+		ID:      cfg.SiteID,
+		Version: "1",
+	}
+	staticRecords := store.NewSyncMapStore(store.SyncMapStoreConfig{})
+	staticRecords.Add(vanflow.HostRecord{
+		BaseRecord: vanflow.NewBase(hostID(cfg.SiteID, cfg.Hostname), time.Now()),
+		Provider:   &cfg.Platform,
+		Name:       &cfg.Hostname,
+	}, source)
+	processes := store.NewSyncMapStore(store.SyncMapStoreConfig{})
+
+	container := cfg.Factory.Create()
+	manager := eventsource.NewManager(container, eventsource.ManagerConfig{
+		Source: eventsource.Info{
+			ID:      cfg.SiteID,
+			Version: 1,
+			Type:    "CONTROLLER",
+			Address: fmt.Sprintf("mc/sfe.%s", cfg.SiteID),
+			Direct:  fmt.Sprintf("sfe.%s", cfg.SiteID),
+		},
+		Stores: []store.Interface{staticRecords, processes},
+
+		UseAlternateHeartbeatAddress: true,
+		FlushDelay:                   time.Millisecond * 100,
+		FlushBatchSize:               20,
+		UpdateBufferTime:             time.Millisecond * 1000,
+		UpdateBatchSize:              10,
+	})
+	return &ProcessController{
+		cfg:          cfg,
+		container:    container,
+		source:       source,
+		processStore: processes,
+		manager:      manager,
+		logger: slog.New(slog.Default().Handler()).With(
+			slog.String("component", "nonkube.flow.processes"),
+			slog.String("site", cfg.SiteID),
+		),
+	}
+}
+
+func (c *ProcessController) Run(ctx context.Context) {
+	c.container.Start(ctx)
+	mgmtCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	c.container.OnSessionError(func(err error) {
+		_, retryable := err.(session.RetryableError)
+		if !retryable {
+			cancel()
+		}
+		c.logger.Error("amqp session error", slog.Any("error", err), slog.Bool("retryable", retryable))
+	})
+	go c.manager.Run(mgmtCtx)
+
+	ticker := time.NewTicker(c.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		if err := c.reconcile(); err != nil {
+			c.logger.Error("process discovery failed", slog.Any("error", err))
+		}
+		select {
+		case <-mgmtCtx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// reconcile publishes the processes currently serving the site connectors
+// and ends the ones that no longer do
+func (c *ProcessController) reconcile() error {
+	connectors, err := c.cfg.Connectors()
+	if err != nil {
+		return fmt.Errorf("error loading connectors: %w", err)
+	}
+	processes, err := c.cfg.Resolver.Resolve(connectors)
+	if err != nil {
+		return fmt.Errorf("error resolving processes: %w", err)
+	}
+	desired := make(map[string]vanflow.ProcessRecord, len(processes))
+	for _, process := range processes {
+		process.Parent = &c.source.ID
+		if process.Hostname == nil {
+			process.Hostname = &c.cfg.Hostname
+		}
+		if process.Mode == nil {
+			process.Mode = &modeExternal
+		}
+		desired[process.ID] = process
+	}
+	for _, entry := range c.processStore.List() {
+		if _, ok := desired[entry.Record.Identity()]; ok {
+			continue
+		}
+		c.processStore.Delete(entry.Record.Identity())
+		terminalRecord := entry.Record.(vanflow.ProcessRecord)
+		terminalRecord.EndTime = &vanflow.Time{Time: time.Now()}
+		c.manager.PublishUpdate(eventsource.RecordUpdate{
+			Prev: entry.Record,
+			Curr: terminalRecord,
+		})
+	}
+	for _, process := range desired {
+		var prev vanflow.Record
+		if curr, exists := c.processStore.Get(process.ID); exists {
+			c.processStore.Update(process)
+			prev = curr.Record
+		} else {
+			c.processStore.Add(process, c.source)
+		}
+		c.manager.PublishUpdate(eventsource.RecordUpdate{
+			Prev: prev,
+			Curr: process,
+		})
+	}
+	return nil
+}
+
+func hostID(siteID string, hostname string) string {
+	return fmt.Sprintf("%s:host:%s", siteID, hostname)
+}
@@ -0,0 +1,102 @@
+package flow
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+	"github.com/skupperproject/skupper/pkg/container"
+	"github.com/skupperproject/skupper/pkg/vanflow"
+)
+
+// ContainerClient is the subset of container.Client used to find the
+// containers behind connectors
+type ContainerClient interface {
+	ContainerList() ([]*container.Container, error)
+	ContainerInspect(id string) (*container.Container, error)
+}
+
+// ContainerProcessResolver resolves connectors on podman and docker sites to
+// the containers they target, matching the connector host against container
+// names, network aliases and IP addresses.
+type ContainerProcessResolver struct {
+	Client ContainerClient
+}
+
+func (r *ContainerProcessResolver) Resolve(connectors []*v2alpha1.Connector) ([]vanflow.ProcessRecord, error) {
+	containers, err := r.Client.ContainerList()
+	if err != nil {
+		return nil, err
+	}
+	byHost := make(map[string]*container.Container)
+	for _, c := range containers {
+		if !c.Running {
+			continue
+		}
+		byHost[c.Name] = c
+		for _, network := range c.Networks {
+			if network.IPAddress != "" {
+				byHost[network.IPAddress] = c
+			}
+			for _, alias := range network.Aliases {
+				if _, ok := byHost[alias]; !ok {
+					byHost[alias] = c
+				}
+			}
+		}
+	}
+
+	var processes []vanflow.ProcessRecord
+	found := make(map[string]bool)
+	for _, connector := range connectors {
+		host := connector.Spec.Host
+		target, ok := byHost[host]
+		if !ok || found[target.ID] {
+			continue
+		}
+		found[target.ID] = true
+		inspected, err := r.Client.ContainerInspect(target.ID)
+		if err != nil {
+			return nil, fmt.Errorf("error inspecting container %s for connector %s: %w", target.Name, connector.Name, err)
+		}
+		processes = append(processes, asContainerProcessRecord(inspected, host))
+	}
+	return processes, nil
+}
+
+func asContainerProcessRecord(c *container.Container, host string) vanflow.ProcessRecord {
+	process := vanflow.ProcessRecord{
+		BaseRecord: vanflow.NewBase(c.ID, c.CreatedAt),
+		Name:       &c.Name,
+		Mode:       &modeExternal,
+		SourceHost: &host,
+	}
+	if c.Image != "" {
+		image := c.Image
+		process.ImageName = &image
+		if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+			version := image[i+1:]
+			process.ImageVersion = &version
+		}
+	}
+	if application, ok := c.Labels["application"]; ok && application == container.AppName {
+		group := "skupper"
+		process.Group = &group
+		process.Mode = &modeInternal
+	} else if labelName, ok := c.Labels["app.kubernetes.io/part-of"]; ok {
+		process.Group = &labelName
+	} else if labelComponent, ok := c.Labels["app.kubernetes.io/name"]; ok {
+		process.Group = &labelComponent
+	} else if partOf, ok := c.Labels["app.kubernetes.io/component"]; ok {
+		process.Group = &partOf
+	} else if process.ImageName != nil {
+		// generate process group from image name
+		parts := strings.Split(*process.ImageName, "/")
+		part := parts[len(parts)-1]
+		pg := strings.Split(part, ":")
+		process.Group = &pg[0]
+	} else {
+		process.Group = &c.Name
+	}
+	return process
+}
@@ -0,0 +1,305 @@
+package flow
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+	"github.com/skupperproject/skupper/pkg/vanflow"
+)
+
+const (
+	// tcpListen is the state of listening sockets in /proc/net/tcp
+	tcpListen = "0A"
+	// clockTicks is the USER_HZ value used by the kernel for the process
+	// start times in /proc/<pid>/stat
+	clockTicks = 100
+)
+
+// SocketProcessResolver resolves connectors on linux sites to the local
+// processes owning the sockets listening on the connector host and port.
+// Connectors to hosts that are not local are ignored.
+type SocketProcessResolver struct {
+	// ProcRoot is the mount point of procfs. Defaults to /proc.
+	ProcRoot string
+	// Hostname of the machine, connectors to this host are local
+	Hostname string
+	// LocalAddresses returns the addresses of the machine. Defaults to the
+	// addresses of all network interfaces.
+	LocalAddresses func() ([]net.IP, error)
+}
+
+type socketListener struct {
+	ip    net.IP
+	port  int
+	inode string
+}
+
+func (r *SocketProcessResolver) Resolve(connectors []*v2alpha1.Connector) ([]vanflow.ProcessRecord, error) {
+	local, err := r.localAddresses()
+	if err != nil {
+		return nil, err
+	}
+	var listeners []socketListener
+	for _, file := range []string{"net/tcp", "net/tcp6"} {
+		found, err := readSocketListeners(filepath.Join(r.procRoot(), file))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		listeners = append(listeners, found...)
+	}
+
+	hosts := make(map[string]string)
+	for _, connector := range connectors {
+		ips := r.hostAddresses(connector.Spec.Host, local)
+		for _, l := range listeners {
+			if l.port != connector.Spec.Port {
+				continue
+			}
+			for _, ip := range ips {
+				if l.ip.IsUnspecified() || l.ip.Equal(ip) {
+					if _, ok := hosts[l.inode]; !ok {
+						hosts[l.inode] = connector.Spec.Host
+					}
+				}
+			}
+		}
+	}
+	if len(hosts) == 0 {
+		return nil, nil
+	}
+
+	owners, err := r.socketOwners(hosts)
+	if err != nil {
+		return nil, err
+	}
+	var processes []vanflow.ProcessRecord
+	found := make(map[int]bool)
+	for inode, pid := range owners {
+		if found[pid] {
+			continue
+		}
+		found[pid] = true
+		process, err := r.processRecord(pid, hosts[inode])
+		if err != nil {
+			// the process may have exited since its sockets were read
+			continue
+		}
+		processes = append(processes, process)
+	}
+	return processes, nil
+}
+
+func (r *SocketProcessResolver) procRoot() string {
+	if r.ProcRoot == "" {
+		return "/proc"
+	}
+	return r.ProcRoot
+}
+
+func (r *SocketProcessResolver) localAddresses() ([]net.IP, error) {
+	if r.LocalAddresses != nil {
+		return r.LocalAddresses()
+	}
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil, err
+	}
+	var ips []net.IP
+	for _, addr := range addrs {
+		if ipnet, ok := addr.(*net.IPNet); ok {
+			ips = append(ips, ipnet.IP)
+		}
+	}
+	return ips, nil
+}
+
+// hostAddresses returns the local addresses a connector host refers to
+func (r *SocketProcessResolver) hostAddresses(host string, local []net.IP) []net.IP {
+	if host == "localhost" {
+		return []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback}
+	}
+	if host == r.Hostname && host != "" {
+		return local
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return nil
+	}
+	if ip.IsLoopback() {
+		return []net.IP{ip}
+	}
+	for _, addr := range local {
+		if addr.Equal(ip) {
+			return []net.IP{ip}
+		}
+	}
+	return nil
+}
+
+// socketOwners maps the socket inodes given to the pids of the processes
+// holding them open
+func (r *SocketProcessResolver) socketOwners(inodes map[string]string) (map[string]int, error) {
+	entries, err := os.ReadDir(r.procRoot())
+	if err != nil {
+		return nil, err
+	}
+	owners := make(map[string]int)
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || !entry.IsDir() {
+			continue
+		}
+		fdDir := filepath.Join(r.procRoot(), entry.Name(), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			// processes of other users cannot be inspected without
+			// privileges
+			continue
+		}
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err != nil || !strings.HasPrefix(link, "socket:[") {
+				continue
+			}
+			inode := strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]")
+			if _, ok := inodes[inode]; ok {
+				owners[inode] = pid
+			}
+		}
+		if len(owners) == len(inodes) {
+			break
+		}
+	}
+	return owners, nil
+}
+
+func (r *SocketProcessResolver) processRecord(pid int, host string) (vanflow.ProcessRecord, error) {
+	dir := filepath.Join(r.procRoot(), strconv.Itoa(pid))
+	comm, err := os.ReadFile(filepath.Join(dir, "comm"))
+	if err != nil {
+		return vanflow.ProcessRecord{}, err
+	}
+	name := strings.TrimSpace(string(comm))
+	started, err := r.startTime(dir)
+	if err != nil {
+		return vanflow.ProcessRecord{}, err
+	}
+	process := vanflow.ProcessRecord{
+		BaseRecord: vanflow.NewBase(fmt.Sprintf("%s:process:%d:%d", r.Hostname, pid, started.Unix()), started),
+		Name:       &name,
+		Group:      &name,
+		Mode:       &modeExternal,
+		SourceHost: &host,
+	}
+	if name == "skrouterd" {
+		group := "skupper"
+		process.Group = &group
+		process.Mode = &modeInternal
+	}
+	return process, nil
+}
+
+// startTime of a process from its stat file and the boot time of the system
+func (r *SocketProcessResolver) startTime(dir string) (time.Time, error) {
+	stat, err := os.ReadFile(filepath.Join(dir, "stat"))
+	if err != nil {
+		return time.Time{}, err
+	}
+	// skip past the command name as it may contain spaces
+	end := strings.LastIndexByte(string(stat), ')')
+	if end < 0 {
+		return time.Time{}, fmt.Errorf("invalid stat file for %s", dir)
+	}
+	fields := strings.Fields(string(stat[end+1:]))
+	// starttime is the 22nd field, the 20th after the command name
+	if len(fields) < 20 {
+		return time.Time{}, fmt.Errorf("invalid stat file for %s", dir)
+	}
+	ticks, err := strconv.ParseInt(fields[19], 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	boot, err := r.bootTime()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return boot.Add(time.Duration(ticks) * time.Second / clockTicks), nil
+}
+
+func (r *SocketProcessResolver) bootTime() (time.Time, error) {
+	f, err := os.Open(filepath.Join(r.procRoot(), "stat"))
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(scanner.Text(), "btime "); ok {
+			btime, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+			if err != nil {
+				return time.Time{}, err
+			}
+			return time.Unix(btime, 0), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return time.Time{}, err
+	}
+	return time.Time{}, fmt.Errorf("boot time not found in %s", f.Name())
+}
+
+// readSocketListeners returns the listening sockets in a /proc/net/tcp or
+// /proc/net/tcp6 file
+func readSocketListeners(name string) ([]socketListener, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var listeners []socketListener
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // header
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 || fields[3] != tcpListen {
+			continue
+		}
+		ip, port, err := parseSocketAddress(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %w", name, err)
+		}
+		listeners = append(listeners, socketListener{ip: ip, port: port, inode: fields[9]})
+	}
+	return listeners, scanner.Err()
+}
+
+// parseSocketAddress parses an address of the form 0100007F:1F90, where the
+// address is printed as a sequence of 32 bit words in host byte order
+func parseSocketAddress(s string) (net.IP, int, error) {
+	addr, portHex, ok := strings.Cut(s, ":")
+	if !ok {
+		return nil, 0, fmt.Errorf("invalid socket address %q", s)
+	}
+	raw, err := hex.DecodeString(addr)
+	if err != nil || (len(raw) != net.IPv4len && len(raw) != net.IPv6len) {
+		return nil, 0, fmt.Errorf("invalid socket address %q", s)
+	}
+	ip := make(net.IP, len(raw))
+	for i := 0; i < len(raw); i += 4 {
+		binary.NativeEndian.PutUint32(ip[i:], binary.BigEndian.Uint32(raw[i:]))
+	}
+	port, err := strconv.ParseUint(portHex, 16, 16)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid socket address %q", s)
+	}
+	return ip, int(port), nil
+}
@@ -0,0 +1,304 @@
+package flow
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/skupperproject/skupper/internal/qdr/fake"
+	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+	"github.com/skupperproject/skupper/pkg/container"
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	"github.com/skupperproject/skupper/pkg/vanflow/eventsource"
+	"github.com/skupperproject/skupper/pkg/vanflow/session"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestContainerProcessResolver(t *testing.T) {
+	created := time.Unix(1700000000, 0)
+	client := &fakeContainerClient{containers: []*container.Container{
+		{
+			ID:        "c1",
+			Name:      "backend",
+			Image:     "quay.io/example/backend:1.2",
+			Running:   true,
+			CreatedAt: created,
+			Networks: map[string]container.ContainerNetworkInfo{
+				"skupper": {IPAddress: "10.88.0.5", Aliases: []string{"api"}},
+			},
+		},
+		{
+			ID:        "c2",
+			Name:      "database",
+			Image:     "postgres",
+			Running:   true,
+			CreatedAt: created,
+			Labels:    map[string]string{"app.kubernetes.io/part-of": "inventory"},
+			Networks: map[string]container.ContainerNetworkInfo{
+				"skupper": {IPAddress: "10.88.0.6"},
+			},
+		},
+		{
+			ID:      "c3",
+			Name:    "default-skupper-router",
+			Image:   "quay.io/skupper/skupper-router:main",
+			Running: true,
+			Labels:  map[string]string{"application": container.AppName},
+		},
+		{
+			ID:    "c4",
+			Name:  "stopped",
+			Image: "stopped",
+		},
+	}}
+	resolver := &ContainerProcessResolver{Client: client}
+	processes, err := resolver.Resolve([]*v2alpha1.Connector{
+		connector("backend", "backend", 8080),
+		connector("api", "api", 8081),
+		connector("database", "10.88.0.6", 5432),
+		connector("router", "default-skupper-router", 8080),
+		connector("stopped", "stopped", 8080),
+		connector("remote", "192.168.1.10", 8080),
+	})
+	assert.Assert(t, err)
+	assert.DeepEqual(t, client.inspected, []string{"c1", "c2", "c3"})
+	assert.Equal(t, len(processes), 3)
+
+	backend := processes[0]
+	assert.Equal(t, backend.ID, "c1")
+	assert.Equal(t, backend.StartTime.Time, created)
+	assert.Equal(t, *backend.Name, "backend")
+	assert.Equal(t, *backend.SourceHost, "backend")
+	assert.Equal(t, *backend.ImageName, "quay.io/example/backend:1.2")
+	assert.Equal(t, *backend.ImageVersion, "1.2")
+	assert.Equal(t, *backend.Group, "backend")
+	assert.Equal(t, *backend.Mode, modeExternal)
+
+	database := processes[1]
+	assert.Equal(t, database.ID, "c2")
+	assert.Equal(t, *database.SourceHost, "10.88.0.6")
+	assert.Equal(t, *database.Group, "inventory")
+	assert.Assert(t, database.ImageVersion == nil)
+
+	router := processes[2]
+	assert.Equal(t, *router.Group, "skupper")
+	assert.Equal(t, *router.Mode, modeInternal)
+}
+
+func TestSocketProcessResolver(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "stat"), "cpu  1 2 3\nbtime 1700000000\nprocesses 100\n")
+	writeFile(t, filepath.Join(root, "net", "tcp"), socketTable(
+		socketLine(net.IPv4(127, 0, 0, 1), 8080, "0A", "1111"),
+		socketLine(net.IPv4(10, 0, 0, 2), 9000, "0A", "3333"),
+		socketLine(net.IPv4(127, 0, 0, 1), 9999, "01", "4444"),
+	))
+	writeFile(t, filepath.Join(root, "net", "tcp6"), socketTable(
+		socketLine(net.IPv6unspecified, 9090, "0A", "2222"),
+	))
+	writeProcess(t, root, 42, "backend", 500, "1111")
+	writeProcess(t, root, 43, "skrouterd", 100, "2222", "5555")
+	writeProcess(t, root, 44, "other", 100, "3333")
+
+	resolver := &SocketProcessResolver{
+		ProcRoot: root,
+		Hostname: "vm1",
+		LocalAddresses: func() ([]net.IP, error) {
+			return []net.IP{net.IPv4(127, 0, 0, 1), net.IPv4(10, 0, 0, 1)}, nil
+		},
+	}
+	processes, err := resolver.Resolve([]*v2alpha1.Connector{
+		connector("backend", "localhost", 8080),
+		connector("router", "vm1", 9090),
+		connector("remote", "10.0.0.2", 9000),
+		connector("closed", "127.0.0.1", 9999),
+		connector("missing", "127.0.0.1", 7000),
+	})
+	assert.Assert(t, err)
+	sort.Slice(processes, func(i, j int) bool { return processes[i].ID < processes[j].ID })
+	assert.Equal(t, len(processes), 2)
+
+	backend := processes[0]
+	assert.Equal(t, backend.ID, "vm1:process:42:1700000005")
+	assert.Equal(t, backend.StartTime.Time, time.Unix(1700000005, 0))
+	assert.Equal(t, *backend.Name, "backend")
+	assert.Equal(t, *backend.Group, "backend")
+	assert.Equal(t, *backend.SourceHost, "localhost")
+	assert.Equal(t, *backend.Mode, modeExternal)
+
+	router := processes[1]
+	assert.Equal(t, router.ID, "vm1:process:43:1700000001")
+	assert.Equal(t, *router.SourceHost, "vm1")
+	assert.Equal(t, *router.Group, "skupper")
+	assert.Equal(t, *router.Mode, modeInternal)
+}
+
+func TestProcessController(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	router, err := fake.NewRouter(fake.Config{ID: "router-1", SiteID: "site-1"})
+	assert.Assert(t, err)
+	defer router.Close()
+	factory := session.NewContainerFactory(router.URL(), session.ContainerConfig{ContainerID: "test-processes"})
+
+	resolver := &stubResolver{processes: []vanflow.ProcessRecord{
+		{BaseRecord: vanflow.NewBase("p1", time.Now()), Name: ptr("backend"), SourceHost: ptr("backend")},
+	}}
+	controller := NewProcessController(ProcessControllerConfig{
+		Factory:  factory,
+		SiteID:   "site-1",
+		Platform: "podman",
+		Hostname: "vm1",
+		Resolver: resolver,
+		Connectors: func() ([]*v2alpha1.Connector, error) {
+			return []*v2alpha1.Connector{connector("backend", "backend", 8080)}, nil
+		},
+		Interval: 50 * time.Millisecond,
+	})
+	go controller.Run(ctx)
+
+	container := factory.Create()
+	container.Start(ctx)
+	records := make(chan vanflow.Record, 32)
+	client := eventsource.NewClient(container, eventsource.ClientOptions{
+		Source: eventsource.Info{ID: "site-1", Address: "mc/sfe.site-1", Direct: "sfe.site-1"},
+	})
+	client.OnRecord(func(msg vanflow.RecordMessage) {
+		for _, record := range msg.Records {
+			records <- record
+		}
+	})
+	assert.Assert(t, client.Listen(ctx, eventsource.FromSourceAddress()))
+	assert.Assert(t, client.SendFlush(ctx))
+
+	seen := map[string]vanflow.Record{}
+	for len(seen) < 2 {
+		select {
+		case record := <-records:
+			seen[record.Identity()] = record
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for records: %v", seen)
+		}
+	}
+	host, ok := seen["site-1:host:vm1"].(vanflow.HostRecord)
+	assert.Assert(t, ok)
+	assert.Equal(t, *host.Name, "vm1")
+	assert.Equal(t, *host.Provider, "podman")
+	process, ok := seen["p1"].(vanflow.ProcessRecord)
+	assert.Assert(t, ok)
+	assert.Equal(t, *process.Parent, "site-1")
+	assert.Equal(t, *process.Hostname, "vm1")
+	assert.Equal(t, *process.Mode, modeExternal)
+	assert.Equal(t, *process.SourceHost, "backend")
+
+	resolver.set(nil)
+	for {
+		select {
+		case record := <-records:
+			if process, ok := record.(vanflow.ProcessRecord); ok && process.ID == "p1" && process.EndTime != nil {
+				return
+			}
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for the process to end")
+		}
+	}
+}
+
+type fakeContainerClient struct {
+	containers []*container.Container
+	inspected  []string
+}
+
+func (c *fakeContainerClient) ContainerList() ([]*container.Container, error) {
+	return c.containers, nil
+}
+
+func (c *fakeContainerClient) ContainerInspect(id string) (*container.Container, error) {
+	c.inspected = append(c.inspected, id)
+	for _, ct := range c.containers {
+		if ct.ID == id {
+			return ct, nil
+		}
+	}
+	return nil, fmt.Errorf("container %s not found", id)
+}
+
+type stubResolver struct {
+	mu        sync.Mutex
+	processes []vanflow.ProcessRecord
+}
+
+func (r *stubResolver) Resolve([]*v2alpha1.Connector) ([]vanflow.ProcessRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.processes, nil
+}
+
+func (r *stubResolver) set(processes []vanflow.ProcessRecord) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.processes = processes
+}
+
+func connector(name string, host string, port int) *v2alpha1.Connector {
+	return &v2alpha1.Connector{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v2alpha1.ConnectorSpec{
+			Host: host,
+			Port: port,
+		},
+	}
+}
+
+func socketTable(lines ...string) string {
+	table := "  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n"
+	for _, line := range lines {
+		table += line + "\n"
+	}
+	return table
+}
+
+func socketLine(ip net.IP, port int, state string, inode string) string {
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+	var addr string
+	for i := 0; i < len(ip); i += 4 {
+		addr += fmt.Sprintf("%08X", binary.NativeEndian.Uint32(ip[i:]))
+	}
+	remote := "00000000"
+	if len(ip) == net.IPv6len {
+		remote = "00000000000000000000000000000000"
+	}
+	return fmt.Sprintf("   0: %s:%04X %s:0000 %s 00000000:00000000 00:00000000 00000000  1000        0 %s 1 0000000000000000 100 0 0 10 0",
+		addr, port, remote, state, inode)
+}
+
+func writeProcess(t *testing.T, root string, pid int, comm string, startTicks int, inodes ...string) {
+	t.Helper()
+	dir := filepath.Join(root, fmt.Sprint(pid))
+	writeFile(t, filepath.Join(dir, "comm"), comm+"\n")
+	writeFile(t, filepath.Join(dir, "stat"), fmt.Sprintf("%d (%s) S 1 %d %d 0 -1 4194560 100 0 0 0 1 1 0 0 20 0 1 0 %d 1000 100", pid, comm, pid, pid, startTicks))
+	assert.Assert(t, os.MkdirAll(filepath.Join(dir, "fd"), 0755))
+	for i, inode := range inodes {
+		assert.Assert(t, os.Symlink(fmt.Sprintf("socket:[%s]", inode), filepath.Join(dir, "fd", fmt.Sprint(i+3))))
+	}
+}
+
+func writeFile(t *testing.T, name string, content string) {
+	t.Helper()
+	assert.Assert(t, os.MkdirAll(filepath.Dir(name), 0755))
+	assert.Assert(t, os.WriteFile(name, []byte(content), 0644))
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
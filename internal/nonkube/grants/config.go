@@ -0,0 +1,142 @@
+package grants
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+
+	"github.com/skupperproject/skupper/internal/nonkube/secrets"
+	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+	"github.com/skupperproject/skupper/pkg/nonkube/api"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ServerPortSetting is the Site setting for the port the grant
+	// server of the site listens on
+	ServerPortSetting = "grant-server-port"
+	DefaultServerPort = 9090
+
+	defaultIssuer = "skupper-site-ca"
+)
+
+// ErrNoLinkAccess is returned for sites that cannot be linked to, which
+// have no use for a grant server
+var ErrNoLinkAccess = errors.New("site has no link access")
+
+var hostname = os.Hostname
+
+// Config describes the grant server of a site, which is reached on the
+// same host as the first RouterAccess with a link access role, and
+// issues links to that RouterAccess.
+type Config struct {
+	Host         string
+	Port         int
+	RouterAccess *v2alpha1.RouterAccess
+	Issuer       string
+}
+
+// ServerPort returns the port set for the grant server of a site
+func ServerPort(settings map[string]string) (int, error) {
+	value, ok := settings[ServerPortSetting]
+	if !ok {
+		return DefaultServerPort, nil
+	}
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf("invalid %s %q, must be a port number", ServerPortSetting, value)
+	}
+	return port, nil
+}
+
+// ConfigFromSiteState returns the configuration of the grant server for a
+// site, or ErrNoLinkAccess if the site has no link access
+func ConfigFromSiteState(siteState *api.SiteState) (*Config, error) {
+	if siteState.Site == nil {
+		return nil, fmt.Errorf("no site defined")
+	}
+	port, err := ServerPort(siteState.Site.Spec.Settings)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range slices.Sorted(maps.Keys(siteState.RouterAccesses)) {
+		routerAccess := siteState.RouterAccesses[name]
+		if routerAccess.FindRole("inter-router") == nil && routerAccess.FindRole("edge") == nil {
+			continue
+		}
+		config := &Config{
+			Port:         port,
+			RouterAccess: routerAccess,
+			Issuer:       routerAccess.Spec.Issuer,
+		}
+		if config.Issuer == "" {
+			config.Issuer = defaultIssuer
+		}
+		if config.Host, err = serverHost(routerAccess); err != nil {
+			return nil, err
+		}
+		return config, nil
+	}
+	return nil, ErrNoLinkAccess
+}
+
+// serverHost returns the host that remote sites use to reach the
+// RouterAccess, which is the first of its subject alternative names,
+// or the host it is bound to, or else the host name
+func serverHost(routerAccess *v2alpha1.RouterAccess) (string, error) {
+	if len(routerAccess.Spec.SubjectAlternativeNames) > 0 {
+		return routerAccess.Spec.SubjectAlternativeNames[0], nil
+	}
+	if host := routerAccess.Spec.BindHost; host != "" {
+		if ip := net.ParseIP(host); ip == nil || !ip.IsUnspecified() {
+			return host, nil
+		}
+	}
+	host, err := hostname()
+	if err != nil {
+		return "", fmt.Errorf("unable to determine host of the grant server: %w", err)
+	}
+	return host, nil
+}
+
+// Address returns the address the grant server listens on
+func (c *Config) Address() string {
+	return net.JoinHostPort(c.RouterAccess.Spec.BindHost, strconv.Itoa(c.Port))
+}
+
+// Url returns the host and port remote sites use to reach the grant
+// server
+func (c *Config) Url() string {
+	return net.JoinHostPort(c.Host, strconv.Itoa(c.Port))
+}
+
+// LoadIssuer reads the CA used to issue links to the RouterAccess from the
+// runtime issuers of a namespace
+func (c *Config) LoadIssuer(namespace string, store secrets.Store) (*corev1.Secret, error) {
+	issuerPath := path.Join(api.GetInternalOutputPath(namespace, api.IssuersPath), c.Issuer)
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Secret",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      c.Issuer,
+			Namespace: namespace,
+		},
+		Data: map[string][]byte{},
+	}
+	for _, key := range []string{"tls.crt", "tls.key"} {
+		data, err := secrets.ReadFile(store, path.Join(issuerPath, key))
+		if err != nil {
+			return nil, fmt.Errorf("unable to load issuer %s: %w", c.Issuer, err)
+		}
+		secret.Data[key] = data
+	}
+	return secret, nil
+}
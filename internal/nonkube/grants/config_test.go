@@ -0,0 +1,135 @@
+package grants
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/skupperproject/skupper/internal/certs"
+	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+	"github.com/skupperproject/skupper/pkg/nonkube/api"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTestRouterAccess(name string, spec v2alpha1.RouterAccessSpec) *v2alpha1.RouterAccess {
+	return &v2alpha1.RouterAccess{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       spec,
+	}
+}
+
+func TestConfigFromSiteState(t *testing.T) {
+	hostname = func() (string, error) { return "my-host", nil }
+	defer func() { hostname = os.Hostname }()
+	linkRoles := []v2alpha1.RouterAccessRole{{Name: "inter-router", Port: 55671}, {Name: "edge", Port: 45671}}
+	tests := []struct {
+		name            string
+		settings        map[string]string
+		routerAccesses  []*v2alpha1.RouterAccess
+		expectedError   string
+		expectedConfig  *Config
+		expectedAddress string
+	}{
+		{
+			name: "subject-alternative-names",
+			routerAccesses: []*v2alpha1.RouterAccess{
+				newTestRouterAccess("skupper-local", v2alpha1.RouterAccessSpec{Roles: []v2alpha1.RouterAccessRole{{Name: "normal", Port: 5671}}}),
+				newTestRouterAccess("link-access", v2alpha1.RouterAccessSpec{Roles: linkRoles, BindHost: "0.0.0.0", SubjectAlternativeNames: []string{"site.example.com"}}),
+			},
+			expectedConfig: &Config{
+				Host:   "site.example.com",
+				Port:   DefaultServerPort,
+				Issuer: "skupper-site-ca",
+			},
+			expectedAddress: "0.0.0.0:9090",
+		},
+		{
+			name:     "bind-host-and-issuer",
+			settings: map[string]string{ServerPortSetting: "8443"},
+			routerAccesses: []*v2alpha1.RouterAccess{
+				newTestRouterAccess("link-access", v2alpha1.RouterAccessSpec{Roles: linkRoles, BindHost: "10.0.0.1", Issuer: "my-ca"}),
+			},
+			expectedConfig: &Config{
+				Host:   "10.0.0.1",
+				Port:   8443,
+				Issuer: "my-ca",
+			},
+			expectedAddress: "10.0.0.1:8443",
+		},
+		{
+			name: "host-name",
+			routerAccesses: []*v2alpha1.RouterAccess{
+				newTestRouterAccess("link-access", v2alpha1.RouterAccessSpec{Roles: linkRoles}),
+			},
+			expectedConfig: &Config{
+				Host:   "my-host",
+				Port:   DefaultServerPort,
+				Issuer: "skupper-site-ca",
+			},
+			expectedAddress: ":9090",
+		},
+		{
+			name: "no-link-access",
+			routerAccesses: []*v2alpha1.RouterAccess{
+				newTestRouterAccess("skupper-local", v2alpha1.RouterAccessSpec{Roles: []v2alpha1.RouterAccessRole{{Name: "normal", Port: 5671}}}),
+			},
+			expectedError: ErrNoLinkAccess.Error(),
+		},
+		{
+			name:          "invalid-port",
+			settings:      map[string]string{ServerPortSetting: "https"},
+			expectedError: `invalid grant-server-port "https", must be a port number`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			siteState := &api.SiteState{
+				Site: &v2alpha1.Site{
+					ObjectMeta: metav1.ObjectMeta{Name: "my-site"},
+					Spec:       v2alpha1.SiteSpec{Settings: tt.settings},
+				},
+				RouterAccesses: map[string]*v2alpha1.RouterAccess{},
+			}
+			for _, routerAccess := range tt.routerAccesses {
+				siteState.RouterAccesses[routerAccess.Name] = routerAccess
+			}
+			config, err := ConfigFromSiteState(siteState)
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			assert.Assert(t, err)
+			assert.Equal(t, config.RouterAccess.Name, "link-access")
+			assert.Equal(t, config.Address(), tt.expectedAddress)
+			config.RouterAccess = nil
+			assert.DeepEqual(t, config, tt.expectedConfig)
+		})
+	}
+}
+
+func TestConfigLoadIssuer(t *testing.T) {
+	tempDir := t.TempDir()
+	if os.Getuid() == 0 {
+		api.DefaultRootDataHome = tempDir
+	} else {
+		t.Setenv("XDG_DATA_HOME", tempDir)
+	}
+	ca, err := certs.GenerateSecret("skupper-site-ca", "skupper-site-ca", "", 0, nil)
+	assert.Assert(t, err)
+	issuerPath := path.Join(api.GetInternalOutputPath("default", api.IssuersPath), "skupper-site-ca")
+	assert.Assert(t, os.MkdirAll(issuerPath, 0755))
+	for _, key := range []string{"tls.crt", "tls.key"} {
+		assert.Assert(t, os.WriteFile(path.Join(issuerPath, key), ca.Data[key], 0644))
+	}
+
+	config := &Config{Issuer: "skupper-site-ca"}
+	secret, err := config.LoadIssuer("default", nil)
+	assert.Assert(t, err)
+	assert.DeepEqual(t, secret.Data["tls.crt"], ca.Data["tls.crt"])
+	assert.DeepEqual(t, secret.Data["tls.key"], ca.Data["tls.key"])
+
+	config.Issuer = "missing-ca"
+	_, err = config.LoadIssuer("default", nil)
+	assert.ErrorContains(t, err, "unable to load issuer missing-ca")
+}
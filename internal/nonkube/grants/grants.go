@@ -0,0 +1,241 @@
+// Package grants serves the AccessGrants of non-kubernetes sites, so that
+// the AccessTokens issued for them can be redeemed by remote sites for a
+// link, as the controller does for sites running on kubernetes.
+package grants
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/skupperproject/skupper/internal/utils"
+	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	defaultExpirationWindow = 10 * time.Minute
+	codeLength              = 24
+)
+
+// GrantResponse writes the link and credentials issued for a redeemed
+// AccessGrant
+type GrantResponse func(name string, subject string, writer io.Writer) error
+
+// StatusUpdater persists the status of an AccessGrant
+type StatusUpdater func(grant *v2alpha1.AccessGrant) error
+
+// Grants holds the AccessGrants of a site by UID, resolving their status
+// and redeeming them through ServeHTTP.
+type Grants struct {
+	generator  GrantResponse
+	update     StatusUpdater
+	url        string
+	ca         string
+	grants     map[types.UID]*v2alpha1.AccessGrant
+	grantIndex map[string]types.UID
+	lock       sync.Mutex
+	logger     *slog.Logger
+	now        func() time.Time
+}
+
+// NewGrants returns the Grants served on url, which is the host and port
+// of the grant server, verified by clients using the ca
+func NewGrants(url string, ca string, generator GrantResponse, update StatusUpdater) *Grants {
+	return &Grants{
+		generator:  generator,
+		update:     update,
+		url:        url,
+		ca:         ca,
+		grants:     map[types.UID]*v2alpha1.AccessGrant{},
+		grantIndex: map[string]types.UID{},
+		logger:     slog.Default().With("component", "nonkube.grants"),
+		now:        time.Now,
+	}
+}
+
+// Update records the AccessGrants defined for the site, resolving the
+// status of those that have changed, and forgets any other grant.
+func (g *Grants) Update(grants map[string]*v2alpha1.AccessGrant) error {
+	var errs []string
+	for name, grant := range grants {
+		if err := g.checkGrant(grant); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", name, err))
+		}
+	}
+	g.lock.Lock()
+	for name, uid := range g.grantIndex {
+		if _, ok := grants[name]; !ok {
+			delete(g.grantIndex, name)
+			delete(g.grants, uid)
+		}
+	}
+	g.lock.Unlock()
+	if len(errs) > 0 {
+		return fmt.Errorf("unable to update status of AccessGrants: %s", strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// record must be called with the lock held
+func (g *Grants) record(grant *v2alpha1.AccessGrant) {
+	if uid, ok := g.grantIndex[grant.Name]; ok && uid != grant.UID {
+		delete(g.grants, uid)
+	}
+	// the grant may have been read before the latest redemption
+	// was persisted
+	if existing, ok := g.grants[grant.UID]; ok && existing.Status.Redemptions > grant.Status.Redemptions {
+		grant.Status.Redemptions = existing.Status.Redemptions
+	}
+	g.grantIndex[grant.Name] = grant.UID
+	g.grants[grant.UID] = grant
+}
+
+func (g *Grants) claimUrl(grant *v2alpha1.AccessGrant) string {
+	return fmt.Sprintf("https://%s/%s", g.url, string(grant.UID))
+}
+
+func (g *Grants) checkGrant(grant *v2alpha1.AccessGrant) error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	changed := false
+	if grant.UID == "" {
+		grant.UID = types.UID(uuid.New().String())
+		changed = true
+	}
+	g.record(grant)
+
+	if url := g.claimUrl(grant); grant.Status.Url != url {
+		g.logger.Info("Setting URL for AccessGrant", slog.String("name", grant.Name), slog.String("url", url))
+		grant.Status.Url = url
+		changed = true
+	}
+	if grant.Status.Ca != g.ca {
+		grant.Status.Ca = g.ca
+		changed = true
+	}
+	if grant.Status.Code == "" {
+		if grant.Spec.Code == "" {
+			grant.Status.Code = utils.RandomId(codeLength)
+		} else {
+			grant.Status.Code = grant.Spec.Code
+		}
+		changed = true
+	}
+
+	var err error
+	if grant.Status.ExpirationTime == "" {
+		window := defaultExpirationWindow
+		if grant.Spec.ExpirationWindow != "" {
+			window, err = time.ParseDuration(grant.Spec.ExpirationWindow)
+			if err != nil {
+				err = fmt.Errorf("Invalid duration %q: %s", grant.Spec.ExpirationWindow, err)
+			}
+		}
+		if err == nil {
+			grant.Status.ExpirationTime = g.now().Add(window).Format(time.RFC3339)
+			changed = true
+		}
+	}
+	if grant.SetProcessed(err) {
+		changed = true
+	}
+	if grant.SetResolved() {
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return g.update(grant)
+}
+
+func (g *Grants) checkAndUpdateAccessToken(key string, data []byte) (*v2alpha1.AccessGrant, *httpError) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	grant, ok := g.grants[types.UID(key)]
+	if !ok {
+		return nil, newHttpError("No such claim", http.StatusNotFound)
+	}
+	logger := g.logger.With(slog.String("name", grant.Name))
+	expiration, err := time.Parse(time.RFC3339, grant.Status.ExpirationTime)
+	if err != nil {
+		logger.Error("Cannot determine expiration of AccessGrant", slog.Any("error", err))
+		return nil, newHttpError("Corrupted claim", http.StatusInternalServerError)
+	}
+	if expiration.Before(g.now()) {
+		logger.Info("AccessGrant expired")
+		return nil, newHttpError("No such claim", http.StatusNotFound)
+	}
+	if grant.Spec.RedemptionsAllowed <= grant.Status.Redemptions {
+		logger.Info("AccessGrant already redeemed")
+		return nil, newHttpError("No such access granted", http.StatusNotFound)
+	}
+	if subtle.ConstantTimeCompare([]byte(grant.Status.Code), data) != 1 {
+		return nil, newHttpError("Redemption of access token refused", http.StatusForbidden)
+	}
+	grant.Status.Redemptions += 1
+	if err = g.update(grant); err != nil {
+		grant.Status.Redemptions -= 1
+		logger.Error("Error updating AccessGrant", slog.Any("error", err))
+		return nil, newHttpError("Internal error", http.StatusServiceUnavailable)
+	}
+	return grant, nil
+}
+
+func (g *Grants) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		g.logger.Info("Bad method", slog.String("method", r.Method), slog.String("path", r.URL.Path))
+		http.Error(w, "Only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	key := strings.Join(strings.Split(r.URL.Path, "/"), "")
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		g.logger.Error("Error reading request body", slog.String("path", r.URL.Path), slog.Any("error", err))
+		http.Error(w, "Request body not valid", http.StatusBadRequest)
+		return
+	}
+
+	grant, e := g.checkAndUpdateAccessToken(key, body)
+	if e != nil {
+		e.write(w)
+		return
+	}
+
+	name := r.Header.Get("name")
+	if name == "" {
+		name = grant.Name
+	}
+	subject := r.Header.Get("subject")
+	if subject == "" {
+		subject = name
+	}
+	if err := g.generator(name, subject, w); err != nil {
+		g.logger.Error("Failed to create token", slog.String("name", grant.Name), slog.Any("error", err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	g.logger.Info("Redemption of access token succeeded", slog.String("name", grant.Name))
+}
+
+type httpError struct {
+	text string
+	code int
+}
+
+func (e *httpError) write(w http.ResponseWriter) {
+	http.Error(w, e.text, e.code)
+}
+
+func newHttpError(text string, code int) *httpError {
+	return &httpError{
+		text: text,
+		code: code,
+	}
+}
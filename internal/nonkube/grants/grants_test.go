@@ -0,0 +1,169 @@
+package grants
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func newTestGrants(t *testing.T, now time.Time) (*Grants, map[string]int) {
+	t.Helper()
+	updates := map[string]int{}
+	generator := func(name string, subject string, writer io.Writer) error {
+		_, err := fmt.Fprintf(writer, "%s/%s", name, subject)
+		return err
+	}
+	update := func(grant *v2alpha1.AccessGrant) error {
+		updates[grant.Name]++
+		return nil
+	}
+	g := NewGrants("10.0.0.1:9090", "my-ca", generator, update)
+	g.now = func() time.Time { return now }
+	return g, updates
+}
+
+func newTestGrant(name string, spec v2alpha1.AccessGrantSpec) *v2alpha1.AccessGrant {
+	return &v2alpha1.AccessGrant{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       spec,
+	}
+}
+
+func TestGrantsUpdate(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	g, updates := newTestGrants(t, now)
+
+	defaults := newTestGrant("defaults", v2alpha1.AccessGrantSpec{RedemptionsAllowed: 1})
+	custom := newTestGrant("custom", v2alpha1.AccessGrantSpec{RedemptionsAllowed: 2, ExpirationWindow: "1h", Code: "secret"})
+	invalid := newTestGrant("invalid", v2alpha1.AccessGrantSpec{RedemptionsAllowed: 1, ExpirationWindow: "forever"})
+	assert.Assert(t, g.Update(map[string]*v2alpha1.AccessGrant{
+		"defaults": defaults,
+		"custom":   custom,
+		"invalid":  invalid,
+	}))
+	assert.DeepEqual(t, updates, map[string]int{"defaults": 1, "custom": 1, "invalid": 1})
+
+	assert.Assert(t, defaults.UID != "")
+	assert.Equal(t, defaults.Status.Url, "https://10.0.0.1:9090/"+string(defaults.UID))
+	assert.Equal(t, defaults.Status.Ca, "my-ca")
+	assert.Equal(t, len(defaults.Status.Code), codeLength)
+	assert.Equal(t, defaults.Status.ExpirationTime, now.Add(defaultExpirationWindow).Format(time.RFC3339))
+	assert.Assert(t, defaults.IsReady())
+
+	assert.Equal(t, custom.Status.Code, "secret")
+	assert.Equal(t, custom.Status.ExpirationTime, now.Add(time.Hour).Format(time.RFC3339))
+	assert.Assert(t, custom.IsReady())
+
+	assert.Equal(t, invalid.Status.ExpirationTime, "")
+	assert.Assert(t, !invalid.IsReady())
+
+	// grants read back with their status are not updated again
+	reloaded := defaults.DeepCopy()
+	assert.Assert(t, g.Update(map[string]*v2alpha1.AccessGrant{
+		"defaults": reloaded,
+		"custom":   custom,
+	}))
+	assert.DeepEqual(t, updates, map[string]int{"defaults": 1, "custom": 1, "invalid": 1})
+	assert.Equal(t, len(g.grants), 2)
+	assert.DeepEqual(t, g.grantIndex, map[string]types.UID{
+		"defaults": defaults.UID,
+		"custom":   custom.UID,
+	})
+}
+
+func TestGrantsServeHTTP(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name                string
+		method              string
+		spec                v2alpha1.AccessGrantSpec
+		redemptions         int
+		code                string
+		path                string
+		later               time.Duration
+		expectedStatus      int
+		expectedBody        string
+		expectedRedemptions int
+	}{
+		{
+			name:                "redeemed",
+			spec:                v2alpha1.AccessGrantSpec{RedemptionsAllowed: 1, Code: "secret"},
+			code:                "secret",
+			expectedStatus:      http.StatusOK,
+			expectedBody:        "my-link/my-site",
+			expectedRedemptions: 1,
+		},
+		{
+			name:           "wrong method",
+			method:         http.MethodGet,
+			spec:           v2alpha1.AccessGrantSpec{RedemptionsAllowed: 1, Code: "secret"},
+			code:           "secret",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:           "unknown grant",
+			spec:           v2alpha1.AccessGrantSpec{RedemptionsAllowed: 1, Code: "secret"},
+			code:           "secret",
+			path:           "/unknown",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "wrong code",
+			spec:           v2alpha1.AccessGrantSpec{RedemptionsAllowed: 1, Code: "secret"},
+			code:           "guess",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "expired",
+			spec:           v2alpha1.AccessGrantSpec{RedemptionsAllowed: 1, Code: "secret", ExpirationWindow: "5m"},
+			code:           "secret",
+			later:          10 * time.Minute,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:                "already redeemed",
+			spec:                v2alpha1.AccessGrantSpec{RedemptionsAllowed: 2, Code: "secret"},
+			redemptions:         2,
+			code:                "secret",
+			expectedStatus:      http.StatusNotFound,
+			expectedRedemptions: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, _ := newTestGrants(t, now)
+			grant := newTestGrant("my-grant", tt.spec)
+			grant.Status.Redemptions = tt.redemptions
+			assert.Assert(t, g.Update(map[string]*v2alpha1.AccessGrant{"my-grant": grant}))
+			g.now = func() time.Time { return now.Add(tt.later) }
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodPost
+			}
+			path := tt.path
+			if path == "" {
+				path = "/" + string(grant.UID)
+			}
+			request := httptest.NewRequest(method, path, bytes.NewReader([]byte(tt.code)))
+			request.Header.Add("name", "my-link")
+			request.Header.Add("subject", "my-site")
+			recorder := httptest.NewRecorder()
+			g.ServeHTTP(recorder, request)
+			assert.Equal(t, recorder.Code, tt.expectedStatus)
+			if tt.expectedBody != "" {
+				assert.Equal(t, recorder.Body.String(), tt.expectedBody)
+			}
+			assert.Equal(t, grant.Status.Redemptions, tt.expectedRedemptions)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package grants
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/skupperproject/skupper/internal/utils/tlscfg"
+)
+
+// Server serves AccessGrant redemptions over HTTPS
+type Server struct {
+	server   *http.Server
+	listener net.Listener
+	logger   *slog.Logger
+}
+
+func NewServer(addr string, cert tls.Certificate, handler http.Handler) *Server {
+	tlsConfig := tlscfg.Modern()
+	tlsConfig.Certificates = []tls.Certificate{cert}
+	return &Server{
+		server: &http.Server{
+			Addr:         addr,
+			Handler:      handler,
+			ReadTimeout:  60 * time.Second,
+			WriteTimeout: 60 * time.Second,
+			TLSConfig:    tlsConfig,
+		},
+		logger: slog.Default().With("component", "nonkube.grants.server"),
+	}
+}
+
+// Start listens on the address of the server and serves requests
+// until the server is stopped
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return fmt.Errorf("grant server failed to listen on %s: %w", s.server.Addr, err)
+	}
+	s.logger.Info("Grant server listening", slog.String("address", listener.Addr().String()))
+	s.listener = listener
+	go func() {
+		if err := s.server.ServeTLS(listener, "", ""); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("Grant server failed", slog.Any("error", err))
+		}
+	}()
+	return nil
+}
+
+func (s *Server) Stop() error {
+	return s.server.Close()
+}
+
+// Port returns the port the server listens on, or 0 if it has not been
+// started
+func (s *Server) Port() int {
+	if s.listener == nil {
+		return 0
+	}
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
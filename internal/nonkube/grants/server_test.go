@@ -0,0 +1,88 @@
+package grants
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/skupperproject/skupper/internal/certs"
+	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+func TestServerRedeem(t *testing.T) {
+	ca, err := certs.GenerateSecret("skupper-site-ca", "skupper-site-ca", "", 0, nil)
+	assert.Assert(t, err)
+	config := &Config{
+		Host: "127.0.0.1",
+		RouterAccess: newTestRouterAccess("link-access", v2alpha1.RouterAccessSpec{
+			BindHost: "127.0.0.1",
+			Roles: []v2alpha1.RouterAccessRole{
+				{Name: "inter-router", Port: 55671},
+				{Name: "edge", Port: 45671},
+			},
+		}),
+		Issuer: "skupper-site-ca",
+	}
+	generator := NewTokenGenerator(config, &ca)
+	cert, err := generator.ServerCertificate()
+	assert.Assert(t, err)
+
+	var grants *Grants
+	server := NewServer(config.Address(), cert, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		grants.ServeHTTP(w, r)
+	}))
+	assert.Assert(t, server.Start())
+	defer server.Stop()
+	config.Port = server.Port()
+	grants = NewGrants(config.Url(), generator.CA(), generator.Generate, func(grant *v2alpha1.AccessGrant) error { return nil })
+	grant := newTestGrant("my-grant", v2alpha1.AccessGrantSpec{RedemptionsAllowed: 1, ExpirationWindow: "1h"})
+	assert.Assert(t, grants.Update(map[string]*v2alpha1.AccessGrant{"my-grant": grant}))
+	assert.Equal(t, grant.Status.Url, fmt.Sprintf("https://127.0.0.1:%d/%s", config.Port, grant.UID))
+
+	caPool := x509.NewCertPool()
+	assert.Assert(t, caPool.AppendCertsFromPEM([]byte(grant.Status.Ca)))
+	client := &http.Client{
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: caPool}},
+		Timeout:   10 * time.Second,
+	}
+	redeem := func() *http.Response {
+		request, err := http.NewRequest(http.MethodPost, grant.Status.Url, bytes.NewReader([]byte(grant.Status.Code)))
+		assert.Assert(t, err)
+		request.Header.Add("name", "my-token")
+		request.Header.Add("subject", "remote-site")
+		response, err := client.Do(request)
+		assert.Assert(t, err)
+		return response
+	}
+
+	response := redeem()
+	defer response.Body.Close()
+	assert.Equal(t, response.StatusCode, http.StatusOK)
+	decoder := yaml.NewYAMLOrJSONDecoder(response.Body, 1024)
+	var secret corev1.Secret
+	assert.Assert(t, decoder.Decode(&secret))
+	assert.Equal(t, secret.Name, "my-token")
+	assert.DeepEqual(t, secret.Data["ca.crt"], ca.Data["tls.crt"])
+	var link v2alpha1.Link
+	assert.Assert(t, decoder.Decode(&link))
+	assert.Equal(t, link.Name, "my-token")
+	assert.Equal(t, link.Spec.TlsCredentials, "my-token")
+	assert.DeepEqual(t, link.Spec.Endpoints, []v2alpha1.Endpoint{
+		{Name: "inter-router", Host: "127.0.0.1", Port: "55671"},
+		{Name: "edge", Host: "127.0.0.1", Port: "45671"},
+	})
+	assert.Equal(t, decoder.Decode(&link), io.EOF)
+
+	// the grant allows a single redemption
+	response = redeem()
+	defer response.Body.Close()
+	assert.Equal(t, response.StatusCode, http.StatusNotFound)
+}
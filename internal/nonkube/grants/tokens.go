@@ -0,0 +1,101 @@
+package grants
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/skupperproject/skupper/internal/certs"
+	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+	"github.com/skupperproject/skupper/pkg/nonkube/api"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const serverCertificateName = "skupper-grant-server"
+
+// TokenGenerator issues links to the RouterAccess of a grant server, with
+// client certificates signed by the issuer of the RouterAccess.
+type TokenGenerator struct {
+	ca        *corev1.Secret
+	host      string
+	endpoints []v2alpha1.Endpoint
+}
+
+func NewTokenGenerator(config *Config, ca *corev1.Secret) *TokenGenerator {
+	generator := &TokenGenerator{
+		ca:   ca,
+		host: config.Host,
+	}
+	// links use the host the grant server has been reached on
+	for _, role := range config.RouterAccess.Spec.Roles {
+		if role.Name != "inter-router" && role.Name != "edge" {
+			continue
+		}
+		generator.endpoints = append(generator.endpoints, v2alpha1.Endpoint{
+			Name: role.Name,
+			Host: config.Host,
+			Port: strconv.Itoa(role.Port),
+		})
+	}
+	return generator
+}
+
+// CA returns the certificate of the issuer, which clients use to verify
+// the grant server
+func (g *TokenGenerator) CA() string {
+	return string(g.ca.Data["tls.crt"])
+}
+
+// ServerCertificate issues the certificate of the grant server
+func (g *TokenGenerator) ServerCertificate() (tls.Certificate, error) {
+	secret, err := certs.GenerateSecret(serverCertificateName, g.host, g.host, 0, g.ca)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.X509KeyPair(secret.Data["tls.crt"], secret.Data["tls.key"])
+}
+
+// NewCertToken issues a link with a new client certificate
+func (g *TokenGenerator) NewCertToken(name string, subject string) (*api.Token, error) {
+	if len(g.endpoints) == 0 {
+		return nil, fmt.Errorf("Could not resolve any endpoints for requested link")
+	}
+	secret, err := certs.GenerateSecret(name, subject, g.host, 0, g.ca)
+	if err != nil {
+		return nil, err
+	}
+	return &api.Token{
+		Links: []*v2alpha1.Link{
+			{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "skupper.io/v2alpha1",
+					Kind:       "Link",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name: name,
+				},
+				Spec: v2alpha1.LinkSpec{
+					Endpoints:      g.endpoints,
+					TlsCredentials: name,
+				},
+			},
+		},
+		Secret: &secret,
+	}, nil
+}
+
+// Generate writes a new link, it is the GrantResponse of the grant server
+func (g *TokenGenerator) Generate(name string, subject string, writer io.Writer) error {
+	token, err := g.NewCertToken(name, subject)
+	if err != nil {
+		return err
+	}
+	data, err := token.Marshal()
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(data)
+	return err
+}
@@ -7,11 +7,14 @@ import (
 
 	"github.com/skupperproject/skupper/api/types"
 	"github.com/skupperproject/skupper/internal/nonkube/common"
+	"github.com/skupperproject/skupper/internal/nonkube/secrets"
 	"github.com/skupperproject/skupper/internal/utils"
 	"github.com/skupperproject/skupper/pkg/nonkube/api"
 )
 
 type SiteStateRenderer struct {
+	// SecretStore encrypts the secrets of the site, when enabled
+	SecretStore     secrets.Store
 	loadedSiteState *api.SiteState
 	siteState       *api.SiteState
 	configRenderer  *common.FileSystemConfigurationRenderer
@@ -84,10 +87,16 @@ func (s *SiteStateRenderer) Render(loadedSiteState *api.SiteState, reload bool)
 	s.siteState.CreateLinkAccessesCertificates()
 	s.siteState.CreateBridgeCertificates()
 	// rendering non-kube configuration files and certificates
-	siteHome := api.GetHostSiteHome(s.siteState.Site)
+	sslProfileBasePath := api.GetHostSiteHome(s.siteState.Site)
+	if s.SecretStore != nil && s.SecretStore.Enabled() {
+		// certificates are decrypted by the systemd service before
+		// the router starts
+		sslProfileBasePath = api.GetUnsealedOutputPath(s.siteState.GetNamespace())
+	}
 	s.configRenderer = &common.FileSystemConfigurationRenderer{
-		SslProfileBasePath: siteHome,
+		SslProfileBasePath: sslProfileBasePath,
 		Platform:           string(types.PlatformLinux),
+		SecretStore:        s.SecretStore,
 	}
 	err = s.configRenderer.Render(s.siteState)
 	if err != nil {
@@ -98,6 +107,9 @@ func (s *SiteStateRenderer) Render(loadedSiteState *api.SiteState, reload bool)
 	if err = s.configRenderer.MarshalSiteStates(loadedSiteState, s.siteState); err != nil {
 		return err
 	}
+	if err = secrets.SaveConfig(s.siteState.GetNamespace(), secrets.ConfigFromSettings(s.siteState.Site.Spec.Settings)); err != nil {
+		return fmt.Errorf("failed to save secrets configuration: %w", err)
+	}
 	// Create systemd service
 	if err = s.createSystemdService(); err != nil {
 		return err
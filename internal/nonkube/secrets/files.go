@@ -0,0 +1,200 @@
+package secrets
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/skupperproject/skupper/pkg/nonkube/api"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+// IsSecretFile returns true for the files holding private keys, which
+// are the only files sealed among certificates
+func IsSecretFile(name string) bool {
+	return strings.HasSuffix(name, ".key")
+}
+
+// secretResourceTypes are the resource types of a site state holding
+// secrets: private keys, link credentials and grant redemption codes
+var secretResourceTypes = []string{"Secret", "AccessToken", "AccessGrant"}
+
+// IsSecretResource returns true for the files holding the Secret,
+// AccessToken and AccessGrant resources of a site state
+func IsSecretResource(name string) bool {
+	base := filepath.Base(name)
+	for _, resourceType := range secretResourceTypes {
+		if strings.HasPrefix(base, resourceType+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+// Sealer returns an api.ResourceSealer that seals the secret resources
+// of a site state using the given store, which may be nil for sites
+// that do not encrypt secrets
+func Sealer(store Store) api.ResourceSealer {
+	return func(resourceType string, data []byte) ([]byte, error) {
+		if store == nil || !store.Enabled() || !slices.Contains(secretResourceTypes, resourceType) {
+			return data, nil
+		}
+		return store.Seal(data)
+	}
+}
+
+// MarshalSiteState writes a site state to a directory, sealing its
+// secret resources before they are written
+func MarshalSiteState(store Store, siteState api.SiteState, dir string) error {
+	return api.MarshalSealedSiteState(siteState, dir, Sealer(store))
+}
+
+// MarshalResource writes a single resource of a site state to a
+// directory, sealing it before it is written if it holds secrets
+func MarshalResource(store Store, dir string, resourceType string, resourceName string, resource runtime.Object) error {
+	return api.MarshalSealedResource(dir, resourceType, resourceName, resource, Sealer(store))
+}
+
+// WriteFile seals the data using the given store, which may be nil for
+// sites that do not encrypt secrets, before writing it
+func WriteFile(store Store, name string, data []byte, perm os.FileMode) error {
+	if store == nil {
+		store = plainStore{}
+	}
+	sealed, err := store.Seal(data)
+	if err != nil {
+		return fmt.Errorf("unable to encrypt %s: %w", name, err)
+	}
+	return os.WriteFile(name, sealed, perm)
+}
+
+// ReadFile reads a file that may have been sealed, opening it using the
+// given store, which may be nil for sites that do not encrypt secrets
+func ReadFile(store Store, name string) ([]byte, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	if store == nil {
+		store = plainStore{}
+	}
+	opened, err := store.Open(data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt %s: %w", name, err)
+	}
+	return opened, nil
+}
+
+// SealFile seals an existing file in place, if not yet sealed
+func SealFile(store Store, name string) error {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	if IsSealed(data) {
+		return nil
+	}
+	info, err := os.Stat(name)
+	if err != nil {
+		return err
+	}
+	return WriteFile(store, name, data, info.Mode().Perm())
+}
+
+// SealResources seals the secret resources found in a directory, which
+// were written in plain text before encryption was enabled
+func SealResources(store Store, dir string) error {
+	if store == nil || !store.Enabled() {
+		return nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !IsSecretResource(entry.Name()) {
+			continue
+		}
+		if err = SealFile(store, filepath.Join(dir, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Unseal copies the source directory into the target directory opening
+// all sealed files, which are only readable by the owner of the site. Any
+// previous content of the target directory is removed.
+func Unseal(store Store, source string, target string) error {
+	if err := os.RemoveAll(target); err != nil {
+		return err
+	}
+	return filepath.WalkDir(source, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relative, err := filepath.Rel(source, name)
+		if err != nil {
+			return err
+		}
+		targetName := filepath.Join(target, relative)
+		if entry.IsDir() {
+			return os.MkdirAll(targetName, 0700)
+		}
+		data, err := ReadFile(store, name)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(targetName, data, 0600)
+	})
+}
+
+// SaveConfig persists the secrets configuration of a namespace, so it is
+// available to the components that read its secrets
+func SaveConfig(namespace string, config Config) error {
+	fileName := api.GetInternalOutputPath(namespace, api.SecretsConfigPath)
+	if !config.Enabled() {
+		if err := os.Remove(fileName); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(fileName, data, 0644)
+}
+
+// LoadConfig returns the secrets configuration of a namespace, which is
+// empty if its secrets are not encrypted
+func LoadConfig(namespace string) (Config, error) {
+	config := Config{}
+	data, err := os.ReadFile(api.GetInternalOutputPath(namespace, api.SecretsConfigPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return config, nil
+		}
+		return config, err
+	}
+	if err = yaml.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("invalid secrets configuration for namespace %s: %w", namespace, err)
+	}
+	return config, nil
+}
+
+// StoreForNamespace returns the Store for the secrets of a namespace
+func StoreForNamespace(namespace string) (Store, error) {
+	config, err := LoadConfig(namespace)
+	if err != nil {
+		return nil, err
+	}
+	return NewStore(config)
+}
@@ -0,0 +1,82 @@
+package secrets
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// minKeySize is the minimum length of the key material used to derive a
+// key encryption key
+const minKeySize = 32
+
+// localKeyEncrypter encrypts data keys with AES-256-GCM using a key
+// derived from local key material, which is only loaded when first used
+type localKeyEncrypter struct {
+	load func() ([]byte, error)
+	once sync.Once
+	key  []byte
+	err  error
+}
+
+func (e *localKeyEncrypter) getKey() ([]byte, error) {
+	e.once.Do(func() {
+		material, err := e.load()
+		if err != nil {
+			e.err = err
+			return
+		}
+		if len(material) < minKeySize {
+			e.err = fmt.Errorf("key must have at least %d bytes", minKeySize)
+			return
+		}
+		key := sha256.Sum256(material)
+		e.key = key[:]
+	})
+	return e.key, e.err
+}
+
+func (e *localKeyEncrypter) Encrypt(dataKey []byte) ([]byte, error) {
+	key, err := e.getKey()
+	if err != nil {
+		return nil, err
+	}
+	nonce, encrypted, err := encrypt(key, dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return append(nonce, encrypted...), nil
+}
+
+func (e *localKeyEncrypter) Decrypt(encryptedKey []byte) ([]byte, error) {
+	key, err := e.getKey()
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(encryptedKey) < aead.NonceSize() {
+		return nil, fmt.Errorf("invalid encrypted key")
+	}
+	return aead.Open(nil, encryptedKey[:aead.NonceSize()], encryptedKey[aead.NonceSize():], nil)
+}
+
+// newKeyFileEncrypter uses the content of a user provided key file,
+// which must only be readable by the owner of the site
+func newKeyFileEncrypter(config Config) (KeyEncrypter, error) {
+	return &localKeyEncrypter{
+		load: func() ([]byte, error) {
+			info, err := os.Stat(config.KeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read key file: %w", err)
+			}
+			if info.Mode().Perm()&0077 != 0 {
+				return nil, fmt.Errorf("key file %s must not be accessible by other users", config.KeyFile)
+			}
+			return os.ReadFile(config.KeyFile)
+		},
+	}, nil
+}
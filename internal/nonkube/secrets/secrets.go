@@ -0,0 +1,283 @@
+// Package secrets provides encryption at rest for the private keys and
+// link credentials of non-kubernetes sites.
+//
+// Secret files are sealed using envelope encryption: each file is
+// encrypted with its own random data key using AES-256-GCM, and the data
+// key is in turn encrypted by a KeyEncrypter. KeyEncrypters backed by a
+// local key file or by a systemd credential are built in, external key
+// management services can be plugged in through RegisterKeyEncrypter.
+package secrets
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// EncryptionSetting is the Site setting that enables the encryption
+	// of secrets, naming the provider of the key encryption key
+	EncryptionSetting = "secrets-encryption"
+	// KeyFileSetting is the path of the key file used by the keyfile
+	// provider
+	KeyFileSetting = "secrets-key-file"
+	// CredentialSetting is the path of the encrypted systemd credential
+	// used by the systemd-creds provider
+	CredentialSetting = "secrets-credential"
+	// OptionSettingPrefix prefixes the Site settings passed on to
+	// external providers as options
+	OptionSettingPrefix = "secrets-option-"
+
+	ProviderKeyFile      = "keyfile"
+	ProviderSystemdCreds = "systemd-creds"
+
+	dataKeySize = 32
+)
+
+// sealedPrefix identifies sealed content, it is followed by the JSON
+// encoded envelope
+var sealedPrefix = []byte("skupper-sealed/v1\n")
+
+// Config selects how the secrets of a site are stored. Secrets are stored
+// in plain text when no Provider is set.
+type Config struct {
+	Provider   string            `json:"provider,omitempty"`
+	KeyFile    string            `json:"keyFile,omitempty"`
+	Credential string            `json:"credential,omitempty"`
+	Options    map[string]string `json:"options,omitempty"`
+}
+
+func (c Config) Enabled() bool {
+	return c.Provider != ""
+}
+
+// ConfigFromSettings returns the secrets configuration from the settings
+// of a Site
+func ConfigFromSettings(settings map[string]string) Config {
+	config := Config{
+		Provider:   settings[EncryptionSetting],
+		KeyFile:    settings[KeyFileSetting],
+		Credential: settings[CredentialSetting],
+	}
+	for key, value := range settings {
+		if option, ok := strings.CutPrefix(key, OptionSettingPrefix); ok {
+			if config.Options == nil {
+				config.Options = map[string]string{}
+			}
+			config.Options[option] = value
+		}
+	}
+	return config
+}
+
+// KeyEncrypter encrypts and decrypts the data keys of sealed secrets.
+type KeyEncrypter interface {
+	Encrypt(dataKey []byte) ([]byte, error)
+	Decrypt(encryptedKey []byte) ([]byte, error)
+}
+
+// KeyEncrypterFactory creates the KeyEncrypter of a provider from the
+// secrets configuration of a site
+type KeyEncrypterFactory func(config Config) (KeyEncrypter, error)
+
+var (
+	providersMutex sync.RWMutex
+	providers      = map[string]KeyEncrypterFactory{
+		ProviderKeyFile:      newKeyFileEncrypter,
+		ProviderSystemdCreds: newSystemdCredsEncrypter,
+	}
+)
+
+// RegisterKeyEncrypter makes an external key provider available to be
+// selected through the secrets-encryption Site setting
+func RegisterKeyEncrypter(provider string, factory KeyEncrypterFactory) {
+	providersMutex.Lock()
+	defer providersMutex.Unlock()
+	providers[provider] = factory
+}
+
+// Providers returns the names of the available key providers
+func Providers() []string {
+	providersMutex.RLock()
+	defer providersMutex.RUnlock()
+	var names []string
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate returns an error if the configuration cannot be used to
+// create a Store, without accessing any key
+func (c Config) Validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	providersMutex.RLock()
+	_, ok := providers[c.Provider]
+	providersMutex.RUnlock()
+	if !ok {
+		return fmt.Errorf("unsupported %s %q, must be one of: %s", EncryptionSetting, c.Provider, strings.Join(Providers(), ", "))
+	}
+	switch c.Provider {
+	case ProviderKeyFile:
+		if c.KeyFile == "" {
+			return fmt.Errorf("%s is required when %s is %q", KeyFileSetting, EncryptionSetting, c.Provider)
+		}
+	case ProviderSystemdCreds:
+		if c.Credential == "" {
+			return fmt.Errorf("%s is required when %s is %q", CredentialSetting, EncryptionSetting, c.Provider)
+		}
+	}
+	return nil
+}
+
+// Store seals secrets before they are written to disk and opens them
+// once read back.
+type Store interface {
+	// Enabled returns false when secrets are stored in plain text
+	Enabled() bool
+	Seal(data []byte) ([]byte, error)
+	Open(data []byte) ([]byte, error)
+}
+
+// NewStore returns the Store for a secrets configuration. The key
+// provider is only accessed when secrets are sealed or opened.
+func NewStore(config Config) (Store, error) {
+	if !config.Enabled() {
+		return plainStore{}, nil
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	providersMutex.RLock()
+	factory := providers[config.Provider]
+	providersMutex.RUnlock()
+	encrypter, err := factory(config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize %s key provider: %w", config.Provider, err)
+	}
+	return &sealedStore{provider: config.Provider, encrypter: encrypter}, nil
+}
+
+// IsSealed returns true if the data has been sealed by a Store
+func IsSealed(data []byte) bool {
+	return bytes.HasPrefix(data, sealedPrefix)
+}
+
+type plainStore struct{}
+
+func (plainStore) Enabled() bool {
+	return false
+}
+
+func (plainStore) Seal(data []byte) ([]byte, error) {
+	return data, nil
+}
+
+func (plainStore) Open(data []byte) ([]byte, error) {
+	if IsSealed(data) {
+		return nil, fmt.Errorf("secret is encrypted, but no %s has been configured", EncryptionSetting)
+	}
+	return data, nil
+}
+
+type envelope struct {
+	Provider string `json:"provider"`
+	Key      []byte `json:"key"`
+	Nonce    []byte `json:"nonce"`
+	Data     []byte `json:"data"`
+}
+
+type sealedStore struct {
+	provider  string
+	encrypter KeyEncrypter
+}
+
+func (s *sealedStore) Enabled() bool {
+	return true
+}
+
+func (s *sealedStore) Seal(data []byte) ([]byte, error) {
+	if IsSealed(data) {
+		return data, nil
+	}
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+	nonce, sealed, err := encrypt(dataKey, data)
+	if err != nil {
+		return nil, err
+	}
+	encryptedKey, err := s.encrypter.Encrypt(dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encrypt data key: %w", err)
+	}
+	encoded, err := json.Marshal(envelope{
+		Provider: s.provider,
+		Key:      encryptedKey,
+		Nonce:    nonce,
+		Data:     sealed,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte{}, sealedPrefix...), encoded...), nil
+}
+
+func (s *sealedStore) Open(data []byte) ([]byte, error) {
+	if !IsSealed(data) {
+		return data, nil
+	}
+	var sealed envelope
+	if err := json.Unmarshal(data[len(sealedPrefix):], &sealed); err != nil {
+		return nil, fmt.Errorf("invalid encrypted secret: %w", err)
+	}
+	if sealed.Provider != s.provider {
+		return nil, fmt.Errorf("secret has been encrypted using the %s provider, not %s", sealed.Provider, s.provider)
+	}
+	dataKey, err := s.encrypter.Decrypt(sealed.Key)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt data key: %w", err)
+	}
+	return decrypt(dataKey, sealed.Nonce, sealed.Data)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func encrypt(key []byte, data []byte) ([]byte, []byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, err
+	}
+	return nonce, aead.Seal(nil, nonce, data, nil), nil
+}
+
+func decrypt(key []byte, nonce []byte, data []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("invalid nonce")
+	}
+	return aead.Open(nil, nonce, data, nil)
+}
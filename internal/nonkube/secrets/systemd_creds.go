@@ -0,0 +1,48 @@
+package secrets
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// CommandExecutor creates the commands used to decrypt systemd credentials
+var CommandExecutor = exec.Command
+
+// newSystemdCredsEncrypter uses the content of a credential encrypted
+// with systemd-creds encrypt. When running as part of a service that
+// loads it through LoadCredentialEncrypted=, the credential decrypted by
+// systemd is used, otherwise it is decrypted through systemd-creds.
+func newSystemdCredsEncrypter(config Config) (KeyEncrypter, error) {
+	name := config.CredentialName()
+	return &localKeyEncrypter{
+		load: func() ([]byte, error) {
+			if dir := os.Getenv("CREDENTIALS_DIRECTORY"); dir != "" {
+				if data, err := os.ReadFile(filepath.Join(dir, name)); err == nil {
+					return data, nil
+				}
+			}
+			args := []string{"decrypt", "--name=" + name, config.Credential, "-"}
+			if os.Getuid() != 0 {
+				args = append([]string{"--user"}, args...)
+			}
+			cmd := CommandExecutor("systemd-creds", args...)
+			var stderr strings.Builder
+			cmd.Stderr = &stderr
+			data, err := cmd.Output()
+			if err != nil {
+				return nil, fmt.Errorf("unable to decrypt credential %s: %w: %s", config.Credential, err, strings.TrimSpace(stderr.String()))
+			}
+			return data, nil
+		},
+	}, nil
+}
+
+// CredentialName is the name the systemd credential has been encrypted
+// with, which is expected to be the name of its file without the .cred
+// suffix
+func (c Config) CredentialName() string {
+	return strings.TrimSuffix(filepath.Base(c.Credential), ".cred")
+}
@@ -140,6 +140,36 @@ func asTcpEndpoint(record Record) TcpEndpoint {
 	return endpoint
 }
 
+func asUdpEndpoint(record Record) UdpEndpoint {
+	return UdpEndpoint{
+		Name:      record.AsString("name"),
+		Host:      record.AsString("host"),
+		Port:      record.AsString("port"),
+		Address:   record.AsString("address"),
+		SiteId:    record.AsString("siteId"),
+		ProcessID: record.AsString("processId"),
+	}
+}
+
+func asHttpEndpoint(record Record) HttpEndpoint {
+	endpoint := HttpEndpoint{
+		Name:            record.AsString("name"),
+		Host:            record.AsString("host"),
+		Port:            record.AsString("port"),
+		Address:         record.AsString("address"),
+		SiteId:          record.AsString("siteId"),
+		ProtocolVersion: HttpProtocolVersion(record.AsString("protocolVersion")),
+		SslProfile:      record.AsString("sslProfile"),
+		ProcessID:       record.AsString("processId"),
+	}
+	if value, ok := record["verifyHostname"]; ok {
+		if verify, ok := value.(bool); ok {
+			endpoint.VerifyHostname = &verify
+		}
+	}
+	return endpoint
+}
+
 func asConnection(record Record) Connection {
 	return Connection{
 		Role:       record.AsString("role"),
@@ -771,6 +801,8 @@ func getBridgeTypes() []string {
 		"io.skupper.router.tcpListener",
 		"io.skupper.router.httpConnector",
 		"io.skupper.router.httpListener",
+		"io.skupper.router.udpConnector",
+		"io.skupper.router.udpListener",
 	}
 }
 
@@ -796,6 +828,18 @@ func (a *Agent) getLocalTcpEndpoints(typename string, filter TcpEndpointFilter)
 	return records, nil
 }
 
+func (a *Agent) getLocalHttpEndpoints(typename string) ([]HttpEndpoint, error) {
+	results, err := a.Query(typename, []string{})
+	if err != nil {
+		return nil, err
+	}
+	endpoints := []HttpEndpoint{}
+	for _, record := range results {
+		endpoints = append(endpoints, asHttpEndpoint(record))
+	}
+	return endpoints, nil
+}
+
 func (a *Agent) GetConnectorByName(name string) (*Connector, error) {
 
 	results, err := a.Query("io.skupper.router.connector", []string{})
@@ -854,6 +898,14 @@ func (a *Agent) GetLocalTcpConnectors(filter TcpEndpointFilter) ([]TcpEndpoint,
 	return a.getLocalTcpEndpoints("io.skupper.router.tcpConnector", filter)
 }
 
+func (a *Agent) GetLocalHttpListeners() ([]HttpEndpoint, error) {
+	return a.getLocalHttpEndpoints("io.skupper.router.httpListener")
+}
+
+func (a *Agent) GetLocalHttpConnectors() ([]HttpEndpoint, error) {
+	return a.getLocalHttpEndpoints("io.skupper.router.httpConnector")
+}
+
 func (a *Agent) GetLocalBridgeConfig() (*BridgeConfig, error) {
 	config := NewBridgeConfig()
 
@@ -873,6 +925,38 @@ func (a *Agent) GetLocalBridgeConfig() (*BridgeConfig, error) {
 		config.AddTcpListener(asTcpEndpoint(record))
 	}
 
+	results, err = a.Query("io.skupper.router.udpConnector", []string{})
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range results {
+		config.AddUdpConnector(asUdpEndpoint(record))
+	}
+
+	results, err = a.Query("io.skupper.router.udpListener", []string{})
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range results {
+		config.AddUdpListener(asUdpEndpoint(record))
+	}
+
+	results, err = a.Query("io.skupper.router.httpConnector", []string{})
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range results {
+		config.AddHttpConnector(asHttpEndpoint(record))
+	}
+
+	results, err = a.Query("io.skupper.router.httpListener", []string{})
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range results {
+		config.AddHttpListener(asHttpEndpoint(record))
+	}
+
 	return &config, nil
 }
 
@@ -887,6 +971,26 @@ func (a *Agent) UpdateLocalBridgeConfig(changes *BridgeConfigDifference) error {
 			return fmt.Errorf("Error deleting tcp listeners: %s", err)
 		}
 	}
+	for _, deleted := range changes.UdpConnectors.Deleted {
+		if err := a.Delete("io.skupper.router.udpConnector", deleted); err != nil {
+			return fmt.Errorf("Error deleting udp connectors: %s", err)
+		}
+	}
+	for _, deleted := range changes.UdpListeners.Deleted {
+		if err := a.Delete("io.skupper.router.udpListener", deleted); err != nil {
+			return fmt.Errorf("Error deleting udp listeners: %s", err)
+		}
+	}
+	for _, deleted := range changes.HttpConnectors.Deleted {
+		if err := a.Delete("io.skupper.router.httpConnector", deleted); err != nil {
+			return fmt.Errorf("Error deleting http connectors: %s", err)
+		}
+	}
+	for _, deleted := range changes.HttpListeners.Deleted {
+		if err := a.Delete("io.skupper.router.httpListener", deleted); err != nil {
+			return fmt.Errorf("Error deleting http listeners: %s", err)
+		}
+	}
 	for _, added := range changes.TcpConnectors.Added {
 		if err := a.Create("io.skupper.router.tcpConnector", added.Name, added); err != nil {
 			return fmt.Errorf("Error adding tcp connectors: %s", err)
@@ -897,6 +1001,26 @@ func (a *Agent) UpdateLocalBridgeConfig(changes *BridgeConfigDifference) error {
 			return fmt.Errorf("Error adding tcp listeners: %s", err)
 		}
 	}
+	for _, added := range changes.UdpConnectors.Added {
+		if err := a.Create("io.skupper.router.udpConnector", added.Name, added); err != nil {
+			return fmt.Errorf("Error adding udp connectors: %s", err)
+		}
+	}
+	for _, added := range changes.UdpListeners.Added {
+		if err := a.Create("io.skupper.router.udpListener", added.Name, added); err != nil {
+			return fmt.Errorf("Error adding udp listeners: %s", err)
+		}
+	}
+	for _, added := range changes.HttpConnectors.Added {
+		if err := a.Create("io.skupper.router.httpConnector", added.Name, added); err != nil {
+			return fmt.Errorf("Error adding http connectors: %s", err)
+		}
+	}
+	for _, added := range changes.HttpListeners.Added {
+		if err := a.Create("io.skupper.router.httpListener", added.Name, added); err != nil {
+			return fmt.Errorf("Error adding http listeners: %s", err)
+		}
+	}
 	return nil
 }
 
@@ -980,6 +1104,22 @@ func (a *Agent) GetLocalTcpConnections() ([]TcpConnection, error) {
 	return getTcpConnectionsFromRecords(records)
 }
 
+// CountConnectorFlows returns the number of TCP connections the router
+// currently has open to the targets of its connectors
+func (a *Agent) CountConnectorFlows() (int, error) {
+	connections, err := a.GetLocalTcpConnections()
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, connection := range connections {
+		if connection.Direction == DirectionOut {
+			count++
+		}
+	}
+	return count, nil
+}
+
 func (a *Agent) getAllEdgeRouters(agents []string) ([]Router, error) {
 	edges := []Router{}
 
@@ -1099,6 +1239,8 @@ func asConnector(record Record) Connector {
 		Name:           record.AsString("name"),
 		Host:           record.AsString("host"),
 		Port:           record.AsString("port"),
+		Role:           asRole(record.AsString("role")),
+		Cost:           int32(record.AsInt("cost")),
 		RouteContainer: record.AsBool("routeContainer"),
 		VerifyHostname: record.AsBool("verifyHostname"),
 		SslProfile:     record.AsString("sslProfile"),
@@ -1238,6 +1380,47 @@ func (a *Agent) UpdateListenerConfig(changes *ListenerDifference) error {
 	return nil
 }
 
+func asAddress(record Record) Address {
+	return Address{
+		Name:         record.AsString("name"),
+		Prefix:       record.AsString("prefix"),
+		Distribution: record.AsString("distribution"),
+	}
+}
+
+func (a *Agent) GetLocalAddresses() (map[string]Address, error) {
+	results, err := a.Query("io.skupper.router.router.config.address", []string{})
+	if err != nil {
+		return nil, err
+	}
+	addresses := map[string]Address{}
+	for _, record := range results {
+		address := asAddress(record)
+		addresses[address.Prefix] = address
+	}
+	return addresses, nil
+}
+
+func (a *Agent) UpdateAddressConfig(changes *AddressDifference) error {
+	for _, deleted := range changes.Deleted {
+		if err := a.Delete("io.skupper.router.router.config.address", deleted.Name); err != nil {
+			return fmt.Errorf("Error deleting addresses: %s", err)
+		}
+	}
+
+	for _, added := range changes.Added {
+		name := added.Name
+		if name == "" {
+			name = added.Prefix
+		}
+		if err := a.Create("io.skupper.router.router.config.address", name, added); err != nil {
+			return fmt.Errorf("Error adding addresses: %s", err)
+		}
+	}
+
+	return nil
+}
+
 func (a *Agent) GetLocalListeners() (map[string]Listener, error) {
 	results, err := a.Query("io.skupper.router.listener", []string{})
 	if err != nil {
@@ -0,0 +1,403 @@
+package fake
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// The subset of the AMQP 1.0 type system needed to exchange performatives
+// with clients. Message payloads are handled with github.com/Azure/go-amqp.
+
+type symbol string
+
+// symbols encodes as an array of symbols
+type symbols []symbol
+
+type described struct {
+	descriptor uint64
+	value      any
+}
+
+var errShortBuffer = errors.New("amqp decode error: short buffer")
+
+type decoder struct {
+	buf []byte
+	pos int
+}
+
+func (d *decoder) remaining() []byte {
+	return d.buf[d.pos:]
+}
+
+func (d *decoder) next(n int) ([]byte, error) {
+	if n < 0 || len(d.buf)-d.pos < n {
+		return nil, errShortBuffer
+	}
+	b := d.buf[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+func (d *decoder) byte() (byte, error) {
+	b, err := d.next(1)
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+func (d *decoder) size(width int) (int, error) {
+	b, err := d.next(width)
+	if err != nil {
+		return 0, err
+	}
+	if width == 1 {
+		return int(b[0]), nil
+	}
+	return int(binary.BigEndian.Uint32(b)), nil
+}
+
+func (d *decoder) decode() (any, error) {
+	code, err := d.byte()
+	if err != nil {
+		return nil, err
+	}
+	if code == 0x00 {
+		descriptor, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		value, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		out := described{value: value}
+		switch descriptor := descriptor.(type) {
+		case uint64:
+			out.descriptor = descriptor
+		case symbol:
+			// descriptors by name are not used by the performatives handled
+		default:
+			return nil, fmt.Errorf("amqp decode error: unexpected descriptor %T", descriptor)
+		}
+		return out, nil
+	}
+	return d.value(code)
+}
+
+func (d *decoder) value(code byte) (any, error) {
+	switch code {
+	case 0x40:
+		return nil, nil
+	case 0x41:
+		return true, nil
+	case 0x42:
+		return false, nil
+	case 0x56:
+		b, err := d.byte()
+		return b != 0, err
+	case 0x50:
+		return d.byte()
+	case 0x51:
+		b, err := d.byte()
+		return int8(b), err
+	case 0x60:
+		b, err := d.next(2)
+		if err != nil {
+			return nil, err
+		}
+		return binary.BigEndian.Uint16(b), nil
+	case 0x61:
+		b, err := d.next(2)
+		if err != nil {
+			return nil, err
+		}
+		return int16(binary.BigEndian.Uint16(b)), nil
+	case 0x43:
+		return uint32(0), nil
+	case 0x52:
+		b, err := d.byte()
+		return uint32(b), err
+	case 0x70:
+		b, err := d.next(4)
+		if err != nil {
+			return nil, err
+		}
+		return binary.BigEndian.Uint32(b), nil
+	case 0x54:
+		b, err := d.byte()
+		return int32(int8(b)), err
+	case 0x71:
+		b, err := d.next(4)
+		if err != nil {
+			return nil, err
+		}
+		return int32(binary.BigEndian.Uint32(b)), nil
+	case 0x72:
+		b, err := d.next(4)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float32frombits(binary.BigEndian.Uint32(b)), nil
+	case 0x73:
+		b, err := d.next(4)
+		if err != nil {
+			return nil, err
+		}
+		return rune(binary.BigEndian.Uint32(b)), nil
+	case 0x44:
+		return uint64(0), nil
+	case 0x53:
+		b, err := d.byte()
+		return uint64(b), err
+	case 0x80, 0x83:
+		b, err := d.next(8)
+		if err != nil {
+			return nil, err
+		}
+		return binary.BigEndian.Uint64(b), nil
+	case 0x55:
+		b, err := d.byte()
+		return int64(int8(b)), err
+	case 0x81:
+		b, err := d.next(8)
+		if err != nil {
+			return nil, err
+		}
+		return int64(binary.BigEndian.Uint64(b)), nil
+	case 0x82:
+		b, err := d.next(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+	case 0x98:
+		return d.next(16)
+	case 0xa0, 0xb0:
+		n, err := d.size(width(code))
+		if err != nil {
+			return nil, err
+		}
+		b, err := d.next(n)
+		return append([]byte(nil), b...), err
+	case 0xa1, 0xb1:
+		n, err := d.size(width(code))
+		if err != nil {
+			return nil, err
+		}
+		b, err := d.next(n)
+		return string(b), err
+	case 0xa3, 0xb3:
+		n, err := d.size(width(code))
+		if err != nil {
+			return nil, err
+		}
+		b, err := d.next(n)
+		return symbol(b), err
+	case 0x45:
+		return []any(nil), nil
+	case 0xc0, 0xd0:
+		count, err := d.compound(width(code))
+		if err != nil {
+			return nil, err
+		}
+		list := make([]any, 0, count)
+		for i := 0; i < count; i++ {
+			item, err := d.decode()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, item)
+		}
+		return list, nil
+	case 0xc1, 0xd1:
+		count, err := d.compound(width(code))
+		if err != nil {
+			return nil, err
+		}
+		m := make(map[any]any, count/2)
+		for i := 0; i+1 < count; i += 2 {
+			key, err := d.decode()
+			if err != nil {
+				return nil, err
+			}
+			value, err := d.decode()
+			if err != nil {
+				return nil, err
+			}
+			m[key] = value
+		}
+		return m, nil
+	case 0xe0, 0xf0:
+		count, err := d.compound(width(code))
+		if err != nil {
+			return nil, err
+		}
+		elem, err := d.byte()
+		if err != nil {
+			return nil, err
+		}
+		array := make([]any, 0, count)
+		for i := 0; i < count; i++ {
+			item, err := d.value(elem)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, item)
+		}
+		return array, nil
+	default:
+		return nil, fmt.Errorf("amqp decode error: unsupported type code %#02x", code)
+	}
+}
+
+// compound reads the size and count of a list, map or array
+func (d *decoder) compound(w int) (int, error) {
+	if _, err := d.size(w); err != nil {
+		return 0, err
+	}
+	return d.size(w)
+}
+
+func width(code byte) int {
+	if code&0xf0 >= 0xb0 {
+		return 4
+	}
+	return 1
+}
+
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) bytes() []byte {
+	return e.buf
+}
+
+func (e *encoder) uint32(v uint32) {
+	e.buf = binary.BigEndian.AppendUint32(e.buf, v)
+}
+
+func (e *encoder) variable(short, long byte, b []byte) {
+	if len(b) < 256 {
+		e.buf = append(e.buf, short, byte(len(b)))
+	} else {
+		e.buf = append(e.buf, long)
+		e.uint32(uint32(len(b)))
+	}
+	e.buf = append(e.buf, b...)
+}
+
+// compound encodes a list or map with a 32 bit size and count
+func (e *encoder) compound(code byte, count int, items func(*encoder) error) error {
+	var body encoder
+	if err := items(&body); err != nil {
+		return err
+	}
+	e.buf = append(e.buf, code)
+	e.uint32(uint32(len(body.buf) + 4))
+	e.uint32(uint32(count))
+	e.buf = append(e.buf, body.buf...)
+	return nil
+}
+
+func (e *encoder) encode(v any) error {
+	switch v := v.(type) {
+	case nil:
+		e.buf = append(e.buf, 0x40)
+	case bool:
+		if v {
+			e.buf = append(e.buf, 0x41)
+		} else {
+			e.buf = append(e.buf, 0x42)
+		}
+	case uint8:
+		e.buf = append(e.buf, 0x50, v)
+	case uint16:
+		e.buf = append(e.buf, 0x60)
+		e.buf = binary.BigEndian.AppendUint16(e.buf, v)
+	case uint32:
+		switch {
+		case v == 0:
+			e.buf = append(e.buf, 0x43)
+		case v < 256:
+			e.buf = append(e.buf, 0x52, byte(v))
+		default:
+			e.buf = append(e.buf, 0x70)
+			e.uint32(v)
+		}
+	case uint64:
+		switch {
+		case v == 0:
+			e.buf = append(e.buf, 0x44)
+		case v < 256:
+			e.buf = append(e.buf, 0x53, byte(v))
+		default:
+			e.buf = append(e.buf, 0x80)
+			e.buf = binary.BigEndian.AppendUint64(e.buf, v)
+		}
+	case int32:
+		e.buf = append(e.buf, 0x71)
+		e.uint32(uint32(v))
+	case int64:
+		e.buf = append(e.buf, 0x81)
+		e.buf = binary.BigEndian.AppendUint64(e.buf, uint64(v))
+	case int:
+		return e.encode(int64(v))
+	case string:
+		e.variable(0xa1, 0xb1, []byte(v))
+	case symbol:
+		e.variable(0xa3, 0xb3, []byte(v))
+	case []byte:
+		e.variable(0xa0, 0xb0, v)
+	case symbols:
+		var body encoder
+		body.buf = append(body.buf, 0xb3)
+		for _, s := range v {
+			body.uint32(uint32(len(s)))
+			body.buf = append(body.buf, s...)
+		}
+		e.buf = append(e.buf, 0xf0)
+		e.uint32(uint32(len(body.buf) + 4))
+		e.uint32(uint32(len(v)))
+		e.buf = append(e.buf, body.buf...)
+	case []any:
+		if len(v) == 0 {
+			e.buf = append(e.buf, 0x45)
+			return nil
+		}
+		return e.compound(0xd0, len(v), func(body *encoder) error {
+			for _, item := range v {
+				if err := body.encode(item); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		return e.compound(0xd1, 2*len(v), func(body *encoder) error {
+			for _, key := range keys {
+				body.encode(key)
+				if err := body.encode(v[key]); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	case described:
+		e.buf = append(e.buf, 0x00)
+		e.encode(v.descriptor)
+		return e.encode(v.value)
+	default:
+		return fmt.Errorf("amqp encode error: unsupported type %T", v)
+	}
+	return nil
+}
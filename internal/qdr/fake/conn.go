@@ -0,0 +1,561 @@
+package fake
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net"
+	"sync"
+	"time"
+
+	amqp "github.com/Azure/go-amqp"
+)
+
+const (
+	frameTypeAMQP = 0x0
+	frameTypeSASL = 0x1
+
+	performativeOpen        = 0x10
+	performativeBegin       = 0x11
+	performativeAttach      = 0x12
+	performativeFlow        = 0x13
+	performativeTransfer    = 0x14
+	performativeDisposition = 0x15
+	performativeDetach      = 0x16
+	performativeEnd         = 0x17
+	performativeClose       = 0x18
+
+	saslMechanisms = 0x40
+	saslInit       = 0x41
+	saslOutcome    = 0x44
+
+	descriptorSource   = 0x28
+	descriptorTarget   = 0x29
+	descriptorAccepted = 0x24
+
+	maxFrameSize  = 1024 * 1024
+	sessionWindow = math.MaxInt32
+	// linkCredit is the credit granted to links sending to the router
+	linkCredit = 1000
+)
+
+var (
+	protocolAMQP = []byte{'A', 'M', 'Q', 'P', 0, 1, 0, 0}
+	protocolSASL = []byte{'A', 'M', 'Q', 'P', 3, 1, 0, 0}
+)
+
+// fields are the fields of a performative
+type fields []any
+
+func (f fields) get(i int) any {
+	if i < len(f) {
+		return f[i]
+	}
+	return nil
+}
+
+func (f fields) uint32(i int) (uint32, bool) {
+	switch v := f.get(i).(type) {
+	case uint32:
+		return v, true
+	case uint64:
+		return uint32(v), true
+	case uint16:
+		return uint32(v), true
+	case uint8:
+		return uint32(v), true
+	}
+	return 0, false
+}
+
+func (f fields) bool(i int) bool {
+	v, _ := f.get(i).(bool)
+	return v
+}
+
+// terminusAddress returns the address of a source or target
+func terminusAddress(terminus any) (address string, dynamic bool) {
+	t, ok := terminus.(described)
+	if !ok {
+		return "", false
+	}
+	f, _ := t.value.([]any)
+	address, _ = fields(f).get(0).(string)
+	return address, fields(f).bool(4)
+}
+
+// conn is a client connection to the router
+type conn struct {
+	router *Router
+	nc     net.Conn
+	r      *bufio.Reader
+	logger *slog.Logger
+
+	mu          sync.Mutex
+	peerMaxSize uint32
+	sessions    map[uint16]*session
+	closed      bool
+	done        chan struct{}
+}
+
+type session struct {
+	channel        uint16
+	nextOutgoingID uint32
+	nextIncomingID uint32
+	nextDelivery   uint32
+	links          map[uint32]*link
+}
+
+// link is a link attached by a client. Messages flow from the router to the
+// client when the client is the receiver.
+type link struct {
+	conn     *conn
+	session  *session
+	handle   uint32
+	receiver bool
+	address  string
+	settled  bool
+
+	// delivering to the client
+	deliveryCount uint32
+	credit        uint32
+	queue         [][]byte
+
+	// receiving from the client
+	received   uint32
+	granted    uint32
+	payload    bytes.Buffer
+	deliveryID *uint32
+	unsettled  bool
+}
+
+func newConn(router *Router, nc net.Conn) *conn {
+	return &conn{
+		router:      router,
+		nc:          nc,
+		r:           bufio.NewReader(nc),
+		logger:      router.logger.With(slog.String("remote", nc.RemoteAddr().String())),
+		peerMaxSize: 512,
+		sessions:    make(map[uint16]*session),
+		done:        make(chan struct{}),
+	}
+}
+
+func (c *conn) serve() {
+	defer c.close()
+	if err := c.handshake(); err != nil {
+		c.logger.Debug("handshake failed", slog.Any("error", err))
+		return
+	}
+	for {
+		frameType, channel, body, err := c.readFrame()
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				c.logger.Debug("error reading frame", slog.Any("error", err))
+			}
+			return
+		}
+		if frameType != frameTypeAMQP || len(body) == 0 {
+			// heartbeat
+			continue
+		}
+		d := &decoder{buf: body}
+		value, err := d.decode()
+		if err != nil {
+			c.logger.Debug("error decoding frame", slog.Any("error", err))
+			return
+		}
+		perf, ok := value.(described)
+		if !ok {
+			c.logger.Debug("unexpected frame body", slog.Any("body", value))
+			return
+		}
+		list, _ := perf.value.([]any)
+		if !c.handle(channel, perf.descriptor, fields(list), d.remaining()) {
+			return
+		}
+	}
+}
+
+func (c *conn) handshake() error {
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(c.r, header); err != nil {
+		return err
+	}
+	if bytes.Equal(header, protocolSASL) {
+		if _, err := c.nc.Write(protocolSASL); err != nil {
+			return err
+		}
+		mechanisms := described{saslMechanisms, []any{symbols{"ANONYMOUS", "EXTERNAL", "PLAIN"}}}
+		if err := c.writeFrame(frameTypeSASL, 0, mechanisms, nil); err != nil {
+			return err
+		}
+		frameType, _, body, err := c.readFrame()
+		if err != nil {
+			return err
+		}
+		init, err := (&decoder{buf: body}).decode()
+		if perf, ok := init.(described); err != nil || frameType != frameTypeSASL || !ok || perf.descriptor != saslInit {
+			return fmt.Errorf("expected sasl-init")
+		}
+		if err := c.writeFrame(frameTypeSASL, 0, described{saslOutcome, []any{uint8(0)}}, nil); err != nil {
+			return err
+		}
+		if _, err := io.ReadFull(c.r, header); err != nil {
+			return err
+		}
+	}
+	if !bytes.Equal(header, protocolAMQP) {
+		c.nc.Write(protocolAMQP)
+		return fmt.Errorf("unsupported protocol header %v", header)
+	}
+	_, err := c.nc.Write(protocolAMQP)
+	return err
+}
+
+func (c *conn) readFrame() (frameType byte, channel uint16, body []byte, err error) {
+	header := make([]byte, 8)
+	if _, err = io.ReadFull(c.r, header); err != nil {
+		return
+	}
+	size := binary.BigEndian.Uint32(header)
+	offset := uint32(header[4]) * 4
+	if size < 8 || offset < 8 || offset > size || size > maxFrameSize {
+		err = fmt.Errorf("invalid frame header %v", header)
+		return
+	}
+	frameType, channel = header[5], binary.BigEndian.Uint16(header[6:])
+	frame := make([]byte, size-8)
+	if _, err = io.ReadFull(c.r, frame); err != nil {
+		return
+	}
+	body = frame[offset-8:]
+	return
+}
+
+// writeFrame writes a performative and its payload. Callers writing AMQP
+// frames after the handshake hold c.mu.
+func (c *conn) writeFrame(frameType byte, channel uint16, perf described, payload []byte) error {
+	var e encoder
+	e.buf = make([]byte, 8, 64+len(payload))
+	if err := e.encode(perf); err != nil {
+		return err
+	}
+	e.buf = append(e.buf, payload...)
+	binary.BigEndian.PutUint32(e.buf, uint32(len(e.buf)))
+	e.buf[4], e.buf[5] = 2, frameType
+	binary.BigEndian.PutUint16(e.buf[6:], channel)
+	_, err := c.nc.Write(e.buf)
+	return err
+}
+
+func (c *conn) write(channel uint16, perf described, payload []byte) {
+	if c.closed {
+		return
+	}
+	if err := c.writeFrame(frameTypeAMQP, channel, perf, payload); err != nil {
+		c.logger.Debug("error writing frame", slog.Any("error", err))
+		c.closed = true
+		c.nc.Close()
+	}
+}
+
+// heartbeat sends empty frames so that the peer does not consider the
+// connection idle
+func (c *conn) heartbeat(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	empty := []byte{0, 0, 0, 8, 2, 0, 0, 0}
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			c.mu.Lock()
+			if !c.closed {
+				c.nc.Write(empty)
+			}
+			c.mu.Unlock()
+		}
+	}
+}
+
+// handle processes a performative. Returns false when the connection is
+// closed.
+func (c *conn) handle(channel uint16, code uint64, f fields, payload []byte) bool {
+	var route func()
+	c.mu.Lock()
+	defer func() {
+		c.mu.Unlock()
+		// routing delivers to other links and must not hold the lock
+		if route != nil {
+			route()
+		}
+	}()
+	switch code {
+	case performativeOpen:
+		if size, ok := f.uint32(2); ok && size >= 512 {
+			c.peerMaxSize = size
+		}
+		if idle, ok := f.uint32(4); ok && idle > 0 {
+			go c.heartbeat(time.Duration(idle) * time.Millisecond / 2)
+		}
+		c.write(0, described{performativeOpen, []any{
+			c.router.cfg.ID, nil, uint32(maxFrameSize), uint16(math.MaxUint16),
+		}}, nil)
+	case performativeBegin:
+		c.sessions[channel] = &session{channel: channel, links: make(map[uint32]*link)}
+		c.write(channel, described{performativeBegin, []any{
+			channel, uint32(0), uint32(sessionWindow), uint32(sessionWindow),
+		}}, nil)
+	case performativeAttach:
+		s, ok := c.sessions[channel]
+		if !ok {
+			return false
+		}
+		c.attach(s, f)
+	case performativeFlow:
+		s, ok := c.sessions[channel]
+		if !ok {
+			return false
+		}
+		handle, ok := f.uint32(4)
+		if !ok {
+			return true
+		}
+		if l, ok := s.links[handle]; ok && l.receiver {
+			l.flow(f)
+		}
+	case performativeTransfer:
+		s, ok := c.sessions[channel]
+		if !ok {
+			return false
+		}
+		s.nextIncomingID++
+		handle, _ := f.uint32(0)
+		l, ok := s.links[handle]
+		if !ok || l.receiver {
+			return false
+		}
+		route = l.transfer(f, payload)
+	case performativeDisposition:
+	case performativeDetach:
+		s, ok := c.sessions[channel]
+		if !ok {
+			return false
+		}
+		handle, _ := f.uint32(0)
+		if l, ok := s.links[handle]; ok {
+			c.detach(l)
+		}
+		c.write(channel, described{performativeDetach, []any{handle, true}}, nil)
+	case performativeEnd:
+		if s, ok := c.sessions[channel]; ok {
+			for _, l := range s.links {
+				c.detach(l)
+			}
+			delete(c.sessions, channel)
+		}
+		c.write(channel, described{performativeEnd, []any{}}, nil)
+	case performativeClose:
+		c.write(0, described{performativeClose, []any{}}, nil)
+		return false
+	}
+	return true
+}
+
+func (c *conn) attach(s *session, f fields) {
+	handle, _ := f.uint32(1)
+	l := &link{
+		conn:     c,
+		session:  s,
+		handle:   handle,
+		receiver: f.bool(2),
+	}
+	sndSettleMode, _ := f.get(3).(uint8)
+	l.settled = sndSettleMode == 1
+	var source, target any
+	if l.receiver {
+		address, dynamic := terminusAddress(f.get(5))
+		if dynamic {
+			address = c.router.dynamicAddress()
+		}
+		l.address = address
+		source = described{descriptorSource, []any{address}}
+		target = f.get(6)
+	} else {
+		l.address, _ = terminusAddress(f.get(6))
+		source = f.get(5)
+		target = described{descriptorTarget, []any{nilIfEmpty(l.address)}}
+	}
+	if source == nil {
+		source = described{descriptorSource, []any{}}
+	}
+	if target == nil {
+		target = described{descriptorTarget, []any{}}
+	}
+	var initialDeliveryCount any
+	if l.receiver {
+		initialDeliveryCount = uint32(0)
+	}
+	s.links[handle] = l
+	c.write(s.channel, described{performativeAttach, []any{
+		f.get(0), handle, !l.receiver, f.get(3), f.get(4), source, target, nil, false, initialDeliveryCount,
+	}}, nil)
+	if l.receiver {
+		c.router.subscribe(l)
+		return
+	}
+	l.grant()
+}
+
+func (c *conn) detach(l *link) {
+	delete(l.session.links, l.handle)
+	if l.receiver {
+		c.router.unsubscribe(l)
+	}
+}
+
+func (c *conn) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.closed {
+		c.closed = true
+		c.nc.Close()
+	}
+	select {
+	case <-c.done:
+		return
+	default:
+		close(c.done)
+	}
+	for _, s := range c.sessions {
+		for _, l := range s.links {
+			c.detach(l)
+		}
+	}
+	c.sessions = nil
+	c.router.forget(c)
+}
+
+func nilIfEmpty(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// grant issues credit to a client sending to the router. Requires c.mu.
+func (l *link) grant() {
+	l.granted = l.received + linkCredit
+	s := l.session
+	l.conn.write(s.channel, described{performativeFlow, []any{
+		s.nextIncomingID, uint32(sessionWindow), s.nextOutgoingID, uint32(sessionWindow),
+		l.handle, l.received, uint32(linkCredit),
+	}}, nil)
+}
+
+// transfer handles a transfer frame from a client. Returns a function routing
+// the message once it is complete. Requires c.mu.
+func (l *link) transfer(f fields, payload []byte) func() {
+	if id, ok := f.uint32(1); ok && l.deliveryID == nil {
+		l.deliveryID = &id
+		l.unsettled = !f.bool(4)
+	}
+	l.payload.Write(payload)
+	if f.bool(5) {
+		return nil
+	}
+	data := bytes.Clone(l.payload.Bytes())
+	l.payload.Reset()
+	if l.unsettled && l.deliveryID != nil {
+		l.conn.write(l.session.channel, described{performativeDisposition, []any{
+			true, *l.deliveryID, *l.deliveryID, true, described{descriptorAccepted, []any{}},
+		}}, nil)
+	}
+	l.deliveryID = nil
+	l.received++
+	if l.granted-l.received < linkCredit/2 {
+		l.grant()
+	}
+
+	msg := new(amqp.Message)
+	if err := msg.UnmarshalBinary(data); err != nil {
+		l.conn.logger.Debug("dropping message that could not be decoded", slog.Any("error", err))
+		return nil
+	}
+	address := l.address
+	if address == "" && msg.Properties != nil && msg.Properties.To != nil {
+		address = *msg.Properties.To
+	}
+	router := l.conn.router
+	return func() { router.route(address, data, msg) }
+}
+
+// flow handles the credit issued by a client receiving from the router.
+// Requires c.mu.
+func (l *link) flow(f fields) {
+	credit, _ := f.uint32(6)
+	if count, ok := f.uint32(5); ok {
+		credit = count + credit - l.deliveryCount
+	}
+	l.credit = credit
+	l.pump()
+	if f.bool(8) && l.credit > 0 {
+		// drain: use up the remaining credit
+		l.deliveryCount += l.credit
+		l.credit = 0
+		s := l.session
+		l.conn.write(s.channel, described{performativeFlow, []any{
+			s.nextIncomingID, uint32(sessionWindow), s.nextOutgoingID, uint32(sessionWindow),
+			l.handle, l.deliveryCount, uint32(0), nil, true,
+		}}, nil)
+	}
+}
+
+// deliver queues a message for a client receiving from the router
+func (l *link) deliver(data []byte) {
+	l.conn.mu.Lock()
+	defer l.conn.mu.Unlock()
+	if l.conn.closed {
+		return
+	}
+	l.queue = append(l.queue, data)
+	l.pump()
+}
+
+// pump sends queued messages while the client has credit. Requires c.mu.
+func (l *link) pump() {
+	s := l.session
+	for len(l.queue) > 0 && l.credit > 0 {
+		data := l.queue[0]
+		l.queue[0] = nil
+		l.queue = l.queue[1:]
+		id := s.nextDelivery
+		s.nextDelivery++
+		tag := binary.BigEndian.AppendUint32(nil, id)
+		// split messages larger than the frames the client accepts
+		room := int(l.conn.peerMaxSize) - 64
+		for {
+			chunk, more := data, false
+			if len(chunk) > room {
+				chunk, more = data[:room], true
+			}
+			data = data[len(chunk):]
+			l.conn.write(s.channel, described{performativeTransfer, []any{
+				l.handle, id, tag, uint32(0), l.settled, more,
+			}}, chunk)
+			s.nextOutgoingID++
+			if !more {
+				break
+			}
+		}
+		l.deliveryCount++
+		l.credit--
+	}
+}
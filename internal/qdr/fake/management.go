@@ -0,0 +1,245 @@
+package fake
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	amqp "github.com/Azure/go-amqp"
+)
+
+const entityTypePrefix = "io.skupper.router."
+
+// writable are the entity types that can be created, updated and deleted
+var writable = map[string]bool{
+	entityTypePrefix + "tcpListener":   true,
+	entityTypePrefix + "tcpConnector":  true,
+	entityTypePrefix + "udpListener":   true,
+	entityTypePrefix + "udpConnector":  true,
+	entityTypePrefix + "httpListener":  true,
+	entityTypePrefix + "httpConnector": true,
+	entityTypePrefix + "listener":      true,
+	entityTypePrefix + "connector":     true,
+	entityTypePrefix + "sslProfile":    true,
+
+	entityTypePrefix + "router.config.address": true,
+}
+
+func entityType(typename string) string {
+	if typename != "" && !strings.HasPrefix(typename, entityTypePrefix) {
+		return entityTypePrefix + typename
+	}
+	return typename
+}
+
+func (r *Router) isManagement(address string) bool {
+	if address == "$management" {
+		return true
+	}
+	return address == "amqp:/_topo/0/"+r.cfg.ID+"/$management" ||
+		address == "amqp:/_edge/"+r.cfg.ID+"/$management"
+}
+
+// Entities returns the attributes of the entities of a type by name
+func (r *Router) Entities(typename string) map[string]map[string]any {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make(map[string]map[string]any)
+	for name, attributes := range r.entities[entityType(typename)] {
+		out[name] = maps.Clone(attributes)
+	}
+	return out
+}
+
+type managementResponse struct {
+	status      int
+	description string
+	body        any
+}
+
+func (r *Router) handleManagement(request *amqp.Message) {
+	if request.Properties == nil || request.Properties.ReplyTo == nil {
+		return
+	}
+	operation, _ := request.ApplicationProperties["operation"].(string)
+	name, _ := request.ApplicationProperties["name"].(string)
+	var response managementResponse
+	switch operation {
+	case "QUERY":
+		typename, _ := request.ApplicationProperties["entityType"].(string)
+		var attributeNames []string
+		if body, ok := request.Value.(map[string]any); ok {
+			attributeNames = toStrings(body["attributeNames"])
+		}
+		response = r.query(entityType(typename), attributeNames)
+	case "READ", "CREATE", "UPDATE", "DELETE":
+		typename, _ := request.ApplicationProperties["type"].(string)
+		attributes, _ := request.Value.(map[string]any)
+		if name == "" {
+			name, _ = attributes["name"].(string)
+		}
+		response = r.operate(operation, entityType(typename), name, attributes)
+	default:
+		response = managementResponse{status: http.StatusNotImplemented, description: fmt.Sprintf("Not Implemented: %s", operation)}
+	}
+	if response.description == "" {
+		response.description = http.StatusText(response.status)
+	}
+	r.send(&amqp.Message{
+		Properties: &amqp.MessageProperties{
+			To:            request.Properties.ReplyTo,
+			CorrelationID: request.Properties.CorrelationID,
+		},
+		ApplicationProperties: map[string]any{
+			"statusCode":        int32(response.status),
+			"statusDescription": response.description,
+		},
+		Value: response.body,
+	})
+}
+
+func (r *Router) operate(operation string, typename string, name string, attributes map[string]any) managementResponse {
+	if operation != "READ" && !writable[typename] {
+		return managementResponse{status: http.StatusBadRequest, description: fmt.Sprintf("Unsupported entity type %q", typename)}
+	}
+	if name == "" {
+		return managementResponse{status: http.StatusBadRequest, description: "No name specified"}
+	}
+	r.mu.Lock()
+	entities, ok := r.entities[typename]
+	if !ok {
+		entities = make(map[string]map[string]any)
+		r.entities[typename] = entities
+	}
+	existing, exists := entities[name]
+	var response managementResponse
+	var changed map[string]any
+	switch operation {
+	case "READ":
+		if !exists {
+			response = managementResponse{status: http.StatusNotFound}
+			break
+		}
+		response = managementResponse{status: http.StatusOK, body: maps.Clone(existing)}
+	case "CREATE":
+		if exists {
+			response = managementResponse{status: http.StatusBadRequest, description: fmt.Sprintf("Name conflicts with an existing entity: %s", name)}
+			break
+		}
+		entity := maps.Clone(attributes)
+		if entity == nil {
+			entity = make(map[string]any)
+		}
+		entity["name"] = name
+		entity["identity"] = name
+		entity["type"] = typename
+		entities[name] = entity
+		changed = entity
+		response = managementResponse{status: http.StatusCreated, body: maps.Clone(entity)}
+	case "UPDATE":
+		if !exists {
+			response = managementResponse{status: http.StatusNotFound}
+			break
+		}
+		maps.Copy(existing, attributes)
+		existing["name"] = name
+		changed = existing
+		response = managementResponse{status: http.StatusOK, body: maps.Clone(existing)}
+	case "DELETE":
+		if !exists {
+			response = managementResponse{status: http.StatusNotFound}
+			break
+		}
+		delete(entities, name)
+		response = managementResponse{status: http.StatusNoContent}
+	}
+	r.mu.Unlock()
+	if operation == "DELETE" && exists {
+		r.entityDeleted(typename, name)
+	} else if changed != nil {
+		r.entityChanged(typename, name, maps.Clone(changed))
+	}
+	return response
+}
+
+func (r *Router) query(typename string, attributeNames []string) managementResponse {
+	var entities []map[string]any
+	r.mu.Lock()
+	switch typename {
+	case entityTypePrefix + "router":
+		entities = append(entities, r.routerEntity())
+	case entityTypePrefix + "router.node":
+		entities = append(entities, map[string]any{
+			"id":      r.cfg.ID,
+			"name":    "router.node/" + r.cfg.ID,
+			"nextHop": "(self)",
+			"address": "amqp:/_topo/0/" + r.cfg.ID,
+		})
+	case entityTypePrefix + "connection", entityTypePrefix + "router.link", entityTypePrefix + "router.address":
+		// types the fake knows of but never has any entities for
+	default:
+		if !writable[typename] {
+			r.mu.Unlock()
+			return managementResponse{status: http.StatusBadRequest, description: fmt.Sprintf("Unsupported entity type %q", typename)}
+		}
+		// entities are cloned, as updates modify them in place
+		for _, name := range slices.Sorted(maps.Keys(r.entities[typename])) {
+			entities = append(entities, maps.Clone(r.entities[typename][name]))
+		}
+	}
+	r.mu.Unlock()
+
+	if len(attributeNames) == 0 {
+		names := map[string]bool{}
+		for _, entity := range entities {
+			for name := range entity {
+				names[name] = true
+			}
+		}
+		attributeNames = slices.Sorted(maps.Keys(names))
+	}
+	results := make([]any, 0, len(entities))
+	for _, entity := range entities {
+		values := make([]any, 0, len(attributeNames))
+		for _, name := range attributeNames {
+			values = append(values, entity[name])
+		}
+		results = append(results, values)
+	}
+	names := make([]any, 0, len(attributeNames))
+	for _, name := range attributeNames {
+		names = append(names, name)
+	}
+	return managementResponse{status: http.StatusOK, body: map[string]any{
+		"attributeNames": names,
+		"results":        results,
+	}}
+}
+
+// routerEntity describes the router itself. Requires r.mu.
+func (r *Router) routerEntity() map[string]any {
+	return map[string]any{
+		"id":       r.cfg.ID,
+		"name":     r.cfg.ID,
+		"mode":     r.cfg.Mode,
+		"version":  version,
+		"metadata": fmt.Sprintf(`{"id":%q,"version":%q}`, r.cfg.SiteID, version),
+	}
+}
+
+func toStrings(value any) []string {
+	var out []string
+	switch value := value.(type) {
+	case []string:
+		out = value
+	case []any:
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
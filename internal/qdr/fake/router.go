@@ -0,0 +1,233 @@
+// Package fake provides an in-process stand-in for the skupper router.
+//
+// The Router speaks enough AMQP 1.0 for the clients used by skupper to
+// connect, attach links and exchange messages. It answers management requests
+// from qdr.Agent for listeners, connectors and ssl profiles, and acts as a
+// vanflow event source reporting the router and its tcp listeners and
+// connectors. It is meant for tests and is not a message router.
+package fake
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	amqp "github.com/Azure/go-amqp"
+	"github.com/skupperproject/skupper/pkg/vanflow"
+)
+
+type Config struct {
+	// ID of the router. Defaults to "fake-router".
+	ID string
+	// SiteID is set in the router metadata and as the parent of the router
+	// vanflow record
+	SiteID string
+	// Mode of the router, interior or edge. Defaults to interior.
+	Mode string
+	// TLSConfig enables TLS on the listener when set
+	TLSConfig *tls.Config
+	// BeaconInterval is the period at which vanflow beacons and heartbeats
+	// are sent. Defaults to ten seconds.
+	BeaconInterval time.Duration
+	Logger         *slog.Logger
+}
+
+// Router is an in-process stand-in for the skupper router
+type Router struct {
+	cfg      Config
+	listener net.Listener
+	logger   *slog.Logger
+
+	mu            sync.Mutex
+	conns         map[*conn]struct{}
+	subscriptions map[string][]*link
+	next          map[string]int
+	dynamic       int
+	entities      map[string]map[string]map[string]any
+	records       map[string]vanflow.Record
+
+	wg   sync.WaitGroup
+	done chan struct{}
+}
+
+// NewRouter starts a router listening on a random port of the loopback
+// interface
+func NewRouter(cfg Config) (*Router, error) {
+	if cfg.ID == "" {
+		cfg.ID = "fake-router"
+	}
+	if cfg.Mode == "" {
+		cfg.Mode = "interior"
+	}
+	if cfg.BeaconInterval <= 0 {
+		cfg.BeaconInterval = 10 * time.Second
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.New(slog.Default().Handler())
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	if cfg.TLSConfig != nil {
+		listener = tls.NewListener(listener, cfg.TLSConfig)
+	}
+	r := &Router{
+		cfg:           cfg,
+		listener:      listener,
+		logger:        cfg.Logger.With(slog.String("component", "fake.router"), slog.String("id", cfg.ID)),
+		conns:         make(map[*conn]struct{}),
+		subscriptions: make(map[string][]*link),
+		next:          make(map[string]int),
+		entities:      make(map[string]map[string]map[string]any),
+		records:       make(map[string]vanflow.Record),
+		done:          make(chan struct{}),
+	}
+	r.records[cfg.ID] = r.routerRecord()
+	r.wg.Add(2)
+	go r.accept()
+	go r.beacons()
+	return r, nil
+}
+
+// Addr is the host:port the router listens on
+func (r *Router) Addr() string {
+	return r.listener.Addr().String()
+}
+
+// URL to connect to the router with
+func (r *Router) URL() string {
+	if r.cfg.TLSConfig != nil {
+		return "amqps://" + r.Addr()
+	}
+	return "amqp://" + r.Addr()
+}
+
+// Close stops the router and closes all connections
+func (r *Router) Close() {
+	close(r.done)
+	r.listener.Close()
+	r.mu.Lock()
+	conns := make([]*conn, 0, len(r.conns))
+	for c := range r.conns {
+		conns = append(conns, c)
+	}
+	r.mu.Unlock()
+	for _, c := range conns {
+		c.mu.Lock()
+		if !c.closed {
+			c.closed = true
+			c.nc.Close()
+		}
+		c.mu.Unlock()
+	}
+	r.wg.Wait()
+}
+
+func (r *Router) accept() {
+	defer r.wg.Done()
+	for {
+		nc, err := r.listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				r.logger.Error("error accepting connection", slog.Any("error", err))
+			}
+			return
+		}
+		c := newConn(r, nc)
+		r.mu.Lock()
+		r.conns[c] = struct{}{}
+		r.mu.Unlock()
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			c.serve()
+		}()
+	}
+}
+
+func (r *Router) forget(c *conn) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.conns, c)
+}
+
+func (r *Router) dynamicAddress() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.dynamic++
+	return fmt.Sprintf("amqp:/_topo/0/%s/temp.%d", r.cfg.ID, r.dynamic)
+}
+
+func (r *Router) subscribe(l *link) {
+	r.mu.Lock()
+	r.subscriptions[l.address] = append(r.subscriptions[l.address], l)
+	r.mu.Unlock()
+	if l.address == beaconAddress || l.address == r.sourceAddress() {
+		// let new event source discovery find the router and new clients
+		// see a heartbeat right away
+		go r.beacon()
+	}
+}
+
+func (r *Router) unsubscribe(l *link) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	links := r.subscriptions[l.address]
+	for i, subscriber := range links {
+		if subscriber == l {
+			links = append(links[:i], links[i+1:]...)
+			break
+		}
+	}
+	if len(links) == 0 {
+		delete(r.subscriptions, l.address)
+		return
+	}
+	r.subscriptions[l.address] = links
+}
+
+// route delivers a message to the links subscribed to its address. Messages
+// to multicast addresses go to all subscribers, others to one of them in
+// turn. Messages without subscribers are dropped.
+func (r *Router) route(address string, data []byte, msg *amqp.Message) {
+	switch {
+	case r.isManagement(address):
+		r.handleManagement(msg)
+		return
+	case address == r.directAddress():
+		r.handleDirect(msg)
+		return
+	}
+	r.mu.Lock()
+	links := r.subscriptions[address]
+	var targets []*link
+	switch {
+	case len(links) == 0:
+	case strings.HasPrefix(address, "mc/"):
+		targets = append(targets, links...)
+	default:
+		i := r.next[address] % len(links)
+		r.next[address] = i + 1
+		targets = append(targets, links[i])
+	}
+	r.mu.Unlock()
+	for _, l := range targets {
+		l.deliver(data)
+	}
+}
+
+// send routes a message originating from the router
+func (r *Router) send(msg *amqp.Message) {
+	data, err := msg.MarshalBinary()
+	if err != nil {
+		r.logger.Error("error encoding message", slog.Any("error", err))
+		return
+	}
+	r.route(*msg.Properties.To, data, msg)
+}
@@ -0,0 +1,172 @@
+package fake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/skupperproject/skupper/internal/qdr"
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	"github.com/skupperproject/skupper/pkg/vanflow/eventsource"
+	vfsession "github.com/skupperproject/skupper/pkg/vanflow/session"
+	"gotest.tools/v3/assert"
+)
+
+func TestRouterManagement(t *testing.T) {
+	router, err := NewRouter(Config{ID: "router-1", SiteID: "site-1"})
+	assert.Assert(t, err)
+	defer router.Close()
+
+	agent, err := qdr.Connect(router.URL(), nil)
+	assert.Assert(t, err)
+	defer agent.Close()
+
+	local, err := agent.GetLocalRouter()
+	assert.Assert(t, err)
+	assert.Equal(t, local.Id, "router-1")
+	assert.Equal(t, local.Site.Id, "site-1")
+	assert.Assert(t, !local.Edge)
+
+	listener := qdr.TcpEndpoint{Name: "backend", Host: "0.0.0.0", Port: "8080", Address: "backend"}
+	assert.Assert(t, agent.Create("io.skupper.router.tcpListener", "backend", listener))
+	listeners, err := agent.GetLocalTcpListeners(nil)
+	assert.Assert(t, err)
+	assert.DeepEqual(t, listeners, []qdr.TcpEndpoint{listener})
+
+	listener.Port = "9090"
+	assert.Assert(t, agent.Update("io.skupper.router.tcpListener", "backend", listener))
+	listeners, err = agent.GetLocalTcpListeners(nil)
+	assert.Assert(t, err)
+	assert.DeepEqual(t, listeners, []qdr.TcpEndpoint{listener})
+
+	connector := qdr.TcpEndpoint{Name: "backend", Host: "backend.svc", Port: "8080", Address: "backend", ProcessID: "process-1"}
+	assert.Assert(t, agent.Create("io.skupper.router.tcpConnector", "backend", connector))
+	connectors, err := agent.GetLocalTcpConnectors(nil)
+	assert.Assert(t, err)
+	assert.DeepEqual(t, connectors, []qdr.TcpEndpoint{connector})
+
+	udpListener := qdr.UdpEndpoint{Name: "dns", Host: "0.0.0.0", Port: "5353", Address: "dns"}
+	assert.Assert(t, agent.Create("io.skupper.router.udpListener", "dns", udpListener))
+	udpConnector := qdr.UdpEndpoint{Name: "dns", Host: "dns.svc", Port: "53", Address: "dns", ProcessID: "process-2"}
+	assert.Assert(t, agent.Create("io.skupper.router.udpConnector", "dns", udpConnector))
+
+	verify := true
+	httpListener := qdr.HttpEndpoint{Name: "web", Host: "0.0.0.0", Port: "8081", Address: "web", ProtocolVersion: qdr.HttpVersion2}
+	assert.Assert(t, agent.Create("io.skupper.router.httpListener", "web", httpListener))
+	httpConnector := qdr.HttpEndpoint{Name: "web", Host: "web.svc", Port: "443", Address: "web", ProtocolVersion: qdr.HttpVersion2, SslProfile: "web-profile", VerifyHostname: &verify}
+	assert.Assert(t, agent.Create("io.skupper.router.httpConnector", "web", httpConnector))
+	httpListeners, err := agent.GetLocalHttpListeners()
+	assert.Assert(t, err)
+	assert.DeepEqual(t, httpListeners, []qdr.HttpEndpoint{httpListener})
+	httpConnectors, err := agent.GetLocalHttpConnectors()
+	assert.Assert(t, err)
+	assert.DeepEqual(t, httpConnectors, []qdr.HttpEndpoint{httpConnector})
+
+	bridges, err := agent.GetLocalBridgeConfig()
+	assert.Assert(t, err)
+	assert.DeepEqual(t, bridges.TcpConnectors["backend"], connector)
+	assert.DeepEqual(t, bridges.TcpListeners["backend"], listener)
+	assert.DeepEqual(t, bridges.UdpConnectors["dns"], udpConnector)
+	assert.DeepEqual(t, bridges.UdpListeners["dns"], udpListener)
+	assert.DeepEqual(t, bridges.HttpConnectors["web"], httpConnector)
+	assert.DeepEqual(t, bridges.HttpListeners["web"], httpListener)
+
+	assert.Assert(t, agent.Delete("io.skupper.router.udpListener", "dns"))
+	assert.Assert(t, agent.Delete("io.skupper.router.httpListener", "web"))
+	assert.Equal(t, len(router.Entities("udpListener")), 0)
+	assert.Equal(t, len(router.Entities("httpListener")), 0)
+
+	profile := qdr.SslProfile{Name: "link-profile", CertFile: "tls.crt", PrivateKeyFile: "tls.key", CaCertFile: "ca.crt"}
+	assert.Assert(t, agent.Create("io.skupper.router.sslProfile", "link-profile", profile))
+	profiles, err := agent.GetSslProfiles()
+	assert.Assert(t, err)
+	assert.DeepEqual(t, profiles, map[string]qdr.SslProfile{"link-profile": profile})
+
+	link := qdr.Connector{Name: "link-1", Role: qdr.RoleInterRouter, Host: "peer", Port: "55671", Cost: 1, SslProfile: "link-profile"}
+	assert.Assert(t, agent.Create("io.skupper.router.connector", "link-1", link))
+	found, err := agent.GetConnectorByName("link-1")
+	assert.Assert(t, err)
+	assert.Equal(t, found.Host, "peer")
+	assert.Equal(t, found.SslProfile, "link-profile")
+
+	closest := qdr.Address{Prefix: "backend", Distribution: "closest"}
+	assert.Assert(t, agent.UpdateAddressConfig(qdr.AddressesDifference(nil, map[string]qdr.Address{"backend": closest})))
+	addresses, err := agent.GetLocalAddresses()
+	assert.Assert(t, err)
+	assert.DeepEqual(t, addresses, map[string]qdr.Address{"backend": {Name: "backend", Prefix: "backend", Distribution: "closest"}})
+	assert.Assert(t, agent.UpdateAddressConfig(qdr.AddressesDifference(addresses, nil)))
+	assert.Equal(t, len(router.Entities("router.config.address")), 0)
+
+	assert.Assert(t, agent.Delete("io.skupper.router.tcpListener", "backend"))
+	listeners, err = agent.GetLocalTcpListeners(nil)
+	assert.Assert(t, err)
+	assert.Equal(t, len(listeners), 0)
+	assert.Equal(t, len(router.Entities("tcpListener")), 0)
+	assert.Equal(t, router.Entities("tcpConnector")["backend"]["processId"], "process-1")
+
+	_, err = agent.Query("io.skupper.router.unknown", []string{})
+	assert.ErrorContains(t, err, "Unsupported entity type")
+}
+
+func TestRouterVanflow(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	router, err := NewRouter(Config{ID: "router-1", SiteID: "site-1"})
+	assert.Assert(t, err)
+	defer router.Close()
+
+	container := vfsession.NewContainer(router.URL(), vfsession.ContainerConfig{ContainerID: "test"})
+	container.Start(ctx)
+	records := make(chan vanflow.Record, 32)
+	discovery := eventsource.NewDiscovery(container, eventsource.DiscoveryOptions{})
+	go discovery.Run(ctx, eventsource.DiscoveryHandlers{
+		Discovered: func(source eventsource.Info) {
+			assert.Check(t, source.ID == "router-1" && source.Type == "ROUTER")
+			client := eventsource.NewClient(container, eventsource.ClientOptions{Source: source})
+			client.OnRecord(func(msg vanflow.RecordMessage) {
+				for _, record := range msg.Records {
+					records <- record
+				}
+			})
+			client.Listen(ctx, eventsource.FromSourceAddress())
+			go eventsource.FlushOnFirstMessage(ctx, client)
+		},
+	})
+	next := func() vanflow.Record {
+		t.Helper()
+		select {
+		case record := <-records:
+			return record
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for records")
+			return nil
+		}
+	}
+
+	routerRecord, ok := next().(vanflow.RouterRecord)
+	assert.Assert(t, ok)
+	assert.Equal(t, routerRecord.ID, "router-1")
+	assert.Equal(t, *routerRecord.Parent, "site-1")
+
+	agent, err := qdr.Connect(router.URL(), nil)
+	assert.Assert(t, err)
+	defer agent.Close()
+	assert.Assert(t, agent.Create("io.skupper.router.tcpListener", "backend",
+		qdr.TcpEndpoint{Name: "backend", Host: "0.0.0.0", Port: "8080", Address: "backend"}))
+	listener, ok := next().(vanflow.ListenerRecord)
+	assert.Assert(t, ok)
+	assert.Equal(t, *listener.Name, "backend")
+	assert.Equal(t, *listener.Address, "backend")
+	assert.Equal(t, *listener.Parent, "router-1")
+
+	assert.Assert(t, agent.Delete("io.skupper.router.tcpListener", "backend"))
+	deleted, ok := next().(vanflow.ListenerRecord)
+	assert.Assert(t, ok)
+	assert.Equal(t, deleted.ID, listener.ID)
+	assert.Assert(t, deleted.EndTime != nil)
+
+	router.Emit(vanflow.SiteRecord{BaseRecord: vanflow.NewBase("site-1", time.Now())})
+	site, ok := next().(vanflow.SiteRecord)
+	assert.Assert(t, ok)
+	assert.Equal(t, site.ID, "site-1")
+}
@@ -0,0 +1,185 @@
+package fake
+
+import (
+	"log/slog"
+	"maps"
+	"reflect"
+	"slices"
+	"time"
+
+	amqp "github.com/Azure/go-amqp"
+	"github.com/skupperproject/skupper/pkg/vanflow"
+)
+
+const (
+	version       = "fake"
+	beaconAddress = "mc/sfe.all"
+)
+
+// sourceAddress is where the router sends its vanflow records
+func (r *Router) sourceAddress() string {
+	return "mc/sfe." + r.cfg.ID
+}
+
+// directAddress is where the router receives vanflow flush requests
+func (r *Router) directAddress() string {
+	return "sfe." + r.cfg.ID
+}
+
+func (r *Router) beacons() {
+	defer r.wg.Done()
+	ticker := time.NewTicker(r.cfg.BeaconInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.done:
+			return
+		case <-ticker.C:
+			r.beacon()
+		}
+	}
+}
+
+// beacon advertises the router as a vanflow event source
+func (r *Router) beacon() {
+	r.send(vanflow.BeaconMessage{
+		Version:    1,
+		SourceType: "ROUTER",
+		Address:    r.sourceAddress(),
+		Direct:     r.directAddress(),
+		Identity:   r.cfg.ID,
+	}.Encode())
+	r.send(vanflow.HeartbeatMessage{
+		Identity: r.cfg.ID,
+		Version:  1,
+		Now:      uint64(time.Now().UnixMicro()),
+	}.Encode())
+}
+
+// handleDirect answers flush requests with all the records of the router
+func (r *Router) handleDirect(msg *amqp.Message) {
+	if msg.Properties == nil || msg.Properties.Subject == nil || *msg.Properties.Subject != "FLUSH" {
+		return
+	}
+	r.mu.Lock()
+	records := make([]vanflow.Record, 0, len(r.records))
+	for _, id := range slices.Sorted(maps.Keys(r.records)) {
+		records = append(records, r.records[id])
+	}
+	r.mu.Unlock()
+	r.publish(records...)
+}
+
+// Emit sends vanflow records from the router. Records with an end time are
+// forgotten, others are included in the responses to flush requests.
+func (r *Router) Emit(records ...vanflow.Record) {
+	r.mu.Lock()
+	for _, record := range records {
+		if ended(record) {
+			delete(r.records, record.Identity())
+			continue
+		}
+		r.records[record.Identity()] = record
+	}
+	r.mu.Unlock()
+	r.publish(records...)
+}
+
+func (r *Router) publish(records ...vanflow.Record) {
+	if len(records) == 0 {
+		return
+	}
+	msg, err := vanflow.RecordMessage{
+		MessageProps: vanflow.MessageProps{To: r.sourceAddress()},
+		Records:      records,
+	}.Encode()
+	if err != nil {
+		r.logger.Error("error encoding records", slog.Any("error", err))
+		return
+	}
+	r.send(msg)
+}
+
+func (r *Router) routerRecord() vanflow.Record {
+	record := vanflow.RouterRecord{
+		BaseRecord:   vanflow.NewBase(r.cfg.ID, time.Now()),
+		Name:         &r.cfg.ID,
+		Mode:         &r.cfg.Mode,
+		BuildVersion: ptrTo(version),
+	}
+	if r.cfg.SiteID != "" {
+		record.Parent = &r.cfg.SiteID
+	}
+	return record
+}
+
+// entityChanged reports tcp listeners and connectors as vanflow records
+func (r *Router) entityChanged(typename string, name string, attributes map[string]any) {
+	str := func(key string) *string {
+		if value, ok := attributes[key].(string); ok {
+			return &value
+		}
+		return nil
+	}
+	tcp := "tcp"
+	switch typename {
+	case entityTypePrefix + "tcpListener":
+		r.Emit(vanflow.ListenerRecord{
+			BaseRecord: vanflow.NewBase(r.recordID(typename, name), time.Now()),
+			Parent:     &r.cfg.ID,
+			Name:       &name,
+			Protocol:   &tcp,
+			DestHost:   str("host"),
+			DestPort:   str("port"),
+			Address:    str("address"),
+		})
+	case entityTypePrefix + "tcpConnector":
+		r.Emit(vanflow.ConnectorRecord{
+			BaseRecord: vanflow.NewBase(r.recordID(typename, name), time.Now()),
+			Parent:     &r.cfg.ID,
+			Name:       &name,
+			Protocol:   &tcp,
+			DestHost:   str("host"),
+			DestPort:   str("port"),
+			Address:    str("address"),
+			ProcessID:  str("processId"),
+		})
+	}
+}
+
+func (r *Router) entityDeleted(typename string, name string) {
+	id := r.recordID(typename, name)
+	r.mu.Lock()
+	record, ok := r.records[id]
+	r.mu.Unlock()
+	if !ok {
+		return
+	}
+	now := time.Now()
+	switch record := record.(type) {
+	case vanflow.ListenerRecord:
+		record.EndTime = &vanflow.Time{Time: now}
+		r.Emit(record)
+	case vanflow.ConnectorRecord:
+		record.EndTime = &vanflow.Time{Time: now}
+		r.Emit(record)
+	}
+}
+
+func (r *Router) recordID(typename string, name string) string {
+	return r.cfg.ID + ":" + typename[len(entityTypePrefix):] + ":" + name
+}
+
+// ended returns true for records with an end time
+func ended(record vanflow.Record) bool {
+	v := reflect.ValueOf(record)
+	if v.Kind() != reflect.Struct {
+		return false
+	}
+	endTime := v.FieldByName("EndTime")
+	return endTime.IsValid() && endTime.Kind() == reflect.Pointer && !endTime.IsNil()
+}
+
+func ptrTo[T any](v T) *T {
+	return &v
+}
@@ -39,12 +39,11 @@ func (p *PortMapping) recovered(key string, portstr string) {
 		log.Printf("Failed to convert port %q to int: %s", portstr, err)
 		return
 	}
-	p.pool.InUse(port)
-	p.mappings[key] = port
-	if existing, ok := p.mappings[key]; ok {
+	if existing, ok := p.mappings[key]; ok && existing != port {
 		p.pool.Release(existing)
-		delete(p.mappings, key)
 	}
+	p.pool.InUse(port)
+	p.mappings[key] = port
 }
 
 func RecoverPortMapping(config *RouterConfig) *PortMapping {
@@ -60,6 +59,12 @@ func RecoverPortMapping(config *RouterConfig) *PortMapping {
 		for key, listener := range config.Bridges.TcpListeners {
 			mapping.recovered(key, listener.Port)
 		}
+		for key, listener := range config.Bridges.UdpListeners {
+			mapping.recovered(key, listener.Port)
+		}
+		for key, listener := range config.Bridges.HttpListeners {
+			mapping.recovered(key, listener.Port)
+		}
 	}
 	return mapping
 }
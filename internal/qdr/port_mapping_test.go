@@ -0,0 +1,38 @@
+package qdr
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestRecoverPortMapping(t *testing.T) {
+	config := InitialConfig("test", "site-id", "v1", false, 3)
+	config.AddListener(Listener{Name: "amqp", Port: 1024})
+	config.AddTcpListener(TcpEndpoint{Name: "tcp", Port: "1025", Address: "tcp"})
+	config.AddUdpListener(UdpEndpoint{Name: "udp", Port: "1026", Address: "udp"})
+	config.AddHttpListener(HttpEndpoint{Name: "http", Port: "1027", Address: "http"})
+	mapping := RecoverPortMapping(&config)
+
+	for key, expected := range map[string]int{"tcp": 1025, "udp": 1026, "http": 1027} {
+		port, err := mapping.GetPortForKey(key)
+		assert.Assert(t, err)
+		assert.Equal(t, port, expected, key)
+	}
+	port, err := mapping.GetPortForKey("new")
+	assert.Assert(t, err)
+	assert.Equal(t, port, 1028)
+
+	// a released port is available for allocation again
+	mapping.ReleasePortForKey("udp")
+	port, err = mapping.GetPortForKey("other")
+	assert.Assert(t, err)
+	assert.Equal(t, port, 1026)
+}
+
+func TestRecoverPortMappingNoConfig(t *testing.T) {
+	mapping := RecoverPortMapping(nil)
+	port, err := mapping.GetPortForKey("new")
+	assert.Assert(t, err)
+	assert.Equal(t, port, 1024)
+}
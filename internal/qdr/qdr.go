@@ -33,10 +33,16 @@ type RouterConfigHandler interface {
 }
 
 type TcpEndpointMap map[string]TcpEndpoint
+type UdpEndpointMap map[string]UdpEndpoint
+type HttpEndpointMap map[string]HttpEndpoint
 
 type BridgeConfig struct {
-	TcpListeners  TcpEndpointMap
-	TcpConnectors TcpEndpointMap
+	TcpListeners   TcpEndpointMap
+	TcpConnectors  TcpEndpointMap
+	UdpListeners   UdpEndpointMap
+	UdpConnectors  UdpEndpointMap
+	HttpListeners  HttpEndpointMap
+	HttpConnectors HttpEndpointMap
 }
 
 func InitialConfig(id string, siteId string, version string, edge bool, helloAge int) RouterConfig {
@@ -51,10 +57,7 @@ func InitialConfig(id string, siteId string, version string, edge bool, helloAge
 		Listeners:   map[string]Listener{},
 		Connectors:  map[string]Connector{},
 		LogConfig:   map[string]LogConfig{},
-		Bridges: BridgeConfig{
-			TcpListeners:  map[string]TcpEndpoint{},
-			TcpConnectors: map[string]TcpEndpoint{},
-		},
+		Bridges:     NewBridgeConfig(),
 	}
 	if edge {
 		config.Metadata.Mode = ModeEdge
@@ -78,8 +81,12 @@ func (r *RouterConfig) AddHealthAndMetricsListener(port int32) {
 
 func NewBridgeConfig() BridgeConfig {
 	return BridgeConfig{
-		TcpListeners:  map[string]TcpEndpoint{},
-		TcpConnectors: map[string]TcpEndpoint{},
+		TcpListeners:   map[string]TcpEndpoint{},
+		TcpConnectors:  map[string]TcpEndpoint{},
+		UdpListeners:   map[string]UdpEndpoint{},
+		UdpConnectors:  map[string]UdpEndpoint{},
+		HttpListeners:  map[string]HttpEndpoint{},
+		HttpConnectors: map[string]HttpEndpoint{},
 	}
 }
 
@@ -91,6 +98,18 @@ func NewBridgeConfigCopy(src BridgeConfig) BridgeConfig {
 	for k, v := range src.TcpConnectors {
 		newBridges.TcpConnectors[k] = v
 	}
+	for k, v := range src.UdpListeners {
+		newBridges.UdpListeners[k] = v
+	}
+	for k, v := range src.UdpConnectors {
+		newBridges.UdpConnectors[k] = v
+	}
+	for k, v := range src.HttpListeners {
+		newBridges.HttpListeners[k] = v
+	}
+	for k, v := range src.HttpConnectors {
+		newBridges.HttpConnectors[k] = v
+	}
 	return newBridges
 }
 
@@ -198,6 +217,12 @@ func (r *RouterConfig) UnreferencedSslProfiles() map[string]SslProfile {
 	for _, o := range r.Bridges.TcpConnectors {
 		delete(results, o.SslProfile)
 	}
+	for _, o := range r.Bridges.HttpListeners {
+		delete(results, o.SslProfile)
+	}
+	for _, o := range r.Bridges.HttpConnectors {
+		delete(results, o.SslProfile)
+	}
 
 	return results
 }
@@ -222,6 +247,38 @@ func (r *RouterConfig) RemoveTcpListener(name string) (bool, TcpEndpoint) {
 	return r.Bridges.RemoveTcpListener(name)
 }
 
+func (r *RouterConfig) AddUdpConnector(e UdpEndpoint) {
+	r.Bridges.AddUdpConnector(e)
+}
+
+func (r *RouterConfig) RemoveUdpConnector(name string) (bool, UdpEndpoint) {
+	return r.Bridges.RemoveUdpConnector(name)
+}
+
+func (r *RouterConfig) AddUdpListener(e UdpEndpoint) {
+	r.Bridges.AddUdpListener(e)
+}
+
+func (r *RouterConfig) RemoveUdpListener(name string) (bool, UdpEndpoint) {
+	return r.Bridges.RemoveUdpListener(name)
+}
+
+func (r *RouterConfig) AddHttpConnector(e HttpEndpoint) {
+	r.Bridges.AddHttpConnector(e)
+}
+
+func (r *RouterConfig) RemoveHttpConnector(name string) (bool, HttpEndpoint) {
+	return r.Bridges.RemoveHttpConnector(name)
+}
+
+func (r *RouterConfig) AddHttpListener(e HttpEndpoint) {
+	r.Bridges.AddHttpListener(e)
+}
+
+func (r *RouterConfig) RemoveHttpListener(name string) (bool, HttpEndpoint) {
+	return r.Bridges.RemoveHttpListener(name)
+}
+
 func (r *RouterConfig) UpdateBridgeConfig(desired BridgeConfig) bool {
 	if reflect.DeepEqual(r.Bridges, desired) {
 		return false
@@ -267,6 +324,62 @@ func (bc *BridgeConfig) RemoveTcpListener(name string) (bool, TcpEndpoint) {
 	}
 }
 
+func (bc *BridgeConfig) AddUdpConnector(e UdpEndpoint) {
+	bc.UdpConnectors[e.Name] = e
+}
+
+func (bc *BridgeConfig) RemoveUdpConnector(name string) (bool, UdpEndpoint) {
+	uc, ok := bc.UdpConnectors[name]
+	if ok {
+		delete(bc.UdpConnectors, name)
+		return true, uc
+	} else {
+		return false, UdpEndpoint{}
+	}
+}
+
+func (bc *BridgeConfig) AddUdpListener(e UdpEndpoint) {
+	bc.UdpListeners[e.Name] = e
+}
+
+func (bc *BridgeConfig) RemoveUdpListener(name string) (bool, UdpEndpoint) {
+	ul, ok := bc.UdpListeners[name]
+	if ok {
+		delete(bc.UdpListeners, name)
+		return true, ul
+	} else {
+		return false, UdpEndpoint{}
+	}
+}
+
+func (bc *BridgeConfig) AddHttpConnector(e HttpEndpoint) {
+	bc.HttpConnectors[e.Name] = e
+}
+
+func (bc *BridgeConfig) RemoveHttpConnector(name string) (bool, HttpEndpoint) {
+	hc, ok := bc.HttpConnectors[name]
+	if ok {
+		delete(bc.HttpConnectors, name)
+		return true, hc
+	} else {
+		return false, HttpEndpoint{}
+	}
+}
+
+func (bc *BridgeConfig) AddHttpListener(e HttpEndpoint) {
+	bc.HttpListeners[e.Name] = e
+}
+
+func (bc *BridgeConfig) RemoveHttpListener(name string) (bool, HttpEndpoint) {
+	hl, ok := bc.HttpListeners[name]
+	if ok {
+		delete(bc.HttpListeners, name)
+		return true, hl
+	} else {
+		return false, HttpEndpoint{}
+	}
+}
+
 func GetTcpConnectors(bridges []BridgeConfig) []TcpEndpoint {
 	connectors := []TcpEndpoint{}
 	for _, bridge := range bridges {
@@ -527,10 +640,25 @@ const (
 )
 
 type Address struct {
+	Name         string `json:"name,omitempty"`
 	Prefix       string `json:"prefix,omitempty"`
 	Distribution string `json:"distribution,omitempty"`
 }
 
+func (a Address) toRecord() Record {
+	result := make(map[string]any)
+	if a.Name != "" {
+		result["name"] = a.Name
+	}
+	if a.Prefix != "" {
+		result["prefix"] = a.Prefix
+	}
+	if a.Distribution != "" {
+		result["distribution"] = a.Distribution
+	}
+	return result
+}
+
 type TcpEndpoint struct {
 	Name           string `json:"name,omitempty"`
 	Host           string `json:"host,omitempty"`
@@ -571,6 +699,89 @@ func (e TcpEndpoint) toRecord() Record {
 	return result
 }
 
+type UdpEndpoint struct {
+	Name      string `json:"name,omitempty"`
+	Host      string `json:"host,omitempty"`
+	Port      string `json:"port,omitempty"`
+	Address   string `json:"address,omitempty"`
+	SiteId    string `json:"siteId,omitempty"`
+	ProcessID string `json:"processId,omitempty"`
+}
+
+func (e UdpEndpoint) toRecord() Record {
+	result := make(map[string]any)
+	if e.Name != "" {
+		result["name"] = e.Name
+	}
+	if e.Host != "" {
+		result["host"] = e.Host
+	}
+	if e.Port != "" {
+		result["port"] = e.Port
+	}
+	if e.Address != "" {
+		result["address"] = e.Address
+	}
+	if e.SiteId != "" {
+		result["siteId"] = e.SiteId
+	}
+	if e.ProcessID != "" {
+		result["processId"] = e.ProcessID
+	}
+	return result
+}
+
+type HttpProtocolVersion string
+
+const (
+	HttpVersion1 HttpProtocolVersion = "HTTP1"
+	HttpVersion2 HttpProtocolVersion = "HTTP2"
+)
+
+type HttpEndpoint struct {
+	Name            string              `json:"name,omitempty"`
+	Host            string              `json:"host,omitempty"`
+	Port            string              `json:"port,omitempty"`
+	Address         string              `json:"address,omitempty"`
+	SiteId          string              `json:"siteId,omitempty"`
+	ProtocolVersion HttpProtocolVersion `json:"protocolVersion,omitempty"`
+	SslProfile      string              `json:"sslProfile,omitempty"`
+	VerifyHostname  *bool               `json:"verifyHostname,omitempty"`
+	ProcessID       string              `json:"processId,omitempty"`
+}
+
+func (e HttpEndpoint) toRecord() Record {
+	result := make(map[string]any)
+	if e.Name != "" {
+		result["name"] = e.Name
+	}
+	if e.Host != "" {
+		result["host"] = e.Host
+	}
+	if e.Port != "" {
+		result["port"] = e.Port
+	}
+	if e.Address != "" {
+		result["address"] = e.Address
+	}
+	if e.SiteId != "" {
+		result["siteId"] = e.SiteId
+	}
+	if e.ProtocolVersion != "" {
+		result["protocolVersion"] = string(e.ProtocolVersion)
+	}
+	if e.SslProfile != "" {
+		result["sslProfile"] = e.SslProfile
+	}
+	if e.VerifyHostname != nil {
+		result["verifyHostname"] = *e.VerifyHostname
+	}
+	if e.ProcessID != "" {
+		result["processId"] = e.ProcessID
+	}
+	return result
+}
+
 type SiteConfig struct {
 	Name      string `json:"name,omitempty"`
 	Location  string `json:"location,omitempty"`
@@ -612,10 +823,7 @@ func UnmarshalRouterConfig(config string) (RouterConfig, error) {
 		Listeners:   map[string]Listener{},
 		Connectors:  map[string]Connector{},
 		LogConfig:   map[string]LogConfig{},
-		Bridges: BridgeConfig{
-			TcpListeners:  map[string]TcpEndpoint{},
-			TcpConnectors: map[string]TcpEndpoint{},
-		},
+		Bridges:     NewBridgeConfig(),
 	}
 	var obj interface{}
 	err := json.Unmarshal([]byte(config), &obj)
@@ -699,6 +907,34 @@ func UnmarshalRouterConfig(config string) (RouterConfig, error) {
 				return result, fmt.Errorf("Invalid %s element got %#v", entityType, element[1])
 			}
 			result.Bridges.TcpListeners[listener.Name] = listener
+		case "udpConnector":
+			connector := UdpEndpoint{}
+			err = convert(element[1], &connector)
+			if err != nil {
+				return result, fmt.Errorf("Invalid %s element got %#v", entityType, element[1])
+			}
+			result.Bridges.UdpConnectors[connector.Name] = connector
+		case "udpListener":
+			listener := UdpEndpoint{}
+			err = convert(element[1], &listener)
+			if err != nil {
+				return result, fmt.Errorf("Invalid %s element got %#v", entityType, element[1])
+			}
+			result.Bridges.UdpListeners[listener.Name] = listener
+		case "httpConnector":
+			connector := HttpEndpoint{}
+			err = convert(element[1], &connector)
+			if err != nil {
+				return result, fmt.Errorf("Invalid %s element got %#v", entityType, element[1])
+			}
+			result.Bridges.HttpConnectors[connector.Name] = connector
+		case "httpListener":
+			listener := HttpEndpoint{}
+			err = convert(element[1], &listener)
+			if err != nil {
+				return result, fmt.Errorf("Invalid %s element got %#v", entityType, element[1])
+			}
+			result.Bridges.HttpListeners[listener.Name] = listener
 		default:
 		}
 	}
@@ -754,6 +990,34 @@ func MarshalRouterConfig(config RouterConfig) (string, error) {
 		}
 		elements = append(elements, tuple)
 	}
+	for _, e := range config.Bridges.UdpConnectors {
+		tuple := []interface{}{
+			"udpConnector",
+			e,
+		}
+		elements = append(elements, tuple)
+	}
+	for _, e := range config.Bridges.UdpListeners {
+		tuple := []interface{}{
+			"udpListener",
+			e,
+		}
+		elements = append(elements, tuple)
+	}
+	for _, e := range config.Bridges.HttpConnectors {
+		tuple := []interface{}{
+			"httpConnector",
+			e,
+		}
+		elements = append(elements, tuple)
+	}
+	for _, e := range config.Bridges.HttpListeners {
+		tuple := []interface{}{
+			"httpListener",
+			e,
+		}
+		elements = append(elements, tuple)
+	}
 	for _, e := range config.LogConfig {
 		tuple := []interface{}{
 			"log",
@@ -886,9 +1150,23 @@ type TcpEndpointDifference struct {
 	Added   []TcpEndpoint
 }
 
+type UdpEndpointDifference struct {
+	Deleted []string
+	Added   []UdpEndpoint
+}
+
+type HttpEndpointDifference struct {
+	Deleted []string
+	Added   []HttpEndpoint
+}
+
 type BridgeConfigDifference struct {
 	TcpListeners       TcpEndpointDifference
 	TcpConnectors      TcpEndpointDifference
+	UdpListeners       UdpEndpointDifference
+	UdpConnectors      UdpEndpointDifference
+	HttpListeners      HttpEndpointDifference
+	HttpConnectors     HttpEndpointDifference
 	AddedSslProfiles   []string
 	DeletedSSlProfiles []string
 }
@@ -948,10 +1226,91 @@ func (a TcpEndpointMap) Difference(b TcpEndpointMap) TcpEndpointDifference {
 	return result
 }
 
+func (a UdpEndpoint) Equivalent(b UdpEndpoint) bool {
+	if !equivalentHost(a.Host, b.Host) || a.Port != b.Port || a.Address != b.Address ||
+		a.SiteId != b.SiteId || a.ProcessID != b.ProcessID {
+		return false
+	}
+	return true
+}
+
+func (a UdpEndpointMap) Difference(b UdpEndpointMap) UdpEndpointDifference {
+	result := UdpEndpointDifference{}
+	for key, v1 := range b {
+		v2, ok := a[key]
+		if !ok {
+			result.Added = append(result.Added, v1)
+		} else if !v1.Equivalent(v2) {
+			result.Deleted = append(result.Deleted, v1.Name)
+			result.Added = append(result.Added, v1)
+		}
+	}
+	for key, v1 := range a {
+		_, ok := b[key]
+		if !ok {
+			result.Deleted = append(result.Deleted, v1.Name)
+		}
+	}
+	return result
+}
+
+func (a HttpEndpoint) equivalentVerifyHostname(b HttpEndpoint) bool {
+	if a.VerifyHostname == nil {
+		return b.VerifyHostname == nil || *b.VerifyHostname == true
+	}
+	if b.VerifyHostname == nil {
+		return a.VerifyHostname == nil || *a.VerifyHostname == true
+	}
+	return *a.VerifyHostname == *b.VerifyHostname
+}
+
+func equivalentProtocolVersion(a HttpProtocolVersion, b HttpProtocolVersion) bool {
+	if a == "" {
+		a = HttpVersion1
+	}
+	if b == "" {
+		b = HttpVersion1
+	}
+	return a == b
+}
+
+func (a HttpEndpoint) Equivalent(b HttpEndpoint) bool {
+	if !equivalentHost(a.Host, b.Host) || a.Port != b.Port || a.Address != b.Address ||
+		a.SiteId != b.SiteId || a.ProcessID != b.ProcessID || a.SslProfile != b.SslProfile ||
+		!equivalentProtocolVersion(a.ProtocolVersion, b.ProtocolVersion) || !a.equivalentVerifyHostname(b) {
+		return false
+	}
+	return true
+}
+
+func (a HttpEndpointMap) Difference(b HttpEndpointMap) HttpEndpointDifference {
+	result := HttpEndpointDifference{}
+	for key, v1 := range b {
+		v2, ok := a[key]
+		if !ok {
+			result.Added = append(result.Added, v1)
+		} else if !v1.Equivalent(v2) {
+			result.Deleted = append(result.Deleted, v1.Name)
+			result.Added = append(result.Added, v1)
+		}
+	}
+	for key, v1 := range a {
+		_, ok := b[key]
+		if !ok {
+			result.Deleted = append(result.Deleted, v1.Name)
+		}
+	}
+	return result
+}
+
 func (a *BridgeConfig) Difference(b *BridgeConfig) *BridgeConfigDifference {
 	result := BridgeConfigDifference{
-		TcpConnectors: a.TcpConnectors.Difference(b.TcpConnectors),
-		TcpListeners:  a.TcpListeners.Difference(b.TcpListeners),
+		TcpConnectors:  a.TcpConnectors.Difference(b.TcpConnectors),
+		TcpListeners:   a.TcpListeners.Difference(b.TcpListeners),
+		UdpConnectors:  a.UdpConnectors.Difference(b.UdpConnectors),
+		UdpListeners:   a.UdpListeners.Difference(b.UdpListeners),
+		HttpConnectors: a.HttpConnectors.Difference(b.HttpConnectors),
+		HttpListeners:  a.HttpListeners.Difference(b.HttpListeners),
 	}
 
 	result.AddedSslProfiles, result.DeletedSSlProfiles = getSslProfilesDifference(a, b)
@@ -975,6 +1334,12 @@ func getSslProfilesDifference(before *BridgeConfig, desired *BridgeConfig) (Adde
 	for _, tcpListener := range before.TcpListeners {
 		originalSslConfig[tcpListener.SslProfile] = tcpListener.SslProfile
 	}
+	for _, httpConnector := range before.HttpConnectors {
+		originalSslConfig[httpConnector.SslProfile] = httpConnector.SslProfile
+	}
+	for _, httpListener := range before.HttpListeners {
+		originalSslConfig[httpListener.SslProfile] = httpListener.SslProfile
+	}
 
 	for _, tcpConnector := range desired.TcpConnectors {
 		newSslConfig[tcpConnector.SslProfile] = tcpConnector.SslProfile
@@ -982,6 +1347,12 @@ func getSslProfilesDifference(before *BridgeConfig, desired *BridgeConfig) (Adde
 	for _, tcpListener := range desired.TcpListeners {
 		newSslConfig[tcpListener.SslProfile] = tcpListener.SslProfile
 	}
+	for _, httpConnector := range desired.HttpConnectors {
+		newSslConfig[httpConnector.SslProfile] = httpConnector.SslProfile
+	}
+	for _, httpListener := range desired.HttpListeners {
+		newSslConfig[httpListener.SslProfile] = httpListener.SslProfile
+	}
 
 	//Auto-generated Skupper certs will be deleted if they are not used in the desired configuration
 	for key, name := range originalSslConfig {
@@ -1012,13 +1383,27 @@ func (a *TcpEndpointDifference) Empty() bool {
 	return len(a.Deleted) == 0 && len(a.Added) == 0
 }
 
+func (a *UdpEndpointDifference) Empty() bool {
+	return len(a.Deleted) == 0 && len(a.Added) == 0
+}
+
+func (a *HttpEndpointDifference) Empty() bool {
+	return len(a.Deleted) == 0 && len(a.Added) == 0
+}
+
 func (a *BridgeConfigDifference) Empty() bool {
-	return a.TcpConnectors.Empty() && a.TcpListeners.Empty()
+	return a.TcpConnectors.Empty() && a.TcpListeners.Empty() &&
+		a.UdpConnectors.Empty() && a.UdpListeners.Empty() &&
+		a.HttpConnectors.Empty() && a.HttpListeners.Empty()
 }
 
 func (a *BridgeConfigDifference) Print() {
 	log.Printf("TcpConnectors added=%v, deleted=%v", a.TcpConnectors.Added, a.TcpConnectors.Deleted)
 	log.Printf("TcpListeners added=%v, deleted=%v", a.TcpListeners.Added, a.TcpListeners.Deleted)
+	log.Printf("UdpConnectors added=%v, deleted=%v", a.UdpConnectors.Added, a.UdpConnectors.Deleted)
+	log.Printf("UdpListeners added=%v, deleted=%v", a.UdpListeners.Added, a.UdpListeners.Deleted)
+	log.Printf("HttpConnectors added=%v, deleted=%v", a.HttpConnectors.Added, a.HttpConnectors.Deleted)
+	log.Printf("HttpListeners added=%v, deleted=%v", a.HttpListeners.Added, a.HttpListeners.Deleted)
 	log.Printf("SslProfiles added=%v, deleted=%v", a.AddedSslProfiles, a.DeletedSSlProfiles)
 }
 
@@ -1026,10 +1411,15 @@ func ConnectorsDifference(actual map[string]Connector, desired *RouterConfig, ig
 	result := ConnectorDifference{}
 	result.AddedSslProfiles = make(map[string]SslProfile)
 	for key, v1 := range desired.Connectors {
-		_, ok := actual[key]
+		v2, ok := actual[key]
 		if !ok {
 			result.Added = append(result.Added, v1)
 			result.AddedSslProfiles[v1.SslProfile] = desired.SslProfiles[v1.SslProfile]
+		} else if !v1.Equivalent(v2) {
+			// handle change as delete then add, as connectors cannot be updated in place
+			result.Deleted = append(result.Deleted, v2)
+			result.Added = append(result.Added, v1)
+			result.AddedSslProfiles[v1.SslProfile] = desired.SslProfiles[v1.SslProfile]
 		}
 	}
 	for key, v1 := range actual {
@@ -1047,10 +1437,66 @@ func ConnectorsDifference(actual map[string]Connector, desired *RouterConfig, ig
 	return &result
 }
 
+// Equivalent compares connectors ignoring their cost, which only
+// applies to links established after the connector is created.
+// Changing it would mean recreating the connector, dropping the link
+// and every flow routed over it.
+func (desired Connector) Equivalent(actual Connector) bool {
+	return desired.Name == actual.Name &&
+		desired.Host == actual.Host &&
+		desired.Port == actual.Port &&
+		desired.SslProfile == actual.SslProfile
+}
+
 func (a *ConnectorDifference) Empty() bool {
 	return len(a.Deleted) == 0 && len(a.Added) == 0
 }
 
+type AddressDifference struct {
+	Deleted []Address
+	Added   []Address
+}
+
+// Equivalent compares the distribution of addresses, which defaults
+// to balanced when not set
+func (desired Address) Equivalent(actual Address) bool {
+	distribution := func(a Address) string {
+		if a.Distribution == "" {
+			return string(DistributionBalanced)
+		}
+		return a.Distribution
+	}
+	return desired.Prefix == actual.Prefix && distribution(desired) == distribution(actual)
+}
+
+// AddressesDifference returns the changes needed to the addresses of
+// a running router, keyed by prefix. Multicast addresses are only
+// configured when the router starts, so are never deleted.
+func AddressesDifference(actual map[string]Address, desired map[string]Address) *AddressDifference {
+	result := AddressDifference{}
+	for prefix, desiredValue := range desired {
+		if actualValue, ok := actual[prefix]; ok {
+			if !desiredValue.Equivalent(actualValue) {
+				// handle change as delete then add, as addresses cannot be updated in place
+				result.Deleted = append(result.Deleted, actualValue)
+				result.Added = append(result.Added, desiredValue)
+			}
+		} else {
+			result.Added = append(result.Added, desiredValue)
+		}
+	}
+	for prefix, actualValue := range actual {
+		if _, ok := desired[prefix]; !ok && actualValue.Distribution != string(DistributionMulticast) {
+			result.Deleted = append(result.Deleted, actualValue)
+		}
+	}
+	return &result
+}
+
+func (a *AddressDifference) Empty() bool {
+	return len(a.Deleted) == 0 && len(a.Added) == 0
+}
+
 type ListenerDifference struct {
 	Deleted []Listener
 	Added   []Listener
@@ -1098,6 +1544,21 @@ func ListenersDifference(actual map[string]Listener, desired map[string]Listener
 	return &result
 }
 
+// LinkListenersDifference returns the changes required to the
+// listeners of a running router. As for connectors, a change of cost
+// alone is not applied, as recreating the listener would drop the
+// links established through it.
+func LinkListenersDifference(actual map[string]Listener, desired map[string]Listener) *ListenerDifference {
+	withCost := make(map[string]Listener, len(desired))
+	for key, listener := range desired {
+		if existing, ok := actual[key]; ok {
+			listener.Cost = existing.Cost
+		}
+		withCost[key] = listener
+	}
+	return ListenersDifference(actual, withCost)
+}
+
 func (a *ListenerDifference) Empty() bool {
 	return len(a.Deleted) == 0 && len(a.Added) == 0
 }
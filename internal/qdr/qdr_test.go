@@ -252,6 +252,46 @@ func TestMarshalUnmarshalRouterConfig(t *testing.T) {
 					SiteId:  "def",
 				},
 			},
+			UdpConnectors: map[string]UdpEndpoint{
+				"u1": UdpEndpoint{
+					Name:    "u1",
+					Address: "dns",
+					Host:    "nameserver",
+					Port:    "53",
+					SiteId:  "abc",
+				},
+			},
+			UdpListeners: map[string]UdpEndpoint{
+				"u2": UdpEndpoint{
+					Name:    "u2",
+					Address: "syslog",
+					Host:    "0.0.0.0",
+					Port:    "514",
+					SiteId:  "def",
+				},
+			},
+			HttpConnectors: map[string]HttpEndpoint{
+				"h1": HttpEndpoint{
+					Name:            "h1",
+					Address:         "rest",
+					Host:            "backend",
+					Port:            "8080",
+					SiteId:          "abc",
+					ProtocolVersion: HttpVersion1,
+					SslProfile:      "one",
+					VerifyHostname:  verifyHostName,
+				},
+			},
+			HttpListeners: map[string]HttpEndpoint{
+				"h2": HttpEndpoint{
+					Name:            "h2",
+					Address:         "grpc",
+					Host:            "0.0.0.0",
+					Port:            "9090",
+					SiteId:          "def",
+					ProtocolVersion: HttpVersion2,
+				},
+			},
 		},
 		Addresses: map[string]Address{
 			"happy": Address{
@@ -382,6 +422,175 @@ func TestUnmarshalErrorInvalidTcpListenerValue(t *testing.T) {
 	}
 }
 
+func TestUnmarshalErrorInvalidUdpConnectorValue(t *testing.T) {
+	_, err := UnmarshalRouterConfig(`[["udpConnector", ["wrong"]]]`)
+	if err == nil {
+		t.Errorf("Expected error for invalid udpconnector value")
+	}
+}
+
+func TestUnmarshalErrorInvalidUdpListenerValue(t *testing.T) {
+	_, err := UnmarshalRouterConfig(`[["udpListener", ["wrong"]]]`)
+	if err == nil {
+		t.Errorf("Expected error for invalid udplistener value")
+	}
+}
+
+func TestUdpBridgeConfigDifference(t *testing.T) {
+	before := NewBridgeConfig()
+	before.AddUdpConnector(UdpEndpoint{Name: "dns", Host: "10.0.0.1", Port: "53", Address: "dns"})
+	before.AddUdpListener(UdpEndpoint{Name: "syslog", Port: "514", Address: "syslog"})
+	before.AddUdpListener(UdpEndpoint{Name: "ntp", Port: "123", Address: "ntp"})
+
+	after := NewBridgeConfigCopy(before)
+	after.AddUdpConnector(UdpEndpoint{Name: "dns", Host: "10.0.0.2", Port: "53", Address: "dns"})
+	after.RemoveUdpListener("ntp")
+	after.AddUdpListener(UdpEndpoint{Name: "snmp", Port: "161", Address: "snmp"})
+
+	diff := before.Difference(&after)
+	assert.Assert(t, !diff.Empty())
+	assert.Assert(t, diff.TcpConnectors.Empty())
+	assert.Assert(t, diff.TcpListeners.Empty())
+	assert.DeepEqual(t, diff.UdpConnectors.Deleted, []string{"dns"})
+	assert.DeepEqual(t, diff.UdpConnectors.Added, []UdpEndpoint{{Name: "dns", Host: "10.0.0.2", Port: "53", Address: "dns"}})
+	assert.DeepEqual(t, diff.UdpListeners.Deleted, []string{"ntp"})
+	assert.DeepEqual(t, diff.UdpListeners.Added, []UdpEndpoint{{Name: "snmp", Port: "161", Address: "snmp"}})
+
+	same := NewBridgeConfigCopy(before)
+	assert.Assert(t, before.Difference(&same).Empty())
+}
+
+func TestUnmarshalErrorInvalidHttpConnectorValue(t *testing.T) {
+	_, err := UnmarshalRouterConfig(`[["httpConnector", ["wrong"]]]`)
+	if err == nil {
+		t.Errorf("Expected error for invalid httpconnector value")
+	}
+}
+
+func TestUnmarshalErrorInvalidHttpListenerValue(t *testing.T) {
+	_, err := UnmarshalRouterConfig(`[["httpListener", ["wrong"]]]`)
+	if err == nil {
+		t.Errorf("Expected error for invalid httplistener value")
+	}
+}
+
+func TestHttpBridgeConfigDifference(t *testing.T) {
+	before := NewBridgeConfig()
+	before.AddHttpConnector(HttpEndpoint{Name: "rest", Host: "10.0.0.1", Port: "8080", Address: "rest"})
+	before.AddHttpListener(HttpEndpoint{Name: "grpc", Port: "9090", Address: "grpc", ProtocolVersion: HttpVersion2})
+
+	// an unset protocol version is equivalent to HTTP1
+	same := NewBridgeConfigCopy(before)
+	same.AddHttpConnector(HttpEndpoint{Name: "rest", Host: "10.0.0.1", Port: "8080", Address: "rest", ProtocolVersion: HttpVersion1})
+	assert.Assert(t, before.Difference(&same).Empty())
+
+	after := NewBridgeConfigCopy(before)
+	after.AddHttpListener(HttpEndpoint{Name: "grpc", Port: "9090", Address: "grpc", ProtocolVersion: HttpVersion1})
+	after.RemoveHttpConnector("rest")
+	diff := before.Difference(&after)
+	assert.Assert(t, !diff.Empty())
+	assert.DeepEqual(t, diff.HttpConnectors.Deleted, []string{"rest"})
+	assert.Equal(t, len(diff.HttpConnectors.Added), 0)
+	assert.DeepEqual(t, diff.HttpListeners.Deleted, []string{"grpc"})
+	assert.DeepEqual(t, diff.HttpListeners.Added, []HttpEndpoint{{Name: "grpc", Port: "9090", Address: "grpc", ProtocolVersion: HttpVersion1}})
+}
+
+func TestConnectorsDifference(t *testing.T) {
+	desired := InitialConfig("foo", "bar", "1.2.3", false, 3)
+	desired.AddConnector(Connector{Name: "unchanged", Role: RoleInterRouter, Host: "a", Port: "55671", SslProfile: "a-profile"})
+	desired.AddConnector(Connector{Name: "moved", Role: RoleInterRouter, Host: "b2", Port: "55671"})
+	desired.AddConnector(Connector{Name: "costly", Role: RoleInterRouter, Host: "c", Port: "55671", Cost: 100})
+	desired.AddConnector(Connector{Name: "added", Role: RoleInterRouter, Host: "d", Port: "55671"})
+
+	actual := map[string]Connector{
+		// the router reports the default cost for connectors where it was not set
+		"unchanged":      {Name: "unchanged", Role: RoleInterRouter, Host: "a", Port: "55671", SslProfile: "a-profile", Cost: 1},
+		"moved":          {Name: "moved", Role: RoleInterRouter, Host: "b1", Port: "55671"},
+		"costly":         {Name: "costly", Role: RoleInterRouter, Host: "c", Port: "55671", Cost: 1},
+		"removed":        {Name: "removed", Role: RoleInterRouter, Host: "e", Port: "55671"},
+		"auto-mesh-peer": {Name: "auto-mesh-peer", Role: RoleInterRouter, Host: "f", Port: "55671"},
+	}
+	ignore := "auto-mesh"
+	diff := ConnectorsDifference(actual, &desired, &ignore)
+	assert.Assert(t, !diff.Empty())
+
+	names := func(connectors []Connector) map[string]string {
+		result := map[string]string{}
+		for _, c := range connectors {
+			result[c.Name] = c.Host
+		}
+		return result
+	}
+	// a change of cost alone does not replace the connector
+	assert.DeepEqual(t, names(diff.Added), map[string]string{"moved": "b2", "added": "d"})
+	assert.DeepEqual(t, names(diff.Deleted), map[string]string{"moved": "b1", "removed": "e"})
+
+	delete(actual, "removed")
+	delete(actual, "auto-mesh-peer")
+	actual["moved"] = desired.Connectors["moved"]
+	actual["added"] = desired.Connectors["added"]
+	assert.Assert(t, ConnectorsDifference(actual, &desired, nil).Empty())
+}
+
+func TestAddressesDifference(t *testing.T) {
+	desired := map[string]Address{
+		"mc":        {Prefix: "mc", Distribution: DistributionMulticast},
+		"unchanged": {Prefix: "unchanged", Distribution: DistributionClosest},
+		"changed":   {Prefix: "changed", Distribution: DistributionClosest},
+		"added":     {Prefix: "added", Distribution: DistributionClosest},
+		"default":   {Prefix: "default"},
+	}
+	actual := map[string]Address{
+		"mc":        {Name: "router.config.address/0", Prefix: "mc", Distribution: DistributionMulticast},
+		"unchanged": {Name: "unchanged", Prefix: "unchanged", Distribution: DistributionClosest},
+		"changed":   {Name: "changed", Prefix: "changed", Distribution: string(DistributionBalanced)},
+		"removed":   {Name: "removed", Prefix: "removed", Distribution: DistributionClosest},
+		// the router reports the default distribution where it was not set
+		"default": {Name: "default", Prefix: "default", Distribution: string(DistributionBalanced)},
+		// multicast addresses are only configured on startup
+		"other-mc": {Name: "other-mc", Prefix: "other-mc", Distribution: DistributionMulticast},
+	}
+	diff := AddressesDifference(actual, desired)
+	assert.Assert(t, !diff.Empty())
+
+	prefixes := func(addresses []Address) map[string]string {
+		result := map[string]string{}
+		for _, a := range addresses {
+			result[a.Prefix] = a.Distribution
+		}
+		return result
+	}
+	assert.DeepEqual(t, prefixes(diff.Added), map[string]string{"changed": DistributionClosest, "added": DistributionClosest})
+	assert.DeepEqual(t, prefixes(diff.Deleted), map[string]string{"changed": string(DistributionBalanced), "removed": DistributionClosest})
+	for _, deleted := range diff.Deleted {
+		// addresses are deleted by the name the router reported
+		assert.Assert(t, deleted.Name != "")
+	}
+
+	delete(actual, "removed")
+	actual["changed"] = desired["changed"]
+	actual["added"] = desired["added"]
+	assert.Assert(t, AddressesDifference(actual, desired).Empty())
+}
+
+func TestHttpEndpointRecord(t *testing.T) {
+	verify := false
+	endpoint := HttpEndpoint{
+		Name:            "rest",
+		Host:            "backend",
+		Port:            "8080",
+		Address:         "rest",
+		SiteId:          "abc",
+		ProtocolVersion: HttpVersion2,
+		SslProfile:      "my-profile",
+		VerifyHostname:  &verify,
+		ProcessID:       "pod-1",
+	}
+	record := endpoint.toRecord()
+	assert.Equal(t, record["protocolVersion"], "HTTP2")
+	assert.DeepEqual(t, asHttpEndpoint(record), endpoint)
+}
+
 func TestUnmarshalErrorInvalidLogValue(t *testing.T) {
 	_, err := UnmarshalRouterConfig(`[["log", ["wrong"]]]`)
 	if err == nil {
@@ -160,10 +160,7 @@ func (b *Bindings) deleteListener(name string) qdr.ConfigUpdate {
 }
 
 func (b *Bindings) ToBridgeConfig() qdr.BridgeConfig {
-	config := qdr.BridgeConfig{
-		TcpListeners:  qdr.TcpEndpointMap{},
-		TcpConnectors: qdr.TcpEndpointMap{},
-	}
+	config := qdr.NewBridgeConfig()
 	for _, c := range b.connectors {
 		b.configure.connector(b.SiteId, c, &config)
 	}
@@ -209,6 +206,7 @@ func (b *Bindings) AddSslProfiles(config *qdr.RouterConfig) bool {
 func (b *Bindings) Apply(config *qdr.RouterConfig) bool {
 	b.AddSslProfiles(config)
 	config.UpdateBridgeConfig(b.ToBridgeConfig())
+	UpdateAddresses(config, b.Addresses())
 	config.RemoveUnreferencedSslProfiles()
 	return true //TODO: can optimise by indicating if no change was required
 }
@@ -1,6 +1,7 @@
 package site
 
 import (
+	"fmt"
 	"strconv"
 
 	"github.com/skupperproject/skupper/internal/qdr"
@@ -21,7 +22,8 @@ func UpdateBridgeConfigForConnectorToPod(siteId string, connector *skupperv2alph
 }
 
 func updateBridgeConfigForConnector(name string, siteId string, connector *skupperv2alpha1.Connector, host string, processID string, address string, config *qdr.BridgeConfig) {
-	if connector.Spec.Type == "tcp" || connector.Spec.Type == "" {
+	switch connector.Spec.Type {
+	case "tcp", "":
 		config.AddTcpConnector(qdr.TcpEndpoint{
 			Name:           name,
 			SiteId:         siteId,
@@ -32,7 +34,56 @@ func updateBridgeConfigForConnector(name string, siteId string, connector *skupp
 			ProcessID:      processID,
 			VerifyHostname: getVerifyHostname(connector),
 		})
+	case "udp":
+		config.AddUdpConnector(qdr.UdpEndpoint{
+			Name:      name,
+			SiteId:    siteId,
+			Host:      host,
+			Port:      strconv.Itoa(connector.Spec.Port),
+			Address:   address,
+			ProcessID: processID,
+		})
+	case "http", "http2":
+		config.AddHttpConnector(qdr.HttpEndpoint{
+			Name:            name,
+			SiteId:          siteId,
+			Host:            host,
+			Port:            strconv.Itoa(connector.Spec.Port),
+			Address:         address,
+			ProtocolVersion: HttpProtocolVersion(connector.Spec.Type),
+			SslProfile:      getSslProfileName(connector),
+			ProcessID:       processID,
+			VerifyHostname:  getVerifyHostname(connector),
+		})
+	}
+}
+
+// CheckConnectorType returns an error if the type or load balancing
+// policy of the connector cannot be mapped to router configuration.
+func CheckConnectorType(connector *skupperv2alpha1.Connector) error {
+	if err := checkLoadBalancing(connector.Spec.Settings); err != nil {
+		return err
+	}
+	switch connector.Spec.Type {
+	case "tcp", "", "http", "http2":
+		return nil
+	case "udp":
+		if connector.Spec.TlsCredentials != "" {
+			return fmt.Errorf("TLS is not supported for connector type %q", connector.Spec.Type)
+		}
+		return nil
+	default:
+		return fmt.Errorf("Unsupported connector type %q", connector.Spec.Type)
+	}
+}
+
+// HttpProtocolVersion maps a connector or listener type to the
+// protocol version of the corresponding router http adaptor entity.
+func HttpProtocolVersion(bindingType string) qdr.HttpProtocolVersion {
+	if bindingType == "http2" {
+		return qdr.HttpVersion2
 	}
+	return qdr.HttpVersion1
 }
 
 func getSslProfileName(connector *skupperv2alpha1.Connector) string {
@@ -0,0 +1,152 @@
+package site
+
+import (
+	"strings"
+	"time"
+
+	"github.com/skupperproject/skupper/internal/qdr"
+	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+)
+
+// DrainLinkCost is the cost given to the inter-router links
+// established to or from a draining site, high enough that the rest
+// of the network routes around it wherever there is an alternative.
+// Links that are already established keep their cost.
+const DrainLinkCost = 100000
+
+// Drain puts a site into maintenance mode. While active, the
+// connectors of the site are removed from the router, so that no new
+// flows are accepted for them, and new inter-router links are given a
+// raised cost. Flows that are already established are left to
+// complete. The drain never closes them: once the timeout passes it is
+// reported as complete with TimedOut set, so that maintenance can
+// proceed, and any flows still open end when the router is stopped.
+type Drain struct {
+	active  bool
+	started time.Time
+	timeout time.Duration
+}
+
+func NewDrain() *Drain {
+	return &Drain{
+		timeout: skupperv2alpha1.DefaultDrainTimeout,
+	}
+}
+
+// Update the drain from the site settings, returning true if the site
+// has started or stopped draining. A drain already reported in the
+// site status keeps its original start time.
+func (d *Drain) Update(site *skupperv2alpha1.Site) bool {
+	draining := false
+	if site != nil {
+		draining = site.Spec.IsDraining()
+		d.timeout = site.Spec.GetDrainTimeout()
+	}
+	if draining == d.active {
+		return false
+	}
+	d.active = draining
+	d.started = time.Time{}
+	if draining {
+		d.started = time.Now()
+		if site.Status.Drain != nil {
+			if started, err := time.Parse(time.RFC3339, site.Status.Drain.StartTime); err == nil {
+				d.started = started
+			}
+		}
+	}
+	return true
+}
+
+func (d *Drain) Active() bool {
+	return d.active
+}
+
+func (d *Drain) Started() time.Time {
+	return d.started
+}
+
+// Remaining returns how long existing flows still have to complete
+// before the drain times out
+func (d *Drain) Remaining(now time.Time) time.Duration {
+	if !d.active {
+		return 0
+	}
+	if remaining := d.timeout - now.Sub(d.started); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// Status reports the progress of the drain given the number of flows
+// still active on the connectors of the site, or nil if the site is
+// not draining
+func (d *Drain) Status(activeFlows int, now time.Time) *skupperv2alpha1.DrainStatus {
+	if !d.active {
+		return nil
+	}
+	status := &skupperv2alpha1.DrainStatus{
+		Phase:       skupperv2alpha1.DrainPhaseDraining,
+		StartTime:   d.started.UTC().Format(time.RFC3339),
+		ActiveFlows: activeFlows,
+	}
+	if activeFlows == 0 {
+		status.Phase = skupperv2alpha1.DrainPhaseDrained
+	} else if d.Remaining(now) == 0 {
+		status.Phase = skupperv2alpha1.DrainPhaseDrained
+		status.TimedOut = true
+	}
+	return status
+}
+
+// Apply removes connectors and raises link costs while the site is
+// draining. Once it is no longer draining, the listeners for incoming
+// links are reset to the default cost; connectors and outgoing links
+// are restored by the bindings and links that own them.
+func (d *Drain) Apply(config *qdr.RouterConfig) bool {
+	changed := false
+	if !d.active {
+		for name, listener := range config.Listeners {
+			if listener.Role == qdr.RoleInterRouter && listener.Cost == DrainLinkCost {
+				listener.Cost = 0
+				config.Listeners[name] = listener
+				changed = true
+			}
+		}
+		return changed
+	}
+	for name := range config.Bridges.TcpConnectors {
+		config.RemoveTcpConnector(name)
+		changed = true
+	}
+	for name := range config.Bridges.UdpConnectors {
+		config.RemoveUdpConnector(name)
+		changed = true
+	}
+	for name := range config.Bridges.HttpConnectors {
+		config.RemoveHttpConnector(name)
+		changed = true
+	}
+	for name, connector := range config.Connectors {
+		// links between the routers of the same site are left alone
+		if connector.Role != qdr.RoleInterRouter || strings.HasPrefix(connector.Name, "auto-mesh") {
+			continue
+		}
+		if connector.Cost < DrainLinkCost {
+			connector.Cost = DrainLinkCost
+			config.Connectors[name] = connector
+			changed = true
+		}
+	}
+	for name, listener := range config.Listeners {
+		if listener.Role != qdr.RoleInterRouter {
+			continue
+		}
+		if listener.Cost < DrainLinkCost {
+			listener.Cost = DrainLinkCost
+			config.Listeners[name] = listener
+			changed = true
+		}
+	}
+	return changed
+}
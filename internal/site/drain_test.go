@@ -0,0 +1,131 @@
+package site
+
+import (
+	"testing"
+	"time"
+
+	"github.com/skupperproject/skupper/internal/qdr"
+	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+	"gotest.tools/v3/assert"
+)
+
+func drainingSite(settings map[string]string, status *skupperv2alpha1.DrainStatus) *skupperv2alpha1.Site {
+	return &skupperv2alpha1.Site{
+		Spec: skupperv2alpha1.SiteSpec{
+			Settings: settings,
+		},
+		Status: skupperv2alpha1.SiteStatus{
+			Drain: status,
+		},
+	}
+}
+
+func drainTestConfig() qdr.RouterConfig {
+	config := qdr.InitialConfig("router-1", "site-1", "v2.0", false, 3)
+	config.AddTcpConnector(qdr.TcpEndpoint{Name: "db", Host: "db", Port: "5432", Address: "db"})
+	config.AddTcpListener(qdr.TcpEndpoint{Name: "api", Port: "8080", Address: "api"})
+	config.AddUdpConnector(qdr.UdpEndpoint{Name: "dns", Host: "dns", Port: "53", Address: "dns"})
+	config.AddHttpConnector(qdr.HttpEndpoint{Name: "web", Host: "web", Port: "80", Address: "web"})
+	config.AddConnector(qdr.Connector{Name: "link-1", Role: qdr.RoleInterRouter, Host: "peer", Port: "55671", Cost: 2})
+	config.AddConnector(qdr.Connector{Name: "auto-mesh-skupper-router", Role: qdr.RoleInterRouter, Host: "skupper-router", Port: "55671"})
+	config.AddListener(qdr.Listener{Name: "skupper-inter-router", Role: qdr.RoleInterRouter, Port: 55671})
+	config.AddListener(qdr.Listener{Name: "skupper-edge", Role: qdr.RoleEdge, Port: 45671})
+	return config
+}
+
+func TestDrain_Update(t *testing.T) {
+	drain := NewDrain()
+	assert.Assert(t, !drain.Update(nil))
+	assert.Assert(t, !drain.Update(drainingSite(nil, nil)))
+	assert.Assert(t, !drain.Active())
+
+	before := time.Now()
+	assert.Assert(t, drain.Update(drainingSite(map[string]string{"drain": "true", "drain-timeout": "1m"}, nil)))
+	assert.Assert(t, drain.Active())
+	assert.Assert(t, !drain.Started().Before(before))
+	assert.Equal(t, drain.Remaining(drain.Started().Add(10*time.Second)), 50*time.Second)
+	assert.Assert(t, !drain.Update(drainingSite(map[string]string{"drain": "true", "drain-timeout": "1m"}, nil)))
+
+	assert.Assert(t, drain.Update(drainingSite(map[string]string{"drain": "false"}, nil)))
+	assert.Assert(t, !drain.Active())
+	assert.Equal(t, drain.Remaining(time.Now()), time.Duration(0))
+
+	// a drain in progress is recovered from the site status
+	started := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	assert.Assert(t, drain.Update(drainingSite(map[string]string{"drain": "true"}, &skupperv2alpha1.DrainStatus{
+		Phase:     skupperv2alpha1.DrainPhaseDraining,
+		StartTime: started.Format(time.RFC3339),
+	})))
+	assert.Equal(t, drain.Started(), started)
+	assert.Equal(t, drain.Remaining(started.Add(time.Minute)), skupperv2alpha1.DefaultDrainTimeout-time.Minute)
+}
+
+func TestDrain_Status(t *testing.T) {
+	drain := NewDrain()
+	assert.Assert(t, drain.Status(3, time.Now()) == nil)
+
+	drain.Update(drainingSite(map[string]string{"drain": "true", "drain-timeout": "30s"}, nil))
+	started := drain.Started()
+	tests := []struct {
+		name        string
+		activeFlows int
+		elapsed     time.Duration
+		phase       skupperv2alpha1.DrainPhase
+		timedOut    bool
+	}{
+		{
+			name:        "flows active",
+			activeFlows: 3,
+			elapsed:     10 * time.Second,
+			phase:       skupperv2alpha1.DrainPhaseDraining,
+		},
+		{
+			name:        "flows complete",
+			activeFlows: 0,
+			elapsed:     10 * time.Second,
+			phase:       skupperv2alpha1.DrainPhaseDrained,
+		},
+		{
+			name:        "timed out",
+			activeFlows: 1,
+			elapsed:     time.Minute,
+			phase:       skupperv2alpha1.DrainPhaseDrained,
+			timedOut:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := drain.Status(tt.activeFlows, started.Add(tt.elapsed))
+			assert.DeepEqual(t, status, &skupperv2alpha1.DrainStatus{
+				Phase:       tt.phase,
+				StartTime:   started.UTC().Format(time.RFC3339),
+				ActiveFlows: tt.activeFlows,
+				TimedOut:    tt.timedOut,
+			})
+		})
+	}
+}
+
+func TestDrain_Apply(t *testing.T) {
+	drain := NewDrain()
+	config := drainTestConfig()
+	assert.Assert(t, !drain.Apply(&config))
+	assert.DeepEqual(t, config, drainTestConfig())
+
+	drain.Update(drainingSite(map[string]string{"drain": "true"}, nil))
+	assert.Assert(t, drain.Apply(&config))
+	assert.Equal(t, len(config.Bridges.TcpConnectors), 0)
+	assert.Equal(t, len(config.Bridges.UdpConnectors), 0)
+	assert.Equal(t, len(config.Bridges.HttpConnectors), 0)
+	assert.Equal(t, len(config.Bridges.TcpListeners), 1)
+	assert.Equal(t, config.Connectors["link-1"].Cost, int32(DrainLinkCost))
+	assert.Equal(t, config.Connectors["auto-mesh-skupper-router"].Cost, int32(0))
+	assert.Equal(t, config.Listeners["skupper-inter-router"].Cost, int32(DrainLinkCost))
+	assert.Equal(t, config.Listeners["skupper-edge"].Cost, int32(0))
+	assert.Assert(t, !drain.Apply(&config))
+
+	drain.Update(drainingSite(nil, nil))
+	assert.Assert(t, drain.Apply(&config))
+	assert.Equal(t, config.Listeners["skupper-inter-router"].Cost, int32(0))
+	assert.Assert(t, !drain.Apply(&config))
+}
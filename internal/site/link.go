@@ -1,17 +1,38 @@
 package site
 
 import (
+	"hash/fnv"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/skupperproject/skupper/internal/qdr"
 	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
 )
 
+const (
+	// LinkFailoverPolicySetting selects how the candidate endpoints of a
+	// link are used: "ordered" (default) starts with the first endpoint
+	// listed for the role, "round-robin" spreads links over the endpoints
+	// by starting with an endpoint derived from the link name. In both
+	// cases a link that is not operational moves on to the next endpoint.
+	LinkFailoverPolicySetting = "failover-policy"
+	// LinkFailoverThresholdSetting is how long a link can be
+	// non-operational before moving to the next endpoint
+	LinkFailoverThresholdSetting = "failover-threshold"
+
+	LinkFailoverOrdered    = "ordered"
+	LinkFailoverRoundRobin = "round-robin"
+
+	DefaultLinkFailoverThreshold = time.Minute
+)
+
 type Link struct {
 	name        string
 	profilePath string
 	definition  *skupperv2alpha1.Link
+	failovers   int
+	downSince   time.Time
 }
 
 func NewLink(name string, profilePath string) *Link {
@@ -29,7 +50,7 @@ func (l *Link) Apply(current *qdr.RouterConfig) bool {
 	if current.IsEdge() {
 		role = qdr.RoleEdge
 	}
-	endpoint, ok := l.definition.Spec.GetEndpointForRole(string(role))
+	endpoint, ok := l.ActiveEndpoint(role)
 	if !ok {
 		return false
 	}
@@ -47,6 +68,82 @@ func (l *Link) Apply(current *qdr.RouterConfig) bool {
 	return true //TODO: optimise by indicating if no change was actually needed
 }
 
+// ActiveEndpoint returns the candidate endpoint for the role that the link
+// currently connects to
+func (l *Link) ActiveEndpoint(role qdr.Role) (skupperv2alpha1.Endpoint, bool) {
+	if l.definition == nil {
+		return skupperv2alpha1.Endpoint{}, false
+	}
+	endpoints := l.definition.Spec.GetEndpointsForRole(string(role))
+	if len(endpoints) == 0 {
+		return skupperv2alpha1.Endpoint{}, false
+	}
+	start := 0
+	if l.definition.Spec.Settings[LinkFailoverPolicySetting] == LinkFailoverRoundRobin {
+		h := fnv.New32a()
+		h.Write([]byte(l.name))
+		start = int(h.Sum32() % uint32(len(endpoints)))
+	}
+	return endpoints[(start+l.failovers)%len(endpoints)], true
+}
+
+// HasFailover returns true if there is more than one candidate endpoint
+// for the role
+func (l *Link) HasFailover(role qdr.Role) bool {
+	return l.definition != nil && len(l.definition.Spec.GetEndpointsForRole(string(role))) > 1
+}
+
+// FailoverThreshold is how long the link can be non-operational before
+// it moves to the next candidate endpoint
+func (l *Link) FailoverThreshold() time.Duration {
+	if l.definition != nil {
+		if value, ok := l.definition.Spec.Settings[LinkFailoverThresholdSetting]; ok {
+			if threshold, err := time.ParseDuration(value); err == nil && threshold > 0 {
+				return threshold
+			}
+		}
+	}
+	return DefaultLinkFailoverThreshold
+}
+
+// RecordOperational records whether the link is operational, noting
+// when it was first seen not to be
+func (l *Link) RecordOperational(operational bool, now time.Time) {
+	if operational {
+		l.downSince = time.Time{}
+	} else if l.downSince.IsZero() {
+		l.downSince = now
+	}
+}
+
+// CheckOperational records whether the link is operational. When it has
+// not been for at least the failover threshold, the link moves to the
+// next candidate endpoint for the role and true is returned, indicating
+// that the router config must be updated.
+func (l *Link) CheckOperational(role qdr.Role, operational bool, now time.Time) bool {
+	l.RecordOperational(operational, now)
+	if operational || !l.HasFailover(role) || now.Sub(l.downSince) < l.FailoverThreshold() {
+		return false
+	}
+	l.failovers++
+	l.downSince = now
+	return true
+}
+
+// NextFailoverCheck returns how long after now the link should next be
+// checked, i.e. the time remaining before the failover threshold is
+// reached if the link is down, the full threshold otherwise
+func (l *Link) NextFailoverCheck(now time.Time) time.Duration {
+	threshold := l.FailoverThreshold()
+	if l.downSince.IsZero() {
+		return threshold
+	}
+	if remaining := threshold - now.Sub(l.downSince); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
 func sslProfileName(link *skupperv2alpha1.Link) string {
 	return link.Spec.TlsCredentials + "-profile"
 }
@@ -70,6 +167,11 @@ func (m LinkMap) Apply(current *qdr.RouterConfig) bool {
 
 func (link *Link) Update(definition *skupperv2alpha1.Link) bool {
 	changed := !reflect.DeepEqual(link.definition, definition)
+	if link.definition == nil || definition == nil || !reflect.DeepEqual(link.definition.Spec, definition.Spec) {
+		// start over with the preferred endpoint
+		link.failovers = 0
+		link.downSince = time.Time{}
+	}
 	link.definition = definition
 	return changed
 }
@@ -3,6 +3,7 @@ package site
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/skupperproject/skupper/internal/qdr"
 	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
@@ -480,3 +481,110 @@ func TestRemoveConnector_Apply(t *testing.T) {
 		})
 	}
 }
+
+func TestLink_Failover(t *testing.T) {
+	endpoints := []skupperv2alpha1.Endpoint{
+		{Name: string(qdr.RoleInterRouter), Host: "lb.example.com", Port: "55671"},
+		{Name: string(qdr.RoleEdge), Host: "lb.example.com", Port: "45671"},
+		{Name: string(qdr.RoleInterRouter), Host: "route.example.com", Port: "443"},
+		{Name: string(qdr.RoleInterRouter), Host: "10.10.10.1", Port: "55671", Group: "skupper-router-2"},
+	}
+	newLink := func(name string, settings map[string]string) *Link {
+		l := NewLink(name, "/etc/skupper-router-certs")
+		l.Update(&skupperv2alpha1.Link{
+			ObjectMeta: v1.ObjectMeta{Name: name, Namespace: "test"},
+			Spec: skupperv2alpha1.LinkSpec{
+				Endpoints: endpoints,
+				Settings:  settings,
+			},
+		})
+		return l
+	}
+	activeHost := func(l *Link, role qdr.Role) string {
+		endpoint, ok := l.ActiveEndpoint(role)
+		assert.Assert(t, ok)
+		return endpoint.Host
+	}
+	connectorHost := func(l *Link) string {
+		config := qdr.InitialConfig("router-1", "site-1", "v2.0", false, 10)
+		assert.Assert(t, l.Apply(&config))
+		return config.Connectors[l.name].Host
+	}
+
+	t.Run("ordered", func(t *testing.T) {
+		l := newLink("link1", map[string]string{LinkFailoverThresholdSetting: "30s"})
+		assert.Equal(t, l.FailoverThreshold(), 30*time.Second)
+		assert.Assert(t, l.HasFailover(qdr.RoleInterRouter))
+		assert.Assert(t, !l.HasFailover(qdr.RoleEdge))
+		assert.Equal(t, connectorHost(l), "lb.example.com")
+
+		now := time.Now()
+		assert.Assert(t, !l.CheckOperational(qdr.RoleInterRouter, false, now))
+		assert.Assert(t, !l.CheckOperational(qdr.RoleInterRouter, false, now.Add(10*time.Second)))
+		assert.Assert(t, l.CheckOperational(qdr.RoleInterRouter, false, now.Add(30*time.Second)))
+		assert.Equal(t, connectorHost(l), "route.example.com")
+
+		// an operational link stays on its endpoint
+		assert.Assert(t, !l.CheckOperational(qdr.RoleInterRouter, true, now.Add(40*time.Second)))
+		assert.Assert(t, !l.CheckOperational(qdr.RoleInterRouter, false, now.Add(50*time.Second)))
+		assert.Assert(t, !l.CheckOperational(qdr.RoleInterRouter, false, now.Add(70*time.Second)))
+		assert.Assert(t, l.CheckOperational(qdr.RoleInterRouter, false, now.Add(80*time.Second)))
+		assert.Equal(t, activeHost(l, qdr.RoleInterRouter), "10.10.10.1")
+		assert.Assert(t, l.CheckOperational(qdr.RoleInterRouter, false, now.Add(110*time.Second)))
+		assert.Equal(t, activeHost(l, qdr.RoleInterRouter), "lb.example.com")
+
+		// a single candidate never fails over
+		assert.Assert(t, !l.CheckOperational(qdr.RoleEdge, false, now.Add(time.Hour)))
+		assert.Equal(t, activeHost(l, qdr.RoleEdge), "lb.example.com")
+	})
+	t.Run("failover at threshold", func(t *testing.T) {
+		l := newLink("link1", map[string]string{LinkFailoverThresholdSetting: "30s"})
+		now := time.Now()
+		assert.Equal(t, l.NextFailoverCheck(now), 30*time.Second)
+
+		// a link going down between checks fails over once the
+		// threshold has passed since it went down
+		l.RecordOperational(false, now.Add(5*time.Second))
+		assert.Equal(t, l.NextFailoverCheck(now.Add(30*time.Second)), 5*time.Second)
+		assert.Assert(t, !l.CheckOperational(qdr.RoleInterRouter, false, now.Add(30*time.Second)))
+		assert.Assert(t, l.CheckOperational(qdr.RoleInterRouter, false, now.Add(35*time.Second)))
+		assert.Equal(t, connectorHost(l), "route.example.com")
+		assert.Equal(t, l.NextFailoverCheck(now.Add(35*time.Second)), 30*time.Second)
+		assert.Equal(t, l.NextFailoverCheck(now.Add(time.Hour)), time.Duration(0))
+
+		l.RecordOperational(true, now.Add(40*time.Second))
+		assert.Equal(t, l.NextFailoverCheck(now.Add(40*time.Second)), 30*time.Second)
+	})
+	t.Run("spec change resets failover", func(t *testing.T) {
+		l := newLink("link1", nil)
+		assert.Equal(t, l.FailoverThreshold(), DefaultLinkFailoverThreshold)
+		now := time.Now()
+		l.CheckOperational(qdr.RoleInterRouter, false, now)
+		assert.Assert(t, l.CheckOperational(qdr.RoleInterRouter, false, now.Add(DefaultLinkFailoverThreshold)))
+		assert.Equal(t, activeHost(l, qdr.RoleInterRouter), "route.example.com")
+
+		// a status update keeps the active endpoint
+		updated := l.Definition().DeepCopy()
+		updated.Status.ActiveEndpoint = &endpoints[2]
+		l.Update(updated)
+		assert.Equal(t, activeHost(l, qdr.RoleInterRouter), "route.example.com")
+
+		updated = updated.DeepCopy()
+		updated.Spec.Cost = 2
+		l.Update(updated)
+		assert.Equal(t, activeHost(l, qdr.RoleInterRouter), "lb.example.com")
+	})
+	t.Run("round-robin", func(t *testing.T) {
+		hosts := map[string]bool{}
+		for i := 0; i < 20; i++ {
+			l := newLink(fmt.Sprintf("link%d", i), map[string]string{LinkFailoverPolicySetting: LinkFailoverRoundRobin})
+			first := activeHost(l, qdr.RoleInterRouter)
+			hosts[first] = true
+			now := time.Now()
+			l.CheckOperational(qdr.RoleInterRouter, false, now)
+			assert.Assert(t, l.CheckOperational(qdr.RoleInterRouter, false, now.Add(DefaultLinkFailoverThreshold)))
+			assert.Assert(t, activeHost(l, qdr.RoleInterRouter) != first)
+		}
+		assert.Equal(t, len(hosts), 3, "expected links to be spread over all endpoints")
+	})
+}
@@ -1,6 +1,7 @@
 package site
 
 import (
+	"fmt"
 	"strconv"
 
 	"github.com/skupperproject/skupper/internal/qdr"
@@ -13,7 +14,8 @@ func UpdateBridgeConfigForListener(siteId string, listener *skupperv2alpha1.List
 
 func UpdateBridgeConfigForListenerWithHostAndPort(siteId string, listener *skupperv2alpha1.Listener, host string, port int, config *qdr.BridgeConfig) {
 	name := listener.Name
-	if listener.Spec.Type == "tcp" || listener.Spec.Type == "" {
+	switch listener.Spec.Type {
+	case "tcp", "":
 		config.AddTcpListener(qdr.TcpEndpoint{
 			Name:       name,
 			SiteId:     siteId,
@@ -22,5 +24,42 @@ func UpdateBridgeConfigForListenerWithHostAndPort(siteId string, listener *skupp
 			Address:    listener.Spec.RoutingKey,
 			SslProfile: listener.Spec.TlsCredentials,
 		})
+	case "udp":
+		config.AddUdpListener(qdr.UdpEndpoint{
+			Name:    name,
+			SiteId:  siteId,
+			Host:    host,
+			Port:    strconv.Itoa(port),
+			Address: listener.Spec.RoutingKey,
+		})
+	case "http", "http2":
+		config.AddHttpListener(qdr.HttpEndpoint{
+			Name:            name,
+			SiteId:          siteId,
+			Host:            host,
+			Port:            strconv.Itoa(port),
+			Address:         listener.Spec.RoutingKey,
+			ProtocolVersion: HttpProtocolVersion(listener.Spec.Type),
+			SslProfile:      listener.Spec.TlsCredentials,
+		})
+	}
+}
+
+// CheckListenerType returns an error if the type or load balancing
+// policy of the listener cannot be mapped to router configuration.
+func CheckListenerType(listener *skupperv2alpha1.Listener) error {
+	if err := checkLoadBalancing(listener.Spec.Settings); err != nil {
+		return err
+	}
+	switch listener.Spec.Type {
+	case "tcp", "", "http", "http2":
+		return nil
+	case "udp":
+		if listener.Spec.TlsCredentials != "" {
+			return fmt.Errorf("TLS is not supported for listener type %q", listener.Spec.Type)
+		}
+		return nil
+	default:
+		return fmt.Errorf("Unsupported listener type %q", listener.Spec.Type)
 	}
 }
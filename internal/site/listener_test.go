@@ -22,6 +22,8 @@ func TestUpdateBridgeConfigForListener(t *testing.T) {
 		args               args
 		expectedTcpAdded   int
 		expectedTcpDeleted int
+		expectedUdpAdded   int
+		expectedHttpAdded  int
 	}{
 		{
 			name: "no spec type",
@@ -65,6 +67,48 @@ func TestUpdateBridgeConfigForListener(t *testing.T) {
 			expectedTcpAdded:   1,
 			expectedTcpDeleted: 0,
 		},
+		{
+			name: "udp spec type",
+			args: args{
+				siteId: "my-site-123",
+				listener: &skupperv2alpha1.Listener{
+					ObjectMeta: v1.ObjectMeta{
+						Name:      "syslog",
+						Namespace: "test",
+					},
+					Spec: skupperv2alpha1.ListenerSpec{
+						RoutingKey: "syslog:514",
+						Host:       "10.10.10.1",
+						Port:       514,
+						Type:       "udp",
+					},
+				},
+				config: qdr.NewBridgeConfig(),
+			},
+			expectedTcpAdded:   0,
+			expectedTcpDeleted: 0,
+			expectedUdpAdded:   1,
+		},
+		{
+			name: "http spec type",
+			args: args{
+				siteId: "my-site-123",
+				listener: &skupperv2alpha1.Listener{
+					ObjectMeta: v1.ObjectMeta{
+						Name:      "rest",
+						Namespace: "test",
+					},
+					Spec: skupperv2alpha1.ListenerSpec{
+						RoutingKey: "rest:8080",
+						Host:       "10.10.10.1",
+						Port:       8080,
+						Type:       "http",
+					},
+				},
+				config: qdr.NewBridgeConfig(),
+			},
+			expectedHttpAdded: 1,
+		},
 		{
 			name: "bad spec type",
 			args: args{
@@ -95,6 +139,53 @@ func TestUpdateBridgeConfigForListener(t *testing.T) {
 			result := tt.args.config.Difference(&configToUpdate)
 			assert.Assert(t, len(result.TcpListeners.Added) == tt.expectedTcpAdded)
 			assert.Assert(t, len(result.TcpListeners.Deleted) == tt.expectedTcpDeleted)
+			assert.Assert(t, len(result.UdpListeners.Added) == tt.expectedUdpAdded)
+			assert.Assert(t, len(result.HttpListeners.Added) == tt.expectedHttpAdded)
+		})
+	}
+}
+
+func TestCheckListenerType(t *testing.T) {
+	tests := []struct {
+		name          string
+		spec          skupperv2alpha1.ListenerSpec
+		expectedError string
+	}{
+		{
+			name: "default",
+			spec: skupperv2alpha1.ListenerSpec{},
+		},
+		{
+			name: "tcp",
+			spec: skupperv2alpha1.ListenerSpec{Type: "tcp", TlsCredentials: "mysecret"},
+		},
+		{
+			name: "udp",
+			spec: skupperv2alpha1.ListenerSpec{Type: "udp"},
+		},
+		{
+			name: "http2 with tls",
+			spec: skupperv2alpha1.ListenerSpec{Type: "http2", TlsCredentials: "mysecret"},
+		},
+		{
+			name:          "udp with tls",
+			spec:          skupperv2alpha1.ListenerSpec{Type: "udp", TlsCredentials: "mysecret"},
+			expectedError: "TLS is not supported for listener type \"udp\"",
+		},
+		{
+			name:          "unsupported",
+			spec:          skupperv2alpha1.ListenerSpec{Type: "sctp"},
+			expectedError: "Unsupported listener type \"sctp\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckListenerType(&skupperv2alpha1.Listener{Spec: tt.spec})
+			if tt.expectedError == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Error(t, err, tt.expectedError)
+			}
 		})
 	}
 }
@@ -0,0 +1,87 @@
+package site
+
+import (
+	"fmt"
+
+	"github.com/skupperproject/skupper/internal/qdr"
+)
+
+const (
+	// LoadBalancingSetting selects how flows for a routing key are
+	// distributed over the connectors for it: "balanced" (default)
+	// sends each flow to the connector with the fewest flows in
+	// progress, taking link cost into account, while "closest"
+	// prefers connectors in the same site, then those with the
+	// lowest link cost. As flows are routed by the site they enter
+	// the network through, the setting applies to flows from
+	// listeners in sites where a listener or connector for the
+	// routing key has it set.
+	LoadBalancingSetting = "load-balancing"
+
+	LoadBalancingBalanced = "balanced"
+	LoadBalancingClosest  = "closest"
+)
+
+// checkLoadBalancing returns an error if the load balancing policy
+// of a listener or connector is not valid.
+func checkLoadBalancing(settings map[string]string) error {
+	if value, ok := settings[LoadBalancingSetting]; ok && value != LoadBalancingBalanced && value != LoadBalancingClosest {
+		return fmt.Errorf("Unsupported %s %q", LoadBalancingSetting, value)
+	}
+	return nil
+}
+
+func loadBalancing(settings map[string]string) string {
+	if settings[LoadBalancingSetting] == LoadBalancingClosest {
+		return LoadBalancingClosest
+	}
+	return LoadBalancingBalanced
+}
+
+// Addresses returns the router address configuration required for
+// the load balancing settings of the bindings. Routing keys that are
+// balanced, as the router does by default, need no configuration.
+func (b *Bindings) Addresses() map[string]qdr.Address {
+	addresses := map[string]qdr.Address{}
+	add := func(routingKey string, settings map[string]string) {
+		if routingKey != "" && loadBalancing(settings) == LoadBalancingClosest {
+			addresses[routingKey] = qdr.Address{
+				Prefix:       routingKey,
+				Distribution: string(qdr.DistributionClosest),
+			}
+		}
+	}
+	for _, c := range b.connectors {
+		add(c.Spec.RoutingKey, c.Spec.Settings)
+	}
+	for _, l := range b.listeners {
+		add(l.Spec.RoutingKey, l.Spec.Settings)
+	}
+	return addresses
+}
+
+// UpdateAddresses sets the addresses for routing keys in the router
+// configuration to those desired. Multicast addresses are not used
+// for routing keys and are left in place.
+func UpdateAddresses(config *qdr.RouterConfig, desired map[string]qdr.Address) bool {
+	if config.Addresses == nil {
+		config.Addresses = map[string]qdr.Address{}
+	}
+	changed := false
+	for prefix, address := range config.Addresses {
+		if address.Distribution == string(qdr.DistributionMulticast) {
+			continue
+		}
+		if _, ok := desired[prefix]; !ok {
+			delete(config.Addresses, prefix)
+			changed = true
+		}
+	}
+	for prefix, address := range desired {
+		if current, ok := config.Addresses[prefix]; !ok || current != address {
+			config.AddAddress(address)
+			changed = true
+		}
+	}
+	return changed
+}
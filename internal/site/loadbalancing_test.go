@@ -0,0 +1,95 @@
+package site
+
+import (
+	"testing"
+
+	"github.com/skupperproject/skupper/internal/qdr"
+	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+	"gotest.tools/v3/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func connectorWithSettings(name string, routingKey string, settings map[string]string) *skupperv2alpha1.Connector {
+	return &skupperv2alpha1.Connector{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      name,
+			Namespace: "test",
+		},
+		Spec: skupperv2alpha1.ConnectorSpec{
+			RoutingKey: routingKey,
+			Host:       name,
+			Port:       8080,
+			Settings:   settings,
+		},
+	}
+}
+
+func TestCheckLoadBalancing(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings map[string]string
+		err      string
+	}{
+		{
+			name: "default",
+		},
+		{
+			name:     "closest",
+			settings: map[string]string{"load-balancing": "closest"},
+		},
+		{
+			name:     "bad policy",
+			settings: map[string]string{"load-balancing": "random"},
+			err:      "Unsupported load-balancing \"random\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckConnectorType(connectorWithSettings("backend", "backend", tt.settings))
+			if tt.err == "" {
+				assert.Assert(t, err)
+			} else {
+				assert.Error(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestBindings_Addresses(t *testing.T) {
+	b := NewBindings("")
+	b.UpdateConnector("a", connectorWithSettings("a", "a", map[string]string{"load-balancing": "closest"}))
+	b.UpdateConnector("b", connectorWithSettings("b", "b", map[string]string{"load-balancing": "balanced"}))
+	b.UpdateConnector("c", connectorWithSettings("c", "c", nil))
+	b.UpdateListener("c", &skupperv2alpha1.Listener{
+		ObjectMeta: v1.ObjectMeta{Name: "c"},
+		Spec: skupperv2alpha1.ListenerSpec{
+			RoutingKey: "c",
+			Host:       "c",
+			Port:       8080,
+			Settings:   map[string]string{"load-balancing": "closest"},
+		},
+	})
+	expected := map[string]qdr.Address{
+		"a": {Prefix: "a", Distribution: "closest"},
+		"c": {Prefix: "c", Distribution: "closest"},
+	}
+	assert.DeepEqual(t, b.Addresses(), expected)
+
+	config := qdr.InitialConfig("router", "site", "v2", false, 3)
+	config.AddAddress(qdr.Address{Prefix: "mc", Distribution: "multicast"})
+	config.AddAddress(qdr.Address{Prefix: "stale", Distribution: "closest"})
+	assert.Assert(t, b.Apply(&config))
+	assert.DeepEqual(t, config.Addresses, map[string]qdr.Address{
+		"mc": {Prefix: "mc", Distribution: "multicast"},
+		"a":  {Prefix: "a", Distribution: "closest"},
+		"c":  {Prefix: "c", Distribution: "closest"},
+	})
+	assert.Assert(t, !UpdateAddresses(&config, expected))
+
+	b.UpdateListener("c", nil)
+	b.UpdateConnector("a", nil)
+	assert.Assert(t, UpdateAddresses(&config, b.Addresses()))
+	assert.DeepEqual(t, config.Addresses, map[string]qdr.Address{
+		"mc": {Prefix: "mc", Distribution: "multicast"},
+	})
+}
@@ -109,6 +109,20 @@ func (s *Status) SetStatusMessage(message string) bool {
 	return false
 }
 
+// SetDegraded records that the controller has repeatedly failed to
+// process the resource, with the last error encountered. Passing a nil
+// error removes the condition.
+func (s *Status) SetDegraded(err error, generation int64) bool {
+	if err == nil {
+		return meta.RemoveStatusCondition(&s.Conditions, CONDITION_TYPE_DEGRADED)
+	}
+	return s.SetCondition(CONDITION_TYPE_DEGRADED, ConditionState{
+		Status:  v1.ConditionTrue,
+		Reason:  "RetriesExhausted",
+		Message: err.Error(),
+	}, generation)
+}
+
 func (s *Status) SetCondition(conditionType string, state ConditionState, generation int64) bool {
 	condition := v1.Condition{
 		Type:               conditionType,
@@ -223,6 +237,27 @@ func (s *SiteSpec) GetRouterDataConnectionCount() string {
 	return ""
 }
 
+const DefaultDrainTimeout = 5 * time.Minute
+
+// IsDraining returns true if the site has been put into maintenance
+// mode, in which it accepts no new flows on its connectors and
+// steers traffic away from its links
+func (s *SiteSpec) IsDraining() bool {
+	value, ok := s.Settings["drain"]
+	return ok && value == "true"
+}
+
+// GetDrainTimeout returns how long existing flows are given to complete
+// once the site starts draining
+func (s *SiteSpec) GetDrainTimeout() time.Duration {
+	if value, ok := s.Settings["drain-timeout"]; ok {
+		if timeout, err := time.ParseDuration(value); err == nil && timeout > 0 {
+			return timeout
+		}
+	}
+	return DefaultDrainTimeout
+}
+
 func (s *Site) SetConfigured(err error) bool {
 	if s.Status.SetCondition(CONDITION_TYPE_CONFIGURED, ErrorOrReadyCondition(err), s.ObjectMeta.Generation) {
 		s.Status.setReady(s.requiredConditions(), s.ObjectMeta.Generation)
@@ -252,6 +287,14 @@ func (s *Site) SetRunning(state ConditionState) bool {
 	return false
 }
 
+func (s *Site) SetDrainStatus(drain *DrainStatus) bool {
+	if reflect.DeepEqual(s.Status.Drain, drain) {
+		return false
+	}
+	s.Status.Drain = drain
+	return true
+}
+
 func (s *Site) resolutionRequired() bool {
 	return s.Spec.LinkAccess != "" && s.Spec.LinkAccess != "none"
 }
@@ -281,6 +324,7 @@ const CONDITION_TYPE_PROCESSED = "Processed"
 const CONDITION_TYPE_REDEEMED = "Redeemed"
 const CONDITION_TYPE_OPERATIONAL = "Operational"
 const CONDITION_TYPE_READY = "Ready"
+const CONDITION_TYPE_DEGRADED = "Degraded"
 
 type SiteStatus struct {
 	Status         `json:",inline"`
@@ -289,6 +333,21 @@ type SiteStatus struct {
 	Network        []SiteRecord `json:"network,omitempty"`
 	DefaultIssuer  string       `json:"defaultIssuer,omitempty"`
 	Controller     *Controller  `json:"controller,omitempty"`
+	Drain          *DrainStatus `json:"drain,omitempty"`
+}
+
+type DrainPhase string
+
+const (
+	DrainPhaseDraining DrainPhase = "Draining"
+	DrainPhaseDrained  DrainPhase = "Drained"
+)
+
+type DrainStatus struct {
+	Phase       DrainPhase `json:"phase,omitempty"`
+	StartTime   string     `json:"startTime,omitempty"`
+	ActiveFlows int        `json:"activeFlows,omitempty"`
+	TimedOut    bool       `json:"timedOut,omitempty"`
 }
 
 type Controller struct {
@@ -333,6 +392,7 @@ type LinkRecord struct {
 	RemoteSiteId   string `json:"remoteSiteId,omitempty"`
 	RemoteSiteName string `json:"remoteSiteName,omitempty"`
 	Operational    bool   `json:"operational,omitempty"`
+	Cost           uint64 `json:"cost,omitempty"`
 }
 
 // +genclient
@@ -557,10 +617,22 @@ func (l *Link) SetOperational(operational bool, remoteSiteId string, remoteSiteN
 	return changed
 }
 
+func (l *Link) SetActiveEndpoint(endpoint *Endpoint) bool {
+	if reflect.DeepEqual(l.Status.ActiveEndpoint, endpoint) {
+		return false
+	}
+	l.Status.ActiveEndpoint = endpoint
+	return true
+}
+
 func (l *Link) IsConfigured() bool {
 	return meta.IsStatusConditionTrue(l.Status.Conditions, CONDITION_TYPE_CONFIGURED)
 }
 
+func (l *Link) IsOperational() bool {
+	return meta.IsStatusConditionTrue(l.Status.Conditions, CONDITION_TYPE_OPERATIONAL)
+}
+
 func (l *Link) IsReady() bool {
 	return meta.IsStatusConditionTrue(l.Status.Conditions, CONDITION_TYPE_CONFIGURED) &&
 		meta.IsStatusConditionTrue(l.Status.Conditions, CONDITION_TYPE_OPERATIONAL)
@@ -591,10 +663,23 @@ func (s *LinkSpec) GetEndpointForRole(name string) (Endpoint, bool) {
 	return Endpoint{}, false
 }
 
+// GetEndpointsForRole returns the candidate endpoints for a role, in the
+// order they are listed
+func (s *LinkSpec) GetEndpointsForRole(name string) []Endpoint {
+	var endpoints []Endpoint
+	for _, endpoint := range s.Endpoints {
+		if endpoint.Name == name {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	return endpoints
+}
+
 type LinkStatus struct {
 	Status         `json:",inline"`
-	RemoteSiteId   string `json:"remoteSiteId,omitempty"`
-	RemoteSiteName string `json:"remoteSiteName,omitempty"`
+	RemoteSiteId   string    `json:"remoteSiteId,omitempty"`
+	RemoteSiteName string    `json:"remoteSiteName,omitempty"`
+	ActiveEndpoint *Endpoint `json:"activeEndpoint,omitempty"`
 }
 
 // +genclient
@@ -823,18 +908,23 @@ type CertificateList struct {
 }
 
 type CertificateSpec struct {
-	Ca       string            `json:"ca"`
-	Subject  string            `json:"subject"`
-	Hosts    []string          `json:"hosts,omitempty"`
-	Client   bool              `json:"client,omitempty"`
-	Server   bool              `json:"server,omitempty"`
-	Signing  bool              `json:"signing,omitempty"`
-	Settings map[string]string `json:"settings,omitempty"`
+	Ca          string            `json:"ca"`
+	Subject     string            `json:"subject"`
+	Hosts       []string          `json:"hosts,omitempty"`
+	Client      bool              `json:"client,omitempty"`
+	Server      bool              `json:"server,omitempty"`
+	Signing     bool              `json:"signing,omitempty"`
+	Validity    string            `json:"validity,omitempty"`
+	RenewBefore string            `json:"renewBefore,omitempty"`
+	Settings    map[string]string `json:"settings,omitempty"`
 }
 
 type CertificateStatus struct {
-	Status     `json:",inline"`
-	Expiration string `json:"expiration,omitempty"`
+	Status      `json:",inline"`
+	Expiration  string `json:"expiration,omitempty"`
+	NotBefore   string `json:"notBefore,omitempty"`
+	NotAfter    string `json:"notAfter,omitempty"`
+	RenewalTime string `json:"renewalTime,omitempty"`
 }
 
 func (c *Certificate) Key() string {
@@ -845,6 +935,33 @@ func (c *Certificate) SetReady(err error) bool {
 	return c.Status.SetCondition(CONDITION_TYPE_READY, ErrorOrReadyCondition(err), c.ObjectMeta.Generation)
 }
 
+func (c *Certificate) SetPending(message string) bool {
+	return c.Status.SetCondition(CONDITION_TYPE_READY, PendingCondition(message), c.ObjectMeta.Generation)
+}
+
+func (c *Certificate) SetValidity(notBefore time.Time, notAfter time.Time, renewal time.Time) bool {
+	renewalTime := ""
+	if !renewal.IsZero() {
+		renewalTime = renewal.UTC().Format(time.RFC3339)
+	}
+	changed := false
+	for _, field := range []struct {
+		value   *string
+		desired string
+	}{
+		{&c.Status.Expiration, notAfter.UTC().Format(time.RFC3339)},
+		{&c.Status.NotBefore, notBefore.UTC().Format(time.RFC3339)},
+		{&c.Status.NotAfter, notAfter.UTC().Format(time.RFC3339)},
+		{&c.Status.RenewalTime, renewalTime},
+	} {
+		if *field.value != field.desired {
+			*field.value = field.desired
+			changed = true
+		}
+	}
+	return changed
+}
+
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
@@ -679,6 +679,22 @@ func (in *Controller) DeepCopy() *Controller {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrainStatus) DeepCopyInto(out *DrainStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrainStatus.
+func (in *DrainStatus) DeepCopy() *DrainStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DrainStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Endpoint) DeepCopyInto(out *Endpoint) {
 	*out = *in
@@ -804,6 +820,11 @@ func (in *LinkSpec) DeepCopy() *LinkSpec {
 func (in *LinkStatus) DeepCopyInto(out *LinkStatus) {
 	*out = *in
 	in.Status.DeepCopyInto(&out.Status)
+	if in.ActiveEndpoint != nil {
+		in, out := &in.ActiveEndpoint, &out.ActiveEndpoint
+		*out = new(Endpoint)
+		**out = **in
+	}
 	return
 }
 
@@ -1375,6 +1396,11 @@ func (in *SiteStatus) DeepCopyInto(out *SiteStatus) {
 		*out = new(Controller)
 		**out = **in
 	}
+	if in.Drain != nil {
+		in, out := &in.Drain, &out.Drain
+		*out = new(DrainStatus)
+		**out = **in
+	}
 	return
 }
 
@@ -24,6 +24,8 @@ const (
 	InternalBasePath      InternalPath = "internal"
 	LoadedSiteStatePath   InternalPath = "internal/snapshot"
 	ScriptsPath           InternalPath = "internal/scripts"
+	RouterHeartbeatPath   InternalPath = "internal/router-heartbeat.json"
+	SecretsConfigPath     InternalPath = "internal/secrets.yaml"
 )
 
 type IdGetter func() int
@@ -185,6 +187,16 @@ func GetInternalOutputPath(namespace string, internalPath InternalPath) string {
 	return path.Join(GetDefaultOutputPath(namespace), string(internalPath))
 }
 
+// GetUnsealedOutputPath returns the location, under the runtime directory,
+// where the encrypted secrets of a namespace are decrypted to when its
+// router is started
+func GetUnsealedOutputPath(namespace string) string {
+	if namespace == "" {
+		namespace = "default"
+	}
+	return path.Join(GetRuntimeDir(), "skupper", "namespaces", namespace)
+}
+
 func GetInternalBundleOutputPath(namespace string, internalPath InternalPath) string {
 	return path.Join(GetDefaultBundleOutputPath(namespace), string(internalPath))
 }
@@ -1,15 +1,18 @@
 package api
 
 import (
+	"bytes"
 	encodingjson "encoding/json"
 	"fmt"
+	"maps"
 	"os"
 	"path"
-	"reflect"
 	"slices"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/skupperproject/skupper/internal/certs"
 	"github.com/skupperproject/skupper/internal/network"
 	"github.com/skupperproject/skupper/internal/qdr"
 	"github.com/skupperproject/skupper/internal/site"
@@ -228,6 +231,17 @@ func (s *SiteState) CreateBridgeCertificates() {
 }
 
 func (s *SiteState) newCertificate(name string, spec *v2alpha1.CertificateSpec) *v2alpha1.Certificate {
+	// key options configured on the site apply to all its certificates
+	if s.Site != nil {
+		for _, setting := range []string{certs.SettingKeyAlgorithm, certs.SettingKeySize} {
+			if value, ok := s.Site.Spec.Settings[setting]; ok {
+				if spec.Settings == nil {
+					spec.Settings = map[string]string{}
+				}
+				spec.Settings[setting] = value
+			}
+		}
+	}
 	return &v2alpha1.Certificate{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "skupper.io/v2alpha1",
@@ -267,11 +281,11 @@ func (s *SiteState) linkMap(sslProfileBasePath string) site.LinkMap {
 func (s *SiteState) bindings(sslProfileBasePath string) *site.Bindings {
 	b := site.NewBindings(path.Join(sslProfileBasePath, string(CertificatesPath)))
 	for name, connector := range s.Connectors {
-		connector.SetConfigured(nil)
+		connector.SetConfigured(site.CheckConnectorType(connector))
 		_ = b.UpdateConnector(name, connector)
 	}
 	for name, listener := range s.Listeners {
-		listener.SetConfigured(nil)
+		listener.SetConfigured(site.CheckListenerType(listener))
 		_ = b.UpdateListener(name, listener)
 	}
 	return b
@@ -312,6 +326,10 @@ func (s *SiteState) ToRouterConfig(sslProfileBasePath string, platform string) q
 	s.linkMap(sslProfileBasePath).Apply(&routerConfig)
 	// Bindings
 	s.bindings(sslProfileBasePath).Apply(&routerConfig)
+	// Drain
+	drain := site.NewDrain()
+	drain.Update(s.Site)
+	drain.Apply(&routerConfig)
 	// Log (static for now) TODO use site specific options to configure logging
 	routerConfig.SetLogLevel("ROUTER_CORE", "error+")
 
@@ -345,62 +363,121 @@ func (s *SiteState) SetNamespace(namespace string) {
 	setNamespaceOnMap(s.ConfigMaps, namespace)
 }
 
+// UpdateStatus computes the conditions of the site resources from the
+// given network status, as done by the controller on kubernetes sites.
 func (s *SiteState) UpdateStatus(networkStatus network.NetworkStatusInfo) {
 	siteRecords := network.ExtractSiteRecords(networkStatus)
-	if reflect.DeepEqual(s.Site.Status.Network, siteRecords) {
-		return
-	}
 	s.Site.Status.Network = siteRecords
 	s.Site.Status.SitesInNetwork = len(siteRecords)
-	linkRecords := network.GetLinkRecordsForSite(s.SiteId, siteRecords)
-
-	for _, linkRecord := range linkRecords {
-		if link, ok := s.Links[linkRecord.Name]; ok {
-			link.SetOperational(linkRecord.Operational, linkRecord.RemoteSiteId, linkRecord.RemoteSiteName)
-		}
+	s.Site.SetConfigured(nil)
+	s.Site.SetEndpoints(s.endpoints())
+	running := slices.ContainsFunc(siteRecords, func(record v2alpha1.SiteRecord) bool {
+		return record.Id == s.SiteId
+	})
+	if running {
+		s.Site.SetRunning(v2alpha1.ReadyCondition())
+	} else {
+		s.Site.SetRunning(v2alpha1.PendingCondition("Router not running"))
 	}
-	for linkName, existingLink := range s.Links {
-		exists := slices.ContainsFunc(linkRecords, func(record v2alpha1.LinkRecord) bool {
+	for _, routerAccess := range s.RouterAccesses {
+		routerAccess.SetConfigured(nil)
+	}
+
+	linkRecords := network.GetLinkRecordsForSite(s.SiteId, siteRecords)
+	for linkName, link := range s.Links {
+		link.SetConfigured(nil)
+		index := slices.IndexFunc(linkRecords, func(record v2alpha1.LinkRecord) bool {
 			return record.Name == linkName
 		})
-		if !exists {
-			existingLink.SetOperational(false, "", "")
+		if index < 0 {
+			link.SetOperational(false, "", "")
+			continue
 		}
+		linkRecord := linkRecords[index]
+		link.SetOperational(linkRecord.Operational, linkRecord.RemoteSiteId, linkRecord.RemoteSiteName)
 	}
 
 	// updating listeners and connectors
 	for _, listener := range s.Listeners {
+		listener.SetConfigured(site.CheckListenerType(listener))
 		listener.SetHasMatchingConnector(network.HasMatchingPair(networkStatus, listener.Spec.RoutingKey))
 	}
 	for _, connector := range s.Connectors {
+		connector.SetConfigured(site.CheckConnectorType(connector))
 		connector.SetHasMatchingListener(network.HasMatchingPair(networkStatus, connector.Spec.RoutingKey))
 	}
 }
 
-func marshal(outputDirectory, resourceType, resourceName string, resource interface{}) error {
+// endpoints returns the link endpoints exposed by the router accesses
+func (s *SiteState) endpoints() []v2alpha1.Endpoint {
+	var endpoints []v2alpha1.Endpoint
+	names := slices.Sorted(maps.Keys(s.RouterAccesses))
+	for _, name := range names {
+		routerAccess := s.RouterAccesses[name]
+		host := routerAccess.Spec.BindHost
+		if len(routerAccess.Spec.SubjectAlternativeNames) > 0 {
+			host = routerAccess.Spec.SubjectAlternativeNames[0]
+		}
+		for _, role := range routerAccess.Spec.Roles {
+			if role.Name != "inter-router" && role.Name != "edge" {
+				continue
+			}
+			endpoints = append(endpoints, v2alpha1.Endpoint{
+				Name:  role.Name,
+				Host:  host,
+				Port:  strconv.Itoa(role.Port),
+				Group: name,
+			})
+		}
+	}
+	return endpoints
+}
+
+// ResourceSealer transforms an encoded resource before it is written,
+// allowing the resources holding secrets to be encrypted in memory
+type ResourceSealer func(resourceType string, data []byte) ([]byte, error)
+
+// MarshalResource writes a single resource to the output directory of
+// a site state, using the file name given to it by MarshalSiteState
+func MarshalResource(outputDirectory, resourceType, resourceName string, resource runtime.Object) error {
+	return marshal(outputDirectory, resourceType, resourceName, resource, nil)
+}
+
+// MarshalSealedResource is like MarshalResource, but passes the encoded
+// resource through the sealer before writing it
+func MarshalSealedResource(outputDirectory, resourceType, resourceName string, resource runtime.Object, sealer ResourceSealer) error {
+	return marshal(outputDirectory, resourceType, resourceName, resource, sealer)
+}
+
+func marshal(outputDirectory, resourceType, resourceName string, resource interface{}, sealer ResourceSealer) error {
 	var err error
 	err = os.MkdirAll(outputDirectory, 0755)
 	if err != nil {
 		return fmt.Errorf("error creating directory %s: %w", outputDirectory, err)
 	}
 	fileName := path.Join(outputDirectory, fmt.Sprintf("%s-%s.yaml", resourceType, resourceName))
-	file, err := os.Create(fileName)
-	defer file.Close()
-	if err != nil {
-		return fmt.Errorf("error creating file %s: %w", fileName, err)
-	}
+	var buffer bytes.Buffer
 	yaml := json.NewYAMLSerializer(json.DefaultMetaFactory, scheme.Scheme, scheme.Scheme)
-	err = yaml.Encode(resource.(runtime.Object), file)
+	err = yaml.Encode(resource.(runtime.Object), &buffer)
 	if err != nil {
 		return fmt.Errorf("error marshalling resource %s-%s: %w", resourceType, resourceName, err)
 	}
+	data := buffer.Bytes()
+	if sealer != nil {
+		if data, err = sealer(resourceType, data); err != nil {
+			return fmt.Errorf("error sealing resource %s-%s: %w", resourceType, resourceName, err)
+		}
+	}
+	if err = os.WriteFile(fileName, data, 0644); err != nil {
+		return fmt.Errorf("error creating file %s: %w", fileName, err)
+	}
 	return nil
 }
 
-func marshalMap[V any](outputDirectory, resourceType string, resourceMap map[string]V) error {
+func marshalMap[V any](outputDirectory, resourceType string, resourceMap map[string]V, sealer ResourceSealer) error {
 	var err error
 	for resourceName, resource := range resourceMap {
-		if err = marshal(outputDirectory, resourceType, resourceName, resource); err != nil {
+		if err = marshal(outputDirectory, resourceType, resourceName, resource, sealer); err != nil {
 			return err
 		}
 	}
@@ -408,41 +485,48 @@ func marshalMap[V any](outputDirectory, resourceType string, resourceMap map[str
 }
 
 func MarshalSiteState(siteState SiteState, outputDirectory string) error {
+	return MarshalSealedSiteState(siteState, outputDirectory, nil)
+}
+
+// MarshalSealedSiteState is like MarshalSiteState, but passes each
+// encoded resource through the sealer before writing it, so that no
+// secret is written to disk in plain text
+func MarshalSealedSiteState(siteState SiteState, outputDirectory string, sealer ResourceSealer) error {
 	var err error
 	if siteState.Site != nil && siteState.Site.ObjectMeta.UID == "" {
 		siteState.Site.ObjectMeta.UID = types.UID(siteState.SiteId)
 	}
-	if err = marshal(outputDirectory, "Site", siteState.Site.Name, siteState.Site); err != nil {
+	if err = marshal(outputDirectory, "Site", siteState.Site.Name, siteState.Site, sealer); err != nil {
 		return err
 	}
-	if err = marshalMap(outputDirectory, "Listener", siteState.Listeners); err != nil {
+	if err = marshalMap(outputDirectory, "Listener", siteState.Listeners, sealer); err != nil {
 		return err
 	}
-	if err = marshalMap(outputDirectory, "Connector", siteState.Connectors); err != nil {
+	if err = marshalMap(outputDirectory, "Connector", siteState.Connectors, sealer); err != nil {
 		return err
 	}
-	if err = marshalMap(outputDirectory, "RouterAccess", siteState.RouterAccesses); err != nil {
+	if err = marshalMap(outputDirectory, "RouterAccess", siteState.RouterAccesses, sealer); err != nil {
 		return err
 	}
-	if err = marshalMap(outputDirectory, "Link", siteState.Links); err != nil {
+	if err = marshalMap(outputDirectory, "Link", siteState.Links, sealer); err != nil {
 		return err
 	}
-	if err = marshalMap(outputDirectory, "AccessGrant", siteState.Grants); err != nil {
+	if err = marshalMap(outputDirectory, "AccessGrant", siteState.Grants, sealer); err != nil {
 		return err
 	}
-	if err = marshalMap(outputDirectory, "AccessToken", siteState.Claims); err != nil {
+	if err = marshalMap(outputDirectory, "AccessToken", siteState.Claims, sealer); err != nil {
 		return err
 	}
-	if err = marshalMap(outputDirectory, "Certificate", siteState.Certificates); err != nil {
+	if err = marshalMap(outputDirectory, "Certificate", siteState.Certificates, sealer); err != nil {
 		return err
 	}
-	if err = marshalMap(outputDirectory, "SecuredAccess", siteState.SecuredAccesses); err != nil {
+	if err = marshalMap(outputDirectory, "SecuredAccess", siteState.SecuredAccesses, sealer); err != nil {
 		return err
 	}
-	if err = marshalMap(outputDirectory, "Secret", siteState.Secrets); err != nil {
+	if err = marshalMap(outputDirectory, "Secret", siteState.Secrets, sealer); err != nil {
 		return err
 	}
-	if err = marshalMap(outputDirectory, "ConfigMap", siteState.ConfigMaps); err != nil {
+	if err = marshalMap(outputDirectory, "ConfigMap", siteState.ConfigMaps, sealer); err != nil {
 		return err
 	}
 	return nil
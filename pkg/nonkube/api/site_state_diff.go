@@ -0,0 +1,124 @@
+package api
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"sort"
+
+	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ResourceDiff holds the names of the resources of a given kind that have
+// been added, updated or deleted between two site states.
+type ResourceDiff struct {
+	Added   []string
+	Updated []string
+	Deleted []string
+}
+
+func (r ResourceDiff) Empty() bool {
+	return len(r.Added) == 0 && len(r.Updated) == 0 && len(r.Deleted) == 0
+}
+
+// SiteStateDiff describes the changes between two site states. Listeners,
+// Connectors, Links and the drain settings of the site can be applied to a
+// running router and AccessGrants are served by the system controller
+// without involving the router, any other change is listed in
+// ReloadReasons and requires the site to be reloaded.
+type SiteStateDiff struct {
+	Listeners     ResourceDiff
+	Connectors    ResourceDiff
+	Links         ResourceDiff
+	Grants        ResourceDiff
+	Drain         bool
+	ReloadReasons []string
+}
+
+func (d *SiteStateDiff) Empty() bool {
+	return d.Grants.Empty() && !d.RouterChanged() && !d.RequiresReload()
+}
+
+// RouterChanged returns true if the diff has changes to be applied to
+// the running router
+func (d *SiteStateDiff) RouterChanged() bool {
+	return !d.Listeners.Empty() || !d.Connectors.Empty() || !d.Links.Empty() || d.Drain
+}
+
+func (d *SiteStateDiff) RequiresReload() bool {
+	return len(d.ReloadReasons) > 0
+}
+
+// DiffSiteStates compares the specs of the resources from the current
+// and desired site states.
+func DiffSiteStates(current, desired *SiteState) *SiteStateDiff {
+	diff := &SiteStateDiff{
+		Listeners:  diffMap(current.Listeners, desired.Listeners, listenerSpec),
+		Connectors: diffMap(current.Connectors, desired.Connectors, connectorSpec),
+		Links:      diffMap(current.Links, desired.Links, linkSpec),
+		Grants:     diffMap(current.Grants, desired.Grants, grantSpec),
+	}
+	if current.Site == nil || desired.Site == nil || current.Site.Name != desired.Site.Name ||
+		current.Site.UID != desired.Site.UID || !reflect.DeepEqual(siteSpec(current.Site), siteSpec(desired.Site)) {
+		diff.ReloadReasons = append(diff.ReloadReasons, "site has changed")
+	} else if !reflect.DeepEqual(current.Site.Spec, desired.Site.Spec) {
+		diff.Drain = true
+	}
+	diff.addReloadReason("RouterAccess", diffMap(current.RouterAccesses, desired.RouterAccesses, routerAccessSpec))
+	diff.addReloadReason("AccessToken", diffMap(current.Claims, desired.Claims, claimSpec))
+	diff.addReloadReason("Certificate", diffMap(current.Certificates, desired.Certificates, certificateSpec))
+	diff.addReloadReason("SecuredAccess", diffMap(current.SecuredAccesses, desired.SecuredAccesses, securedAccessSpec))
+	diff.addReloadReason("Secret", diffMap(current.Secrets, desired.Secrets, secretData))
+	return diff
+}
+
+func (d *SiteStateDiff) addReloadReason(kind string, resources ResourceDiff) {
+	if !resources.Empty() {
+		d.ReloadReasons = append(d.ReloadReasons, fmt.Sprintf("%s resources have changed", kind))
+	}
+}
+
+func diffMap[V any](current, desired map[string]V, spec func(V) interface{}) ResourceDiff {
+	diff := ResourceDiff{}
+	for name, resource := range desired {
+		currentResource, ok := current[name]
+		if !ok {
+			diff.Added = append(diff.Added, name)
+		} else if !reflect.DeepEqual(spec(currentResource), spec(resource)) {
+			diff.Updated = append(diff.Updated, name)
+		}
+	}
+	for name := range current {
+		if _, ok := desired[name]; !ok {
+			diff.Deleted = append(diff.Deleted, name)
+		}
+	}
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Updated)
+	sort.Strings(diff.Deleted)
+	return diff
+}
+
+// siteSpec returns the spec of a site without the drain settings, which
+// can be changed without reloading the site
+func siteSpec(s *v2alpha1.Site) v2alpha1.SiteSpec {
+	spec := s.Spec
+	spec.Settings = maps.Clone(spec.Settings)
+	delete(spec.Settings, "drain")
+	delete(spec.Settings, "drain-timeout")
+	if len(spec.Settings) == 0 {
+		spec.Settings = nil
+	}
+	return spec
+}
+
+func listenerSpec(l *v2alpha1.Listener) interface{}           { return l.Spec }
+func connectorSpec(c *v2alpha1.Connector) interface{}         { return c.Spec }
+func linkSpec(l *v2alpha1.Link) interface{}                   { return l.Spec }
+func routerAccessSpec(r *v2alpha1.RouterAccess) interface{}   { return r.Spec }
+func grantSpec(g *v2alpha1.AccessGrant) interface{}           { return g.Spec }
+func claimSpec(c *v2alpha1.AccessToken) interface{}           { return c.Spec }
+func certificateSpec(c *v2alpha1.Certificate) interface{}     { return c.Spec }
+func securedAccessSpec(s *v2alpha1.SecuredAccess) interface{} { return s.Spec }
+func secretData(s *corev1.Secret) interface{}                 { return s.Data }
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDiffSiteStates(t *testing.T) {
+	tests := []struct {
+		name           string
+		modify         func(ss *SiteState)
+		listeners      ResourceDiff
+		connectors     ResourceDiff
+		links          ResourceDiff
+		grants         ResourceDiff
+		drain          bool
+		reloadReasons  []string
+		expectEmpty    bool
+		expectedReload bool
+	}{
+		{
+			name:        "unchanged",
+			modify:      func(ss *SiteState) {},
+			expectEmpty: true,
+		},
+		{
+			name: "status only",
+			modify: func(ss *SiteState) {
+				ss.Listeners["listener-one"].SetConfigured(nil)
+				ss.Links["link-one"].SetOperational(true, "remote-id", "remote")
+			},
+			expectEmpty: true,
+		},
+		{
+			name: "listeners and connectors",
+			modify: func(ss *SiteState) {
+				ss.Listeners["listener-one"].Spec.Port = 8080
+				delete(ss.Listeners, "listener-two")
+				ss.Listeners["listener-three"] = &v2alpha1.Listener{
+					ObjectMeta: metav1.ObjectMeta{Name: "listener-three"},
+					Spec:       v2alpha1.ListenerSpec{RoutingKey: "three", Host: "three", Port: 8080},
+				}
+				delete(ss.Connectors, "connector-one")
+			},
+			listeners: ResourceDiff{
+				Added:   []string{"listener-three"},
+				Updated: []string{"listener-one"},
+				Deleted: []string{"listener-two"},
+			},
+			connectors: ResourceDiff{
+				Deleted: []string{"connector-one"},
+			},
+		},
+		{
+			name: "links",
+			modify: func(ss *SiteState) {
+				ss.Links["link-one"].Spec.Cost = 5
+			},
+			links: ResourceDiff{
+				Updated: []string{"link-one"},
+			},
+		},
+		{
+			name: "grants",
+			modify: func(ss *SiteState) {
+				ss.Grants["grant-two"] = &v2alpha1.AccessGrant{
+					ObjectMeta: metav1.ObjectMeta{Name: "grant-two"},
+					Spec:       v2alpha1.AccessGrantSpec{RedemptionsAllowed: 1, ExpirationWindow: "15m"},
+				}
+			},
+			grants: ResourceDiff{
+				Added: []string{"grant-two"},
+			},
+		},
+		{
+			name: "site and router access",
+			modify: func(ss *SiteState) {
+				ss.Site.Spec.Edge = true
+				ss.RouterAccesses["link-access-one"].Spec.Roles[0].Port = 55672
+			},
+			reloadReasons:  []string{"site has changed", "RouterAccess resources have changed"},
+			expectedReload: true,
+		},
+		{
+			name: "drain",
+			modify: func(ss *SiteState) {
+				ss.Site.Spec.Settings = map[string]string{"drain": "true", "drain-timeout": "10m"}
+			},
+			drain: true,
+		},
+		{
+			name: "drain and other settings",
+			modify: func(ss *SiteState) {
+				ss.Site.Spec.Settings = map[string]string{"drain": "true", "router-logging": "debug"}
+			},
+			reloadReasons:  []string{"site has changed"},
+			expectedReload: true,
+		},
+		{
+			name: "secrets",
+			modify: func(ss *SiteState) {
+				ss.Secrets["link-one-profile"].Data["tls.crt"] = []byte("new.crt")
+				ss.Links["link-one"].Spec.TlsCredentials = "link-one-profile"
+			},
+			links: ResourceDiff{
+				Updated: []string{"link-one"},
+			},
+			reloadReasons:  []string{"Secret resources have changed"},
+			expectedReload: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			desired := fakeSiteState()
+			test.modify(desired)
+			diff := DiffSiteStates(fakeSiteState(), desired)
+			assert.Equal(t, diff.Empty(), test.expectEmpty)
+			assert.Equal(t, diff.RequiresReload(), test.expectedReload)
+			assert.DeepEqual(t, diff.Listeners, test.listeners)
+			assert.DeepEqual(t, diff.Connectors, test.connectors)
+			assert.DeepEqual(t, diff.Links, test.links)
+			assert.DeepEqual(t, diff.Grants, test.grants)
+			assert.Equal(t, diff.Drain, test.drain)
+			assert.DeepEqual(t, diff.ReloadReasons, test.reloadReasons)
+		})
+	}
+}
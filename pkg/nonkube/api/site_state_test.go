@@ -57,6 +57,23 @@ func TestSiteState_CreateLinkAccessesCertificates(t *testing.T) {
 	assert.Assert(t, hasLinkAccessToken)
 }
 
+func TestSiteState_CertificateKeySettings(t *testing.T) {
+	ss := fakeSiteState()
+	ss.Site.Spec.Settings = map[string]string{
+		"key-algorithm": "ecdsa",
+		"key-size":      "384",
+		"size":          "large",
+	}
+	ss.CreateLinkAccessesCertificates()
+	ss.CreateBridgeCertificates()
+	for _, certificate := range ss.Certificates {
+		assert.DeepEqual(t, certificate.Spec.Settings, map[string]string{
+			"key-algorithm": "ecdsa",
+			"key-size":      "384",
+		})
+	}
+}
+
 func TestSiteState_HasRouterAccess(t *testing.T) {
 	assert.Equal(t, fakeSiteState().HasRouterAccess(), true)
 }
@@ -179,6 +196,22 @@ func TestUpdateStatus(t *testing.T) {
 	assert.Equal(t, ss.Connectors["connector-one"].Status.HasMatchingListener, true)
 	assert.Equal(t, meta.IsStatusConditionTrue(ss.Links["link-one"].Status.Conditions, v2alpha1.CONDITION_TYPE_OPERATIONAL), true)
 	assert.Equal(t, meta.IsStatusConditionTrue(ss.Links["link-broken"].Status.Conditions, v2alpha1.CONDITION_TYPE_OPERATIONAL), false)
+	assert.Assert(t, ss.Site.IsReady())
+	assert.Equal(t, len(ss.Site.Status.Endpoints), 2)
+	assert.Assert(t, ss.Links["link-one"].IsReady())
+	assert.Equal(t, ss.Links["link-broken"].Status.StatusType, v2alpha1.StatusType("Pending"))
+	assert.Equal(t, ss.Links["link-broken"].Status.Message, "Not operational")
+	assert.Equal(t, ss.Listeners["listener-one"].Status.StatusType, v2alpha1.StatusType("Ready"))
+	assert.Equal(t, ss.Listeners["listener-two"].Status.Message, "No matching connectors")
+	assert.Equal(t, ss.Connectors["connector-one"].Status.StatusType, v2alpha1.StatusType("Ready"))
+	assert.Assert(t, meta.IsStatusConditionTrue(ss.RouterAccesses["skupper-local"].Status.Conditions, v2alpha1.CONDITION_TYPE_CONFIGURED))
+
+	// router not running
+	ss.UpdateStatus(network.NetworkStatusInfo{})
+	assert.Equal(t, ss.Site.Status.SitesInNetwork, 0)
+	assert.Equal(t, ss.Site.Status.Message, "Router not running")
+	assert.Equal(t, ss.Links["link-one"].IsReady(), false)
+	assert.Equal(t, ss.Listeners["listener-one"].Status.HasMatchingConnector, false)
 }
 
 func fakeNetworkStatusInfo() network.NetworkStatusInfo {
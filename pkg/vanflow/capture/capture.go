@@ -0,0 +1,157 @@
+// capture records the vanflow messages sent by event sources to a file so
+// that they can be replayed later, decoupled from the router network that
+// produced them.
+//
+// A capture is a gzip compressed stream starting with a fixed header followed
+// by frames. Each frame holds the time the message was received and the
+// message itself in its amqp binary encoding.
+package capture
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	amqp "github.com/Azure/go-amqp"
+	"github.com/skupperproject/skupper/pkg/vanflow"
+)
+
+const (
+	header = "VANFLOWCAP\x00\x01"
+	// maxFrameSize bounds the size of a single message read from a capture
+	maxFrameSize = 64 * 1024 * 1024
+)
+
+var ErrInvalidCapture = errors.New("invalid vanflow capture")
+
+// Frame is a message captured at a point in time
+type Frame struct {
+	Time    time.Time
+	Message *amqp.Message
+}
+
+// Writer writes frames to a capture. It is safe for concurrent use.
+type Writer struct {
+	mu  sync.Mutex
+	gz  *gzip.Writer
+	buf [12]byte
+}
+
+// NewWriter starts a capture written to w
+func NewWriter(w io.Writer) (*Writer, error) {
+	gz := gzip.NewWriter(w)
+	if _, err := io.WriteString(gz, header); err != nil {
+		return nil, err
+	}
+	return &Writer{gz: gz}, nil
+}
+
+// WriteMessage writes a vanflow message to the capture. Supports
+// vanflow.BeaconMessage, vanflow.HeartbeatMessage and vanflow.RecordMessage.
+func (w *Writer) WriteMessage(ts time.Time, message any) error {
+	var (
+		msg *amqp.Message
+		err error
+		to  string
+	)
+	switch message := message.(type) {
+	case vanflow.BeaconMessage:
+		msg = message.Encode()
+	case vanflow.HeartbeatMessage:
+		msg, to = message.Encode(), message.To
+	case vanflow.RecordMessage:
+		msg, err = message.Encode()
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("cannot capture message of type %T", message)
+	}
+	if to != "" {
+		// keep the address the message was received on
+		msg.Properties.To = &to
+	}
+	return w.Write(Frame{Time: ts, Message: msg})
+}
+
+// Write a frame to the capture
+func (w *Writer) Write(frame Frame) error {
+	data, err := frame.Message.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("error encoding message: %w", err)
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	binary.BigEndian.PutUint64(w.buf[:8], uint64(frame.Time.UnixNano()))
+	binary.BigEndian.PutUint32(w.buf[8:], uint32(len(data)))
+	if _, err := w.gz.Write(w.buf[:]); err != nil {
+		return err
+	}
+	_, err = w.gz.Write(data)
+	return err
+}
+
+// Flush writes any buffered frames to the underlying writer
+func (w *Writer) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.gz.Flush()
+}
+
+// Close flushes the capture. It does not close the underlying writer.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.gz.Close()
+}
+
+// Reader reads the frames of a capture
+type Reader struct {
+	r   *bufio.Reader
+	buf [12]byte
+}
+
+// NewReader reads a capture from r
+func NewReader(r io.Reader) (*Reader, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCapture, err)
+	}
+	reader := &Reader{r: bufio.NewReader(gz)}
+	var hdr [len(header)]byte
+	if _, err := io.ReadFull(reader.r, hdr[:]); err != nil || string(hdr[:]) != header {
+		return nil, fmt.Errorf("%w: unexpected header", ErrInvalidCapture)
+	}
+	return reader, nil
+}
+
+// Next returns the next frame of the capture. Returns io.EOF at the end of the
+// capture.
+func (r *Reader) Next() (Frame, error) {
+	var frame Frame
+	if _, err := io.ReadFull(r.r, r.buf[:]); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return frame, fmt.Errorf("%w: truncated frame", ErrInvalidCapture)
+		}
+		return frame, err
+	}
+	size := binary.BigEndian.Uint32(r.buf[8:])
+	if size > maxFrameSize {
+		return frame, fmt.Errorf("%w: frame size %d exceeds limit", ErrInvalidCapture, size)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r.r, data); err != nil {
+		return frame, fmt.Errorf("%w: truncated frame", ErrInvalidCapture)
+	}
+	frame.Time = time.Unix(0, int64(binary.BigEndian.Uint64(r.buf[:8])))
+	frame.Message = new(amqp.Message)
+	if err := frame.Message.UnmarshalBinary(data); err != nil {
+		return frame, fmt.Errorf("%w: %s", ErrInvalidCapture, err)
+	}
+	return frame, nil
+}
@@ -0,0 +1,191 @@
+package capture
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	"github.com/skupperproject/skupper/pkg/vanflow/eventsource"
+	"github.com/skupperproject/skupper/pkg/vanflow/session"
+	"gotest.tools/v3/assert"
+)
+
+func TestCaptureRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf)
+	assert.Assert(t, err)
+
+	t0 := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	beacon := vanflow.BeaconMessage{
+		Version: 1, SourceType: "ROUTER", Address: "mc/sfe.router-1", Direct: "sfe.router-1", Identity: "router-1",
+	}
+	heartbeat := vanflow.HeartbeatMessage{
+		MessageProps: vanflow.MessageProps{To: "mc/sfe.router-1.heartbeats", Subject: "HEARTBEAT"},
+		Identity:     "router-1", Version: 1, Now: 22,
+	}
+	name := "west"
+	record := vanflow.RecordMessage{
+		MessageProps: vanflow.MessageProps{To: "mc/sfe.router-1", Subject: "RECORD"},
+		Records:      []vanflow.Record{vanflow.SiteRecord{BaseRecord: vanflow.NewBase("site-1"), Name: &name}},
+	}
+	assert.Assert(t, w.WriteMessage(t0, beacon))
+	assert.Assert(t, w.WriteMessage(t0.Add(time.Second), heartbeat))
+	assert.Assert(t, w.WriteMessage(t0.Add(2*time.Second), record))
+	assert.ErrorContains(t, w.WriteMessage(t0, "beacon"), "cannot capture message of type string")
+	assert.Assert(t, w.Close())
+
+	r, err := NewReader(bytes.NewReader(buf.Bytes()))
+	assert.Assert(t, err)
+	var decoded []any
+	for i := 0; ; i++ {
+		frame, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		assert.Assert(t, err)
+		assert.Assert(t, frame.Time.Equal(t0.Add(time.Duration(i)*time.Second)))
+		msg, err := vanflow.Decode(frame.Message)
+		assert.Assert(t, err)
+		decoded = append(decoded, msg)
+	}
+	beacon.To, beacon.Subject = "mc/sfe.all", "BEACON"
+	assert.DeepEqual(t, decoded, []any{beacon, heartbeat, record})
+
+	_, err = NewReader(bytes.NewReader([]byte("not a capture")))
+	assert.ErrorIs(t, err, ErrInvalidCapture)
+	r, err = NewReader(bytes.NewReader(buf.Bytes()[:buf.Len()-20]))
+	if err == nil {
+		for err == nil {
+			_, err = r.Next()
+		}
+	}
+	assert.Assert(t, !errors.Is(err, io.EOF), "expected truncated capture to fail: %s", err)
+}
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return bytes.Clone(b.buf.Bytes())
+}
+
+// frames returns the frames flushed to the buffer so far
+func (b *syncBuffer) frames() []Frame {
+	r, err := NewReader(bytes.NewReader(b.Bytes()))
+	if err != nil {
+		return nil
+	}
+	var frames []Frame
+	for {
+		frame, err := r.Next()
+		if err != nil {
+			return frames
+		}
+		frames = append(frames, frame)
+	}
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	source := eventsource.Info{ID: "router-1", Version: 1, Type: "ROUTER", Address: "mc/sfe.router-1", Direct: "sfe.router-1"}
+	name := "west"
+	records := vanflow.RecordMessage{
+		MessageProps: vanflow.MessageProps{To: source.Address, Subject: "RECORD"},
+		Records:      []vanflow.Record{vanflow.SiteRecord{BaseRecord: vanflow.NewBase("site-1"), Name: &name}},
+	}
+
+	// capture a source on a mock network
+	router := session.NewMockRouter()
+	var buf syncBuffer
+	w, err := NewWriter(&buf)
+	assert.Assert(t, err)
+	go NewRecorder(session.NewMockContainer(router), w).Run(ctx)
+
+	sourceCtr := session.NewMockContainer(router)
+	beacon := vanflow.BeaconMessage{
+		Version: uint32(source.Version), SourceType: source.Type, Address: source.Address, Direct: source.Direct, Identity: source.ID,
+	}
+	assert.Assert(t, sourceCtr.NewSender("mc/sfe.all", session.SenderOptions{}).Send(ctx, beacon.Encode()))
+	msg, err := records.Encode()
+	assert.Assert(t, err)
+	assert.Assert(t, sourceCtr.NewSender(source.Address, session.SenderOptions{}).Send(ctx, msg))
+	for len(buf.frames()) < 2 {
+		assert.Assert(t, ctx.Err())
+		assert.Assert(t, w.Flush())
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Assert(t, w.Close())
+
+	// replay it to a client on another mock network
+	replayRouter := session.NewMockRouter()
+	received := make(chan vanflow.RecordMessage, 1)
+	discovery := eventsource.NewDiscovery(session.NewMockContainer(replayRouter), eventsource.DiscoveryOptions{})
+	go discovery.Run(ctx, eventsource.DiscoveryHandlers{
+		Discovered: func(info eventsource.Info) {
+			client := eventsource.NewClient(session.NewMockContainer(replayRouter), eventsource.ClientOptions{Source: info})
+			client.OnRecord(func(msg vanflow.RecordMessage) { received <- msg })
+			client.Listen(ctx, eventsource.FromSourceAddress())
+		},
+	})
+	r, err := NewReader(bytes.NewReader(buf.Bytes()))
+	assert.Assert(t, err)
+	assert.Assert(t, NewReplayer(session.NewMockContainer(replayRouter), r, ReplayOptions{}).Run(ctx))
+	select {
+	case msg := <-received:
+		assert.DeepEqual(t, msg, records)
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for replayed records")
+	}
+	info, ok := discovery.Get(source.ID)
+	assert.Assert(t, ok)
+	assert.Equal(t, info.Address, source.Address)
+}
+
+func TestReplaySpeed(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf)
+	assert.Assert(t, err)
+	t0 := time.Now()
+	heartbeat := vanflow.HeartbeatMessage{
+		MessageProps: vanflow.MessageProps{To: "mc/sfe.router-1.heartbeats", Subject: "HEARTBEAT"},
+		Identity:     "router-1",
+	}
+	for i := 0; i < 3; i++ {
+		heartbeat.Now = uint64(i)
+		assert.Assert(t, w.WriteMessage(t0.Add(time.Duration(i)*time.Second), heartbeat))
+	}
+	assert.Assert(t, w.Close())
+
+	router := session.NewMockRouter()
+	receiver := session.NewMockContainer(router).NewReceiver(heartbeat.To, session.ReceiverOptions{})
+	r, err := NewReader(&buf)
+	assert.Assert(t, err)
+	start := time.Now()
+	// replayed ten times faster
+	assert.Assert(t, NewReplayer(session.NewMockContainer(router), r, ReplayOptions{Speed: 10}).Run(context.Background()))
+	elapsed := time.Since(start)
+	assert.Assert(t, elapsed >= 200*time.Millisecond, "replay too fast: %s", elapsed)
+	assert.Assert(t, elapsed < 2*time.Second, "replay too slow: %s", elapsed)
+	for i := 0; i < 3; i++ {
+		msg, err := receiver.Next(context.Background())
+		assert.Assert(t, err)
+		assert.Equal(t, vanflow.DecodeHeartbeat(msg).Now, uint64(i))
+	}
+}
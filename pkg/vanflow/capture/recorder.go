@@ -0,0 +1,125 @@
+package capture
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	"github.com/skupperproject/skupper/pkg/vanflow/eventsource"
+	"github.com/skupperproject/skupper/pkg/vanflow/session"
+)
+
+const flushInterval = 5 * time.Second
+
+// Recorder captures the beacons, heartbeats and record messages of every
+// event source discovered through a container.
+type Recorder struct {
+	container session.Container
+	writer    *Writer
+	discovery *eventsource.Discovery
+	logger    *slog.Logger
+	now       func() time.Time
+}
+
+func NewRecorder(container session.Container, writer *Writer) *Recorder {
+	return &Recorder{
+		container: container,
+		writer:    writer,
+		discovery: eventsource.NewDiscovery(container, eventsource.DiscoveryOptions{}),
+		logger: slog.New(slog.Default().Handler()).With(
+			slog.String("component", "vanflow.capture.recorder"),
+		),
+		now: time.Now,
+	}
+}
+
+// Run captures messages until the context is cancelled
+func (r *Recorder) Run(ctx context.Context) error {
+	r.container.Start(ctx)
+	go r.flush(ctx)
+	err := r.discovery.Run(ctx, eventsource.DiscoveryHandlers{
+		Discovered: func(source eventsource.Info) { r.discovered(ctx, source) },
+	})
+	if errors.Is(err, ctx.Err()) {
+		return nil
+	}
+	return err
+}
+
+// flush periodically so that the capture is usable when the process exits
+// without closing it
+func (r *Recorder) flush(ctx context.Context) {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.writer.Flush(); err != nil {
+				r.logger.Error("error flushing capture", slog.Any("error", err))
+			}
+		}
+	}
+}
+
+func (r *Recorder) write(message any) {
+	if err := r.writer.WriteMessage(r.now(), message); err != nil {
+		r.logger.Error("error capturing message", slog.Any("error", err))
+	}
+}
+
+func (r *Recorder) discovered(ctx context.Context, source eventsource.Info) {
+	r.logger.Info("capturing event source", slog.String("id", source.ID), slog.String("type", source.Type))
+	r.write(vanflow.BeaconMessage{
+		Version:    uint32(source.Version),
+		SourceType: source.Type,
+		Address:    source.Address,
+		Direct:     source.Direct,
+		Identity:   source.ID,
+	})
+
+	client := eventsource.NewClient(r.container, eventsource.ClientOptions{Source: source})
+	err := r.discovery.NewWatchClient(ctx, eventsource.WatchConfig{
+		Client:      client,
+		ID:          source.ID,
+		Timeout:     time.Second * 30,
+		GracePeriod: time.Second * 30,
+	})
+	if err != nil {
+		r.logger.Error("error creating watcher for discovered source", slog.Any("error", err))
+		r.discovery.Forget(source.ID)
+		return
+	}
+	client.OnRecord(func(msg vanflow.RecordMessage) { r.write(msg) })
+	client.OnHeartbeat(func(msg vanflow.HeartbeatMessage) { r.write(msg) })
+
+	addresses := []eventsource.ListenerConfigProvider{
+		eventsource.FromSourceAddress(),
+	}
+	switch source.Type {
+	case "CONTROLLER":
+		addresses = append(addresses, eventsource.FromSourceAddressHeartbeats())
+	case "ROUTER":
+		addresses = append(addresses, eventsource.FromSourceAddressFlows())
+	}
+	for _, address := range addresses {
+		client.Listen(ctx, address)
+	}
+
+	// start the capture of each source from its full state
+	go func() {
+		ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+		defer cancel()
+		if err := eventsource.FlushOnFirstMessage(ctx, client); err != nil {
+			if errors.Is(err, ctx.Err()) {
+				err = client.SendFlush(ctx)
+			}
+			if err != nil {
+				r.logger.Error("error sending flush", slog.Any("error", err))
+			}
+		}
+	}()
+}
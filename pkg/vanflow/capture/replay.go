@@ -0,0 +1,138 @@
+package capture
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	"github.com/skupperproject/skupper/pkg/vanflow/session"
+)
+
+type ReplayOptions struct {
+	// Speed at which the capture is replayed relative to the pace it was
+	// captured at. Values of zero or less replay as fast as possible.
+	Speed float64
+	// SendTimeout is how long to wait for a message to be sent before
+	// skipping it, as when no one is listening on its address. Defaults to
+	// ten seconds.
+	SendTimeout time.Duration
+}
+
+// Replayer sends the messages of a capture through a container, standing in
+// for the event sources that were captured. Typically used with a container
+// from session.NewMockContainer.
+type Replayer struct {
+	container session.Container
+	reader    *Reader
+	opts      ReplayOptions
+	senders   map[string]session.Sender
+	sources   map[string]bool
+	logger    *slog.Logger
+}
+
+func NewReplayer(container session.Container, reader *Reader, opts ReplayOptions) *Replayer {
+	if opts.SendTimeout <= 0 {
+		opts.SendTimeout = 10 * time.Second
+	}
+	return &Replayer{
+		container: container,
+		reader:    reader,
+		opts:      opts,
+		senders:   make(map[string]session.Sender),
+		sources:   make(map[string]bool),
+		logger: slog.New(slog.Default().Handler()).With(
+			slog.String("component", "vanflow.capture.replayer"),
+		),
+	}
+}
+
+// Run replays the capture until it is exhausted or the context is cancelled
+func (r *Replayer) Run(ctx context.Context) error {
+	defer func() {
+		for _, sender := range r.senders {
+			sender.Close(context.Background())
+		}
+	}()
+	var (
+		first time.Time
+		start = time.Now()
+		timer = time.NewTimer(0)
+	)
+	defer timer.Stop()
+	<-timer.C
+	for i := 0; ; i++ {
+		frame, err := r.reader.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if i == 0 {
+			first = frame.Time
+		}
+		if r.opts.Speed > 0 {
+			offset := time.Duration(float64(frame.Time.Sub(first)) / r.opts.Speed)
+			if wait := time.Until(start.Add(offset)); wait > 0 {
+				timer.Reset(wait)
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-timer.C:
+				}
+			}
+		}
+		if err := r.send(ctx, frame); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			r.logger.Debug("skipping message", slog.Any("error", err))
+		}
+	}
+}
+
+func (r *Replayer) send(ctx context.Context, frame Frame) error {
+	msg := frame.Message
+	if msg.Properties == nil || msg.Properties.To == nil {
+		return errors.New("message without address")
+	}
+	if msg.Properties.Subject != nil && *msg.Properties.Subject == "BEACON" {
+		r.standIn(ctx, vanflow.DecodeBeacon(msg))
+	}
+	address := *msg.Properties.To
+	sender, ok := r.senders[address]
+	if !ok {
+		sender = r.container.NewSender(address, session.SenderOptions{})
+		r.senders[address] = sender
+	}
+	sendCtx, cancel := context.WithTimeout(ctx, r.opts.SendTimeout)
+	defer cancel()
+	if err := sender.Send(sendCtx, msg); err != nil {
+		return fmt.Errorf("error sending message to %q: %w", address, err)
+	}
+	return nil
+}
+
+// standIn receives and discards the messages sent directly to a replayed
+// event source, such as flush requests.
+func (r *Replayer) standIn(ctx context.Context, beacon vanflow.BeaconMessage) {
+	if beacon.Direct == "" || r.sources[beacon.Identity] {
+		return
+	}
+	r.sources[beacon.Identity] = true
+	receiver := r.container.NewReceiver(beacon.Direct, session.ReceiverOptions{})
+	go func() {
+		defer receiver.Close(context.Background())
+		for {
+			msg, err := receiver.Next(ctx)
+			if err != nil {
+				return
+			}
+			receiver.Accept(ctx, msg)
+		}
+	}()
+}
@@ -0,0 +1,357 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	bolt "go.etcd.io/bbolt"
+)
+
+// BoltStoreConfig configures a store that is kept in memory and persisted to
+// a bucket of a bbolt database
+type BoltStoreConfig struct {
+	SyncMapStoreConfig
+
+	// Bucket containing the persisted entries
+	Bucket string
+	// RecordTypes to persist. Defaults to the vanflow record types. Records
+	// of other types are only kept in memory.
+	RecordTypes []vanflow.Record
+	// MaxAge of an entry since its last update before it is purged. Zero
+	// disables retention by age.
+	MaxAge time.Duration
+	// MaxSize in bytes of the persisted entries. The entries with the oldest
+	// updates are purged first once exceeded. Zero disables retention by
+	// size.
+	MaxSize int64
+	// OnLoad, when set, is called with each entry loaded from the database
+	// before it is indexed. It can be used to restore state that is not
+	// persisted.
+	OnLoad func(Entry) Entry
+}
+
+// BoltStore is a store backed by a bbolt database. Entries are served from
+// memory and written to the database by Flush, so that high volume updates
+// do not each require a transaction.
+type BoltStore struct {
+	Interface
+
+	db     *bolt.DB
+	cfg    BoltStoreConfig
+	types  map[string]reflect.Type
+	flush  sync.Mutex
+	mu     sync.Mutex
+	dirty  map[string]struct{}
+	sizes  map[string]int64
+	size   int64
+	loaded int
+}
+
+type persistedEntry struct {
+	Type       string          `json:"type"`
+	LastUpdate time.Time       `json:"lastUpdate"`
+	Source     SourceRef       `json:"source"`
+	Record     json.RawMessage `json:"record"`
+}
+
+// NewBoltStore loads the entries persisted in the bucket and rebuilds the
+// store indexes from them. Entries of record types that are no longer
+// persisted are removed from the bucket.
+func NewBoltStore(db *bolt.DB, cfg BoltStoreConfig) (*BoltStore, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("bucket name is required")
+	}
+	s := &BoltStore{
+		Interface: NewSyncMapStore(cfg.SyncMapStoreConfig),
+		db:        db,
+		cfg:       cfg,
+		types:     make(map[string]reflect.Type),
+		dirty:     make(map[string]struct{}),
+		sizes:     make(map[string]int64),
+	}
+	recordTypes := cfg.RecordTypes
+	if len(recordTypes) == 0 {
+		recordTypes = VanflowRecordTypes()
+	}
+	for _, record := range recordTypes {
+		s.types[record.GetTypeMeta().String()] = reflect.TypeOf(record)
+	}
+
+	var entries []Entry
+	err := db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(cfg.Bucket))
+		if err != nil {
+			return err
+		}
+		var unknown [][]byte
+		err = bucket.ForEach(func(k, v []byte) error {
+			entry, err := s.decode(v)
+			if err != nil {
+				unknown = append(unknown, append([]byte(nil), k...))
+				return nil
+			}
+			if cfg.OnLoad != nil {
+				entry = cfg.OnLoad(entry)
+			}
+			entries = append(entries, entry)
+			s.sizes[string(k)] = int64(len(v))
+			s.size += int64(len(v))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, key := range unknown {
+			if err := bucket.Delete(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error loading bucket %q: %w", cfg.Bucket, err)
+	}
+	s.Interface.Replace(entries)
+	s.loaded = len(entries)
+	return s, nil
+}
+
+// Loaded returns the number of entries loaded from the database
+func (s *BoltStore) Loaded() int {
+	return s.loaded
+}
+
+func (s *BoltStore) Add(record vanflow.Record, source SourceRef) bool {
+	ok := s.Interface.Add(record, source)
+	if ok {
+		s.markDirty(record.Identity())
+	}
+	return ok
+}
+
+func (s *BoltStore) Update(record vanflow.Record) bool {
+	ok := s.Interface.Update(record)
+	if ok {
+		s.markDirty(record.Identity())
+	}
+	return ok
+}
+
+func (s *BoltStore) Delete(id string) (Entry, bool) {
+	entry, ok := s.Interface.Delete(id)
+	if ok {
+		s.markDirty(id)
+	}
+	return entry, ok
+}
+
+func (s *BoltStore) Patch(record vanflow.Record, source SourceRef) {
+	s.Interface.Patch(record, source)
+	s.markDirty(record.Identity())
+}
+
+func (s *BoltStore) Replace(entries []Entry) {
+	for _, entry := range s.Interface.List() {
+		s.markDirty(entry.Record.Identity())
+	}
+	s.Interface.Replace(entries)
+	for _, entry := range entries {
+		s.markDirty(entry.Record.Identity())
+	}
+}
+
+func (s *BoltStore) markDirty(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dirty[id] = struct{}{}
+}
+
+// Flush writes the entries changed since the last flush to the database
+func (s *BoltStore) Flush() error {
+	s.flush.Lock()
+	defer s.flush.Unlock()
+	s.mu.Lock()
+	dirty := s.dirty
+	s.dirty = make(map[string]struct{})
+	s.mu.Unlock()
+	if len(dirty) == 0 {
+		return nil
+	}
+
+	sizes := make(map[string]int64, len(dirty))
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(s.cfg.Bucket))
+		if bucket == nil {
+			return fmt.Errorf("bucket %q not found", s.cfg.Bucket)
+		}
+		for id := range dirty {
+			entry, ok := s.Interface.Get(id)
+			if !ok {
+				if err := bucket.Delete([]byte(id)); err != nil {
+					return err
+				}
+				sizes[id] = 0
+				continue
+			}
+			if _, ok := s.types[entry.Record.GetTypeMeta().String()]; !ok {
+				continue
+			}
+			data, err := s.encode(entry)
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put([]byte(id), data); err != nil {
+				return err
+			}
+			sizes[id] = int64(len(data))
+		}
+		return nil
+	})
+	if err != nil {
+		// retry on the next flush
+		s.mu.Lock()
+		for id := range dirty {
+			s.dirty[id] = struct{}{}
+		}
+		s.mu.Unlock()
+		return fmt.Errorf("error flushing bucket %q: %w", s.cfg.Bucket, err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, size := range sizes {
+		s.size += size - s.sizes[id]
+		if size == 0 {
+			delete(s.sizes, id)
+		} else {
+			s.sizes[id] = size
+		}
+	}
+	return nil
+}
+
+// Size returns the size in bytes of the persisted entries as of the last
+// flush
+func (s *BoltStore) Size() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.size
+}
+
+// Purge deletes the entries exceeding the retention by age or size of the
+// store. Deletes are handled as any other delete, notifying the store event
+// handlers. It returns the number of entries purged.
+func (s *BoltStore) Purge() int {
+	entries := s.Interface.List()
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].LastUpdate.Before(entries[j].LastUpdate)
+	})
+	purged := 0
+	if s.cfg.MaxAge > 0 {
+		cutoff := time.Now().Add(-1 * s.cfg.MaxAge)
+		for purged < len(entries) && entries[purged].LastUpdate.Before(cutoff) {
+			s.Delete(entries[purged].Record.Identity())
+			purged++
+		}
+	}
+	if s.cfg.MaxSize > 0 {
+		s.mu.Lock()
+		excess := s.size - s.cfg.MaxSize
+		for i := 0; i < purged; i++ {
+			excess -= s.sizes[entries[i].Record.Identity()]
+		}
+		s.mu.Unlock()
+		for ; excess > 0 && purged < len(entries); purged++ {
+			id := entries[purged].Record.Identity()
+			s.mu.Lock()
+			excess -= s.sizes[id]
+			s.mu.Unlock()
+			s.Delete(id)
+		}
+	}
+	return purged
+}
+
+// Run periodically applies the retention of the store and flushes it to the
+// database until the context is cancelled, when it is flushed a last time.
+func (s *BoltStore) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if err := s.Flush(); err != nil && onError != nil {
+				onError(err)
+			}
+			return
+		case <-ticker.C:
+			s.Purge()
+			if err := s.Flush(); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+func (s *BoltStore) encode(entry Entry) ([]byte, error) {
+	record, err := json.Marshal(entry.Record)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(persistedEntry{
+		Type:       entry.Record.GetTypeMeta().String(),
+		LastUpdate: entry.LastUpdate,
+		Source:     entry.Source,
+		Record:     record,
+	})
+}
+
+func (s *BoltStore) decode(data []byte) (Entry, error) {
+	var persisted persistedEntry
+	if err := json.Unmarshal(data, &persisted); err != nil {
+		return Entry{}, err
+	}
+	typ, ok := s.types[persisted.Type]
+	if !ok {
+		return Entry{}, fmt.Errorf("unknown record type %q", persisted.Type)
+	}
+	value := reflect.New(typ)
+	if err := json.Unmarshal(persisted.Record, value.Interface()); err != nil {
+		return Entry{}, err
+	}
+	record, ok := value.Elem().Interface().(vanflow.Record)
+	if !ok {
+		return Entry{}, fmt.Errorf("type %q is not a record", persisted.Type)
+	}
+	return Entry{
+		Metadata: Metadata{
+			LastUpdate: persisted.LastUpdate,
+			Source:     persisted.Source,
+		},
+		Record: record,
+	}, nil
+}
+
+// VanflowRecordTypes returns an instance of each of the vanflow record types
+func VanflowRecordTypes() []vanflow.Record {
+	return []vanflow.Record{
+		vanflow.SiteRecord{},
+		vanflow.RouterRecord{},
+		vanflow.LinkRecord{},
+		vanflow.ControllerRecord{},
+		vanflow.ListenerRecord{},
+		vanflow.ConnectorRecord{},
+		vanflow.FlowRecord{},
+		vanflow.ProcessRecord{},
+		vanflow.HostRecord{},
+		vanflow.LogRecord{},
+		vanflow.RouterAccessRecord{},
+		vanflow.TransportBiflowRecord{},
+		vanflow.AppBiflowRecord{},
+	}
+}
@@ -0,0 +1,219 @@
+package store
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	bolt "go.etcd.io/bbolt"
+)
+
+func openTestBoltDB(t *testing.T, path string) *bolt.DB {
+	t.Helper()
+	db, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		t.Fatalf("unexpected error opening database: %s", err)
+	}
+	return db
+}
+
+func TestBoltStoreReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	db := openTestBoltDB(t, path)
+	stor, err := NewBoltStore(db, BoltStoreConfig{Bucket: "test"})
+	if err != nil {
+		t.Fatalf("unexpected error creating store: %s", err)
+	}
+
+	var expected []Entry
+	for i := 0; i < 8; i++ {
+		source := SourceRef{ID: fmt.Sprint(i % 2)}
+		record := vanflow.LogRecord{BaseRecord: vanflow.NewBase(fmt.Sprint(i)), LogText: ptrTo(fmt.Sprintf("log %d", i))}
+		stor.Add(record, source)
+		expected = append(expected, Entry{Record: record, Metadata: Metadata{Source: source}})
+	}
+	stor.Add(vanflow.SiteRecord{BaseRecord: vanflow.NewBase("site"), Name: ptrTo("east")}, SourceRef{ID: "0"})
+	stor.Delete("site")
+	stor.Update(vanflow.LogRecord{BaseRecord: vanflow.NewBase("0"), LogText: ptrTo("updated")})
+	expected[0].Record = vanflow.LogRecord{BaseRecord: vanflow.NewBase("0"), LogText: ptrTo("updated")}
+	if err := stor.Flush(); err != nil {
+		t.Fatalf("unexpected error flushing store: %s", err)
+	}
+	if stor.Size() <= 0 {
+		t.Errorf("expected positive size after flush but got %d", stor.Size())
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("unexpected error closing database: %s", err)
+	}
+
+	db = openTestBoltDB(t, path)
+	defer db.Close()
+	var loaded []Entry
+	reloaded, err := NewBoltStore(db, BoltStoreConfig{
+		Bucket: "test",
+		OnLoad: func(e Entry) Entry {
+			loaded = append(loaded, e)
+			return e
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error reloading store: %s", err)
+	}
+	if actual := reloaded.Loaded(); actual != len(expected) {
+		t.Errorf("expected %d entries to be loaded but got %d", len(expected), actual)
+	}
+	if actual := reloaded.List(); !cmp.Equal(actual, expected, ignoreLastUpdateAndOrder...) {
+		t.Errorf("reloaded store contents do not match expected: %s", cmp.Diff(actual, expected, ignoreLastUpdateAndOrder...))
+	}
+	if !cmp.Equal(loaded, expected, ignoreLastUpdateAndOrder...) {
+		t.Errorf("entries passed to OnLoad do not match expected: %s", cmp.Diff(loaded, expected, ignoreLastUpdateAndOrder...))
+	}
+	items := reloaded.Index(SourceIndex, Entry{Metadata: Metadata{Source: SourceRef{ID: "1"}}})
+	if expected, actual := 4, len(items); expected != actual {
+		t.Errorf("expected %d entries for source '1' but got %d", expected, actual)
+	}
+	if _, ok := reloaded.Get("site"); ok {
+		t.Errorf("expected deleted record to not be reloaded")
+	}
+}
+
+func TestBoltStorePurge(t *testing.T) {
+	db := openTestBoltDB(t, filepath.Join(t.TempDir(), "test.db"))
+	defer db.Close()
+
+	testCases := []struct {
+		Name     string
+		MaxAge   time.Duration
+		MaxSize  func(s *BoltStore) int64
+		Expected []string
+	}{
+		{
+			Name:     "no retention",
+			Expected: []string{"0", "1", "2", "3"},
+		}, {
+			Name:     "by age",
+			MaxAge:   90 * time.Minute,
+			Expected: []string{"2", "3"},
+		}, {
+			Name:     "by size",
+			MaxSize:  func(s *BoltStore) int64 { return s.sizes["2"] + s.sizes["3"] },
+			Expected: []string{"2", "3"},
+		}, {
+			Name:     "by age and size",
+			MaxAge:   150 * time.Minute,
+			MaxSize:  func(s *BoltStore) int64 { return s.sizes["3"] },
+			Expected: []string{"3"},
+		},
+	}
+	for i, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			var deleted []string
+			stor, err := NewBoltStore(db, BoltStoreConfig{
+				SyncMapStoreConfig: SyncMapStoreConfig{
+					Handlers: EventHandlerFuncs{
+						OnDelete: func(e Entry) { deleted = append(deleted, e.Record.Identity()) },
+					},
+				},
+				Bucket: fmt.Sprintf("test-%d", i),
+				MaxAge: tc.MaxAge,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error creating store: %s", err)
+			}
+			var initialState []Entry
+			for c := 0; c < 4; c++ {
+				initialState = append(initialState, Entry{
+					Metadata: Metadata{LastUpdate: time.Now().Add(time.Duration(c-3) * time.Hour)},
+					Record:   vanflow.LogRecord{BaseRecord: vanflow.NewBase(fmt.Sprint(c)), LogText: ptrTo("text")},
+				})
+			}
+			stor.Replace(initialState)
+			if err := stor.Flush(); err != nil {
+				t.Fatalf("unexpected error flushing store: %s", err)
+			}
+			if tc.MaxSize != nil {
+				stor.cfg.MaxSize = tc.MaxSize(stor)
+			}
+
+			purged := stor.Purge()
+			if err := stor.Flush(); err != nil {
+				t.Fatalf("unexpected error flushing store: %s", err)
+			}
+			if expected := len(initialState) - len(tc.Expected); purged != expected || len(deleted) != expected {
+				t.Errorf("expected %d entries to be purged but got %d with %d delete events", expected, purged, len(deleted))
+			}
+			var actual []string
+			for _, entry := range stor.List() {
+				actual = append(actual, entry.Record.Identity())
+			}
+			if !cmp.Equal(actual, tc.Expected, sortStrings) {
+				t.Errorf("unexpected entries after purge: %s", cmp.Diff(actual, tc.Expected, sortStrings))
+			}
+			err = db.View(func(tx *bolt.Tx) error {
+				if n := tx.Bucket([]byte(fmt.Sprintf("test-%d", i))).Stats().KeyN; n != len(tc.Expected) {
+					t.Errorf("expected %d persisted entries but got %d", len(tc.Expected), n)
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("unexpected error reading database: %s", err)
+			}
+		})
+	}
+}
+
+func TestBoltStoreRecordTypes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	db := openTestBoltDB(t, path)
+	stor, err := NewBoltStore(db, BoltStoreConfig{
+		Bucket:      "test",
+		RecordTypes: []vanflow.Record{testRecord{}, vanflow.LogRecord{}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating store: %s", err)
+	}
+	stor.Add(testRecord{ID: "custom"}, SourceRef{})
+	stor.Add(vanflow.LogRecord{BaseRecord: vanflow.NewBase("log")}, SourceRef{})
+	stor.Add(vanflow.SiteRecord{BaseRecord: vanflow.NewBase("site")}, SourceRef{})
+	if err := stor.Flush(); err != nil {
+		t.Fatalf("unexpected error flushing store: %s", err)
+	}
+	if _, ok := stor.Get("site"); !ok {
+		t.Errorf("expected record that is not persisted to be kept in memory")
+	}
+	db.Close()
+
+	db = openTestBoltDB(t, path)
+	defer db.Close()
+	reloaded, err := NewBoltStore(db, BoltStoreConfig{Bucket: "test"})
+	if err != nil {
+		t.Fatalf("unexpected error reloading store: %s", err)
+	}
+	if _, ok := reloaded.Get("custom"); ok {
+		t.Errorf("expected record of type no longer persisted to be dropped")
+	}
+	if _, ok := reloaded.Get("site"); ok {
+		t.Errorf("expected record that was not persisted to not be reloaded")
+	}
+	if _, ok := reloaded.Get("log"); !ok {
+		t.Errorf("expected log record to be reloaded")
+	}
+}
+
+type testRecord struct {
+	ID string
+}
+
+func (r testRecord) Identity() string {
+	return r.ID
+}
+
+func (r testRecord) GetTypeMeta() vanflow.TypeMeta {
+	return vanflow.TypeMeta{APIVersion: "test", Type: "TestRecord"}
+}
+
+var sortStrings = cmpopts.SortSlices(func(a, b string) bool { return a < b })